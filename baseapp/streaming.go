@@ -79,6 +79,13 @@ func (app *BaseApp) registerABCIListenerPlugin(
 	)
 }
 
+// AddABCIListener appends an in-process ABCIListener to the streaming manager, next to
+// the listeners of the streaming plugins. It must be called after
+// RegisterStreamingServices, which replaces the streaming manager.
+func (app *BaseApp) AddABCIListener(listener storetypes.ABCIListener) {
+	app.streamingManager.ABCIListeners = append(app.streamingManager.ABCIListeners, listener)
+}
+
 func exposeAll(list []string) bool {
 	for _, ele := range list {
 		if ele == "*" {
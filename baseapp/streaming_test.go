@@ -2,6 +2,7 @@ package baseapp_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -20,6 +21,10 @@ var _ storetypes.ABCIListener = (*MockABCIListener)(nil)
 type MockABCIListener struct {
 	name      string
 	ChangeSet []*storetypes.StoreKVPair
+
+	FinalizeBlockReqs []abci.RequestFinalizeBlock
+	FinalizeBlockRes  []abci.ResponseFinalizeBlock
+	FinalizeBlockErr  error
 }
 
 func NewMockABCIListener(name string) MockABCIListener {
@@ -29,8 +34,10 @@ func NewMockABCIListener(name string) MockABCIListener {
 	}
 }
 
-func (m MockABCIListener) ListenFinalizeBlock(_ context.Context, _ abci.RequestFinalizeBlock, _ abci.ResponseFinalizeBlock) error {
-	return nil
+func (m *MockABCIListener) ListenFinalizeBlock(_ context.Context, req abci.RequestFinalizeBlock, res abci.ResponseFinalizeBlock) error {
+	m.FinalizeBlockReqs = append(m.FinalizeBlockReqs, req)
+	m.FinalizeBlockRes = append(m.FinalizeBlockRes, res)
+	return m.FinalizeBlockErr
 }
 
 func (m *MockABCIListener) ListenCommit(_ context.Context, _ abci.ResponseCommit, cs []*storetypes.StoreKVPair) error {
@@ -110,6 +117,51 @@ func TestABCI_MultiListener_StateChanges(t *testing.T) {
 	}
 }
 
+func TestABCI_ListenFinalizeBlock(t *testing.T) {
+	anteKey := []byte("ante-key")
+	anteOpt := func(bapp *baseapp.BaseApp) { bapp.SetAnteHandler(anteHandlerTxTest(t, capKey1, anteKey)) }
+	failingListener := NewMockABCIListener("failing")
+	failingListener.FinalizeBlockErr = errors.New("listener failed")
+	mockListener := NewMockABCIListener("lis_1")
+	streamingManagerOpt := func(bapp *baseapp.BaseApp) {
+		bapp.SetStreamingManager(storetypes.StreamingManager{ABCIListeners: []storetypes.ABCIListener{&failingListener}})
+	}
+	// AddABCIListener appends to the listeners of the streaming manager
+	addListenerOpt := func(bapp *baseapp.BaseApp) { bapp.AddABCIListener(&mockListener) }
+	suite := NewBaseAppSuite(t, anteOpt, streamingManagerOpt, addListenerOpt)
+
+	_, err := suite.baseApp.InitChain(&abci.RequestInitChain{
+		ConsensusParams: &tmproto.ConsensusParams{},
+	})
+	require.NoError(t, err)
+	deliverKey := []byte("deliver-key")
+	baseapptestutil.RegisterCounterServer(suite.baseApp.MsgServiceRouter(), CounterServerImpl{t, capKey1, deliverKey})
+
+	nBlocks := 3
+	for blockN := 0; blockN < nBlocks; blockN++ {
+		tx := newTxCounter(t, suite.txConfig, int64(blockN), int64(blockN))
+		txBytes, err := suite.txConfig.TxEncoder()(tx)
+		require.NoError(t, err)
+
+		req := &abci.RequestFinalizeBlock{Height: int64(blockN) + 1, Txs: [][]byte{txBytes}}
+		res, err := suite.baseApp.FinalizeBlock(req)
+		require.NoError(t, err, "a failing listener must not fail the block")
+
+		// Every listener is called once per block with the request and the final response,
+		// app hash included
+		for _, listener := range []*MockABCIListener{&failingListener, &mockListener} {
+			require.Len(t, listener.FinalizeBlockReqs, blockN+1)
+			require.Equal(t, *req, listener.FinalizeBlockReqs[blockN])
+			require.Equal(t, *res, listener.FinalizeBlockRes[blockN])
+			require.NotEmpty(t, listener.FinalizeBlockRes[blockN].AppHash)
+			require.Len(t, listener.FinalizeBlockRes[blockN].TxResults, 1)
+		}
+
+		_, err = suite.baseApp.Commit()
+		require.NoError(t, err)
+	}
+}
+
 func Test_Ctx_with_StreamingManager(t *testing.T) {
 	mockListener1 := NewMockABCIListener("lis_1")
 	mockListener2 := NewMockABCIListener("lis_2")
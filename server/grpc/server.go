@@ -39,6 +39,9 @@ func NewGRPCServer(clientCtx client.Context, app types.Application, cfg config.G
 	)
 
 	app.RegisterGRPCServer(grpcSrv)
+	if streamingApp, ok := app.(types.GRPCStreamingApplication); ok {
+		streamingApp.RegisterGRPCStreamingServices(grpcSrv)
+	}
 
 	// Reflection allows consumers to build dynamic clients that can write to any
 	// Cosmos SDK application without relying on application packages at compile
@@ -64,6 +64,15 @@ type (
 		Close() error
 	}
 
+	// GRPCStreamingApplication is implemented by applications serving streaming gRPC
+	// services. Unlike RegisterGRPCServer, which routes unary queries through the
+	// BaseApp query router, the services are registered as is and may stream.
+	GRPCStreamingApplication interface {
+		// RegisterGRPCStreamingServices registers streaming gRPC services with the
+		// gRPC server.
+		RegisterGRPCStreamingServices(grpc.Server)
+	}
+
 	// AppCreator is a function that allows us to lazily initialize an
 	// application using various configurations.
 	AppCreator func(log.Logger, dbm.DB, io.Writer, AppOptions) Application
@@ -13,6 +13,9 @@ import (
 type HandlerOptions struct {
 	ante.HandlerOptions
 	CircuitKeeper circuitante.CircuitBreaker
+	// CapsuleSpamDecorator optionally charges and rate limits capsule creation right after
+	// the fees are deducted. It is only set by apps wiring the timecapsule module.
+	CapsuleSpamDecorator sdk.AnteDecorator
 }
 
 // NewAnteHandler returns an AnteHandler that checks and increments sequence
@@ -40,12 +43,17 @@ func NewAnteHandler(options HandlerOptions) (sdk.AnteHandler, error) {
 		ante.NewValidateMemoDecorator(options.AccountKeeper),
 		ante.NewConsumeGasForTxSizeDecorator(options.AccountKeeper),
 		ante.NewDeductFeeDecorator(options.AccountKeeper, options.BankKeeper, options.FeegrantKeeper, options.TxFeeChecker),
+	}
+	if options.CapsuleSpamDecorator != nil {
+		anteDecorators = append(anteDecorators, options.CapsuleSpamDecorator)
+	}
+	anteDecorators = append(anteDecorators,
 		ante.NewSetPubKeyDecorator(options.AccountKeeper), // SetPubKeyDecorator must be called before all signature verification decorators
 		ante.NewValidateSigCountDecorator(options.AccountKeeper),
 		ante.NewSigGasConsumeDecorator(options.AccountKeeper, options.SigGasConsumer),
 		ante.NewSigVerificationDecorator(options.AccountKeeper, options.SignModeHandler),
 		ante.NewIncrementSequenceDecorator(options.AccountKeeper),
-	}
+	)
 
 	return sdk.ChainAnteDecorators(anteDecorators...), nil
 }
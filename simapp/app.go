@@ -4,6 +4,7 @@ package simapp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -36,6 +37,7 @@ import (
 	upgradetypes "cosmossdk.io/x/upgrade/types"
 	abci "github.com/cometbft/cometbft/abci/types"
 	dbm "github.com/cosmos/cosmos-db"
+	gogogrpc "github.com/cosmos/gogoproto/grpc"
 	"github.com/cosmos/gogoproto/proto"
 	"github.com/spf13/cast"
 
@@ -105,9 +107,17 @@ import (
 	stakingkeeper "github.com/cosmos/cosmos-sdk/x/staking/keeper"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	
+	// Oracle module
+	"github.com/cosmos/cosmos-sdk/x/oracle"
+	oraclekeeper "github.com/cosmos/cosmos-sdk/x/oracle/keeper"
+	oracletypes "github.com/cosmos/cosmos-sdk/x/oracle/types"
+	
 	// Time Capsule module
 	"github.com/cosmos/cosmos-sdk/x/timecapsule"
+	timecapsuleante "github.com/cosmos/cosmos-sdk/x/timecapsule/ante"
+	timecapsulecrypto "github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
 	timecapsulekeeper "github.com/cosmos/cosmos-sdk/x/timecapsule/keeper"
+	timecapsulestream "github.com/cosmos/cosmos-sdk/x/timecapsule/stream"
 	timecapsuletypes "github.com/cosmos/cosmos-sdk/x/timecapsule/types"
 )
 
@@ -132,8 +142,9 @@ var (
 )
 
 var (
-	_ runtime.AppI            = (*SimApp)(nil)
-	_ servertypes.Application = (*SimApp)(nil)
+	_ runtime.AppI                         = (*SimApp)(nil)
+	_ servertypes.Application              = (*SimApp)(nil)
+	_ servertypes.GRPCStreamingApplication = (*SimApp)(nil)
 )
 
 // SimApp extends an ABCI application, but with most of its parameters exported.
@@ -168,8 +179,12 @@ type SimApp struct {
 	ConsensusParamsKeeper consensusparamkeeper.Keeper
 	CircuitKeeper         circuitkeeper.Keeper
 	PoolKeeper            poolkeeper.Keeper
+	OracleKeeper          oraclekeeper.Keeper
 	TimeCapsuleKeeper     timecapsulekeeper.Keeper
 
+	// TimeCapsuleWatcher streams capsule lifecycle events to gRPC clients
+	TimeCapsuleWatcher *timecapsulestream.Watcher
+
 	// the module manager
 	ModuleManager      *module.Manager
 	BasicModuleManager module.BasicManager
@@ -243,12 +258,8 @@ func NewSimApp(
 	// }
 	// baseAppOptions = append(baseAppOptions, prepareOpt)
 
-	// create and set dummy vote extension handler
-	voteExtOp := func(bApp *baseapp.BaseApp) {
-		voteExtHandler := NewVoteExtensionHandler()
-		voteExtHandler.SetHandlers(bApp)
-	}
-	baseAppOptions = append(baseAppOptions, voteExtOp, baseapp.SetOptimisticExecution())
+	// the timelock vote extension handler is set once the timecapsule keeper exists
+	baseAppOptions = append(baseAppOptions, baseapp.SetOptimisticExecution())
 
 	bApp := baseapp.NewBaseApp(appName, logger, db, txConfig.TxDecoder(), baseAppOptions...)
 	bApp.SetCommitMultiStoreTracer(traceStore)
@@ -262,7 +273,7 @@ func NewSimApp(
 		govtypes.StoreKey, consensusparamtypes.StoreKey, upgradetypes.StoreKey, feegrant.StoreKey,
 		evidencetypes.StoreKey, circuittypes.StoreKey,
 		authzkeeper.StoreKey, nftkeeper.StoreKey, group.StoreKey, pooltypes.StoreKey,
-		timecapsuletypes.StoreKey,
+		oracletypes.StoreKey, timecapsuletypes.StoreKey,
 	)
 
 	// register streaming services
@@ -270,6 +281,11 @@ func NewSimApp(
 		panic(err)
 	}
 
+	// the capsule watcher listens next to the streaming services, which replace the
+	// streaming manager and so must be registered first
+	timeCapsuleWatcher := timecapsulestream.NewWatcher(logger, cast.ToInt(appOpts.Get(timecapsulestream.FlagWatchHistoryBlocks)))
+	bApp.AddABCIListener(timeCapsuleWatcher)
+
 	app := &SimApp{
 		BaseApp:           bApp,
 		legacyAmino:       legacyAmino,
@@ -277,6 +293,8 @@ func NewSimApp(
 		txConfig:          txConfig,
 		interfaceRegistry: interfaceRegistry,
 		keys:              keys,
+
+		TimeCapsuleWatcher: timeCapsuleWatcher,
 	}
 
 	// set the BaseApp's parameter store
@@ -313,12 +331,6 @@ func NewSimApp(
 
 	app.FeeGrantKeeper = feegrantkeeper.NewKeeper(appCodec, runtime.NewKVStoreService(keys[feegrant.StoreKey]), app.AccountKeeper)
 
-	// register the staking hooks
-	// NOTE: stakingKeeper above is passed by reference, so that it will contain these hooks
-	app.StakingKeeper.SetHooks(
-		stakingtypes.NewMultiStakingHooks(app.DistrKeeper.Hooks(), app.SlashingKeeper.Hooks()),
-	)
-
 	app.CircuitKeeper = circuitkeeper.NewKeeper(appCodec, runtime.NewKVStoreService(keys[circuittypes.StoreKey]), authtypes.NewModuleAddress(govtypes.ModuleName).String(), app.AccountKeeper.AddressCodec())
 	app.BaseApp.SetCircuitBreaker(&app.CircuitKeeper)
 
@@ -366,6 +378,15 @@ func NewSimApp(
 
 	app.NFTKeeper = nftkeeper.NewKeeper(runtime.NewKVStoreService(keys[nftkeeper.StoreKey]), appCodec, app.AccountKeeper, app.BankKeeper)
 
+	// Oracle keeper, fed by validator votes
+	app.OracleKeeper = oraclekeeper.NewKeeper(
+		appCodec,
+		runtime.NewKVStoreService(keys[oracletypes.StoreKey]),
+		logger,
+		app.StakingKeeper,
+		authtypes.NewModuleAddress(govtypes.ModuleName).String(),
+	)
+
 	// TimeCapsule keeper
 	app.TimeCapsuleKeeper = timecapsulekeeper.NewKeeper(
 		appCodec,
@@ -374,7 +395,36 @@ func NewSimApp(
 		logger,
 		app.BankKeeper,
 		app.AccountKeeper,
+		app.StakingKeeper,
+		app.GroupKeeper,
+		authtypes.NewModuleAddress(govtypes.ModuleName).String(),
+	)
+
+	// let time-locked capsules unlock a number of epochs after an upgrade plan is applied
+	app.TimeCapsuleKeeper.SetEpochSource(timecapsuletypes.EpochSourceUpgrade, timecapsulekeeper.NewUpgradeEpochSource(app.UpgradeKeeper, timecapsuletypes.DefaultUpgradeEpochBlocks))
+
+	// evaluate oracle conditions against the on-chain oracle
+	app.TimeCapsuleKeeper.SetOracleKeeper(app.OracleKeeper)
+
+	// register the staking hooks
+	// NOTE: stakingKeeper above is passed by reference, so that it will contain these hooks
+	app.StakingKeeper.SetHooks(
+		stakingtypes.NewMultiStakingHooks(app.DistrKeeper.Hooks(), app.SlashingKeeper.Hooks(), app.TimeCapsuleKeeper.Hooks()),
+	)
+
+	// Validators without a timelock key still verify and relay vote extensions, they only
+	// skip dealing and releasing epoch key shares
+	timelockKey, _, keyErr := timecapsulecrypto.LoadTimelockKey(filepath.Join(homePath, timecapsuletypes.DefaultTimelockKeyFile))
+	if keyErr != nil && !errors.Is(keyErr, os.ErrNotExist) {
+		panic(keyErr)
+	}
+	timelockHandler := timecapsulekeeper.NewTimelockHandler(
+		app.TimeCapsuleKeeper,
+		app.StakingKeeper,
+		baseapp.NewDefaultProposalHandler(bApp.Mempool(), bApp),
+		timelockKey,
 	)
+	timelockHandler.SetHandlers(bApp)
 
 	// create evidence keeper with router
 	evidenceKeeper := evidencekeeper.NewKeeper(
@@ -414,7 +464,8 @@ func NewSimApp(
 		consensus.NewAppModule(appCodec, app.ConsensusParamsKeeper),
 		circuit.NewAppModule(appCodec, app.CircuitKeeper),
 		protocolpool.NewAppModule(appCodec, app.PoolKeeper, app.AccountKeeper, app.BankKeeper),
-		timecapsule.NewAppModule(appCodec, app.TimeCapsuleKeeper, app.AccountKeeper, app.BankKeeper, authcodec.NewBech32Codec(sdk.Bech32MainPrefix)),
+		oracle.NewAppModule(appCodec, app.OracleKeeper, authcodec.NewBech32Codec(sdk.Bech32MainPrefix)),
+		timecapsule.NewAppModule(appCodec, app.TimeCapsuleKeeper, app.AccountKeeper, app.BankKeeper, app.StakingKeeper, authcodec.NewBech32Codec(sdk.Bech32MainPrefix)),
 	)
 
 	// BasicModuleManager defines the module BasicManager is in charge of setting up basic,
@@ -457,6 +508,7 @@ func NewSimApp(
 		genutiltypes.ModuleName,
 		feegrant.ModuleName,
 		group.ModuleName,
+		oracletypes.ModuleName,
 		timecapsuletypes.ModuleName,
 	)
 
@@ -469,7 +521,7 @@ func NewSimApp(
 		minttypes.ModuleName, crisistypes.ModuleName, genutiltypes.ModuleName, evidencetypes.ModuleName, authz.ModuleName,
 		feegrant.ModuleName, nft.ModuleName, group.ModuleName, upgradetypes.ModuleName,
 		vestingtypes.ModuleName, consensusparamtypes.ModuleName, circuittypes.ModuleName, pooltypes.ModuleName,
-		timecapsuletypes.ModuleName,
+		oracletypes.ModuleName, timecapsuletypes.ModuleName,
 	}
 	app.ModuleManager.SetOrderInitGenesis(genesisModuleOrder...)
 	app.ModuleManager.SetOrderExportGenesis(genesisModuleOrder...)
@@ -487,6 +539,7 @@ func NewSimApp(
 	// RegisterUpgradeHandlers is used for registering any on-chain upgrades.
 	// Make sure it's called after `app.ModuleManager` and `app.configurator` are set.
 	app.RegisterUpgradeHandlers()
+	app.RegisterTimeCapsuleUpgradeHandlers()
 
 	autocliv1.RegisterQueryServer(app.GRPCQueryRouter(), runtimeservices.NewAutoCLIQueryService(app.ModuleManager.Modules))
 
@@ -568,6 +621,7 @@ func (app *SimApp) setAnteHandler(txConfig client.TxConfig) {
 				SigGasConsumer:  ante.DefaultSigVerificationGasConsumer,
 			},
 			&app.CircuitKeeper,
+			timecapsuleante.NewCapsuleSpamDecorator(app.TimeCapsuleKeeper, app.BankKeeper),
 		},
 	)
 	if err != nil {
@@ -592,9 +646,22 @@ func (app *SimApp) setPostHandler() {
 // Name returns the name of the App
 func (app *SimApp) Name() string { return app.BaseApp.Name() }
 
-// PreBlocker application updates every pre block
-func (app *SimApp) PreBlocker(ctx sdk.Context, _ *abci.RequestFinalizeBlock) (*sdk.ResponsePreBlock, error) {
-	return app.ModuleManager.PreBlock(ctx)
+// PreBlocker application updates every pre block. The timelock vote extensions are
+// applied after the module pre blockers, so an upgrade plan or a consensus params
+// change of the block is in effect before capsule state changes.
+func (app *SimApp) PreBlocker(ctx sdk.Context, req *abci.RequestFinalizeBlock) (*sdk.ResponsePreBlock, error) {
+	rsp, err := app.ModuleManager.PreBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.ConsensusParamsChanged {
+		ctx = ctx.WithConsensusParams(app.GetConsensusParams(ctx))
+	}
+
+	if err := app.TimeCapsuleKeeper.ApplyTimelockInjection(ctx, req); err != nil {
+		return nil, err
+	}
+	return rsp, nil
 }
 
 // BeginBlocker application updates every begin block
@@ -739,6 +806,11 @@ func (app *SimApp) RegisterNodeService(clientCtx client.Context, cfg config.Conf
 	nodeservice.RegisterNodeService(clientCtx, app.GRPCQueryRouter(), cfg)
 }
 
+// RegisterGRPCStreamingServices implements the GRPCStreamingApplication interface.
+func (app *SimApp) RegisterGRPCStreamingServices(srv gogogrpc.Server) {
+	timecapsulestream.RegisterWatchServer(srv, app.TimeCapsuleWatcher)
+}
+
 // GetMaccPerms returns a copy of the module account permissions
 //
 // NOTE: This is solely to be used for testing purposes.
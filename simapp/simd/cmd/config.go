@@ -8,6 +8,7 @@ import (
 	clientconfig "github.com/cosmos/cosmos-sdk/client/config"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	serverconfig "github.com/cosmos/cosmos-sdk/server/config"
+	timecapsulestream "github.com/cosmos/cosmos-sdk/x/timecapsule/stream"
 )
 
 // initCometBFTConfig helps to override default CometBFT Config values.
@@ -79,10 +80,19 @@ func initAppConfig() (string, interface{}) {
 		LruSize uint64 `mapstructure:"lru-size"`
 	}
 
+	// TimeCapsuleConfig defines the node configuration of the timecapsule module.
+	type TimeCapsuleConfig struct {
+		// WatchHistoryBlocks is the number of committed blocks the capsule watch stream
+		// keeps in memory to replay
+		WatchHistoryBlocks uint64 `mapstructure:"watch-history-blocks"`
+	}
+
 	type CustomAppConfig struct {
 		serverconfig.Config `mapstructure:",squash"`
 
 		WASM WASMConfig `mapstructure:"wasm"`
+
+		TimeCapsule TimeCapsuleConfig `mapstructure:"timecapsule"`
 	}
 
 	// Optionally allow the chain developer to overwrite the SDK's default
@@ -110,6 +120,9 @@ func initAppConfig() (string, interface{}) {
 			LruSize:       1,
 			QueryGasLimit: 300000,
 		},
+		TimeCapsule: TimeCapsuleConfig{
+			WatchHistoryBlocks: timecapsulestream.DefaultHistoryBlocks,
+		},
 	}
 
 	// The default SDK app template is defined in serverconfig.DefaultConfigTemplate.
@@ -121,7 +134,12 @@ func initAppConfig() (string, interface{}) {
 query-gas-limit = {{ .WASM.QueryGasLimit }}
 # This is the number of wasm vm instances we keep cached in memory for speed-up
 # Warning: this is currently unstable and may lead to crashes, best to keep for 0 unless testing locally
-lru-size = {{ .WASM.LruSize }}`
+lru-size = {{ .WASM.LruSize }}
+
+[timecapsule]
+# The number of committed blocks the capsule watch stream keeps in memory, so that
+# clients can resume from a cursor or replay from a height
+watch-history-blocks = {{ .TimeCapsule.WatchHistoryBlocks }}`
 
 	return customAppTemplate, customAppConfig
 }
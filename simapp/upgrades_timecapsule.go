@@ -0,0 +1,76 @@
+//go:build app_v1
+
+package simapp
+
+import (
+	"context"
+
+	storetypes "cosmossdk.io/store/types"
+	upgradetypes "cosmossdk.io/x/upgrade/types"
+
+	"github.com/cosmos/cosmos-sdk/types/module"
+	oracletypes "github.com/cosmos/cosmos-sdk/x/oracle/types"
+)
+
+// TimeCapsuleUpgradeName defines the on-chain upgrade that migrates the timecapsule
+// store to consensus version 2 and adds the oracle store.
+const TimeCapsuleUpgradeName = "timecapsule-v2"
+
+// TimeCapsuleStatsUpgradeName defines the on-chain upgrade that migrates the timecapsule
+// store to consensus version 3.
+const TimeCapsuleStatsUpgradeName = "timecapsule-v3"
+
+// TimeCapsuleRetentionUpgradeName defines the on-chain upgrade that migrates the
+// timecapsule store to consensus version 4.
+const TimeCapsuleRetentionUpgradeName = "timecapsule-v4"
+
+// TimeCapsuleUnlockTimeUpgradeName defines the on-chain upgrade that migrates the
+// timecapsule store to consensus version 5.
+const TimeCapsuleUnlockTimeUpgradeName = "timecapsule-v5"
+
+// TimeCapsuleTagIndexUpgradeName defines the on-chain upgrade that migrates the
+// timecapsule store to consensus version 6.
+const TimeCapsuleTagIndexUpgradeName = "timecapsule-v6"
+
+// timeCapsuleUpgradeNames lists the timecapsule upgrades in the order they were released:
+//   - timecapsule-v2 builds the custodian and emergency action indexes
+//   - timecapsule-v3 derives the capsule statistics
+//   - timecapsule-v4 queues capsules for their expiry and the purge of their data
+//   - timecapsule-v5 queues capsules unlocking on a block time
+//   - timecapsule-v6 indexes capsules by owner and tag, and by indexed metadata entry
+var timeCapsuleUpgradeNames = []string{
+	TimeCapsuleUpgradeName,
+	TimeCapsuleStatsUpgradeName,
+	TimeCapsuleRetentionUpgradeName,
+	TimeCapsuleUnlockTimeUpgradeName,
+	TimeCapsuleTagIndexUpgradeName,
+}
+
+// RegisterTimeCapsuleUpgradeHandlers registers the timecapsule upgrades, which only
+// apply to the app wiring the timecapsule and oracle modules.
+func (app SimApp) RegisterTimeCapsuleUpgradeHandlers() {
+	// each timecapsule upgrade runs the store migrations up to its consensus version
+	for _, name := range timeCapsuleUpgradeNames {
+		app.UpgradeKeeper.SetUpgradeHandler(
+			name,
+			func(ctx context.Context, _ upgradetypes.Plan, fromVM module.VersionMap) (module.VersionMap, error) {
+				return app.ModuleManager.RunMigrations(ctx, app.Configurator(), fromVM)
+			},
+		)
+	}
+
+	upgradeInfo, err := app.UpgradeKeeper.ReadUpgradeInfoFromDisk()
+	if err != nil {
+		panic(err)
+	}
+
+	if upgradeInfo.Name == TimeCapsuleUpgradeName && !app.UpgradeKeeper.IsSkipHeight(upgradeInfo.Height) {
+		storeUpgrades := storetypes.StoreUpgrades{
+			Added: []string{
+				oracletypes.StoreKey,
+			},
+		}
+
+		app.SetStoreLoader(upgradetypes.UpgradeStoreLoader(upgradeInfo.Height, &storeUpgrades))
+	}
+}
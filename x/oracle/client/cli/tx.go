@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"cosmossdk.io/core/address"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/oracle/types"
+)
+
+// GetTxCmd returns the transaction commands for this module
+func GetTxCmd(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                        types.ModuleName,
+		Short:                      fmt.Sprintf("%s transactions subcommands", types.ModuleName),
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	cmd.AddCommand(
+		CmdPrevote(ac),
+		CmdVote(ac),
+	)
+
+	return cmd
+}
+
+// CmdPrevote returns a CLI command for committing to a vote
+func CmdPrevote(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prevote [salt] [prices] [validator]",
+		Short: "Commit to the hash of the vote revealed in the next voting period",
+		Long: `Commit to a vote without revealing it. The vote must be revealed with the same
+salt, prices and data in the next voting period. The validator defaults to the
+operator address of the sender.
+
+Example:
+$ simd tx oracle prevote 9f2c7d3a1e "ATOM=8.42,BTC=64000" \
+  --data="weather.berlin=736e6f77" \
+  --from=validator`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			prices, data, validator, err := parseVote(cmd, clientCtx, args)
+			if err != nil {
+				return err
+			}
+
+			hash := types.VoteHash(args[0], prices, data, validator)
+			msg := types.NewMsgPrevote(clientCtx.GetFromAddress().String(), validator, hash)
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().StringSlice("data", nil, "Data values as key=hex-value, can be repeated")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdVote returns a CLI command for revealing a prevoted vote
+func CmdVote(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vote [salt] [prices] [validator]",
+		Short: "Reveal the vote committed to in the previous voting period",
+		Long: `Reveal a vote with the salt, prices and data of its prevote. The validator
+defaults to the operator address of the sender.
+
+Example:
+$ simd tx oracle vote 9f2c7d3a1e "ATOM=8.42,BTC=64000" \
+  --data="weather.berlin=736e6f77" \
+  --from=validator`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			prices, data, validator, err := parseVote(cmd, clientCtx, args)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgVote(clientCtx.GetFromAddress().String(), validator, args[0], prices, data)
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().StringSlice("data", nil, "Data values as key=hex-value, can be repeated")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// parseVote parses the prices, data and validator of a prevote or vote command
+func parseVote(cmd *cobra.Command, clientCtx client.Context, args []string) ([]types.PriceVote, []types.DataVote, string, error) {
+	prices, err := types.ParsePrices(args[1])
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	entries, _ := cmd.Flags().GetStringSlice("data")
+	var data []types.DataVote
+	for _, entry := range entries {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, nil, "", fmt.Errorf("invalid data value %q, expected key=hex-value", entry)
+		}
+		bz, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("invalid hex value for %s: %w", key, err)
+		}
+		data = append(data, types.DataVote{Key: key, Value: bz})
+	}
+
+	if err := types.ValidateVote(prices, data); err != nil {
+		return nil, nil, "", err
+	}
+
+	validator := sdk.ValAddress(clientCtx.GetFromAddress()).String()
+	if len(args) > 2 {
+		validator = args[2]
+	}
+
+	return prices, data, validator, nil
+}
@@ -0,0 +1,147 @@
+package oracle
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/oracle/keeper"
+	"github.com/cosmos/cosmos-sdk/x/oracle/types"
+)
+
+// DefaultGenesis returns the default oracle genesis state
+func DefaultGenesis() *types.GenesisState {
+	return &types.GenesisState{
+		Params:   types.DefaultParams(),
+		Prices:   []types.Price{},
+		Data:     []types.DataValue{},
+		Prevotes: []types.Prevote{},
+		Votes:    []types.Vote{},
+	}
+}
+
+// ValidateGenesis validates the oracle module's genesis state
+func ValidateGenesis(genState *types.GenesisState) error {
+	if err := genState.Params.Validate(); err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
+
+	symbols := make(map[string]bool)
+	for i, price := range genState.Prices {
+		if err := types.ValidateSymbol(price.Symbol); err != nil {
+			return fmt.Errorf("invalid price at index %d: %w", i, err)
+		}
+		if symbols[price.Symbol] {
+			return fmt.Errorf("duplicate price for %s", price.Symbol)
+		}
+		symbols[price.Symbol] = true
+		if price.Price.IsNil() || !price.Price.IsPositive() {
+			return fmt.Errorf("price of %s must be positive", price.Symbol)
+		}
+	}
+
+	keys := make(map[string]bool)
+	for i, value := range genState.Data {
+		if err := types.ValidateSymbol(value.Key); err != nil {
+			return fmt.Errorf("invalid data value at index %d: %w", i, err)
+		}
+		if keys[value.Key] {
+			return fmt.Errorf("duplicate data value for %s", value.Key)
+		}
+		keys[value.Key] = true
+	}
+
+	prevoters := make(map[string]bool)
+	for i, prevote := range genState.Prevotes {
+		if _, err := sdk.ValAddressFromBech32(prevote.Validator); err != nil {
+			return fmt.Errorf("invalid prevote at index %d: %w", i, err)
+		}
+		if prevoters[prevote.Validator] {
+			return fmt.Errorf("duplicate prevote for %s", prevote.Validator)
+		}
+		prevoters[prevote.Validator] = true
+	}
+
+	voters := make(map[string]bool)
+	for i, vote := range genState.Votes {
+		if _, err := sdk.ValAddressFromBech32(vote.Validator); err != nil {
+			return fmt.Errorf("invalid vote at index %d: %w", i, err)
+		}
+		if voters[vote.Validator] {
+			return fmt.Errorf("duplicate vote for %s", vote.Validator)
+		}
+		voters[vote.Validator] = true
+		if err := types.ValidateVote(vote.Prices, vote.Data); err != nil {
+			return fmt.Errorf("invalid vote at index %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// InitGenesis initializes the oracle module's state from a provided genesis state
+func InitGenesis(ctx sdk.Context, k keeper.Keeper, genState *types.GenesisState) {
+	if err := k.SetParams(ctx, genState.Params); err != nil {
+		panic(fmt.Sprintf("failed to set params: %v", err))
+	}
+
+	for _, price := range genState.Prices {
+		if err := k.SetPrice(ctx, price); err != nil {
+			panic(fmt.Sprintf("failed to set price of %s: %v", price.Symbol, err))
+		}
+	}
+
+	for _, value := range genState.Data {
+		if err := k.SetData(ctx, value); err != nil {
+			panic(fmt.Sprintf("failed to set data value of %s: %v", value.Key, err))
+		}
+	}
+
+	for _, prevote := range genState.Prevotes {
+		if err := k.SetPrevote(ctx, prevote); err != nil {
+			panic(fmt.Sprintf("failed to set prevote of %s: %v", prevote.Validator, err))
+		}
+	}
+
+	for _, vote := range genState.Votes {
+		if err := k.SetVote(ctx, vote); err != nil {
+			panic(fmt.Sprintf("failed to set vote of %s: %v", vote.Validator, err))
+		}
+	}
+}
+
+// ExportGenesis returns the oracle module's exported genesis
+func ExportGenesis(ctx sdk.Context, k keeper.Keeper) *types.GenesisState {
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		panic(fmt.Sprintf("failed to get params: %v", err))
+	}
+
+	prices, err := k.GetAllPrices(ctx)
+	if err != nil {
+		panic(fmt.Sprintf("failed to get prices: %v", err))
+	}
+
+	data, err := k.GetAllData(ctx)
+	if err != nil {
+		panic(fmt.Sprintf("failed to get data values: %v", err))
+	}
+
+	prevotes, err := k.GetAllPrevotes(ctx)
+	if err != nil {
+		panic(fmt.Sprintf("failed to get prevotes: %v", err))
+	}
+
+	votes, err := k.GetAllVotes(ctx)
+	if err != nil {
+		panic(fmt.Sprintf("failed to get votes: %v", err))
+	}
+
+	return &types.GenesisState{
+		Params:   params,
+		Prices:   prices,
+		Data:     data,
+		Prevotes: prevotes,
+		Votes:    votes,
+	}
+}
@@ -0,0 +1,259 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/core/store"
+	"cosmossdk.io/log"
+	"cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/oracle/types"
+)
+
+// Keeper of the oracle store
+type Keeper struct {
+	cdc          codec.BinaryCodec
+	storeService store.KVStoreService
+	logger       log.Logger
+
+	// the address capable of executing a MsgUpdateParams message, typically the gov module account
+	authority string
+
+	// State management
+	params   collections.Item[types.Params]
+	prevotes collections.Map[string, types.Prevote]   // key: validator operator address
+	votes    collections.Map[string, types.Vote]      // key: validator operator address
+	prices   collections.Map[string, types.Price]     // key: symbol
+	data     collections.Map[string, types.DataValue] // key: data key
+
+	// Expected keepers
+	stakingKeeper types.StakingKeeper
+}
+
+// NewKeeper creates a new oracle keeper
+func NewKeeper(
+	cdc codec.BinaryCodec,
+	storeService store.KVStoreService,
+	logger log.Logger,
+	stakingKeeper types.StakingKeeper,
+	authority string,
+) Keeper {
+	sb := collections.NewSchemaBuilder(storeService)
+
+	k := Keeper{
+		cdc:          cdc,
+		storeService: storeService,
+		logger:       logger.With("module", "x/"+types.ModuleName),
+		authority:    authority,
+
+		params:   collections.NewItem(sb, types.ParamsKey, "params", codec.CollValue[types.Params](cdc)),
+		prevotes: collections.NewMap(sb, types.PrevotesKeyPrefix, "prevotes", collections.StringKey, codec.CollValue[types.Prevote](cdc)),
+		votes:    collections.NewMap(sb, types.VotesKeyPrefix, "votes", collections.StringKey, codec.CollValue[types.Vote](cdc)),
+		prices:   collections.NewMap(sb, types.PricesKeyPrefix, "prices", collections.StringKey, codec.CollValue[types.Price](cdc)),
+		data:     collections.NewMap(sb, types.DataKeyPrefix, "data", collections.StringKey, codec.CollValue[types.DataValue](cdc)),
+
+		stakingKeeper: stakingKeeper,
+	}
+
+	if _, err := sb.Build(); err != nil {
+		panic(err)
+	}
+
+	return k
+}
+
+// GetAuthority returns the module's authority
+func (k Keeper) GetAuthority() string {
+	return k.authority
+}
+
+// Logger returns a module-specific logger
+func (k Keeper) Logger(ctx context.Context) log.Logger {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+	return sdkCtx.Logger().With("module", "x/"+types.ModuleName)
+}
+
+// GetParams returns the module parameters, or the defaults if none are stored
+func (k Keeper) GetParams(ctx context.Context) (types.Params, error) {
+	params, err := k.params.Get(ctx)
+	if errors.Is(err, collections.ErrNotFound) {
+		return types.DefaultParams(), nil
+	}
+	return params, err
+}
+
+// SetParams sets the module parameters
+func (k Keeper) SetParams(ctx context.Context, params types.Params) error {
+	if err := params.Validate(); err != nil {
+		return types.ErrInvalidParams.Wrap(err.Error())
+	}
+	return k.params.Set(ctx, params)
+}
+
+// GetPrice returns the tallied price of a symbol. It implements the OracleKeeper
+// expected by x/timecapsule; prices older than MaxResultAge blocks are stale.
+func (k Keeper) GetPrice(ctx context.Context, symbol string) (math.LegacyDec, error) {
+	price, stale, err := k.GetPriceResult(ctx, symbol)
+	if err != nil {
+		return math.LegacyDec{}, err
+	}
+	if stale {
+		return math.LegacyDec{}, types.ErrStaleResult.Wrapf("price of %s was last updated at height %d", symbol, price.Height)
+	}
+	return price.Price, nil
+}
+
+// GetData returns the tallied value of a data key. It implements the OracleKeeper
+// expected by x/timecapsule; values older than MaxResultAge blocks are stale.
+func (k Keeper) GetData(ctx context.Context, key string) ([]byte, error) {
+	value, stale, err := k.GetDataResult(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if stale {
+		return nil, types.ErrStaleResult.Wrapf("value of %s was last updated at height %d", key, value.Height)
+	}
+	return value.Value, nil
+}
+
+// IsDataAvailable returns true if a data key has a fresh tallied value
+func (k Keeper) IsDataAvailable(ctx context.Context, key string) bool {
+	_, err := k.GetData(ctx, key)
+	return err == nil
+}
+
+// GetPriceResult returns the tallied price of a symbol and whether it is stale
+func (k Keeper) GetPriceResult(ctx context.Context, symbol string) (types.Price, bool, error) {
+	price, err := k.prices.Get(ctx, symbol)
+	if errors.Is(err, collections.ErrNotFound) {
+		return types.Price{}, false, types.ErrResultNotFound.Wrapf("no price for %s", symbol)
+	}
+	if err != nil {
+		return types.Price{}, false, err
+	}
+
+	stale, err := k.isStale(ctx, price.Height)
+	return price, stale, err
+}
+
+// GetDataResult returns the tallied value of a data key and whether it is stale
+func (k Keeper) GetDataResult(ctx context.Context, key string) (types.DataValue, bool, error) {
+	value, err := k.data.Get(ctx, key)
+	if errors.Is(err, collections.ErrNotFound) {
+		return types.DataValue{}, false, types.ErrResultNotFound.Wrapf("no value for %s", key)
+	}
+	if err != nil {
+		return types.DataValue{}, false, err
+	}
+
+	stale, err := k.isStale(ctx, value.Height)
+	return value, stale, err
+}
+
+// GetAllPrices returns all tallied prices
+func (k Keeper) GetAllPrices(ctx context.Context) ([]types.Price, error) {
+	var prices []types.Price
+	err := k.prices.Walk(ctx, nil, func(_ string, price types.Price) (bool, error) {
+		prices = append(prices, price)
+		return false, nil
+	})
+	return prices, err
+}
+
+// GetAllData returns all tallied data values
+func (k Keeper) GetAllData(ctx context.Context) ([]types.DataValue, error) {
+	var values []types.DataValue
+	err := k.data.Walk(ctx, nil, func(_ string, value types.DataValue) (bool, error) {
+		values = append(values, value)
+		return false, nil
+	})
+	return values, err
+}
+
+// GetAllPrevotes returns all pending prevotes
+func (k Keeper) GetAllPrevotes(ctx context.Context) ([]types.Prevote, error) {
+	var prevotes []types.Prevote
+	err := k.prevotes.Walk(ctx, nil, func(_ string, prevote types.Prevote) (bool, error) {
+		prevotes = append(prevotes, prevote)
+		return false, nil
+	})
+	return prevotes, err
+}
+
+// GetAllVotes returns all votes revealed in the current voting period
+func (k Keeper) GetAllVotes(ctx context.Context) ([]types.Vote, error) {
+	var votes []types.Vote
+	err := k.votes.Walk(ctx, nil, func(_ string, vote types.Vote) (bool, error) {
+		votes = append(votes, vote)
+		return false, nil
+	})
+	return votes, err
+}
+
+// SetPrice stores a tallied price
+func (k Keeper) SetPrice(ctx context.Context, price types.Price) error {
+	if err := types.ValidateSymbol(price.Symbol); err != nil {
+		return types.ErrUnknownSymbol.Wrap(err.Error())
+	}
+	return k.prices.Set(ctx, price.Symbol, price)
+}
+
+// SetData stores a tallied data value
+func (k Keeper) SetData(ctx context.Context, value types.DataValue) error {
+	if err := types.ValidateSymbol(value.Key); err != nil {
+		return types.ErrInvalidVote.Wrap(err.Error())
+	}
+	return k.data.Set(ctx, value.Key, value)
+}
+
+// SetPrevote stores the prevote of a validator
+func (k Keeper) SetPrevote(ctx context.Context, prevote types.Prevote) error {
+	return k.prevotes.Set(ctx, prevote.Validator, prevote)
+}
+
+// SetVote stores the revealed vote of a validator
+func (k Keeper) SetVote(ctx context.Context, vote types.Vote) error {
+	return k.votes.Set(ctx, vote.Validator, vote)
+}
+
+// isStale returns true if a result tallied at height is older than MaxResultAge blocks
+func (k Keeper) isStale(ctx context.Context, height int64) (bool, error) {
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	age := sdk.UnwrapSDKContext(ctx).BlockHeight() - height
+	return age > 0 && uint64(age) > params.MaxResultAge, nil
+}
+
+// votePeriod returns the voting period a height belongs to
+func votePeriod(height int64, params types.Params) int64 {
+	return height / int64(params.VotePeriod)
+}
+
+// EndBlocker tallies the votes of a voting period on its last block
+func (k Keeper) EndBlocker(ctx context.Context) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	if (sdkCtx.BlockHeight()+1)%int64(params.VotePeriod) != 0 {
+		return nil
+	}
+
+	if err := k.tally(ctx, params); err != nil {
+		return fmt.Errorf("failed to tally oracle votes: %w", err)
+	}
+
+	return k.pruneVotes(ctx, params)
+}
@@ -0,0 +1,105 @@
+package keeper_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/stretchr/testify/suite"
+
+	"cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	"github.com/cosmos/cosmos-sdk/runtime"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	moduletestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/cosmos/cosmos-sdk/x/oracle/keeper"
+	"github.com/cosmos/cosmos-sdk/x/oracle/types"
+)
+
+type KeeperTestSuite struct {
+	suite.Suite
+
+	ctx           sdk.Context
+	keeper        keeper.Keeper
+	msgServer     types.MsgServer
+	stakingKeeper *mockStakingKeeper
+}
+
+func TestKeeperTestSuite(t *testing.T) {
+	suite.Run(t, new(KeeperTestSuite))
+}
+
+func (s *KeeperTestSuite) SetupTest() {
+	key := storetypes.NewKVStoreKey(types.StoreKey)
+	testCtx := testutil.DefaultContextWithDB(s.T(), key, storetypes.NewTransientStoreKey("transient_test"))
+	s.ctx = testCtx.Ctx.WithBlockHeader(cmtproto.Header{Height: 1, Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)})
+
+	encCfg := moduletestutil.MakeTestEncodingConfig()
+	s.stakingKeeper = &mockStakingKeeper{validators: make(map[string]stakingtypes.Validator)}
+
+	s.keeper = keeper.NewKeeper(
+		encCfg.Codec,
+		runtime.NewKVStoreService(key),
+		s.ctx.Logger(),
+		s.stakingKeeper,
+		authtypes.NewModuleAddress(govtypes.ModuleName).String(),
+	)
+	s.msgServer = keeper.NewMsgServerImpl(s.keeper)
+
+	s.Require().NoError(s.keeper.SetParams(s.ctx, types.DefaultParams()))
+}
+
+// atHeight moves the context to a block height
+func (s *KeeperTestSuite) atHeight(height int64) {
+	header := s.ctx.BlockHeader()
+	header.Height = height
+	s.ctx = s.ctx.WithBlockHeader(header)
+}
+
+// mockStakingKeeper serves a fixed validator set
+type mockStakingKeeper struct {
+	validators map[string]stakingtypes.Validator
+}
+
+func (m *mockStakingKeeper) GetValidator(_ context.Context, addr sdk.ValAddress) (stakingtypes.Validator, error) {
+	val, ok := m.validators[addr.String()]
+	if !ok {
+		return stakingtypes.Validator{}, stakingtypes.ErrNoValidatorFound
+	}
+	return val, nil
+}
+
+func (m *mockStakingKeeper) TotalBondedTokens(_ context.Context) (math.Int, error) {
+	total := math.ZeroInt()
+	for _, val := range m.validators {
+		if val.IsBonded() {
+			total = total.Add(val.Tokens)
+		}
+	}
+	return total, nil
+}
+
+// addValidator adds a validator with its bonded tokens and returns its operator address
+func (m *mockStakingKeeper) addValidator(name string, tokens int64) sdk.ValAddress {
+	valAddr := sdk.ValAddress([]byte(name + "____________________")[:20])
+	m.validators[valAddr.String()] = stakingtypes.Validator{
+		OperatorAddress: valAddr.String(),
+		Status:          stakingtypes.Bonded,
+		Tokens:          math.NewInt(tokens),
+	}
+	return valAddr
+}
+
+// setStatus changes the bond status of a validator
+func (m *mockStakingKeeper) setStatus(valAddr sdk.ValAddress, status stakingtypes.BondStatus) {
+	val := m.validators[valAddr.String()]
+	val.Status = status
+	m.validators[valAddr.String()] = val
+}
@@ -0,0 +1,50 @@
+package keeper
+
+import (
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/x/oracle/types"
+)
+
+// MsgServer implements the module's message server
+type MsgServer struct {
+	keeper Keeper
+}
+
+// NewMsgServerImpl returns an implementation of the module's MsgServer interface
+func NewMsgServerImpl(keeper Keeper) types.MsgServer {
+	return &MsgServer{keeper: keeper}
+}
+
+var _ types.MsgServer = MsgServer{}
+
+// Prevote commits a validator to the hash of its next vote
+func (ms MsgServer) Prevote(ctx context.Context, msg *types.MsgPrevote) (*types.MsgPrevoteResponse, error) {
+	if err := ms.keeper.SubmitPrevote(ctx, msg.Validator, msg.Hash); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgPrevoteResponse{}, nil
+}
+
+// Vote reveals a vote committed to in the previous voting period
+func (ms MsgServer) Vote(ctx context.Context, msg *types.MsgVote) (*types.MsgVoteResponse, error) {
+	if err := ms.keeper.SubmitVote(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgVoteResponse{}, nil
+}
+
+// UpdateParams updates the module parameters
+func (ms MsgServer) UpdateParams(ctx context.Context, msg *types.MsgUpdateParams) (*types.MsgUpdateParamsResponse, error) {
+	if msg.Authority != ms.keeper.GetAuthority() {
+		return nil, types.ErrUnauthorized.Wrapf("expected %s, got %s", ms.keeper.GetAuthority(), msg.Authority)
+	}
+
+	if err := ms.keeper.SetParams(ctx, msg.Params); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgUpdateParamsResponse{}, nil
+}
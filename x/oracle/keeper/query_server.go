@@ -0,0 +1,88 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+
+	"cosmossdk.io/collections"
+
+	"github.com/cosmos/cosmos-sdk/x/oracle/types"
+)
+
+// QueryServer implements the module query server
+type QueryServer struct {
+	keeper Keeper
+}
+
+// NewQueryServerImpl returns an implementation of the module QueryServer interface
+func NewQueryServerImpl(keeper Keeper) types.QueryServer {
+	return &QueryServer{keeper: keeper}
+}
+
+var _ types.QueryServer = QueryServer{}
+
+// Params returns the module parameters
+func (qs QueryServer) Params(ctx context.Context, req *types.QueryParamsRequest) (*types.QueryParamsResponse, error) {
+	params, err := qs.keeper.GetParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryParamsResponse{Params: params}, nil
+}
+
+// Price returns the tallied price of a symbol
+func (qs QueryServer) Price(ctx context.Context, req *types.QueryPriceRequest) (*types.QueryPriceResponse, error) {
+	price, stale, err := qs.keeper.GetPriceResult(ctx, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryPriceResponse{Price: price, Stale: stale}, nil
+}
+
+// Prices returns all tallied prices
+func (qs QueryServer) Prices(ctx context.Context, req *types.QueryPricesRequest) (*types.QueryPricesResponse, error) {
+	prices, err := qs.keeper.GetAllPrices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryPricesResponse{Prices: prices}, nil
+}
+
+// Data returns the tallied value of a data key
+func (qs QueryServer) Data(ctx context.Context, req *types.QueryDataRequest) (*types.QueryDataResponse, error) {
+	value, stale, err := qs.keeper.GetDataResult(ctx, req.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryDataResponse{Data: value, Stale: stale}, nil
+}
+
+// Prevote returns the pending prevote of a validator
+func (qs QueryServer) Prevote(ctx context.Context, req *types.QueryPrevoteRequest) (*types.QueryPrevoteResponse, error) {
+	prevote, err := qs.keeper.prevotes.Get(ctx, req.Validator)
+	if errors.Is(err, collections.ErrNotFound) {
+		return nil, types.ErrNoPrevote.Wrapf("validator %s has no prevote", req.Validator)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryPrevoteResponse{Prevote: prevote}, nil
+}
+
+// Vote returns the revealed vote of a validator in the current voting period
+func (qs QueryServer) Vote(ctx context.Context, req *types.QueryVoteRequest) (*types.QueryVoteResponse, error) {
+	vote, err := qs.keeper.votes.Get(ctx, req.Validator)
+	if errors.Is(err, collections.ErrNotFound) {
+		return nil, types.ErrResultNotFound.Wrapf("validator %s has not voted in this period", req.Validator)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryVoteResponse{Vote: vote}, nil
+}
@@ -0,0 +1,323 @@
+package keeper
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/oracle/types"
+)
+
+// SubmitPrevote records the vote hash a validator reveals in the next voting period.
+// A new prevote in the same voting period replaces the previous one.
+func (k Keeper) SubmitPrevote(ctx context.Context, validator, hash string) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	if _, err := k.bondedPower(ctx, validator); err != nil {
+		return err
+	}
+
+	prevote := types.Prevote{
+		Hash:        hash,
+		Validator:   validator,
+		SubmitBlock: sdkCtx.BlockHeight(),
+	}
+	if err := k.prevotes.Set(ctx, validator, prevote); err != nil {
+		return fmt.Errorf("failed to store prevote: %w", err)
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypePrevote,
+			sdk.NewAttribute(types.AttributeKeyValidator, validator),
+			sdk.NewAttribute(types.AttributeKeyHash, hash),
+		),
+	)
+
+	return nil
+}
+
+// SubmitVote reveals the vote a validator prevoted in the previous voting period. The
+// vote is tallied at the end of the current voting period.
+func (k Keeper) SubmitVote(ctx context.Context, msg *types.MsgVote) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := k.bondedPower(ctx, msg.Validator); err != nil {
+		return err
+	}
+
+	prevote, err := k.prevotes.Get(ctx, msg.Validator)
+	if errors.Is(err, collections.ErrNotFound) {
+		return types.ErrNoPrevote.Wrapf("validator %s has no prevote", msg.Validator)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The vote is revealed in the voting period right after its prevote, so no validator
+	// can see the votes of others before committing to its own
+	period := votePeriod(sdkCtx.BlockHeight(), params)
+	if votePeriod(prevote.SubmitBlock, params) != period-1 {
+		return types.ErrRevealPeriod.Wrapf("prevote at height %d cannot be revealed in voting period %d", prevote.SubmitBlock, period)
+	}
+
+	if msg.Hash() != prevote.Hash {
+		return types.ErrHashMismatch.Wrapf("vote of %s does not match prevote %s", msg.Validator, prevote.Hash)
+	}
+
+	for _, price := range msg.Prices {
+		if !params.IsSymbol(price.Symbol) {
+			return types.ErrUnknownSymbol.Wrapf("%s is not voted on", price.Symbol)
+		}
+	}
+
+	if uint32(len(msg.Data)) > params.MaxDataKeys {
+		return types.ErrInvalidVote.Wrapf("vote has %d data keys, maximum is %d", len(msg.Data), params.MaxDataKeys)
+	}
+	for _, value := range msg.Data {
+		if uint32(len(value.Value)) > params.MaxDataSize {
+			return types.ErrInvalidVote.Wrapf("value of %s exceeds %d bytes", value.Key, params.MaxDataSize)
+		}
+	}
+
+	vote := types.Vote{
+		Validator:   msg.Validator,
+		Prices:      msg.Prices,
+		Data:        msg.Data,
+		SubmitBlock: sdkCtx.BlockHeight(),
+	}
+	if err := k.votes.Set(ctx, msg.Validator, vote); err != nil {
+		return fmt.Errorf("failed to store vote: %w", err)
+	}
+	if err := k.prevotes.Remove(ctx, msg.Validator); err != nil {
+		return err
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeVote,
+			sdk.NewAttribute(types.AttributeKeyValidator, msg.Validator),
+			sdk.NewAttribute(types.AttributeKeyFeeder, msg.Feeder),
+		),
+	)
+
+	return nil
+}
+
+// bondedPower returns the bonded tokens of a validator, which weigh its votes
+func (k Keeper) bondedPower(ctx context.Context, validator string) (math.Int, error) {
+	valAddr, err := sdk.ValAddressFromBech32(validator)
+	if err != nil {
+		return math.Int{}, types.ErrInvalidAddress.Wrapf("invalid validator address: %s", err)
+	}
+
+	val, err := k.stakingKeeper.GetValidator(ctx, valAddr)
+	if err != nil {
+		return math.Int{}, types.ErrValidatorNotFound.Wrapf("validator %s: %s", validator, err)
+	}
+	if !val.IsBonded() || val.IsJailed() {
+		return math.Int{}, types.ErrValidatorNotFound.Wrapf("validator %s is not bonded", validator)
+	}
+
+	return val.GetBondedTokens(), nil
+}
+
+// weightedPrice is a price voted by a validator with its voting power
+type weightedPrice struct {
+	price math.LegacyDec
+	power math.Int
+}
+
+// weightedValue is a data value with the voting power of its voters
+type weightedValue struct {
+	value []byte
+	power math.Int
+}
+
+// tally computes the weighted median price of each symbol and the weighted mode value
+// of each data key from the votes of the voting period. A result is only recorded when
+// its voters hold at least VoteThreshold of the bonded tokens.
+func (k Keeper) tally(ctx context.Context, params types.Params) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	totalBonded, err := k.stakingKeeper.TotalBondedTokens(ctx)
+	if err != nil {
+		return err
+	}
+	if !totalBonded.IsPositive() {
+		return nil
+	}
+	threshold := params.VoteThreshold.MulInt(totalBonded)
+
+	prices := make(map[string][]weightedPrice)
+	values := make(map[string]map[string]*weightedValue)
+
+	err = k.votes.Walk(ctx, nil, func(validator string, vote types.Vote) (bool, error) {
+		// Validators unbonded or jailed since revealing do not count
+		power, err := k.bondedPower(ctx, validator)
+		if err != nil || !power.IsPositive() {
+			return false, nil
+		}
+
+		for _, price := range vote.Prices {
+			prices[price.Symbol] = append(prices[price.Symbol], weightedPrice{price: price.Price, power: power})
+		}
+
+		for _, value := range vote.Data {
+			if values[value.Key] == nil {
+				values[value.Key] = make(map[string]*weightedValue)
+			}
+			id := hex.EncodeToString(value.Value)
+			if values[value.Key][id] == nil {
+				values[value.Key][id] = &weightedValue{value: value.Value, power: math.ZeroInt()}
+			}
+			values[value.Key][id].power = values[value.Key][id].power.Add(power)
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, symbol := range sortedKeys(prices) {
+		votes := prices[symbol]
+
+		power := math.ZeroInt()
+		for _, vote := range votes {
+			power = power.Add(vote.power)
+		}
+		if math.LegacyNewDecFromInt(power).LT(threshold) {
+			continue
+		}
+
+		price := types.Price{
+			Symbol:    symbol,
+			Price:     weightedMedian(votes),
+			Height:    sdkCtx.BlockHeight(),
+			UpdatedAt: sdkCtx.BlockTime(),
+			Voters:    uint32(len(votes)),
+		}
+		if err := k.prices.Set(ctx, symbol, price); err != nil {
+			return err
+		}
+
+		sdkCtx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypePriceUpdated,
+				sdk.NewAttribute(types.AttributeKeySymbol, symbol),
+				sdk.NewAttribute(types.AttributeKeyPrice, price.Price.String()),
+				sdk.NewAttribute(types.AttributeKeyPower, power.String()),
+			),
+		)
+	}
+
+	for _, key := range sortedKeys(values) {
+		// The value with the most voting power wins, ties go to the lowest value
+		var mode *weightedValue
+		for _, id := range sortedKeys(values[key]) {
+			candidate := values[key][id]
+			if mode == nil || candidate.power.GT(mode.power) {
+				mode = candidate
+			}
+		}
+		if math.LegacyNewDecFromInt(mode.power).LT(threshold) {
+			continue
+		}
+
+		value := types.DataValue{
+			Key:       key,
+			Value:     mode.value,
+			Height:    sdkCtx.BlockHeight(),
+			UpdatedAt: sdkCtx.BlockTime(),
+		}
+		if err := k.data.Set(ctx, key, value); err != nil {
+			return err
+		}
+
+		sdkCtx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeDataUpdated,
+				sdk.NewAttribute(types.AttributeKeyDataKey, key),
+				sdk.NewAttribute(types.AttributeKeyValueHash, types.ValueHash(mode.value)),
+				sdk.NewAttribute(types.AttributeKeyPower, mode.power.String()),
+			),
+		)
+	}
+
+	return nil
+}
+
+// pruneVotes clears the tallied votes and the prevotes that can no longer be revealed
+func (k Keeper) pruneVotes(ctx context.Context, params types.Params) error {
+	period := votePeriod(sdk.UnwrapSDKContext(ctx).BlockHeight(), params)
+
+	if err := k.votes.Clear(ctx, nil); err != nil {
+		return err
+	}
+
+	var expired []string
+	err := k.prevotes.Walk(ctx, nil, func(validator string, prevote types.Prevote) (bool, error) {
+		if votePeriod(prevote.SubmitBlock, params) < period {
+			expired = append(expired, validator)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, validator := range expired {
+		if err := k.prevotes.Remove(ctx, validator); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// weightedMedian returns the price at which half of the voting power voted lower or equal
+func weightedMedian(votes []weightedPrice) math.LegacyDec {
+	sorted := make([]weightedPrice, len(votes))
+	copy(sorted, votes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].price.LT(sorted[j].price)
+	})
+
+	total := math.ZeroInt()
+	for _, vote := range sorted {
+		total = total.Add(vote.power)
+	}
+
+	cumulative := math.ZeroInt()
+	for _, vote := range sorted {
+		cumulative = cumulative.Add(vote.power)
+		if cumulative.MulRaw(2).GTE(total) {
+			return vote.price
+		}
+	}
+
+	return sorted[len(sorted)-1].price
+}
+
+// sortedKeys returns the keys of a map in order, so tallies are deterministic
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
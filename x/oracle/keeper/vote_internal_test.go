@@ -0,0 +1,62 @@
+package keeper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+)
+
+func TestWeightedMedian(t *testing.T) {
+	vote := func(price string, power int64) weightedPrice {
+		return weightedPrice{price: math.LegacyMustNewDecFromStr(price), power: math.NewInt(power)}
+	}
+
+	testCases := []struct {
+		name     string
+		votes    []weightedPrice
+		expected string
+	}{
+		{
+			name:     "single voter",
+			votes:    []weightedPrice{vote("42", 7)},
+			expected: "42",
+		},
+		{
+			name:     "equal weights take the lower middle price",
+			votes:    []weightedPrice{vote("3", 1), vote("1", 1), vote("4", 1), vote("2", 1)},
+			expected: "2",
+		},
+		{
+			name:     "odd number of equal weights",
+			votes:    []weightedPrice{vote("30", 1), vote("10", 1), vote("20", 1)},
+			expected: "20",
+		},
+		{
+			name:     "heavy voter outweighs the others",
+			votes:    []weightedPrice{vote("1", 10), vote("2", 10), vote("100", 30)},
+			expected: "100",
+		},
+		{
+			name:     "cumulative power reaching exactly half",
+			votes:    []weightedPrice{vote("5", 50), vote("6", 25), vote("7", 25)},
+			expected: "5",
+		},
+		{
+			name:     "tied prices",
+			votes:    []weightedPrice{vote("8", 1), vote("8", 3), vote("9", 3)},
+			expected: "8",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			votes := append([]weightedPrice(nil), tc.votes...)
+			require.Equal(t, math.LegacyMustNewDecFromStr(tc.expected), weightedMedian(tc.votes))
+
+			// The votes of the caller are left in place
+			require.Equal(t, votes, tc.votes)
+		})
+	}
+}
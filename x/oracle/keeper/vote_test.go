@@ -0,0 +1,178 @@
+package keeper_test
+
+import (
+	"cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/cosmos/cosmos-sdk/x/oracle/types"
+)
+
+const salt = "0123456789abcdef"
+
+// prevote commits a validator to a vote in the voting period of the current height
+func (s *KeeperTestSuite) prevote(valAddr sdk.ValAddress, prices []types.PriceVote, data []types.DataVote) {
+	hash := types.VoteHash(salt, prices, data, valAddr.String())
+	_, err := s.msgServer.Prevote(s.ctx, types.NewMsgPrevote(sdk.AccAddress(valAddr).String(), valAddr.String(), hash))
+	s.Require().NoError(err)
+}
+
+// vote reveals the vote of a validator
+func (s *KeeperTestSuite) vote(valAddr sdk.ValAddress, prices []types.PriceVote, data []types.DataVote) error {
+	_, err := s.msgServer.Vote(s.ctx, types.NewMsgVote(sdk.AccAddress(valAddr).String(), valAddr.String(), salt, prices, data))
+	return err
+}
+
+func price(symbol, amount string) []types.PriceVote {
+	return []types.PriceVote{{Symbol: symbol, Price: math.LegacyMustNewDecFromStr(amount)}}
+}
+
+func (s *KeeperTestSuite) TestVoteAggregation() {
+	// With the default vote period of 5, prevotes of period 0 are revealed in period 1
+	// and tallied at its last block
+	validators := []struct {
+		tokens int64
+		price  string
+		value  string
+	}{
+		{tokens: 10, price: "9.5", value: "a"},
+		{tokens: 40, price: "10.5", value: "b"},
+		{tokens: 20, price: "11", value: "b"},
+		{tokens: 30, price: "12", value: "a"},
+	}
+
+	s.atHeight(3)
+	var addrs []sdk.ValAddress
+	for i, v := range validators {
+		valAddr := s.stakingKeeper.addValidator(string(rune('a'+i)), v.tokens)
+		addrs = append(addrs, valAddr)
+		s.prevote(valAddr, price("ATOM", v.price), []types.DataVote{{Key: "WEATHER", Value: []byte(v.value)}})
+	}
+
+	s.atHeight(6)
+	for i, v := range validators {
+		s.Require().NoError(s.vote(addrs[i], price("ATOM", v.price), []types.DataVote{{Key: "WEATHER", Value: []byte(v.value)}}))
+	}
+
+	// Nothing is tallied before the last block of the voting period
+	s.Require().NoError(s.keeper.EndBlocker(s.ctx))
+	_, err := s.keeper.GetPrice(s.ctx, "ATOM")
+	s.Require().ErrorIs(err, types.ErrResultNotFound)
+
+	s.atHeight(9)
+	s.Require().NoError(s.keeper.EndBlocker(s.ctx))
+
+	// Half of the 100 tokens voted at most 10.5
+	result, stale, err := s.keeper.GetPriceResult(s.ctx, "ATOM")
+	s.Require().NoError(err)
+	s.Require().False(stale)
+	s.Require().Equal(math.LegacyMustNewDecFromStr("10.5"), result.Price)
+	s.Require().Equal(uint32(4), result.Voters)
+	s.Require().Equal(int64(9), result.Height)
+
+	// "b" has 60 tokens behind it against 40 for "a"
+	value, err := s.keeper.GetData(s.ctx, "WEATHER")
+	s.Require().NoError(err)
+	s.Require().Equal([]byte("b"), value)
+
+	// Votes are cleared once tallied
+	votes, err := s.keeper.GetAllVotes(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Empty(votes)
+
+	// The result goes stale after MaxResultAge blocks
+	s.atHeight(9 + int64(types.DefaultMaxResultAge) + 1)
+	_, err = s.keeper.GetPrice(s.ctx, "ATOM")
+	s.Require().ErrorIs(err, types.ErrStaleResult)
+}
+
+func (s *KeeperTestSuite) TestMissedVotes() {
+	s.atHeight(3)
+	voter := s.stakingKeeper.addValidator("voter", 40)
+	absent := s.stakingKeeper.addValidator("absent", 35)
+	late := s.stakingKeeper.addValidator("late", 25)
+
+	s.prevote(voter, price("BTC", "100"), nil)
+	s.prevote(absent, price("BTC", "200"), nil)
+
+	// A prevote must be revealed in the next voting period, not the same one
+	s.Require().ErrorIs(s.vote(voter, price("BTC", "100"), nil), types.ErrRevealPeriod)
+
+	s.atHeight(6)
+	s.Require().NoError(s.vote(voter, price("BTC", "100"), nil))
+
+	// A validator without a prevote cannot vote
+	s.Require().ErrorIs(s.vote(late, price("BTC", "300"), nil), types.ErrNoPrevote)
+
+	// Neither can a vote differing from its prevote
+	s.Require().ErrorIs(s.vote(absent, price("BTC", "250"), nil), types.ErrHashMismatch)
+
+	// Only 40 of the 100 bonded tokens voted, below the threshold of a half
+	s.atHeight(9)
+	s.Require().NoError(s.keeper.EndBlocker(s.ctx))
+	_, err := s.keeper.GetPrice(s.ctx, "BTC")
+	s.Require().ErrorIs(err, types.ErrResultNotFound)
+
+	// The unrevealed prevote can no longer be revealed and is pruned
+	prevotes, err := s.keeper.GetAllPrevotes(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Empty(prevotes)
+
+	s.atHeight(11)
+	s.Require().ErrorIs(s.vote(absent, price("BTC", "200"), nil), types.ErrNoPrevote)
+}
+
+func (s *KeeperTestSuite) TestVotesOfUnbondedValidatorsAreNotTallied() {
+	s.atHeight(3)
+	stays := s.stakingKeeper.addValidator("stays", 45)
+	leaves := s.stakingKeeper.addValidator("leaves", 55)
+
+	s.prevote(stays, price("ETH", "3000"), nil)
+	s.prevote(leaves, price("ETH", "5000"), nil)
+
+	s.atHeight(6)
+	s.Require().NoError(s.vote(stays, price("ETH", "3000"), nil))
+	s.Require().NoError(s.vote(leaves, price("ETH", "5000"), nil))
+
+	// Unbonding after revealing drops the vote
+	s.stakingKeeper.setStatus(leaves, stakingtypes.Unbonding)
+	s.Require().ErrorIs(s.vote(leaves, price("ETH", "5000"), nil), types.ErrValidatorNotFound)
+
+	s.atHeight(9)
+	s.Require().NoError(s.keeper.EndBlocker(s.ctx))
+
+	// Only the bonded validator remains, holding all of the bonded tokens
+	result, _, err := s.keeper.GetPriceResult(s.ctx, "ETH")
+	s.Require().NoError(err)
+	s.Require().Equal(math.LegacyMustNewDecFromStr("3000"), result.Price)
+	s.Require().Equal(uint32(1), result.Voters)
+}
+
+func (s *KeeperTestSuite) TestVoteRejections() {
+	s.atHeight(3)
+	valAddr := s.stakingKeeper.addValidator("val", 100)
+
+	// Only bonded validators vote
+	unknown := sdk.ValAddress([]byte("unknown_____________"))
+	_, err := s.msgServer.Prevote(s.ctx, types.NewMsgPrevote(sdk.AccAddress(unknown).String(), unknown.String(), types.VoteHash(salt, price("ATOM", "1"), nil, unknown.String())))
+	s.Require().ErrorIs(err, types.ErrValidatorNotFound)
+
+	s.prevote(valAddr, price("DOGE", "1"), nil)
+	s.atHeight(6)
+	s.Require().ErrorIs(s.vote(valAddr, price("DOGE", "1"), nil), types.ErrUnknownSymbol)
+
+	// Data values are bounded by the params
+	s.atHeight(8)
+	large := []types.DataVote{{Key: "BLOB", Value: make([]byte, types.DefaultMaxDataSize+1)}}
+	s.prevote(valAddr, nil, large)
+	s.atHeight(11)
+	s.Require().ErrorIs(s.vote(valAddr, nil, large), types.ErrInvalidVote)
+
+	// Params are only updated by the authority
+	_, err = s.msgServer.UpdateParams(s.ctx, &types.MsgUpdateParams{
+		Authority: sdk.AccAddress(valAddr).String(),
+		Params:    types.DefaultParams(),
+	})
+	s.Require().ErrorIs(err, types.ErrUnauthorized)
+}
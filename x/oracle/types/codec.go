@@ -0,0 +1,40 @@
+package types
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	cdctypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/msgservice"
+)
+
+// RegisterCodec registers the necessary x/oracle interfaces and concrete types
+// on the provided Amino codec. These types are used for Amino JSON serialization.
+func RegisterCodec(cdc *codec.LegacyAmino) {
+	cdc.RegisterConcrete(&MsgPrevote{}, "oracle/MsgPrevote", nil)
+	cdc.RegisterConcrete(&MsgVote{}, "oracle/MsgVote", nil)
+	cdc.RegisterConcrete(&MsgUpdateParams{}, "oracle/MsgUpdateParams", nil)
+}
+
+// RegisterInterfaces registers the x/oracle interfaces types with the
+// interface registry.
+func RegisterInterfaces(registry cdctypes.InterfaceRegistry) {
+	registry.RegisterImplementations((*sdk.Msg)(nil),
+		&MsgPrevote{},
+		&MsgVote{},
+		&MsgUpdateParams{},
+	)
+
+	msgservice.RegisterMsgServiceDesc(registry, &_Msg_serviceDesc)
+}
+
+var (
+	// Amino is the legacy amino codec
+	Amino = codec.NewLegacyAmino()
+	// ModuleCdc is the module codec
+	ModuleCdc = codec.NewProtoCodec(cdctypes.NewInterfaceRegistry())
+)
+
+func init() {
+	RegisterCodec(Amino)
+	Amino.Seal()
+}
@@ -0,0 +1,20 @@
+package types
+
+import (
+	"cosmossdk.io/errors"
+)
+
+// x/oracle module sentinel errors
+var (
+	ErrInvalidVote       = errors.Register(ModuleName, 2, "invalid oracle vote")
+	ErrNoPrevote         = errors.Register(ModuleName, 3, "no prevote found")
+	ErrRevealPeriod      = errors.Register(ModuleName, 4, "vote revealed in the wrong voting period")
+	ErrHashMismatch      = errors.Register(ModuleName, 5, "vote does not match prevote hash")
+	ErrUnknownSymbol     = errors.Register(ModuleName, 6, "symbol is not voted on")
+	ErrValidatorNotFound = errors.Register(ModuleName, 7, "validator not found or not bonded")
+	ErrUnauthorized      = errors.Register(ModuleName, 8, "unauthorized")
+	ErrResultNotFound    = errors.Register(ModuleName, 9, "oracle result not found")
+	ErrStaleResult       = errors.Register(ModuleName, 10, "oracle result is stale")
+	ErrInvalidParams     = errors.Register(ModuleName, 11, "invalid oracle params")
+	ErrInvalidAddress    = errors.Register(ModuleName, 12, "invalid address")
+)
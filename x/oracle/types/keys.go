@@ -0,0 +1,54 @@
+package types
+
+import (
+	"cosmossdk.io/collections"
+)
+
+const (
+	// ModuleName defines the module name
+	ModuleName = "oracle"
+
+	// StoreKey defines the primary module store key
+	StoreKey = ModuleName
+
+	// RouterKey defines the module's message routing key
+	RouterKey = ModuleName
+)
+
+// KVStore keys
+var (
+	// ParamsKey is the key for the module parameters
+	ParamsKey = collections.NewPrefix(0)
+
+	// PrevotesKeyPrefix is the prefix for vote commitments, by validator
+	PrevotesKeyPrefix = collections.NewPrefix(1)
+
+	// VotesKeyPrefix is the prefix for revealed votes of the current voting period, by validator
+	VotesKeyPrefix = collections.NewPrefix(2)
+
+	// PricesKeyPrefix is the prefix for the tallied prices, by symbol
+	PricesKeyPrefix = collections.NewPrefix(3)
+
+	// DataKeyPrefix is the prefix for the tallied keyed data, by key
+	DataKeyPrefix = collections.NewPrefix(4)
+)
+
+// Event types
+const (
+	EventTypePrevote      = "oracle_prevote"
+	EventTypeVote         = "oracle_vote"
+	EventTypePriceUpdated = "oracle_price_updated"
+	EventTypeDataUpdated  = "oracle_data_updated"
+)
+
+// Event attributes
+const (
+	AttributeKeyValidator = "validator"
+	AttributeKeyFeeder    = "feeder"
+	AttributeKeyHash      = "hash"
+	AttributeKeySymbol    = "symbol"
+	AttributeKeyPrice     = "price"
+	AttributeKeyDataKey   = "key"
+	AttributeKeyValueHash = "value_hash"
+	AttributeKeyPower     = "power"
+)
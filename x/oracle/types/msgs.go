@@ -0,0 +1,179 @@
+package types
+
+import (
+	"encoding/hex"
+
+	"cosmossdk.io/errors"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Message types for oracle operations
+const (
+	TypeMsgPrevote      = "prevote"
+	TypeMsgVote         = "vote"
+	TypeMsgUpdateParams = "update_params"
+)
+
+// NewMsgPrevote creates a new MsgPrevote
+func NewMsgPrevote(feeder, validator, hash string) *MsgPrevote {
+	return &MsgPrevote{
+		Feeder:    feeder,
+		Validator: validator,
+		Hash:      hash,
+	}
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgPrevote) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgPrevote) Type() string {
+	return TypeMsgPrevote
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgPrevote) GetSigners() []sdk.AccAddress {
+	feeder, err := sdk.AccAddressFromBech32(msg.Feeder)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{feeder}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgPrevote) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgPrevote) ValidateBasic() error {
+	if err := validateFeeder(msg.Feeder, msg.Validator); err != nil {
+		return err
+	}
+
+	hash, err := hex.DecodeString(msg.Hash)
+	if err != nil || len(hash) != 32 {
+		return errors.Wrap(ErrInvalidVote, "prevote hash must be a hex SHA-256 hash")
+	}
+
+	return nil
+}
+
+// NewMsgVote creates a new MsgVote
+func NewMsgVote(feeder, validator, salt string, prices []PriceVote, data []DataVote) *MsgVote {
+	return &MsgVote{
+		Feeder:    feeder,
+		Validator: validator,
+		Salt:      salt,
+		Prices:    prices,
+		Data:      data,
+	}
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgVote) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgVote) Type() string {
+	return TypeMsgVote
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgVote) GetSigners() []sdk.AccAddress {
+	feeder, err := sdk.AccAddressFromBech32(msg.Feeder)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{feeder}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgVote) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgVote) ValidateBasic() error {
+	if err := validateFeeder(msg.Feeder, msg.Validator); err != nil {
+		return err
+	}
+
+	// A short salt lets the vote be brute-forced from its prevote hash
+	if len(msg.Salt) < 8 || len(msg.Salt) > 64 {
+		return errors.Wrap(ErrInvalidVote, "salt must be between 8 and 64 characters")
+	}
+
+	if err := ValidateVote(msg.Prices, msg.Data); err != nil {
+		return errors.Wrap(ErrInvalidVote, err.Error())
+	}
+
+	return nil
+}
+
+// Hash returns the prevote hash the vote reveals
+func (msg *MsgVote) Hash() string {
+	return VoteHash(msg.Salt, msg.Prices, msg.Data, msg.Validator)
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgUpdateParams) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgUpdateParams) Type() string {
+	return TypeMsgUpdateParams
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgUpdateParams) GetSigners() []sdk.AccAddress {
+	authority, err := sdk.AccAddressFromBech32(msg.Authority)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{authority}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgUpdateParams) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgUpdateParams) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Authority); err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid authority address (%s)", err)
+	}
+
+	if err := msg.Params.Validate(); err != nil {
+		return errors.Wrap(ErrInvalidParams, err.Error())
+	}
+
+	return nil
+}
+
+// validateFeeder checks that a vote is fed by the operator account of its validator
+func validateFeeder(feeder, validator string) error {
+	feederAddr, err := sdk.AccAddressFromBech32(feeder)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid feeder address (%s)", err)
+	}
+
+	valAddr, err := sdk.ValAddressFromBech32(validator)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid validator address (%s)", err)
+	}
+
+	if !feederAddr.Equals(sdk.AccAddress(valAddr)) {
+		return errors.Wrap(ErrUnauthorized, "feeder must be the validator operator account")
+	}
+
+	return nil
+}
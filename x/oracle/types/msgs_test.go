@@ -0,0 +1,87 @@
+package types_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/oracle/types"
+)
+
+func TestMsgVoteValidateBasic(t *testing.T) {
+	valAddr := sdk.ValAddress([]byte("validator___________"))
+	operator := sdk.AccAddress(valAddr).String()
+	other := sdk.AccAddress([]byte("other_______________")).String()
+	prices := []types.PriceVote{{Symbol: "ATOM", Price: math.LegacyNewDec(10)}}
+
+	testCases := []struct {
+		name   string
+		msg    *types.MsgVote
+		expErr error
+	}{
+		{
+			name: "fed by the operator account",
+			msg:  types.NewMsgVote(operator, valAddr.String(), "0123456789", prices, nil),
+		},
+		{
+			name:   "fed by another account",
+			msg:    types.NewMsgVote(other, valAddr.String(), "0123456789", prices, nil),
+			expErr: types.ErrUnauthorized,
+		},
+		{
+			name:   "invalid feeder",
+			msg:    types.NewMsgVote("cosmos1invalid", valAddr.String(), "0123456789", prices, nil),
+			expErr: types.ErrInvalidAddress,
+		},
+		{
+			name:   "account address as validator",
+			msg:    types.NewMsgVote(operator, operator, "0123456789", prices, nil),
+			expErr: types.ErrInvalidAddress,
+		},
+		{
+			name:   "short salt",
+			msg:    types.NewMsgVote(operator, valAddr.String(), "0123", prices, nil),
+			expErr: types.ErrInvalidVote,
+		},
+		{
+			name:   "long salt",
+			msg:    types.NewMsgVote(operator, valAddr.String(), strings.Repeat("s", 65), prices, nil),
+			expErr: types.ErrInvalidVote,
+		},
+		{
+			name:   "empty vote",
+			msg:    types.NewMsgVote(operator, valAddr.String(), "0123456789", nil, nil),
+			expErr: types.ErrInvalidVote,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.msg.ValidateBasic()
+			if tc.expErr != nil {
+				require.ErrorIs(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestMsgPrevoteValidateBasic(t *testing.T) {
+	valAddr := sdk.ValAddress([]byte("validator___________"))
+	operator := sdk.AccAddress(valAddr).String()
+	hash := types.VoteHash("0123456789", []types.PriceVote{{Symbol: "ATOM", Price: math.LegacyNewDec(10)}}, nil, valAddr.String())
+
+	require.NoError(t, types.NewMsgPrevote(operator, valAddr.String(), hash).ValidateBasic())
+
+	err := types.NewMsgPrevote(sdk.AccAddress([]byte("other_______________")).String(), valAddr.String(), hash).ValidateBasic()
+	require.ErrorIs(t, err, types.ErrUnauthorized)
+
+	err = types.NewMsgPrevote(operator, valAddr.String(), hash[:10]).ValidateBasic()
+	require.ErrorIs(t, err, types.ErrInvalidVote)
+}
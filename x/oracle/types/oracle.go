@@ -0,0 +1,122 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cosmossdk.io/math"
+)
+
+// MaxSymbolLength bounds the length of a price symbol or data key
+const MaxSymbolLength = 64
+
+// ValidateSymbol validates a price symbol or data key. Both are part of the vote hash,
+// so they cannot contain its separators.
+func ValidateSymbol(symbol string) error {
+	if symbol == "" {
+		return fmt.Errorf("symbol cannot be empty")
+	}
+	if len(symbol) > MaxSymbolLength {
+		return fmt.Errorf("symbol %s exceeds %d characters", symbol, MaxSymbolLength)
+	}
+	if strings.ContainsAny(symbol, "=,: \t\n") {
+		return fmt.Errorf("symbol %s cannot contain '=', ',', ':' or whitespace", symbol)
+	}
+	return nil
+}
+
+// ValidateVote validates the prices and data of a vote
+func ValidateVote(prices []PriceVote, data []DataVote) error {
+	if len(prices) == 0 && len(data) == 0 {
+		return fmt.Errorf("vote must contain at least one price or data value")
+	}
+
+	symbols := make(map[string]bool)
+	for _, price := range prices {
+		if err := ValidateSymbol(price.Symbol); err != nil {
+			return err
+		}
+		if symbols[price.Symbol] {
+			return fmt.Errorf("duplicate price for %s", price.Symbol)
+		}
+		symbols[price.Symbol] = true
+
+		if price.Price.IsNil() || !price.Price.IsPositive() {
+			return fmt.Errorf("price for %s must be positive", price.Symbol)
+		}
+	}
+
+	keys := make(map[string]bool)
+	for _, value := range data {
+		if err := ValidateSymbol(value.Key); err != nil {
+			return err
+		}
+		if keys[value.Key] {
+			return fmt.Errorf("duplicate value for %s", value.Key)
+		}
+		keys[value.Key] = true
+
+		if len(value.Value) == 0 {
+			return fmt.Errorf("value for %s cannot be empty", value.Key)
+		}
+	}
+
+	return nil
+}
+
+// VoteHash returns the commitment a validator prevotes for a vote: the hex SHA-256 of
+// "salt:SYM=price,...:key=hexvalue,...:validator" with prices and data sorted
+func VoteHash(salt string, prices []PriceVote, data []DataVote, validator string) string {
+	priceStrs := make([]string, len(prices))
+	for i, price := range prices {
+		priceStrs[i] = fmt.Sprintf("%s=%s", price.Symbol, price.Price.String())
+	}
+	sort.Strings(priceStrs)
+
+	dataStrs := make([]string, len(data))
+	for i, value := range data {
+		dataStrs[i] = fmt.Sprintf("%s=%s", value.Key, hex.EncodeToString(value.Value))
+	}
+	sort.Strings(dataStrs)
+
+	payload := strings.Join([]string{
+		salt,
+		strings.Join(priceStrs, ","),
+		strings.Join(dataStrs, ","),
+		validator,
+	}, ":")
+
+	hash := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(hash[:])
+}
+
+// ParsePrices parses prices formatted as "ATOM=8.42,BTC=64000"
+func ParsePrices(s string) ([]PriceVote, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var prices []PriceVote
+	for _, entry := range strings.Split(s, ",") {
+		symbol, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid price %q, expected SYMBOL=price", entry)
+		}
+		price, err := math.LegacyNewDecFromStr(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price for %s: %w", symbol, err)
+		}
+		prices = append(prices, PriceVote{Symbol: symbol, Price: price})
+	}
+
+	return prices, nil
+}
+
+// ValueHash returns the hex SHA-256 of a data value, as emitted in events
+func ValueHash(value []byte) string {
+	hash := sha256.Sum256(value)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"fmt"
+
+	"cosmossdk.io/math"
+)
+
+// Default parameter values
+const (
+	DefaultVotePeriod   = uint64(5)   // Blocks per voting period
+	DefaultMaxDataKeys  = uint32(16)  // Data keys a validator can vote on per period
+	DefaultMaxDataSize  = uint32(256) // Bytes of a data value
+	DefaultMaxResultAge = uint64(100) // Blocks a tallied result stays fresh
+)
+
+var (
+	// DefaultVoteThreshold is the share of bonded power that must agree on a result
+	DefaultVoteThreshold = math.LegacyNewDecWithPrec(50, 2)
+
+	// DefaultSymbols are the price symbols validators vote on, quoted in USD
+	DefaultSymbols = []string{"ATOM", "BTC", "ETH"}
+)
+
+// NewParams creates a new Params object
+func NewParams(
+	votePeriod uint64,
+	voteThreshold math.LegacyDec,
+	symbols []string,
+	maxDataKeys uint32,
+	maxDataSize uint32,
+	maxResultAge uint64,
+) Params {
+	return Params{
+		VotePeriod:    votePeriod,
+		VoteThreshold: voteThreshold,
+		Symbols:       symbols,
+		MaxDataKeys:   maxDataKeys,
+		MaxDataSize:   maxDataSize,
+		MaxResultAge:  maxResultAge,
+	}
+}
+
+// DefaultParams returns the default parameters for the oracle module
+func DefaultParams() Params {
+	return NewParams(
+		DefaultVotePeriod,
+		DefaultVoteThreshold,
+		DefaultSymbols,
+		DefaultMaxDataKeys,
+		DefaultMaxDataSize,
+		DefaultMaxResultAge,
+	)
+}
+
+// Validate validates the set of params
+func (p Params) Validate() error {
+	if p.VotePeriod == 0 {
+		return fmt.Errorf("vote period must be positive")
+	}
+
+	if p.VoteThreshold.IsNil() || !p.VoteThreshold.GT(math.LegacyNewDecWithPrec(33, 2)) || p.VoteThreshold.GT(math.LegacyOneDec()) {
+		return fmt.Errorf("vote threshold must be above 0.33 and at most 1, got %s", p.VoteThreshold)
+	}
+
+	seen := make(map[string]bool)
+	for _, symbol := range p.Symbols {
+		if err := ValidateSymbol(symbol); err != nil {
+			return err
+		}
+		if seen[symbol] {
+			return fmt.Errorf("duplicate symbol %s", symbol)
+		}
+		seen[symbol] = true
+	}
+
+	if p.MaxDataSize == 0 && p.MaxDataKeys > 0 {
+		return fmt.Errorf("max data size must be positive when data keys are allowed")
+	}
+
+	// Results must survive at least until the next tally
+	if p.MaxResultAge < p.VotePeriod {
+		return fmt.Errorf("max result age (%d) cannot be shorter than the vote period (%d)", p.MaxResultAge, p.VotePeriod)
+	}
+
+	return nil
+}
+
+// IsSymbol returns true if validators vote on the price of symbol
+func (p Params) IsSymbol(symbol string) bool {
+	for _, s := range p.Symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
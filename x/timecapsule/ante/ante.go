@@ -0,0 +1,147 @@
+package ante
+
+import (
+	"sort"
+
+	"cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// maxNestedMsgDepth bounds how deep messages wrapped in other messages, e.g. by
+// authz MsgExec, are inspected
+const maxNestedMsgDepth = 4
+
+// nestedMsgs is implemented by messages that execute other messages, like authz MsgExec
+type nestedMsgs interface {
+	GetMessages() ([]sdk.Msg, error)
+}
+
+// CapsuleSpamDecorator enforces the anti-spam limits of capsule transactions: the
+// capsule payload bytes per transaction, the creations per account and quota window,
+// and a creation fee that rises with module-wide creation volume. The creation fee
+// is charged by the message server; the decorator rejects payers who cannot pay it
+// before the transaction is executed. The payer of a sponsored capsule is its sponsor,
+// while the creation still counts against the quota of its creator.
+//
+// CONTRACT: the decorator must run after the fee is deducted, so the balance left for
+// the creation fee is checked.
+type CapsuleSpamDecorator struct {
+	timeCapsuleKeeper TimeCapsuleKeeper
+	bankKeeper        BankKeeper
+}
+
+// NewCapsuleSpamDecorator returns a new CapsuleSpamDecorator
+func NewCapsuleSpamDecorator(tck TimeCapsuleKeeper, bk BankKeeper) CapsuleSpamDecorator {
+	return CapsuleSpamDecorator{
+		timeCapsuleKeeper: tck,
+		bankKeeper:        bk,
+	}
+}
+
+var _ sdk.AnteDecorator = CapsuleSpamDecorator{}
+
+// AnteHandle implements the AnteDecorator.AnteHandle method
+func (d CapsuleSpamDecorator) AnteHandle(ctx sdk.Context, tx sdk.Tx, simulate bool, next sdk.AnteHandler) (sdk.Context, error) {
+	creations := make(map[string]uint32)
+	payments := make(map[string]uint32)
+	var payload uint64
+	if err := collectCapsuleMsgs(tx.GetMsgs(), 0, creations, payments, &payload); err != nil {
+		return ctx, err
+	}
+	if len(creations) == 0 && payload == 0 {
+		return next(ctx, tx, simulate)
+	}
+
+	params, err := d.timeCapsuleKeeper.GetParams(ctx)
+	if err != nil {
+		return ctx, err
+	}
+
+	if payload > params.MaxTxPayloadBytes {
+		return ctx, types.ErrTxPayloadTooLarge.Wrapf("%d bytes, maximum is %d", payload, params.MaxTxPayloadBytes)
+	}
+
+	if len(creations) == 0 {
+		return next(ctx, tx, simulate)
+	}
+
+	fee, err := d.timeCapsuleKeeper.CreationFee(ctx)
+	if err != nil {
+		return ctx, err
+	}
+
+	if !fee.IsZero() {
+		for _, payer := range sortedKeys(payments) {
+			count := payments[payer]
+
+			addr, err := sdk.AccAddressFromBech32(payer)
+			if err != nil {
+				return ctx, types.ErrInvalidAddress.Wrapf("invalid payer address: %s", err)
+			}
+
+			required := fee.MulInt(math.NewInt(int64(count)))
+			spendable := d.bankKeeper.SpendableCoins(ctx, addr)
+			if !spendable.IsAllGTE(required) {
+				return ctx, types.ErrInsufficientCreationFee.Wrapf("%s needs %s for %d capsules, has %s", payer, required, count, spendable)
+			}
+		}
+	}
+
+	for _, creator := range sortedKeys(creations) {
+		if err := d.timeCapsuleKeeper.ConsumeCreationQuota(ctx, creator, creations[creator]); err != nil {
+			return ctx, err
+		}
+	}
+
+	return next(ctx, tx, simulate)
+}
+
+// sortedKeys returns the accounts of counts in a deterministic order
+func sortedKeys(counts map[string]uint32) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// collectCapsuleMsgs counts the capsule creations per creator and per fee payer, and
+// the capsule payload bytes of msgs, including messages nested in other messages
+func collectCapsuleMsgs(msgs []sdk.Msg, depth int, creations, payments map[string]uint32, payload *uint64) error {
+	for _, msg := range msgs {
+		switch msg := msg.(type) {
+		case *types.MsgCreateCapsule:
+			creations[msg.Creator]++
+			if msg.Sponsor != "" {
+				payments[msg.Sponsor]++
+			} else {
+				payments[msg.Creator]++
+			}
+			*payload += uint64(len(msg.Data))
+
+		case *types.MsgAmendCapsule:
+			*payload += uint64(len(msg.Data))
+
+		case *types.MsgRevealCapsule:
+			*payload += uint64(len(msg.Data))
+
+		case nestedMsgs:
+			if depth >= maxNestedMsgDepth {
+				return types.ErrInvalidRequest.Wrapf("messages nested deeper than %d", maxNestedMsgDepth)
+			}
+			inner, err := msg.GetMessages()
+			if err != nil {
+				return err
+			}
+			if err := collectCapsuleMsgs(inner, depth+1, creations, payments, payload); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,382 @@
+package ante_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/math"
+	storetypes "cosmossdk.io/store/types"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/runtime"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	clitestutil "github.com/cosmos/cosmos-sdk/testutil/cli"
+	"github.com/cosmos/cosmos-sdk/testutil/testdata"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	moduletestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	authante "github.com/cosmos/cosmos-sdk/x/auth/ante"
+	authcodec "github.com/cosmos/cosmos-sdk/x/auth/codec"
+	authkeeper "github.com/cosmos/cosmos-sdk/x/auth/keeper"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/ante"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/keeper"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// txFee is the fee of the test transactions, deducted before the creation fee is checked
+const txFee = 100
+
+// mockBankKeeper keeps balances in memory. Methods the tests do not use are left to
+// the embedded nil interface.
+type mockBankKeeper struct {
+	types.BankKeeper
+	balances map[string]sdk.Coins
+}
+
+func (m *mockBankKeeper) IsSendEnabledCoins(context.Context, ...sdk.Coin) error {
+	return nil
+}
+
+func (m *mockBankKeeper) GetAllBalances(_ context.Context, addr sdk.AccAddress) sdk.Coins {
+	return m.balances[addr.String()]
+}
+
+func (m *mockBankKeeper) SpendableCoins(_ context.Context, addr sdk.AccAddress) sdk.Coins {
+	return m.balances[addr.String()]
+}
+
+func (m *mockBankKeeper) SendCoins(_ context.Context, from, to sdk.AccAddress, amt sdk.Coins) error {
+	balance, hasNeg := m.balances[from.String()].SafeSub(amt...)
+	if hasNeg {
+		return sdkerrors.ErrInsufficientFunds.Wrapf("%s is smaller than %s", m.balances[from.String()], amt)
+	}
+	m.balances[from.String()] = balance
+	m.balances[to.String()] = m.balances[to.String()].Add(amt...)
+	return nil
+}
+
+func (m *mockBankKeeper) SendCoinsFromAccountToModule(ctx context.Context, from sdk.AccAddress, module string, amt sdk.Coins) error {
+	return m.SendCoins(ctx, from, authtypes.NewModuleAddress(module), amt)
+}
+
+// mockStakingKeeper serves an empty validator set
+type mockStakingKeeper struct {
+	types.ValidatorSetKeeper
+}
+
+func (mockStakingKeeper) GetBondedValidatorsByPower(context.Context) ([]stakingtypes.Validator, error) {
+	return nil, nil
+}
+
+// AnteTestSuite runs the capsule decorator with a time capsule keeper behind the fee
+// deduction of x/auth, like the SimApp ante handler. The decorators that verify
+// signatures are left out, the transactions of the tests are unsigned.
+type AnteTestSuite struct {
+	anteHandler   sdk.AnteHandler
+	ctx           sdk.Context
+	clientCtx     client.Context
+	accountKeeper authkeeper.AccountKeeper
+	bankKeeper    *mockBankKeeper
+	tcKeeper      keeper.Keeper
+	params        types.Params
+	encCfg        moduletestutil.TestEncodingConfig
+}
+
+func SetupTestSuite(t *testing.T, isCheckTx bool) *AnteTestSuite {
+	t.Helper()
+	suite := &AnteTestSuite{}
+
+	keys := storetypes.NewKVStoreKeys(authtypes.StoreKey, types.StoreKey)
+	tkeys := storetypes.NewTransientStoreKeys("transient_test")
+	blockTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	suite.ctx = testutil.DefaultContextWithKeys(keys, tkeys, nil).
+		WithIsCheckTx(isCheckTx).
+		WithBlockHeader(cmtproto.Header{Height: 1, Time: blockTime})
+	suite.encCfg = moduletestutil.MakeTestEncodingConfig(auth.AppModuleBasic{})
+	types.RegisterInterfaces(suite.encCfg.InterfaceRegistry)
+	testdata.RegisterInterfaces(suite.encCfg.InterfaceRegistry)
+
+	maccPerms := map[string][]string{
+		authtypes.FeeCollectorName: nil,
+		types.ModuleName:           nil,
+	}
+	authority := authtypes.NewModuleAddress("gov").String()
+
+	suite.accountKeeper = authkeeper.NewAccountKeeper(
+		suite.encCfg.Codec, runtime.NewKVStoreService(keys[authtypes.StoreKey]), authtypes.ProtoBaseAccount, maccPerms,
+		authcodec.NewBech32Codec("cosmos"), sdk.Bech32MainPrefix, authority,
+	)
+	suite.accountKeeper.GetModuleAccount(suite.ctx, authtypes.FeeCollectorName)
+	err := suite.accountKeeper.Params.Set(suite.ctx, authtypes.DefaultParams())
+	require.NoError(t, err)
+
+	suite.bankKeeper = &mockBankKeeper{balances: make(map[string]sdk.Coins)}
+	suite.tcKeeper = keeper.NewKeeper(
+		suite.encCfg.Codec,
+		authcodec.NewBech32Codec("cosmos"),
+		runtime.NewKVStoreService(keys[types.StoreKey]),
+		suite.ctx.Logger(),
+		suite.bankKeeper,
+		suite.accountKeeper,
+		mockStakingKeeper{},
+		nil,
+		authority,
+	)
+
+	params, err := suite.tcKeeper.GetParams(suite.ctx)
+	require.NoError(t, err)
+	suite.params = params
+
+	suite.clientCtx = client.Context{}.
+		WithTxConfig(suite.encCfg.TxConfig).
+		WithClient(clitestutil.NewMockCometRPC(abci.ResponseQuery{}))
+
+	suite.anteHandler = sdk.ChainAnteDecorators(
+		authante.NewSetUpContextDecorator(),
+		authante.NewDeductFeeDecorator(suite.accountKeeper, suite.bankKeeper, nil, nil),
+		ante.NewCapsuleSpamDecorator(suite.tcKeeper, suite.bankKeeper),
+	)
+
+	return suite
+}
+
+// createAccount creates the account of name, or resets its balance, with a balance of amount
+func (s *AnteTestSuite) createAccount(name string, amount int64) sdk.AccAddress {
+	addr := sdk.AccAddress(name)
+	s.accountKeeper.SetAccount(s.ctx, s.accountKeeper.NewAccountWithAddress(s.ctx, addr))
+	s.bankKeeper.balances[addr.String()] = sdk.NewCoins(sdk.NewInt64Coin("stake", amount))
+	return addr
+}
+
+// deliver runs the ante handler on a transaction of msgs paid by payer at height
+func (s *AnteTestSuite) deliver(t *testing.T, height int64, payer sdk.AccAddress, msgs ...sdk.Msg) error {
+	t.Helper()
+
+	txBuilder := s.clientCtx.TxConfig.NewTxBuilder()
+	require.NoError(t, txBuilder.SetMsgs(msgs...))
+	txBuilder.SetFeeAmount(sdk.NewCoins(sdk.NewInt64Coin("stake", txFee)))
+	txBuilder.SetGasLimit(testdata.NewTestGasLimit())
+	txBuilder.SetFeePayer(payer)
+
+	_, err := s.anteHandler(s.ctx.WithBlockHeight(height), txBuilder.GetTx(), false)
+	return err
+}
+
+// endBlock runs the EndBlocker of the time capsule module, which adjusts the creation fee
+func (s *AnteTestSuite) endBlock(t *testing.T) {
+	t.Helper()
+	require.NoError(t, s.tcKeeper.EndBlocker(s.ctx))
+}
+
+func (s *AnteTestSuite) quotaCount(t *testing.T, creator sdk.AccAddress) uint32 {
+	t.Helper()
+	quota, err := s.tcKeeper.GetCreationQuota(s.ctx, creator.String())
+	require.NoError(t, err)
+	return quota.Count
+}
+
+func createMsg(creator sdk.AccAddress, size int) *types.MsgCreateCapsule {
+	return &types.MsgCreateCapsule{
+		Creator:     creator.String(),
+		Data:        make([]byte, size),
+		CapsuleType: types.CapsuleType_SAFE,
+		Threshold:   2,
+		TotalShares: 3,
+	}
+}
+
+// createMsgs returns count capsule creations of creator
+func createMsgs(creator sdk.AccAddress, count uint32) []sdk.Msg {
+	msgs := make([]sdk.Msg, count)
+	for i := range msgs {
+		msgs[i] = createMsg(creator, 10)
+	}
+	return msgs
+}
+
+func TestCreationQuota(t *testing.T) {
+	suite := SetupTestSuite(t, false)
+	alice := suite.createAccount("alice_______________", 1_000_000_000)
+	bob := suite.createAccount("bob_________________", 1_000_000_000)
+	quota := suite.params.MaxCreationsPerWindow
+	window := int64(suite.params.CreationQuotaWindow)
+
+	// Alice uses her quota over two transactions
+	require.NoError(t, suite.deliver(t, 1, alice, createMsgs(alice, quota-1)...))
+	require.NoError(t, suite.deliver(t, 50, alice, createMsg(alice, 10)))
+	require.Equal(t, quota, suite.quotaCount(t, alice))
+
+	err := suite.deliver(t, window, alice, createMsg(alice, 10))
+	require.ErrorIs(t, err, types.ErrCreationQuotaExceeded)
+
+	// Quotas are per account
+	require.NoError(t, suite.deliver(t, window, bob, createMsg(bob, 10)))
+
+	// A new window starts once the window of the first creation ended
+	require.NoError(t, suite.deliver(t, 1+window, alice, createMsg(alice, 10)))
+	require.Equal(t, uint32(1), suite.quotaCount(t, alice))
+
+	// The quotas and the creations of the block are part of the module state
+	quotas, err := suite.tcKeeper.GetAllCreationQuotas(suite.ctx)
+	require.NoError(t, err)
+	require.Len(t, quotas, 2)
+
+	volume, err := suite.tcKeeper.GetCreationVolume(suite.ctx)
+	require.NoError(t, err)
+	require.Equal(t, quota+2, volume.BlockCreations)
+}
+
+func TestCreationQuotaNestedMsgs(t *testing.T) {
+	suite := SetupTestSuite(t, false)
+	alice := suite.createAccount("alice_______________", 1_000_000_000)
+	grantee := suite.createAccount("grantee_____________", 1_000_000_000)
+
+	exec := authz.NewMsgExec(grantee, createMsgs(alice, suite.params.MaxCreationsPerWindow+1))
+	err := suite.deliver(t, 1, grantee, &exec)
+	require.ErrorIs(t, err, types.ErrCreationQuotaExceeded)
+
+	exec = authz.NewMsgExec(grantee, createMsgs(alice, 2))
+	require.NoError(t, suite.deliver(t, 1, grantee, &exec))
+	require.Equal(t, uint32(2), suite.quotaCount(t, alice))
+	require.Zero(t, suite.quotaCount(t, grantee))
+}
+
+func TestTxPayloadLimit(t *testing.T) {
+	suite := SetupTestSuite(t, false)
+	alice := suite.createAccount("alice_______________", 1_000_000_000)
+	limit := int(suite.params.MaxTxPayloadBytes)
+
+	require.NoError(t, suite.deliver(t, 1, alice, createMsg(alice, limit)))
+
+	// The limit applies to the payload of all capsule messages in the transaction
+	err := suite.deliver(t, 1, alice,
+		createMsg(alice, limit/2+1),
+		&types.MsgAmendCapsule{Owner: alice.String(), CapsuleID: 1, Data: make([]byte, limit/2+1)},
+	)
+	require.ErrorIs(t, err, types.ErrTxPayloadTooLarge)
+
+	err = suite.deliver(t, 1, alice, &types.MsgRevealCapsule{Revealer: alice.String(), CapsuleID: 1, Data: make([]byte, limit+1)})
+	require.ErrorIs(t, err, types.ErrTxPayloadTooLarge)
+}
+
+func TestDynamicCreationFee(t *testing.T) {
+	suite := SetupTestSuite(t, false)
+	baseFee := suite.params.CreationFee.AmountOf("stake").Int64()
+
+	// The creation fee is checked against the balance left after the transaction fee
+	carol := suite.createAccount("carol_______________", baseFee)
+	err := suite.deliver(t, 1, carol, createMsg(carol, 10))
+	require.ErrorIs(t, err, types.ErrInsufficientCreationFee)
+
+	// Alice can pay the base fee of one capsule, but not of two
+	alice := suite.createAccount("alice_______________", baseFee+txFee)
+	err = suite.deliver(t, 1, alice, createMsgs(alice, 2)...)
+	require.ErrorIs(t, err, types.ErrInsufficientCreationFee)
+
+	suite.createAccount("alice_______________", baseFee+txFee)
+	require.NoError(t, suite.deliver(t, 1, alice, createMsg(alice, 10)))
+
+	// A block with twice the target creations raises the fee by the full adjustment,
+	// beyond her balance
+	bob := suite.createAccount("bob_________________", 1_000_000_000)
+	require.NoError(t, suite.deliver(t, 1, bob, createMsgs(bob, 2*suite.params.TargetCreationsPerBlock-1)...))
+	suite.endBlock(t)
+
+	volume, err := suite.tcKeeper.GetCreationVolume(suite.ctx)
+	require.NoError(t, err)
+	require.Equal(t, math.LegacyOneDec().Add(suite.params.CreationFeeAdjustment), volume.Multiplier)
+	require.Zero(t, volume.BlockCreations)
+
+	fee, err := suite.tcKeeper.CreationFee(suite.ctx)
+	require.NoError(t, err)
+	require.Equal(t, suite.params.CreationFeeAt(volume.Multiplier), fee)
+	require.True(t, fee.IsAllGT(suite.params.CreationFee))
+
+	suite.createAccount("alice_______________", baseFee+txFee)
+	err = suite.deliver(t, 2, alice, createMsg(alice, 10))
+	require.ErrorIs(t, err, types.ErrInsufficientCreationFee)
+
+	// Quiet blocks lower the fee back to the base fee, never below
+	for i := 0; i < 10; i++ {
+		suite.endBlock(t)
+	}
+	fee, err = suite.tcKeeper.CreationFee(suite.ctx)
+	require.NoError(t, err)
+	require.Equal(t, suite.params.CreationFee, fee)
+}
+
+func TestSponsoredCreationFee(t *testing.T) {
+	suite := SetupTestSuite(t, false)
+	baseFee := suite.params.CreationFee.AmountOf("stake").Int64()
+	alice := suite.createAccount("alice_______________", 10*txFee)
+	bob := suite.createAccount("bob_________________", 0)
+	sponsor := suite.createAccount("sponsor_____________", 0)
+
+	sponsored := func(creator sdk.AccAddress) *types.MsgCreateCapsule {
+		msg := createMsg(creator, 10)
+		msg.Sponsor = sponsor.String()
+		return msg
+	}
+
+	// The sponsor pays the creation fee, so a creator who can only pay the transaction
+	// fee can create sponsored capsules
+	err := suite.deliver(t, 1, alice, sponsored(alice))
+	require.ErrorIs(t, err, types.ErrInsufficientCreationFee)
+
+	suite.createAccount("sponsor_____________", 2*baseFee)
+	require.NoError(t, suite.deliver(t, 1, alice, sponsored(alice)))
+
+	// The fees of all capsules the sponsor pays for in a transaction add up
+	err = suite.deliver(t, 1, alice, sponsored(alice), sponsored(bob), sponsored(bob))
+	require.ErrorIs(t, err, types.ErrInsufficientCreationFee)
+
+	// Sponsored creations count against the quota of the creator
+	suite.createAccount("sponsor_____________", 1_000_000_000)
+	msgs := make([]sdk.Msg, suite.params.MaxCreationsPerWindow-1)
+	for i := range msgs {
+		msgs[i] = sponsored(alice)
+	}
+	require.NoError(t, suite.deliver(t, 1, alice, msgs...))
+	err = suite.deliver(t, 1, alice, sponsored(alice))
+	require.ErrorIs(t, err, types.ErrCreationQuotaExceeded)
+	require.Equal(t, suite.params.MaxCreationsPerWindow, suite.quotaCount(t, alice))
+	require.Zero(t, suite.quotaCount(t, sponsor))
+}
+
+func TestCreationFeeMultiplierCap(t *testing.T) {
+	params := types.DefaultParams()
+	params.TargetCreationsPerBlock = 1
+	params.MaxCreationFeeMultiplier = math.LegacyNewDec(2)
+
+	volume := types.NewCreationVolume()
+	for i := 0; i < 20; i++ {
+		// Creations far above the target raise the fee by at most the adjustment
+		volume.BlockCreations = 1000
+		next := volume.NextMultiplier(params)
+		require.True(t, next.LTE(volume.Multiplier.Mul(math.LegacyOneDec().Add(params.CreationFeeAdjustment))))
+		volume = types.CreationVolume{Multiplier: next}
+	}
+	require.Equal(t, math.LegacyNewDec(2), volume.Multiplier)
+}
+
+func TestNonCapsuleTx(t *testing.T) {
+	suite := SetupTestSuite(t, true)
+	alice := suite.createAccount("alice_______________", txFee)
+
+	// Transactions without capsule messages pass through
+	require.NoError(t, suite.deliver(t, 1, alice, testdata.NewTestMsg(alice)))
+
+	quotas, err := suite.tcKeeper.GetAllCreationQuotas(suite.ctx)
+	require.NoError(t, err)
+	require.Empty(t, quotas)
+}
@@ -0,0 +1,21 @@
+package ante
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// TimeCapsuleKeeper defines the expected time capsule keeper
+type TimeCapsuleKeeper interface {
+	GetParams(ctx context.Context) (types.Params, error)
+	CreationFee(ctx context.Context) (sdk.Coins, error)
+	ConsumeCreationQuota(ctx context.Context, creator string, count uint32) error
+}
+
+// BankKeeper defines the expected bank keeper
+type BankKeeper interface {
+	SpendableCoins(ctx context.Context, addr sdk.AccAddress) sdk.Coins
+}
@@ -0,0 +1,534 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// ArchiveVerification is the outcome of verifying a capsule archive
+type ArchiveVerification struct {
+	CapsuleProofVerification
+	ChainID        string `json:"chain_id"`
+	ArchiveVersion uint32 `json:"archive_version"`
+	TrustAnchor    string `json:"trust_anchor"` // "app-hash", "header-file" or "validator-set"
+	SealedShares   bool   `json:"sealed_shares"`
+	HolderKey      string `json:"holder_key,omitempty"`
+}
+
+// CmdArchive groups the capsule archive commands
+func CmdArchive() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                        "archive",
+		Short:                      "Export, verify and open portable capsule archives",
+		DisableFlagParsing:         true,
+		SuggestionsMinimumDistance: 2,
+		RunE:                       client.ValidateCmd,
+	}
+
+	cmd.AddCommand(
+		CmdArchiveExport(),
+		CmdArchiveVerify(),
+		CmdArchiveOpen(),
+	)
+
+	return cmd
+}
+
+// CmdArchiveExport writes a capsule archive from a node and the gathered key shares
+func CmdArchiveExport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [capsule-id] [archive-file]",
+		Short: "Export a capsule with its proof, ciphertext and key shares to a portable archive",
+		Long: `Export a capsule to a versioned JSON archive that opens offline with 'archive open'.
+The archive holds the capsule record with its Merkle proof and the signed header the
+proof is made against, the ciphertext, the share commitments and the key shares
+passed with --key-shares, encrypted to the holder key.
+
+The holder key defaults to the local recipient key. Capsules delivered to a recipient
+key need no shares. Capsules stored on IPFS need their ciphertext passed with
+--data-file.
+
+Example:
+$ simd query timecapsule archive export 42 capsule-42.archive.json --key-shares share1.json,share2.json,share3.json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			node, err := clientCtx.GetNode()
+			if err != nil {
+				return err
+			}
+
+			// The proof needs the header of the next block, so the latest provable
+			// height is the one before the latest block
+			height := clientCtx.Height
+			if height == 0 {
+				status, err := node.Status(context.Background())
+				if err != nil {
+					return err
+				}
+				height = status.SyncInfo.LatestBlockHeight - 1
+			}
+
+			key, err := types.CapsuleStoreKey(capsuleID)
+			if err != nil {
+				return err
+			}
+			res, err := clientCtx.QueryABCI(abci.RequestQuery{
+				Path:   types.CapsuleProofQueryPath,
+				Data:   key,
+				Height: height,
+				Prove:  true,
+			})
+			if err != nil {
+				return err
+			}
+			if res.ProofOps == nil {
+				return fmt.Errorf("node returned no proof for capsule %d", capsuleID)
+			}
+			if len(res.Value) == 0 {
+				return fmt.Errorf("capsule %d does not exist at height %d", capsuleID, res.Height)
+			}
+
+			headerHeight := res.Height + 1
+			commit, err := node.Commit(context.Background(), &headerHeight)
+			if err != nil {
+				return fmt.Errorf("failed to fetch the header of height %d: %w", headerHeight, err)
+			}
+			header, err := cmtjson.Marshal(commit.SignedHeader)
+			if err != nil {
+				return err
+			}
+
+			var capsule types.TimeCapsule
+			if err := clientCtx.Codec.Unmarshal(res.Value, &capsule); err != nil {
+				return fmt.Errorf("stored value is not a capsule: %w", err)
+			}
+			if capsule.IsPurged() {
+				return fmt.Errorf("capsule %d data was purged at height %d (%s)", capsuleID, capsule.Tombstone.PurgedHeight, capsule.Tombstone.Reason)
+			}
+
+			ciphertext := capsule.EncryptedData
+			if dataFile, _ := cmd.Flags().GetString("data-file"); dataFile != "" {
+				ciphertext, err = os.ReadFile(dataFile)
+				if err != nil {
+					return fmt.Errorf("failed to read data file: %w", err)
+				}
+			}
+			if len(ciphertext) == 0 {
+				return fmt.Errorf("capsule data is stored on IPFS (%s), pass it with --data-file", capsule.IPFSHash)
+			}
+
+			var sealed *types.SealedShares
+			shareFiles, _ := cmd.Flags().GetStringSlice("key-shares")
+			if len(shareFiles) > 0 {
+				shares, err := readShareFiles(shareFiles, &capsule)
+				if err != nil {
+					return err
+				}
+
+				holderKey, err := archiveHolderKey(cmd, clientCtx)
+				if err != nil {
+					return err
+				}
+
+				sealed, err = types.SealShares(holderKey, shares)
+				if err != nil {
+					return err
+				}
+			} else if capsule.Delivery == nil {
+				return fmt.Errorf("capsule %d has not been delivered to a recipient key, pass its key shares with --key-shares", capsuleID)
+			}
+
+			archive := types.CapsuleArchive{
+				Format:     types.ArchiveFormat,
+				Version:    types.ArchiveVersion,
+				ChainID:    commit.SignedHeader.ChainID,
+				CapsuleID:  capsuleID,
+				ExportedAt: time.Now().UTC(),
+				Capsule:    &capsule,
+				Proof: types.CapsuleProof{
+					CapsuleID: capsuleID,
+					Height:    res.Height,
+					Key:       res.Key,
+					Value:     res.Value,
+					ProofOps:  res.ProofOps,
+				},
+				Header:           header,
+				Ciphertext:       ciphertext,
+				ShareCommitments: capsule.ShareCommitments,
+				Shares:           sealed,
+			}
+			if err := archive.Validate(); err != nil {
+				return err
+			}
+
+			bz, err := json.MarshalIndent(archive, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(args[1], bz, 0o600); err != nil {
+				return err
+			}
+
+			return clientCtx.PrintString(fmt.Sprintf("Wrote the archive of capsule %d at height %d to %s\n", capsuleID, res.Height, args[1]))
+		},
+	}
+
+	cmd.Flags().StringSlice("key-shares", nil, "Key share files (JSON format) to seal into the archive")
+	cmd.Flags().String("holder-key", "", "Hex public key to seal the key shares to (default: the local recipient key)")
+	cmd.Flags().String("key-file", "", "Path to the recipient key file (default: <home>/"+types.DefaultRecipientKeyFile+")")
+	cmd.Flags().String("data-file", "", "Encrypted capsule data fetched from IPFS")
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdArchiveVerify verifies a capsule archive offline
+func CmdArchiveVerify() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify [archive-file] [trusted-app-hash]",
+		Short: "Verify a capsule archive without a node",
+		Long: `Verify the proof, commitments and ciphertext of a capsule archive and print the
+proven facts about the capsule. No node is contacted and no key is needed.
+
+The archive is only as trustworthy as the app hash its proof is checked against, so
+one trust anchor is required: a trusted app hash or --header-file, as for
+'verify-capsule-proof', or --validators-file, the trusted validator set of the block
+after the proof height, which must have signed the header held in the archive.
+
+Example:
+$ simd query timecapsule archive verify capsule-42.archive.json 5E3B...A1
+$ simd query timecapsule archive verify capsule-42.archive.json --header-file=commit-1201.json
+$ simd query timecapsule archive verify capsule-42.archive.json --validators-file=validators-1201.json`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx := client.GetClientContextFromCmd(cmd)
+
+			_, _, result, err := verifyArchive(cmd, clientCtx, args)
+			if err != nil {
+				return err
+			}
+
+			out, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintBytes(out)
+		},
+	}
+
+	cmd.Flags().String("header-file", "", "Trusted header of the block after the proof height, in JSON")
+	cmd.Flags().String("validators-file", "", "Trusted validator set of the block after the proof height, in JSON, to check the archived header against")
+	return cmd
+}
+
+// CmdArchiveOpen verifies and decrypts a capsule archive offline
+func CmdArchiveOpen() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "open [archive-file] [output-file] [trusted-app-hash]",
+		Short: "Verify and decrypt a capsule archive without a node",
+		Long: `Verify a capsule archive like 'archive verify', then decrypt its data with the holder
+key and write it to the output file.
+
+The data key is reconstructed from the sealed key shares that match the commitments,
+or, for capsules delivered to a recipient key, opened from the delivery. Deliveries
+protected by a passphrase prompt for it. The decompressed data is bounded by
+--max-data-size, or else by the max_data_size parameter of the chain, which is then
+queried from --node; no node is contacted when --max-data-size is given.
+
+Example:
+$ simd query timecapsule archive open capsule-42.archive.json capsule-42.data --header-file=commit-1201.json --max-data-size=1048576
+$ simd query timecapsule archive open capsule-42.archive.json capsule-42.data 5E3B...A1 --key-file=recipient_key.json`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			// The trusted app hash, if any, follows the output file
+			verifyArgs := []string{args[0]}
+			if len(args) == 3 {
+				verifyArgs = append(verifyArgs, args[2])
+			}
+			archive, capsule, _, err := verifyArchive(cmd, clientCtx, verifyArgs)
+			if err != nil {
+				return err
+			}
+
+			keyFile, _ := cmd.Flags().GetString("key-file")
+			if keyFile == "" {
+				keyFile = filepath.Join(clientCtx.HomeDir, types.DefaultRecipientKeyFile)
+			}
+			privKey, _, err := crypto.LoadTimelockKey(keyFile)
+			if err != nil {
+				return fmt.Errorf("failed to load holder key: %w", err)
+			}
+			defer crypto.WipeKey(privKey)
+
+			// Deliveries protected by a passphrase need it besides the holder key
+			var passphrase string
+			if archive.Shares == nil {
+				passphrase, err = readCapsulePassphrase(clientCtx, capsule)
+				if err != nil {
+					return err
+				}
+			}
+
+			// The archive does not bound its own data, a forged archive could bomb the reader
+			maxDataSize, _ := cmd.Flags().GetUint64("max-data-size")
+			if maxDataSize == 0 {
+				params, err := types.NewQueryClient(clientCtx).Params(context.Background(), &types.QueryParamsRequest{})
+				if err != nil {
+					return fmt.Errorf("pass --max-data-size, or a --node to read the max_data_size parameter from: %w", err)
+				}
+				maxDataSize = params.Params.MaxDataSize
+			}
+
+			data, err := archive.Decrypt(capsule, privKey, passphrase, maxDataSize)
+			if err != nil {
+				return err
+			}
+
+			if err := os.WriteFile(args[1], data, 0o600); err != nil {
+				return err
+			}
+
+			return clientCtx.PrintString(fmt.Sprintf("Wrote %d bytes of capsule %d to %s\n", len(data), capsule.ID, args[1]))
+		},
+	}
+
+	cmd.Flags().String("key-file", "", "Path to the holder's recipient key file (default: <home>/"+types.DefaultRecipientKeyFile+")")
+	cmd.Flags().String("header-file", "", "Trusted header of the block after the proof height, in JSON")
+	cmd.Flags().String("validators-file", "", "Trusted validator set of the block after the proof height, in JSON, to check the archived header against")
+	cmd.Flags().Uint64("max-data-size", 0, "Bound on the decompressed data in bytes (default: the max_data_size parameter of the chain)")
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// verifyArchive reads an archive and verifies it against one trust anchor: the trusted
+// app hash in args, the --header-file flag, or the --validators-file flag, whose
+// validator set must have signed the header held in the archive
+func verifyArchive(cmd *cobra.Command, clientCtx client.Context, args []string) (*types.CapsuleArchive, *types.TimeCapsule, *ArchiveVerification, error) {
+	bz, err := os.ReadFile(args[0])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read archive file: %w", err)
+	}
+	var archive types.CapsuleArchive
+	if err := json.Unmarshal(bz, &archive); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid archive file: %w", err)
+	}
+	if err := archive.Validate(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	headerFile, _ := cmd.Flags().GetString("header-file")
+	validatorsFile, _ := cmd.Flags().GetString("validators-file")
+	anchors := 0
+	for _, set := range []bool{len(args) == 2, headerFile != "", validatorsFile != ""} {
+		if set {
+			anchors++
+		}
+	}
+	if anchors > 1 {
+		return nil, nil, nil, fmt.Errorf("pass only one of a trusted app hash, --header-file and --validators-file")
+	}
+
+	var (
+		anchor  string
+		appHash []byte
+		height  int64
+	)
+	switch {
+	case len(args) == 2:
+		anchor = "app-hash"
+		appHash, err = decodeAppHash(args[1])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		height = archive.Proof.Height + 1
+
+	case headerFile != "":
+		anchor = "header-file"
+		appHash, height, err = readTrustedHeader(headerFile)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+	case validatorsFile != "":
+		anchor = "validator-set"
+		vals, err := readTrustedValidators(validatorsFile)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		appHash, height, err = verifyArchivedHeader(archive.Header, archive.ChainID, vals)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+	default:
+		return nil, nil, nil, fmt.Errorf("a trusted app hash, --header-file or --validators-file is required")
+	}
+	if height != archive.Proof.Height+1 {
+		return nil, nil, nil, fmt.Errorf("proof at height %d needs the header of height %d, got %d", archive.Proof.Height, archive.Proof.Height+1, height)
+	}
+
+	capsule, err := archive.Verify(clientCtx.Codec, appHash)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	result := &ArchiveVerification{
+		CapsuleProofVerification: CapsuleProofVerification{
+			CapsuleID:    capsule.ID,
+			Height:       archive.Proof.Height,
+			AppHash:      hex.EncodeToString(appHash),
+			Exists:       true,
+			Owner:        capsule.Owner,
+			Recipient:    capsule.Recipient,
+			Status:       capsule.Status.String(),
+			DataHash:     capsule.DataHash,
+			Version:      capsule.CurrentVersion(),
+			UnlockTime:   capsule.UnlockTime,
+			UnlockHeight: capsule.UnlockHeight,
+		},
+		ChainID:        archive.ChainID,
+		ArchiveVersion: archive.Version,
+		TrustAnchor:    anchor,
+		SealedShares:   archive.Shares != nil,
+	}
+	if archive.Shares != nil {
+		result.HolderKey = hex.EncodeToString(archive.Shares.HolderKey)
+	}
+
+	return &archive, capsule, result, nil
+}
+
+// readTrustedValidators reads a validator set in JSON. It accepts a validator set and
+// the output of the CometBFT /validators RPC, which must list the whole set.
+func readTrustedValidators(path string) (*cmttypes.ValidatorSet, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read validators file: %w", err)
+	}
+
+	var doc struct {
+		Validators []*cmttypes.Validator `json:"validators"`
+		Result     *struct {
+			Validators []*cmttypes.Validator `json:"validators"`
+		} `json:"result"`
+	}
+	if err := cmtjson.Unmarshal(bz, &doc); err != nil {
+		return nil, fmt.Errorf("invalid validators file: %w", err)
+	}
+	validators := doc.Validators
+	if doc.Result != nil {
+		validators = doc.Result.Validators
+	}
+	if len(validators) == 0 {
+		return nil, fmt.Errorf("validators file lists no validator")
+	}
+
+	vals := &cmttypes.ValidatorSet{}
+	if err := vals.UpdateWithChangeSet(validators); err != nil {
+		return nil, fmt.Errorf("invalid validator set: %w", err)
+	}
+	return vals, nil
+}
+
+// verifyArchivedHeader checks that a trusted validator set signed the header held in an
+// archive and returns the header's app hash and height
+func verifyArchivedHeader(header []byte, chainID string, vals *cmttypes.ValidatorSet) ([]byte, int64, error) {
+	var signed cmttypes.SignedHeader
+	if err := cmtjson.Unmarshal(header, &signed); err != nil {
+		return nil, 0, fmt.Errorf("invalid archived header: %w", err)
+	}
+	if err := signed.ValidateBasic(chainID); err != nil {
+		return nil, 0, fmt.Errorf("invalid archived header: %w", err)
+	}
+	if !bytes.Equal(signed.ValidatorsHash, vals.Hash()) {
+		return nil, 0, fmt.Errorf("archived header of height %d was not produced by the trusted validator set", signed.Height)
+	}
+	if err := vals.VerifyCommitLight(chainID, signed.Commit.BlockID, signed.Height, signed.Commit); err != nil {
+		return nil, 0, fmt.Errorf("archived header of height %d is not signed by the trusted validator set: %w", signed.Height, err)
+	}
+	if len(signed.AppHash) != sha256.Size {
+		return nil, 0, fmt.Errorf("archived header has an app hash of %d bytes, expected %d", len(signed.AppHash), sha256.Size)
+	}
+
+	return signed.AppHash, signed.Height, nil
+}
+
+// readShareFiles reads key shares in JSON and checks them against the capsule's
+// commitments, so that an archive is never written with shares that cannot open it
+func readShareFiles(files []string, capsule *types.TimeCapsule) ([]*crypto.Share, error) {
+	shares := make([]*crypto.Share, 0, len(files))
+	for _, file := range files {
+		bz, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key share file %s: %w", file, err)
+		}
+		var share crypto.Share
+		if err := json.Unmarshal(bz, &share); err != nil {
+			return nil, fmt.Errorf("invalid key share file %s: %w", file, err)
+		}
+		if len(capsule.ShareCommitments) > 0 {
+			if err := crypto.VerifyShare(capsule.ShareCommitments, &share); err != nil {
+				return nil, fmt.Errorf("key share file %s does not match the capsule commitments: %w", file, err)
+			}
+		}
+		shares = append(shares, &share)
+	}
+
+	if len(shares) < int(capsule.Threshold) {
+		return nil, fmt.Errorf("capsule %d needs %d key shares, got %d", capsule.ID, capsule.Threshold, len(shares))
+	}
+	return shares, nil
+}
+
+// archiveHolderKey returns the key to seal archived shares to, from --holder-key or
+// the local recipient key file
+func archiveHolderKey(cmd *cobra.Command, clientCtx client.Context) ([]byte, error) {
+	if holderKeyStr, _ := cmd.Flags().GetString("holder-key"); holderKeyStr != "" {
+		holderKey, err := hex.DecodeString(holderKeyStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holder key: %w", err)
+		}
+		return holderKey, nil
+	}
+
+	keyFile, _ := cmd.Flags().GetString("key-file")
+	if keyFile == "" {
+		keyFile = filepath.Join(clientCtx.HomeDir, types.DefaultRecipientKeyFile)
+	}
+	_, pubKey, err := crypto.LoadOrGenerateTimelockKey(keyFile)
+	return pubKey, err
+}
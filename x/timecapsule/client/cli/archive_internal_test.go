@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cmtjson "github.com/cometbft/cometbft/libs/json"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	cmtversion "github.com/cometbft/cometbft/proto/tendermint/version"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cometbft/cometbft/version"
+	"github.com/stretchr/testify/require"
+)
+
+const testChainID = "timecapsule-1"
+
+// signHeader returns the header of a block at height with appHash, signed by vals
+func signHeader(t *testing.T, vals *cmttypes.ValidatorSet, privVals []cmttypes.PrivValidator, height int64, appHash []byte) cmttypes.SignedHeader {
+	t.Helper()
+
+	header := &cmttypes.Header{
+		Version:            cmtversion.Consensus{Block: version.BlockProtocol},
+		ChainID:            testChainID,
+		Height:             height,
+		Time:               time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		ValidatorsHash:     vals.Hash(),
+		NextValidatorsHash: vals.Hash(),
+		AppHash:            appHash,
+		ProposerAddress:    vals.Proposer.Address,
+	}
+	blockID := cmttypes.BlockID{
+		Hash:          header.Hash(),
+		PartSetHeader: cmttypes.PartSetHeader{Total: 1, Hash: make([]byte, 32)},
+	}
+	voteSet := cmttypes.NewVoteSet(testChainID, height, 0, cmtproto.PrecommitType, vals)
+	extCommit, err := cmttypes.MakeExtCommit(blockID, height, 0, voteSet, privVals, header.Time, false)
+	require.NoError(t, err)
+
+	return cmttypes.SignedHeader{Header: header, Commit: extCommit.ToCommit()}
+}
+
+// writeValidators writes a validator set in the format of the CometBFT /validators RPC
+func writeValidators(t *testing.T, vals *cmttypes.ValidatorSet) string {
+	t.Helper()
+
+	type result struct {
+		BlockHeight int64                 `json:"block_height"`
+		Validators  []*cmttypes.Validator `json:"validators"`
+	}
+	bz, err := cmtjson.Marshal(struct {
+		JSONRPC string `json:"jsonrpc"`
+		Result  result `json:"result"`
+	}{"2.0", result{1201, vals.Validators}})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "validators.json")
+	require.NoError(t, os.WriteFile(path, bz, 0o600))
+	return path
+}
+
+func TestVerifyArchivedHeader(t *testing.T) {
+	appHash, err := hex.DecodeString(testAppHash)
+	require.NoError(t, err)
+
+	vals, privVals := cmttypes.RandValidatorSet(4, 10)
+	trusted, err := readTrustedValidators(writeValidators(t, vals))
+	require.NoError(t, err)
+	require.Equal(t, vals.Hash(), trusted.Hash())
+
+	signed := signHeader(t, vals, privVals, 1201, appHash)
+	header, err := cmtjson.Marshal(signed)
+	require.NoError(t, err)
+
+	gotHash, height, err := verifyArchivedHeader(header, testChainID, trusted)
+	require.NoError(t, err)
+	require.Equal(t, appHash, gotHash)
+	require.Equal(t, int64(1201), height)
+
+	t.Run("other chain", func(t *testing.T) {
+		_, _, err := verifyArchivedHeader(header, "other-1", trusted)
+		require.Error(t, err)
+	})
+
+	t.Run("other validator set", func(t *testing.T) {
+		others, _ := cmttypes.RandValidatorSet(4, 10)
+		_, _, err := verifyArchivedHeader(header, testChainID, others)
+		require.ErrorContains(t, err, "trusted validator set")
+	})
+
+	t.Run("forged app hash", func(t *testing.T) {
+		forged := signHeader(t, vals, privVals, 1201, appHash)
+		forged.AppHash = make([]byte, 32)
+		bz, err := cmtjson.Marshal(forged)
+		require.NoError(t, err)
+
+		_, _, err = verifyArchivedHeader(bz, testChainID, trusted)
+		require.Error(t, err)
+	})
+
+	t.Run("signed by a minority", func(t *testing.T) {
+		// One of the four validators signed, the others' signatures are absent
+		minority := signHeader(t, vals, privVals, 1201, appHash)
+		for i := 1; i < len(minority.Commit.Signatures); i++ {
+			minority.Commit.Signatures[i] = cmttypes.NewCommitSigAbsent()
+		}
+		bz, err := cmtjson.Marshal(minority)
+		require.NoError(t, err)
+
+		_, _, err = verifyArchivedHeader(bz, testChainID, trusted)
+		require.ErrorContains(t, err, "not signed by the trusted validator set")
+	})
+}
+
+func TestReadTrustedValidatorsRejectsEmptySets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "validators.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"validators":[]}`), 0o600))
+
+	_, err := readTrustedValidators(path)
+	require.ErrorContains(t, err, "no validator")
+}
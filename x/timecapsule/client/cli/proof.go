@@ -0,0 +1,280 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// CapsuleProofVerification is the outcome of verifying a capsule proof, with the proven
+// facts about the capsule
+type CapsuleProofVerification struct {
+	CapsuleID    uint64     `json:"capsule_id"`
+	Height       int64      `json:"height"`
+	AppHash      string     `json:"app_hash"`
+	Exists       bool       `json:"exists"`
+	Owner        string     `json:"owner,omitempty"`
+	Recipient    string     `json:"recipient,omitempty"`
+	Status       string     `json:"status,omitempty"`
+	DataHash     string     `json:"data_hash,omitempty"`
+	Version      uint32     `json:"version,omitempty"`
+	UnlockTime   *time.Time `json:"unlock_time,omitempty"`
+	UnlockHeight int64      `json:"unlock_height,omitempty"`
+}
+
+// CmdQueryCapsuleProof queries a capsule with the Merkle proof of its stored value
+func CmdQueryCapsuleProof() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "capsule-proof [capsule-id]",
+		Short: "Query a capsule with the Merkle proof of its state at a height",
+		Long: `Query the stored value of a capsule together with its IAVL/ICS-23 Merkle proof, so
+it can be verified with 'verify-capsule-proof' without trusting the node.
+
+The proof is made against the app hash of the state at the queried height, which is
+committed in the header of the next block. A capsule that does not exist is returned
+with a proof of its absence.
+
+Example:
+$ simd query timecapsule capsule-proof 42 --height=1200 > capsule-42.proof.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			key, err := types.CapsuleStoreKey(capsuleID)
+			if err != nil {
+				return err
+			}
+
+			res, err := clientCtx.QueryABCI(abci.RequestQuery{
+				Path:   types.CapsuleProofQueryPath,
+				Data:   key,
+				Height: clientCtx.Height,
+				Prove:  true,
+			})
+			if err != nil {
+				return err
+			}
+			if res.ProofOps == nil {
+				return fmt.Errorf("node returned no proof for capsule %d", capsuleID)
+			}
+
+			bz, err := json.MarshalIndent(types.CapsuleProof{
+				CapsuleID: capsuleID,
+				Height:    res.Height,
+				Key:       res.Key,
+				Value:     res.Value,
+				ProofOps:  res.ProofOps,
+			}, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintBytes(bz)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdVerifyCapsuleProof verifies a capsule proof offline against a trusted app hash
+func CmdVerifyCapsuleProof() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-capsule-proof [proof-file] [trusted-app-hash]",
+		Short: "Verify a capsule proof against a trusted app hash, without a node",
+		Long: `Verify a proof written by 'capsule-proof' against a trusted app hash and print the
+proven facts about the capsule. No node is contacted.
+
+The app hash of the state at the proof height H is committed in the header of block
+H+1. Pass it in hex or base64, or pass that header with --header-file, e.g. the
+output of the CometBFT /commit RPC for height H+1 from a light client.
+
+Example:
+$ simd query timecapsule verify-capsule-proof capsule-42.proof.json 5E3B...A1
+$ simd query timecapsule verify-capsule-proof capsule-42.proof.json --header-file=commit-1201.json`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			bz, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read proof file: %w", err)
+			}
+			var proof types.CapsuleProof
+			if err := json.Unmarshal(bz, &proof); err != nil {
+				return fmt.Errorf("invalid proof file: %w", err)
+			}
+
+			headerFile, _ := cmd.Flags().GetString("header-file")
+			var appHash []byte
+			switch {
+			case headerFile != "" && len(args) == 2:
+				return fmt.Errorf("pass either a trusted app hash or --header-file, not both")
+
+			case headerFile != "":
+				var height int64
+				appHash, height, err = readTrustedHeader(headerFile)
+				if err != nil {
+					return err
+				}
+				if height != proof.Height+1 {
+					return fmt.Errorf("proof at height %d needs the header of height %d, got %d", proof.Height, proof.Height+1, height)
+				}
+
+			case len(args) == 2:
+				appHash, err = decodeAppHash(args[1])
+				if err != nil {
+					return err
+				}
+
+			default:
+				return fmt.Errorf("a trusted app hash or --header-file is required")
+			}
+
+			if err := proof.Verify(appHash); err != nil {
+				return err
+			}
+
+			result := CapsuleProofVerification{
+				CapsuleID: proof.CapsuleID,
+				Height:    proof.Height,
+				AppHash:   hex.EncodeToString(appHash),
+				Exists:    proof.Exists(),
+			}
+			if proof.Exists() {
+				var capsule types.TimeCapsule
+				if err := clientCtx.Codec.Unmarshal(proof.Value, &capsule); err != nil {
+					return fmt.Errorf("proven value is not a capsule: %w", err)
+				}
+				if capsule.ID != proof.CapsuleID {
+					return fmt.Errorf("proven value is capsule %d, not %d", capsule.ID, proof.CapsuleID)
+				}
+
+				result.Owner = capsule.Owner
+				result.Recipient = capsule.Recipient
+				result.Status = capsule.Status.String()
+				result.DataHash = capsule.DataHash
+				result.Version = capsule.CurrentVersion()
+				result.UnlockTime = capsule.UnlockTime
+				result.UnlockHeight = capsule.UnlockHeight
+			}
+
+			out, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintBytes(out)
+		},
+	}
+
+	cmd.Flags().String("header-file", "", "Trusted header of the block after the proof height, in JSON")
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// trustedHeader is the part of a CometBFT header a capsule proof is verified against
+type trustedHeader struct {
+	Height  json.RawMessage `json:"height"`
+	AppHash string          `json:"app_hash"`
+}
+
+// readTrustedHeader reads the app hash and height of a header file in JSON
+func readTrustedHeader(path string) ([]byte, int64, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read header file: %w", err)
+	}
+	return parseTrustedHeader(bz)
+}
+
+// parseTrustedHeader parses the app hash and height of a header in JSON. It accepts a
+// bare header, a block, a signed header and the output of the CometBFT /commit RPC.
+func parseTrustedHeader(bz []byte) ([]byte, int64, error) {
+	var doc struct {
+		trustedHeader
+		Header *trustedHeader `json:"header"`
+		Block  *struct {
+			Header *trustedHeader `json:"header"`
+		} `json:"block"`
+		SignedHeader *struct {
+			Header *trustedHeader `json:"header"`
+		} `json:"signed_header"`
+		Result *struct {
+			SignedHeader *struct {
+				Header *trustedHeader `json:"header"`
+			} `json:"signed_header"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(bz, &doc); err != nil {
+		return nil, 0, fmt.Errorf("invalid header: %w", err)
+	}
+
+	header := &doc.trustedHeader
+	switch {
+	case doc.Header != nil:
+		header = doc.Header
+	case doc.Block != nil && doc.Block.Header != nil:
+		header = doc.Block.Header
+	case doc.SignedHeader != nil && doc.SignedHeader.Header != nil:
+		header = doc.SignedHeader.Header
+	case doc.Result != nil && doc.Result.SignedHeader != nil && doc.Result.SignedHeader.Header != nil:
+		header = doc.Result.SignedHeader.Header
+	}
+	if header.AppHash == "" {
+		return nil, 0, fmt.Errorf("header has no app hash")
+	}
+
+	// Heights are strings in CometBFT JSON and numbers elsewhere
+	var heightStr string
+	if err := json.Unmarshal(header.Height, &heightStr); err != nil {
+		heightStr = string(header.Height)
+	}
+	height, err := strconv.ParseInt(heightStr, 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid header height: %w", err)
+	}
+
+	appHash, err := decodeAppHash(header.AppHash)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return appHash, height, nil
+}
+
+// decodeAppHash decodes an app hash in hex, as CometBFT prints it, or base64. The app
+// hash of the multistore is a SHA-256 hash, so any other length is rejected.
+func decodeAppHash(s string) ([]byte, error) {
+	if appHash, err := hex.DecodeString(s); err == nil && len(appHash) == sha256.Size {
+		return appHash, nil
+	}
+	if appHash, err := base64.StdEncoding.DecodeString(s); err == nil && len(appHash) == sha256.Size {
+		return appHash, nil
+	}
+	return nil, fmt.Errorf("invalid app hash %q, expected %d bytes in hex or base64", s, sha256.Size)
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testAppHash = "5E3B9A0C1D2E3F405162738495A6B7C8D9EAFB0C1D2E3F405162738495A6B7C8"
+
+func TestParseTrustedHeader(t *testing.T) {
+	appHash, err := hex.DecodeString(testAppHash)
+	require.NoError(t, err)
+
+	header := `{"height":"1201","app_hash":"` + testAppHash + `"}`
+
+	tests := []struct {
+		name   string
+		json   string
+		height int64
+	}{
+		{"bare header", header, 1201},
+		{"number height", `{"height":1201,"app_hash":"` + testAppHash + `"}`, 1201},
+		{"base64 app hash", `{"height":"1201","app_hash":"` + base64.StdEncoding.EncodeToString(appHash) + `"}`, 1201},
+		{"wrapped header", `{"header":` + header + `}`, 1201},
+		{"block", `{"block":{"header":` + header + `}}`, 1201},
+		{"signed header", `{"signed_header":{"header":` + header + `}}`, 1201},
+		{"commit rpc", `{"jsonrpc":"2.0","result":{"signed_header":{"header":` + header + `},"canonical":true}}`, 1201},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gotHash, height, err := parseTrustedHeader([]byte(tc.json))
+			require.NoError(t, err)
+			require.Equal(t, appHash, gotHash)
+			require.Equal(t, tc.height, height)
+		})
+	}
+}
+
+func TestParseTrustedHeaderRejectsMalformedHeaders(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		err  string
+	}{
+		{"not json", `app_hash: ` + testAppHash, "invalid header"},
+		{"truncated", `{"header":{"height":"1201","app_hash":"` + testAppHash, "invalid header"},
+		{"wrong type", `{"height":"1201","app_hash":42}`, "invalid header"},
+		{"empty", `{}`, "no app hash"},
+		{"no app hash", `{"header":{"height":"1201"}}`, "no app hash"},
+		{"empty signed header", `{"result":{"signed_header":{}}}`, "no app hash"},
+		{"no height", `{"app_hash":"` + testAppHash + `"}`, "invalid header height"},
+		{"invalid height", `{"height":"twelve","app_hash":"` + testAppHash + `"}`, "invalid header height"},
+		{"fractional height", `{"height":1201.5,"app_hash":"` + testAppHash + `"}`, "invalid header height"},
+		{"invalid app hash", `{"height":"1201","app_hash":"not a hash"}`, "invalid app hash"},
+		{"short app hash", `{"height":"1201","app_hash":"` + testAppHash[:40] + `"}`, "invalid app hash"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := parseTrustedHeader([]byte(tc.json))
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestDecodeAppHash(t *testing.T) {
+	appHash, err := hex.DecodeString(testAppHash)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		input string
+		valid bool
+	}{
+		{"upper case hex", testAppHash, true},
+		{"lower case hex", strings.ToLower(testAppHash), true},
+		{"base64", base64.StdEncoding.EncodeToString(appHash), true},
+		{"empty", "", false},
+		{"not encoded", "trusted app hash", false},
+		{"odd length hex", testAppHash[:63], false},
+		{"short hex", testAppHash[:40], false},
+		{"long hex", testAppHash + "00", false},
+		{"short base64", base64.StdEncoding.EncodeToString(appHash[:20]), false},
+		{"long base64", base64.StdEncoding.EncodeToString(append(appHash, 0)), false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := decodeAppHash(tc.input)
+			if !tc.valid {
+				require.ErrorContains(t, err, "invalid app hash")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, appHash, got)
+		})
+	}
+}
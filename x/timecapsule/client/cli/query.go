@@ -1,15 +1,23 @@
 package cli
 
 import (
+	"bufio"
 	"context"
+	"encoding/hex"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/client/input"
 	
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
 	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
 )
 
@@ -30,10 +38,29 @@ func GetQueryCmd() *cobra.Command {
 		CmdQueryUserCapsules(),
 		CmdQueryCapsulesByType(),
 		CmdQueryCapsulesByStatus(),
+		CmdQueryCapsulesByTag(),
 		CmdQueryStats(),
 		CmdQueryKeyShares(),
 		CmdQueryConditionContract(),
 		CmdQueryConditionContracts(),
+		CmdQueryTimelockEpoch(),
+		CmdQueryEmergencyAction(),
+		CmdQueryCapsuleEmergencyActions(),
+		CmdQueryAccessLog(),
+		CmdQueryCapsuleVersions(),
+		CmdQueryShareHealth(),
+		CmdRecipientKey(),
+		CmdOpenDelivery(),
+		CmdQueryReveal(),
+		CmdQueryCapsuleProof(),
+		CmdVerifyCapsuleProof(),
+		CmdQueryCapsuleAllowance(),
+		CmdQueryCapsuleAllowances(),
+		CmdQueryAttestor(),
+		CmdQueryAttestors(),
+		CmdQueryCapsuleAttestations(),
+		CmdWatchCapsules(),
+		CmdArchive(),
 	)
 
 	return cmd
@@ -161,7 +188,7 @@ func CmdQueryCapsulesByType() *cobra.Command {
 		Short: "Query capsules filtered by type",
 		Long: `Query capsules filtered by type.
 
-Valid types: safe, time_lock, conditional, multi_sig, dead_mans_switch`,
+Valid types: safe, time_lock, conditional, multi_sig, dead_mans_switch, public_reveal`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			clientCtx, err := client.GetClientQueryContext(cmd)
@@ -226,6 +253,84 @@ Valid statuses: active, unlocked, expired, cancelled`,
 	return cmd
 }
 
+// CmdQueryCapsulesByTag implements the capsules-by-tag query command
+func CmdQueryCapsulesByTag() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "capsules-by-tag [owner] [tag]",
+		Short: "Query an owner's capsules by tag or indexed metadata",
+		Long: `Query an owner's capsules carrying a tag, or an entry of a metadata key indexed
+by the module parameters, filtered by status and unlock time. The unlock time range
+includes --unlock-after and excludes --unlock-before.
+
+Examples:
+$ simd query timecapsule capsules-by-tag cosmos1... family \
+  --status=active \
+  --unlock-after="2030-01-01T00:00:00Z" \
+  --unlock-before="2031-01-01T00:00:00Z"
+
+$ simd query timecapsule capsules-by-tag cosmos1... --metadata=category=letters`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			req := &types.QueryCapsulesByTagRequest{
+				Owner: args[0],
+			}
+			if len(args) > 1 {
+				req.Tag = args[1]
+			}
+
+			if metadata, _ := cmd.Flags().GetString("metadata"); metadata != "" {
+				key, value, ok := strings.Cut(metadata, types.MetadataIndexSeparator)
+				if !ok {
+					return fmt.Errorf("invalid metadata filter %q, use key=value", metadata)
+				}
+				req.MetadataKey, req.MetadataValue = key, value
+			}
+
+			if statusStr, _ := cmd.Flags().GetString("status"); statusStr != "" {
+				req.Status, err = parseCapsuleStatus(statusStr)
+				if err != nil {
+					return err
+				}
+			}
+
+			req.UnlockAfter, err = parseTimeFlag(cmd, "unlock-after")
+			if err != nil {
+				return err
+			}
+			req.UnlockBefore, err = parseTimeFlag(cmd, "unlock-before")
+			if err != nil {
+				return err
+			}
+
+			req.Pagination, err = client.ReadPageRequest(cmd.Flags())
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.CapsulesByTag(context.Background(), req)
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	cmd.Flags().String("metadata", "", "Indexed metadata entry the capsules carry, as key=value")
+	cmd.Flags().String("status", "", "Only capsules with this status: active, unlocked, expired or cancelled")
+	cmd.Flags().String("unlock-after", "", "Only capsules unlocking at or after this time, in RFC3339 format")
+	cmd.Flags().String("unlock-before", "", "Only capsules unlocking before this time, in RFC3339 format")
+	flags.AddQueryFlagsToCmd(cmd)
+	flags.AddPaginationFlagsToCmd(cmd, "capsules-by-tag")
+	return cmd
+}
+
 // CmdQueryStats implements the stats query command
 func CmdQueryStats() *cobra.Command {
 	cmd := &cobra.Command{
@@ -341,8 +446,541 @@ func CmdQueryConditionContracts() *cobra.Command {
 	return cmd
 }
 
+// CmdQueryTimelockEpoch implements the timelock epoch query command
+func CmdQueryTimelockEpoch() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "timelock-epoch [epoch]",
+		Short: "Query a timelock epoch key, including its secret once released",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			epoch, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid epoch: %w", err)
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.TimelockEpoch(context.Background(), &types.QueryTimelockEpochRequest{
+				Epoch: epoch,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryEmergencyAction implements the emergency action query command
+func CmdQueryEmergencyAction() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "emergency-action [action-id]",
+		Short: "Query a guardian emergency action",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.EmergencyAction(context.Background(), &types.QueryEmergencyActionRequest{
+				ActionId: args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryCapsuleEmergencyActions implements the capsule emergency actions query command
+func CmdQueryCapsuleEmergencyActions() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "emergency-actions [capsule-id]",
+		Short: "Query the emergency actions proposed for a capsule",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.CapsuleEmergencyActions(context.Background(), &types.QueryCapsuleEmergencyActionsRequest{
+				CapsuleId: capsuleID,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryAccessLog implements the capsule access log query command
+func CmdQueryAccessLog() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "access-log [capsule-id]",
+		Short: "Query who attempted to open a capsule and when",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.AccessLog(context.Background(), &types.QueryAccessLogRequest{
+				CapsuleId: capsuleID,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryCapsuleVersions implements the capsule version history query command
+func CmdQueryCapsuleVersions() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "capsule-versions [capsule-id]",
+		Short: "Query the version history of a capsule",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.CapsuleVersions(context.Background(), &types.QueryCapsuleVersionsRequest{
+				CapsuleId: capsuleID,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryShareHealth implements the key share health query command
+func CmdQueryShareHealth() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "share-health [capsule-id]",
+		Short: "Query how many key shares of capsules are held by live custodians",
+		Long:  "Query the live key share margin of a capsule, or of every active capsule when no ID is given",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			var capsuleID uint64
+			if len(args) > 0 {
+				capsuleID, err = strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid capsule ID: %w", err)
+				}
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.ShareHealth(context.Background(), &types.QueryShareHealthRequest{
+				CapsuleId: capsuleID,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdRecipientKey prints the public key capsules are delivered to
+func CmdRecipientKey() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recipient-key",
+		Short: "Show the public key capsules can be delivered to",
+		Long: `Show the public key of the local recipient key file, generating the key if the
+file does not exist yet. Give it to capsule owners as --recipient-key.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx := client.GetClientContextFromCmd(cmd)
+
+			keyFile, _ := cmd.Flags().GetString("key-file")
+			if keyFile == "" {
+				keyFile = filepath.Join(clientCtx.HomeDir, types.DefaultRecipientKeyFile)
+			}
+
+			_, pubKey, err := crypto.LoadOrGenerateTimelockKey(keyFile)
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintString(hex.EncodeToString(pubKey) + "\n")
+		},
+	}
+
+	cmd.Flags().String("key-file", "", "Path to the recipient key file (default: <home>/"+types.DefaultRecipientKeyFile+")")
+	return cmd
+}
+
+// CmdOpenDelivery decrypts a capsule delivered to the local recipient key
+func CmdOpenDelivery() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "open-delivery [capsule-id] [output-file]",
+		Short: "Decrypt a capsule delivered to your recipient key",
+		Long: `Decrypt an opened capsule that was delivered to your recipient key and write its
+data to the output file. Capsules stored on IPFS need their ciphertext passed with
+--data-file. Capsules protected by a passphrase prompt for the passphrase the owner
+shared with you.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			keyFile, _ := cmd.Flags().GetString("key-file")
+			if keyFile == "" {
+				keyFile = filepath.Join(clientCtx.HomeDir, types.DefaultRecipientKeyFile)
+			}
+			privKey, _, err := crypto.LoadTimelockKey(keyFile)
+			if err != nil {
+				return fmt.Errorf("failed to load recipient key: %w", err)
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.Capsule(context.Background(), &types.QueryCapsuleRequest{
+				CapsuleId: capsuleID,
+			})
+			if err != nil {
+				return err
+			}
+
+			capsule := res.Capsule
+			if capsule.Delivery == nil {
+				return fmt.Errorf("capsule %d has not been delivered", capsuleID)
+			}
+			if capsule.IsPurged() {
+				return fmt.Errorf("capsule %d data was purged at height %d (%s)", capsuleID, capsule.Tombstone.PurgedHeight, capsule.Tombstone.Reason)
+			}
+
+			ciphertext := capsule.EncryptedData
+			if dataFile, _ := cmd.Flags().GetString("data-file"); dataFile != "" {
+				ciphertext, err = os.ReadFile(dataFile)
+				if err != nil {
+					return fmt.Errorf("failed to read data file: %w", err)
+				}
+			}
+			if len(ciphertext) == 0 {
+				return fmt.Errorf("capsule data is stored on IPFS (%s), pass it with --data-file", capsule.IPFSHash)
+			}
+
+			passphrase, err := readCapsulePassphrase(clientCtx, capsule)
+			if err != nil {
+				return err
+			}
+
+			encryptionKey, err := capsule.OpenDeliveredKey(privKey, passphrase)
+			if err != nil {
+				return err
+			}
+			defer crypto.WipeKey(encryptionKey)
+
+			// Decompressed data is bounded by the maximum data size, like on-chain
+			params, err := queryClient.Params(context.Background(), &types.QueryParamsRequest{})
+			if err != nil {
+				return err
+			}
+
+			data, err := crypto.NewEncryptionManager().DecryptAndDecompress(&crypto.EncryptedData{
+				Data:        ciphertext,
+				Nonce:       capsule.DataNonce,
+				Algorithm:   capsule.EncryptionAlgo,
+				Compression: capsule.Compression,
+			}, encryptionKey, params.Params.MaxDataSize)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt data: %w", err)
+			}
+
+			if !crypto.VerifyDataIntegrity(data, capsule.DataHash) {
+				return fmt.Errorf("data integrity check failed")
+			}
+
+			if err := os.WriteFile(args[1], data, 0o600); err != nil {
+				return err
+			}
+
+			return clientCtx.PrintString(fmt.Sprintf("Wrote %d bytes of capsule %d to %s\n", len(data), capsuleID, args[1]))
+		},
+	}
+
+	cmd.Flags().String("key-file", "", "Path to the recipient key file (default: <home>/"+types.DefaultRecipientKeyFile+")")
+	cmd.Flags().String("data-file", "", "Encrypted capsule data fetched from IPFS")
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryReveal implements the public reveal query command
+func CmdQueryReveal() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reveal [capsule-id]",
+		Short: "Query the revealed content of a public reveal capsule and its commitment block",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.Reveal(context.Background(), &types.QueryRevealRequest{
+				CapsuleId: capsuleID,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryCapsuleAllowance implements the capsule allowance query command
+func CmdQueryCapsuleAllowance() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "capsule-allowance [sponsor] [grantee]",
+		Short: "Query the capsule allowance a sponsor granted to a grantee",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.CapsuleAllowance(context.Background(), &types.QueryCapsuleAllowanceRequest{
+				Sponsor: args[0],
+				Grantee: args[1],
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryCapsuleAllowances implements the capsule allowances query command
+func CmdQueryCapsuleAllowances() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "capsule-allowances [sponsor]",
+		Short: "Query all capsule allowances granted by a sponsor",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.CapsuleAllowances(context.Background(), &types.QueryCapsuleAllowancesRequest{
+				Sponsor: args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryAttestor returns a CLI command for querying a registered attestor
+func CmdQueryAttestor() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attestor [address]",
+		Short: "Query a registered attestor",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.Attestor(context.Background(), &types.QueryAttestorRequest{
+				Address: args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryAttestors returns a CLI command for querying the registered attestors
+func CmdQueryAttestors() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attestors",
+		Short: "Query the registered attestors",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			curatedOnly, _ := cmd.Flags().GetBool("curated")
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.Attestors(context.Background(), &types.QueryAttestorsRequest{
+				CuratedOnly: curatedOnly,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	cmd.Flags().Bool("curated", false, "Only query the attestors curated by governance")
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdQueryCapsuleAttestations returns a CLI command for querying the attestations of a capsule
+func CmdQueryCapsuleAttestations() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "capsule-attestations [capsule-id]",
+		Short: "Query the attestation condition of a capsule and the attestations submitted for it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			queryClient := types.NewQueryClient(clientCtx)
+			res, err := queryClient.CapsuleAttestations(context.Background(), &types.QueryCapsuleAttestationsRequest{
+				CapsuleId: capsuleID,
+			})
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	flags.AddQueryFlagsToCmd(cmd)
+	return cmd
+}
+
 // Helper functions
 
+// parseTimeFlag parses an optional RFC3339 time flag
+func parseTimeFlag(cmd *cobra.Command, name string) (*time.Time, error) {
+	value, _ := cmd.Flags().GetString(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s format (use RFC3339): %w", name, err)
+	}
+	return &parsed, nil
+}
+
+// readCapsulePassphrase prompts for the passphrase of a capsule protected by a
+// passphrase factor. Capsules without one need no passphrase.
+func readCapsulePassphrase(clientCtx client.Context, capsule *types.TimeCapsule) (string, error) {
+	if capsule.PassphraseFactor == nil {
+		return "", nil
+	}
+	buf := bufio.NewReader(clientCtx.Input)
+	return input.GetPassword(fmt.Sprintf("Enter the passphrase of capsule %d:", capsule.ID), buf)
+}
+
 func parseCapsuleStatus(statusStr string) (types.CapsuleStatus, error) {
 	switch statusStr {
 	case "active":
@@ -1,10 +1,14 @@
 package cli
 
 import (
+	"bufio"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -12,9 +16,12 @@ import (
 	"cosmossdk.io/core/address"
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/client/input"
 	"github.com/cosmos/cosmos-sdk/client/tx"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
 	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
 )
 
@@ -36,6 +43,24 @@ func GetTxCmd(ac address.Codec) *cobra.Command {
 		CmdTransferCapsule(ac),
 		CmdBatchTransferCapsules(ac),
 		CmdApproveTransfer(ac),
+		CmdRegisterTimelockKey(ac),
+		CmdProposeEmergencyAction(ac),
+		CmdApproveEmergencyAction(ac),
+		CmdVetoEmergencyAction(ac),
+		CmdExecuteEmergencyAction(ac),
+		CmdReverseEmergencyAction(ac),
+		CmdAmendCapsule(ac),
+		CmdRotateRecipient(ac),
+		CmdCommitCapsule(ac),
+		CmdRevealCapsule(ac),
+		CmdGrantCapsuleAllowance(ac),
+		CmdRevokeCapsuleAllowance(ac),
+		CmdRegisterAttestor(ac),
+		CmdAttest(ac),
+		CmdSignAttestation(ac),
+		CmdSubmitAttestation(ac),
+		CmdRevokeCapsuleAttestor(ac),
+		CmdEraseCapsule(ac),
 	)
 
 	return cmd
@@ -50,15 +75,48 @@ func CmdCreateCapsule(ac address.Codec) *cobra.Command {
 
 Capsule types:
 - safe: Basic secure storage
-- time_lock: Unlocks at specific time
-- conditional: Unlocks based on conditions
-- multi_sig: Requires multiple signatures
+- time_lock: Unlocks at a specific time, block height (--unlock-height) or epoch (--unlock-epoch)
+- conditional: Unlocks based on a condition contract, or once a threshold of
+  registered attestors (--attestors) attest a claim (--attestation-claim)
+- multi_sig: Owned by an x/group policy (--group-policy), opened, transferred and
+  amended by group proposals
 - dead_mans_switch: Unlocks after inactivity period
 
 Example:
 $ simd tx timecapsule create-capsule ./data.json time_lock 2 3 \
   --unlock-time="2025-12-31T23:59:59Z" \
   --recipient="cosmos1..." \
+  --from=alice
+
+$ simd tx timecapsule create-capsule ./data.json time_lock 2 3 \
+  --unlock-epoch="upgrade:v2:10" \
+  --recipient="cosmos1..." \
+  --from=alice
+
+$ simd tx timecapsule create-capsule ./archive.tar safe 2 3 \
+  --compression=zstd \
+  --from=alice
+
+$ simd tx timecapsule create-capsule ./will.pdf safe 2 3 \
+  --sponsor="cosmos1..." \
+  --from=employee
+
+$ simd tx timecapsule create-capsule ./treasury-keys.json multi_sig 2 3 \
+  --group-policy="cosmos1..." \
+  --from=alice
+
+$ simd tx timecapsule create-capsule ./will.pdf conditional 2 3 \
+  --attestors="cosmos1notary...,cosmos1registry..." \
+  --attestation-threshold=2 \
+  --attestation-claim="death certificate of the owner issued" \
+  --recipient="cosmos1..." \
+  --from=alice
+
+$ simd tx timecapsule create-capsule ./will.pdf time_lock 2 3 \
+  --unlock-time="2040-01-01T00:00:00Z" \
+  --recipient="cosmos1..." \
+  --recipient-key=02a1... \
+  --passphrase \
   --from=alice`,
 		Args: cobra.ExactArgs(4),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -99,11 +157,49 @@ $ simd tx timecapsule create-capsule ./data.json time_lock 2 3 \
 			// Get flags
 			recipient, _ := cmd.Flags().GetString("recipient")
 			unlockTimeStr, _ := cmd.Flags().GetString("unlock-time")
+			unlockHeight, _ := cmd.Flags().GetInt64("unlock-height")
+			unlockEpochStr, _ := cmd.Flags().GetString("unlock-epoch")
 			conditionContract, _ := cmd.Flags().GetString("condition-contract")
 			requiredSigs, _ := cmd.Flags().GetUint32("required-sigs")
 			inactivityPeriod, _ := cmd.Flags().GetUint64("inactivity-period")
 			title, _ := cmd.Flags().GetString("title")
 			description, _ := cmd.Flags().GetString("description")
+			escrowStr, _ := cmd.Flags().GetString("escrow")
+			beneficiariesStr, _ := cmd.Flags().GetStringSlice("beneficiaries")
+			guardians, _ := cmd.Flags().GetStringSlice("guardians")
+			guardianThreshold, _ := cmd.Flags().GetUint32("guardian-threshold")
+			versionPolicy, _ := cmd.Flags().GetString("version-policy")
+			recipientKeyStr, _ := cmd.Flags().GetString("recipient-key")
+			executor, _ := cmd.Flags().GetString("executor")
+			compression, _ := cmd.Flags().GetString("compression")
+			sponsor, _ := cmd.Flags().GetString("sponsor")
+			groupPolicy, _ := cmd.Flags().GetString("group-policy")
+			attestors, _ := cmd.Flags().GetStringSlice("attestors")
+			attestationThreshold, _ := cmd.Flags().GetUint32("attestation-threshold")
+			attestationClaim, _ := cmd.Flags().GetString("attestation-claim")
+			curatedAttestors, _ := cmd.Flags().GetBool("curated-attestors")
+			expiresAtStr, _ := cmd.Flags().GetString("expires-at")
+			tags, _ := cmd.Flags().GetStringSlice("tags")
+			usePassphrase, _ := cmd.Flags().GetBool("passphrase")
+
+			recipientKey, err := hex.DecodeString(recipientKeyStr)
+			if err != nil {
+				return fmt.Errorf("invalid recipient key: %w", err)
+			}
+
+			// Parse escrow and beneficiary split if provided
+			var escrow sdk.Coins
+			if escrowStr != "" {
+				escrow, err = sdk.ParseCoinsNormalized(escrowStr)
+				if err != nil {
+					return fmt.Errorf("invalid escrow: %w", err)
+				}
+			}
+
+			beneficiaries, err := parseBeneficiaries(beneficiariesStr)
+			if err != nil {
+				return err
+			}
 
 			// Parse unlock time if provided
 			var unlockTime *time.Time
@@ -115,6 +211,25 @@ $ simd tx timecapsule create-capsule ./data.json time_lock 2 3 \
 				unlockTime = &parsedTime
 			}
 
+			// Parse expiry if provided
+			var expiresAt *time.Time
+			if expiresAtStr != "" {
+				parsedTime, err := time.Parse(time.RFC3339, expiresAtStr)
+				if err != nil {
+					return fmt.Errorf("invalid expiry format (use RFC3339): %w", err)
+				}
+				expiresAt = &parsedTime
+			}
+
+			// Parse unlock epoch if provided
+			var unlockEpoch *types.EpochTrigger
+			if unlockEpochStr != "" {
+				unlockEpoch, err = parseEpochTrigger(unlockEpochStr)
+				if err != nil {
+					return err
+				}
+			}
+
 			// Create message
 			msg := &types.MsgCreateCapsule{
 				Creator:           clientCtx.GetFromAddress().String(),
@@ -124,11 +239,43 @@ $ simd tx timecapsule create-capsule ./data.json time_lock 2 3 \
 				Threshold:         uint32(threshold),
 				TotalShares:       uint32(totalShares),
 				UnlockTime:        unlockTime,
+				UnlockHeight:      unlockHeight,
+				UnlockEpoch:       unlockEpoch,
 				ConditionContract: conditionContract,
 				RequiredSigs:      requiredSigs,
 				InactivityPeriod:  inactivityPeriod,
 				Title:             title,
 				Description:       description,
+				Escrow:            escrow,
+				Beneficiaries:     beneficiaries,
+				Guardians:         guardians,
+				GuardianThreshold: guardianThreshold,
+				VersionPolicy:     versionPolicy,
+				RecipientKey:      recipientKey,
+				Executor:          executor,
+				Compression:       compression,
+				Sponsor:           sponsor,
+				GroupPolicy:       groupPolicy,
+				ExpiresAt:         expiresAt,
+				Tags:              tags,
+			}
+
+			// Unlock a conditional capsule on the attestors' claim
+			if len(attestors) > 0 {
+				msg.Attestation = &types.AttestationCondition{
+					Attestors:   attestors,
+					Threshold:   attestationThreshold,
+					Claim:       attestationClaim,
+					CuratedOnly: curatedAttestors,
+				}
+			}
+
+			// Protect the delivery with a passphrase shared out-of-band
+			if usePassphrase {
+				msg.PassphraseFactor, err = readPassphraseFactor(clientCtx)
+				if err != nil {
+					return err
+				}
 			}
 
 			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
@@ -137,11 +284,30 @@ $ simd tx timecapsule create-capsule ./data.json time_lock 2 3 \
 
 	cmd.Flags().String("recipient", "", "Recipient address for the capsule")
 	cmd.Flags().String("unlock-time", "", "Unlock time in RFC3339 format (e.g., 2025-12-31T23:59:59Z)")
+	cmd.Flags().Int64("unlock-height", 0, "Block height a time-locked capsule unlocks at, instead of a time")
+	cmd.Flags().String("unlock-epoch", "", "Epoch a time-locked capsule unlocks at as source:identifier:epochs (e.g., upgrade:v2:10)")
 	cmd.Flags().String("condition-contract", "", "Address of the condition contract")
 	cmd.Flags().Uint32("required-sigs", 0, "Required signatures for multi-sig capsules")
 	cmd.Flags().Uint64("inactivity-period", 0, "Inactivity period in seconds for dead man's switch")
 	cmd.Flags().String("title", "", "Capsule title")
 	cmd.Flags().String("description", "", "Capsule description")
+	cmd.Flags().String("escrow", "", "Tokens to lock with the capsule and release on unlock (e.g., '1000stake')")
+	cmd.Flags().StringSlice("beneficiaries", []string{}, "Escrow split as address:basis-points pairs summing to 10000")
+	cmd.Flags().StringSlice("guardians", []string{}, "Guardian addresses that approve emergency actions")
+	cmd.Flags().Uint32("guardian-threshold", 0, "Guardian approvals required for an emergency action")
+	cmd.Flags().String("version-policy", types.VersionPolicyLatest, "Versions the recipient receives after amendments: latest or all")
+	cmd.Flags().String("recipient-key", "", "Hex public key the capsule is delivered to on open (see 'query timecapsule recipient-key')")
+	cmd.Flags().String("executor", "", "Address allowed to rotate the recipient besides the creator")
+	cmd.Flags().String("compression", "", "Compress the data before encryption: gzip or zstd")
+	cmd.Flags().String("sponsor", "", "Sponsor paying the creation and storage fees under its capsule allowance")
+	cmd.Flags().String("group-policy", "", "Group policy owning a multi_sig capsule, which acts on it through group proposals")
+	cmd.Flags().StringSlice("attestors", []string{}, "Registered attestors whose attestations unlock a conditional capsule")
+	cmd.Flags().Uint32("attestation-threshold", 0, "Attestations required to unlock the capsule")
+	cmd.Flags().String("attestation-claim", "", "Claim the attestors attest, e.g. 'death certificate of the owner issued'")
+	cmd.Flags().Bool("curated-attestors", false, "Only accept attestors curated by governance")
+	cmd.Flags().String("expires-at", "", "Time the capsule expires unopened and its data is purged, in RFC3339 format")
+	cmd.Flags().Bool("passphrase", false, "Also protect the delivery to --recipient-key with a passphrase, prompted for and confirmed")
+	cmd.Flags().StringSlice("tags", []string{}, "Tags to find the capsule by (see 'query timecapsule capsules-by-tag')")
 	
 	flags.AddTxFlagsToCmd(cmd)
 
@@ -313,6 +479,211 @@ func CmdTransferCapsule(ac address.Codec) *cobra.Command {
 	return cmd
 }
 
+// CmdRegisterTimelockKey returns a CLI command for a validator to register its timelock key
+func CmdRegisterTimelockKey(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "register-timelock-key",
+		Short: "Register the validator key used for timelock key generation",
+		Long: `Register the key the validator receives timelock key-generation shares with.
+The key is read from --key-file, or generated there if the file does not exist yet.
+The node must be started with the same key file to take part in key generation.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			keyFile, _ := cmd.Flags().GetString("key-file")
+			if keyFile == "" {
+				keyFile = filepath.Join(clientCtx.HomeDir, types.DefaultTimelockKeyFile)
+			}
+
+			_, pubKey, err := crypto.LoadOrGenerateTimelockKey(keyFile)
+			if err != nil {
+				return err
+			}
+
+			msg := &types.MsgRegisterTimelockKey{
+				Validator: sdk.ValAddress(clientCtx.GetFromAddress()).String(),
+				PublicKey: pubKey,
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().String("key-file", "", "Path to the timelock key file (default: <home>/"+types.DefaultTimelockKeyFile+")")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdProposeEmergencyAction returns a CLI command for a guardian to propose an emergency action
+func CmdProposeEmergencyAction(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "propose-emergency-action [capsule-id] [action-type] [reason]",
+		Short: "Propose a guardian emergency action on a capsule",
+		Long: `Propose an emergency action as one of the capsule's guardians. The proposal counts
+as your approval. Once the guardian threshold is reached the action can be executed
+after the emergency delay, unless the recipient or a guardian vetoes it.
+
+Action types:
+- contract_deletion: Remove the condition contract and waive the unlock conditions
+- force_unlock: Waive the unlock conditions
+- freeze: Block opening, cancelling and transferring the capsule
+- rotate_recipient: Replace the recipient (requires --new-recipient, optionally --new-recipient-key)
+
+Example:
+$ simd tx timecapsule propose-emergency-action 1 rotate_recipient "recipient key lost" \
+  --new-recipient="cosmos1..." \
+  --from=guardian`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			newRecipient, _ := cmd.Flags().GetString("new-recipient")
+			newRecipientKeyStr, _ := cmd.Flags().GetString("new-recipient-key")
+
+			newRecipientKey, err := hex.DecodeString(newRecipientKeyStr)
+			if err != nil {
+				return fmt.Errorf("invalid recipient key: %w", err)
+			}
+
+			msg := types.NewMsgProposeEmergencyAction(
+				clientCtx.GetFromAddress().String(),
+				capsuleID,
+				args[1],
+				args[2],
+				newRecipient,
+			)
+			msg.NewRecipientKey = newRecipientKey
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().String("new-recipient", "", "New recipient address for rotate_recipient actions")
+	cmd.Flags().String("new-recipient-key", "", "Hex delivery key of the new recipient for rotate_recipient actions")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdApproveEmergencyAction returns a CLI command for a guardian to approve an emergency action
+func CmdApproveEmergencyAction(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve-emergency-action [action-id]",
+		Short: "Approve a pending emergency action as a guardian",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			msg := &types.MsgApproveEmergencyAction{
+				Guardian: clientCtx.GetFromAddress().String(),
+				ActionID: args[0],
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdVetoEmergencyAction returns a CLI command for the recipient or a guardian to veto an emergency action
+func CmdVetoEmergencyAction(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "veto-emergency-action [action-id]",
+		Short: "Veto a pending emergency action as the recipient or a guardian",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			reason, _ := cmd.Flags().GetString("reason")
+
+			msg := &types.MsgVetoEmergencyAction{
+				Sender:   clientCtx.GetFromAddress().String(),
+				ActionID: args[0],
+				Reason:   reason,
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().String("reason", "", "Reason for the veto")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdExecuteEmergencyAction returns a CLI command for a guardian to execute an approved emergency action
+func CmdExecuteEmergencyAction(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "execute-emergency-action [action-id]",
+		Short: "Execute an approved emergency action once its delay has elapsed",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			msg := &types.MsgExecuteEmergencyAction{
+				Guardian: clientCtx.GetFromAddress().String(),
+				ActionID: args[0],
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdReverseEmergencyAction returns a CLI command for the recipient or a guardian to reverse an emergency action
+func CmdReverseEmergencyAction(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reverse-emergency-action [action-id]",
+		Short: "Reverse an executed emergency action as the recipient or a guardian",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			reason, _ := cmd.Flags().GetString("reason")
+
+			msg := &types.MsgReverseEmergencyAction{
+				Sender:   clientCtx.GetFromAddress().String(),
+				ActionID: args[0],
+				Reason:   reason,
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().String("reason", "", "Reason for the reversal")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
 // Helper functions
 
 func readDataFile(filename string) ([]byte, error) {
@@ -326,6 +697,65 @@ func readDataFile(filename string) ([]byte, error) {
 	return json.Marshal(dummyData)
 }
 
+// readPassphraseFactor prompts twice for a capsule passphrase and derives its factor.
+// The passphrase itself never leaves the client.
+func readPassphraseFactor(clientCtx client.Context) (*types.PassphraseFactor, error) {
+	buf := bufio.NewReader(clientCtx.Input)
+	passphrase, err := input.GetPassword("Enter a passphrase to protect the capsule:", buf)
+	if err != nil {
+		return nil, err
+	}
+	confirm, err := input.GetPassword("Repeat the passphrase:", buf)
+	if err != nil {
+		return nil, err
+	}
+	if passphrase != confirm {
+		return nil, fmt.Errorf("passphrases do not match")
+	}
+	return types.NewPassphraseFactor(passphrase)
+}
+
+func parseBeneficiaries(entries []string) ([]types.Beneficiary, error) {
+	var beneficiaries []types.Beneficiary
+	for _, entry := range entries {
+		parts := strings.Split(entry, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid beneficiary %q (use address:basis-points)", entry)
+		}
+
+		weight, err := strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid beneficiary weight %q: %w", parts[1], err)
+		}
+
+		beneficiaries = append(beneficiaries, types.Beneficiary{
+			Address: parts[0],
+			Weight:  uint32(weight),
+		})
+	}
+	return beneficiaries, nil
+}
+
+// parseEpochTrigger parses an epoch trigger given as source:identifier:epochs
+func parseEpochTrigger(value string) (*types.EpochTrigger, error) {
+	first := strings.Index(value, ":")
+	last := strings.LastIndex(value, ":")
+	if first < 0 || first == last {
+		return nil, fmt.Errorf("invalid unlock epoch %q (use source:identifier:epochs)", value)
+	}
+
+	epochs, err := strconv.ParseUint(value[last+1:], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid unlock epochs %q: %w", value[last+1:], err)
+	}
+
+	return &types.EpochTrigger{
+		Source:     value[:first],
+		Identifier: value[first+1 : last],
+		Epochs:     epochs,
+	}, nil
+}
+
 func parseCapsuleType(typeStr string) (types.CapsuleType, error) {
 	switch typeStr {
 	case "safe":
@@ -338,6 +768,8 @@ func parseCapsuleType(typeStr string) (types.CapsuleType, error) {
 		return types.CapsuleType_MULTI_SIG, nil
 	case "dead_mans_switch":
 		return types.CapsuleType_DEAD_MANS_SWITCH, nil
+	case "public_reveal":
+		return types.CapsuleType_PUBLIC_REVEAL, nil
 	default:
 		return types.CapsuleType_UNKNOWN, fmt.Errorf("unknown capsule type: %s", typeStr)
 	}
@@ -453,4 +885,596 @@ Example:
 
 	flags.AddTxFlagsToCmd(cmd)
 	return cmd
-}
\ No newline at end of file
+}
+
+// CmdAmendCapsule returns a CLI command for replacing the content of a capsule with a new version
+func CmdAmendCapsule(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "amend-capsule [capsule-id] [data-file]",
+		Short: "Amend the content of an active capsule",
+		Long: `Replace the content of an active capsule with a new version encrypted under a new key.
+Superseded versions stay hash-committed in the capsule history.
+
+Capsules with the "all" version policy also deliver superseded versions to the
+recipient; each superseded version keeps its own key shares or timelock key and
+opens with the capsule.
+
+Example:
+$ simd tx timecapsule amend-capsule 1 ./will-v2.json \
+  --title="Last will (revised)" \
+  --from=alice`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			data, err := readDataFile(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to read data file: %w", err)
+			}
+
+			title, _ := cmd.Flags().GetString("title")
+			description, _ := cmd.Flags().GetString("description")
+			tags, _ := cmd.Flags().GetStringSlice("tags")
+			compression, _ := cmd.Flags().GetString("compression")
+
+			msg := &types.MsgAmendCapsule{
+				Owner:       clientCtx.GetFromAddress().String(),
+				CapsuleID:   capsuleID,
+				Data:        data,
+				Title:       title,
+				Description: description,
+				Tags:        tags,
+				Compression: compression,
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().String("title", "", "New capsule title (kept if empty)")
+	cmd.Flags().String("description", "", "New capsule description (kept if empty)")
+	cmd.Flags().StringSlice("tags", []string{}, "New capsule tags (kept if empty)")
+	cmd.Flags().String("compression", "", "Compress the data before encryption: gzip or zstd")
+
+	flags.AddTxFlagsToCmd(cmd)
+
+	return cmd
+}
+
+// CmdRotateRecipient returns a CLI command for the owner or executor to re-target a capsule
+func CmdRotateRecipient(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate-recipient [capsule-id] [new-recipient]",
+		Short: "Re-target a capsule to a new recipient",
+		Long: `Re-target an active capsule to a new recipient as its owner or executor. With
+--recipient-key the capsule is delivered to the new recipient's key on open: the
+custodians re-encrypt the data key to it, so the plaintext is not needed. Without it,
+the capsule is no longer delivered to a recipient key.
+
+Example:
+$ simd tx timecapsule rotate-recipient 1 cosmos1... \
+  --recipient-key=02a1... \
+  --from=alice`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			recipientKeyStr, _ := cmd.Flags().GetString("recipient-key")
+			recipientKey, err := hex.DecodeString(recipientKeyStr)
+			if err != nil {
+				return fmt.Errorf("invalid recipient key: %w", err)
+			}
+
+			msg := types.NewMsgRotateRecipient(
+				clientCtx.GetFromAddress().String(),
+				capsuleID,
+				args[1],
+				recipientKey,
+			)
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().String("recipient-key", "", "Hex public key of the new recipient")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdCommitCapsule returns a CLI command for committing to content that is revealed publicly
+func CmdCommitCapsule(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "commit-capsule [data-file]",
+		Short: "Commit to content that anyone can reveal after the unlock time",
+		Long: `Create a public reveal capsule. Only a salted commitment to the content is
+published now; the content stays with you. After the unlock time anyone holding the
+content and salt can reveal it with reveal-capsule, and the chain checks it against
+the commitment.
+
+The salt is written to --salt-file (default: <data-file>.salt). Keep it with the
+content: the capsule cannot be revealed without it.
+
+Example:
+$ simd tx timecapsule commit-capsule ./prediction.txt \
+  --unlock-time="2026-12-31T23:59:59Z" \
+  --from=alice`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read data file: %w", err)
+			}
+
+			unlockTimeStr, _ := cmd.Flags().GetString("unlock-time")
+			unlockTime, err := time.Parse(time.RFC3339, unlockTimeStr)
+			if err != nil {
+				return fmt.Errorf("invalid unlock time format (use RFC3339): %w", err)
+			}
+
+			saltFile, _ := cmd.Flags().GetString("salt-file")
+			if saltFile == "" {
+				saltFile = args[0] + ".salt"
+			}
+			if _, err := os.Stat(saltFile); err == nil {
+				return fmt.Errorf("salt file %s already exists", saltFile)
+			}
+
+			salt, err := crypto.SecureRandom(crypto.RevealSaltSize)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(saltFile, []byte(hex.EncodeToString(salt)), 0o600); err != nil {
+				return fmt.Errorf("failed to write salt file: %w", err)
+			}
+
+			title, _ := cmd.Flags().GetString("title")
+			description, _ := cmd.Flags().GetString("description")
+			sponsor, _ := cmd.Flags().GetString("sponsor")
+			tags, _ := cmd.Flags().GetStringSlice("tags")
+
+			msg := &types.MsgCreateCapsule{
+				Creator:     clientCtx.GetFromAddress().String(),
+				CapsuleType: types.CapsuleType_PUBLIC_REVEAL,
+				UnlockTime:  &unlockTime,
+				Title:       title,
+				Description: description,
+				Commitment:  crypto.CommitContent(data, salt),
+				Sponsor:     sponsor,
+				Tags:        tags,
+			}
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().String("unlock-time", "", "Time from which the content can be revealed, in RFC3339 format")
+	cmd.Flags().String("salt-file", "", "File to write the commitment salt to (default: <data-file>.salt)")
+	cmd.Flags().String("title", "", "Capsule title")
+	cmd.Flags().String("description", "", "Capsule description")
+	cmd.Flags().String("sponsor", "", "Sponsor paying the creation fee under its capsule allowance")
+	cmd.Flags().StringSlice("tags", []string{}, "Tags to find the capsule by")
+	_ = cmd.MarkFlagRequired("unlock-time")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdRevealCapsule returns a CLI command for revealing the content of a public reveal capsule
+func CmdRevealCapsule(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reveal-capsule [capsule-id] [data-file]",
+		Short: "Reveal the content of a public reveal capsule",
+		Long: `Publish the content of a public reveal capsule after its unlock time. Anyone
+holding the content and its salt can reveal it.
+
+Example:
+$ simd tx timecapsule reveal-capsule 1 ./prediction.txt \
+  --salt-file=./prediction.txt.salt \
+  --from=bob`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			data, err := os.ReadFile(args[1])
+			if err != nil {
+				return fmt.Errorf("failed to read data file: %w", err)
+			}
+
+			saltFile, _ := cmd.Flags().GetString("salt-file")
+			if saltFile == "" {
+				saltFile = args[1] + ".salt"
+			}
+			saltHex, err := os.ReadFile(saltFile)
+			if err != nil {
+				return fmt.Errorf("failed to read salt file: %w", err)
+			}
+			salt, err := hex.DecodeString(strings.TrimSpace(string(saltHex)))
+			if err != nil {
+				return fmt.Errorf("invalid salt: %w", err)
+			}
+
+			msg := types.NewMsgRevealCapsule(clientCtx.GetFromAddress().String(), capsuleID, data, salt)
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().String("salt-file", "", "File holding the commitment salt (default: <data-file>.salt)")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdGrantCapsuleAllowance returns a CLI command for a sponsor to pay the capsule fees of a grantee
+func CmdGrantCapsuleAllowance(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "grant-capsule-allowance [grantee]",
+		Short: "Pay the creation and storage fees of the capsules a grantee creates",
+		Long: `Grant a capsule allowance: the grantee creates capsules with --sponsor set to
+your address, and you pay their creation and storage fees. The allowance covers at
+most --max-capsules capsules and --max-bytes stored bytes (0 for no limit), and
+at most --spend-limit in fees (no limit when unset), until --expiration. A new
+grant replaces the grantee's allowance and resets its usage.
+
+Example:
+$ simd tx timecapsule grant-capsule-allowance cosmos1... \
+  --max-capsules=10 \
+  --max-bytes=10485760 \
+  --spend-limit=1000000stake \
+  --expiration="2026-12-31T23:59:59Z" \
+  --from=company`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			maxCapsules, _ := cmd.Flags().GetUint32("max-capsules")
+			maxBytes, _ := cmd.Flags().GetUint64("max-bytes")
+			spendLimitStr, _ := cmd.Flags().GetString("spend-limit")
+			expirationStr, _ := cmd.Flags().GetString("expiration")
+
+			var spendLimit sdk.Coins
+			if spendLimitStr != "" {
+				spendLimit, err = sdk.ParseCoinsNormalized(spendLimitStr)
+				if err != nil {
+					return fmt.Errorf("invalid spend limit: %w", err)
+				}
+			}
+
+			var expiration *time.Time
+			if expirationStr != "" {
+				parsedTime, err := time.Parse(time.RFC3339, expirationStr)
+				if err != nil {
+					return fmt.Errorf("invalid expiration format (use RFC3339): %w", err)
+				}
+				expiration = &parsedTime
+			}
+
+			msg := types.NewMsgGrantCapsuleAllowance(
+				clientCtx.GetFromAddress().String(),
+				args[0],
+				maxCapsules,
+				maxBytes,
+				spendLimit,
+				expiration,
+			)
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().Uint32("max-capsules", 0, "Capsules the allowance pays for, 0 for no limit")
+	cmd.Flags().Uint64("max-bytes", 0, "Stored bytes the allowance pays for, 0 for no limit")
+	cmd.Flags().String("spend-limit", "", "Fees the allowance pays, no limit when unset")
+	cmd.Flags().String("expiration", "", "Time the allowance expires at, in RFC3339 format")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdRevokeCapsuleAllowance returns a CLI command for a sponsor to revoke the allowance of a grantee
+func CmdRevokeCapsuleAllowance(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revoke-capsule-allowance [grantee]",
+		Short: "Revoke the capsule allowance of a grantee",
+		Long: `Revoke the capsule allowance of a grantee. Capsules already created under the
+allowance are not affected.
+
+Example:
+$ simd tx timecapsule revoke-capsule-allowance cosmos1... --from=company`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgRevokeCapsuleAllowance(clientCtx.GetFromAddress().String(), args[0])
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdRegisterAttestor returns a CLI command for registering an attestor
+func CmdRegisterAttestor(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "register-attestor [description]",
+		Short: "Register as an attestor of capsule claims",
+		Long: `Register the sending account as an attestor, or update the key and description
+it registered before. Attestations are signed with the key of the sending account,
+unless another secp256k1 or ed25519 public key is given, e.g. the key of a notary's
+signing device.
+
+Example:
+$ simd tx timecapsule register-attestor "Civil registry of deaths, Springfield" --from=registry
+
+$ simd tx timecapsule register-attestor "Notary office Smith & Co" \
+  --key-type=ed25519 \
+  --pub-key=3b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da29 \
+  --from=notary`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			keyType, _ := cmd.Flags().GetString("key-type")
+			pubKeyStr, _ := cmd.Flags().GetString("pub-key")
+
+			var pubKey []byte
+			if pubKeyStr != "" {
+				pubKey, err = hex.DecodeString(pubKeyStr)
+				if err != nil {
+					return fmt.Errorf("invalid public key: %w", err)
+				}
+			} else {
+				// Sign attestations with the key of the sending account
+				record, err := clientCtx.Keyring.Key(clientCtx.FromName)
+				if err != nil {
+					return err
+				}
+				accountKey, err := record.GetPubKey()
+				if err != nil {
+					return err
+				}
+				keyType = accountKey.Type()
+				pubKey = accountKey.Bytes()
+			}
+
+			msg := types.NewMsgRegisterAttestor(clientCtx.GetFromAddress().String(), keyType, pubKey, args[0])
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	cmd.Flags().String("key-type", types.AttestorKeyTypeSecp256k1, "Type of --pub-key: secp256k1 or ed25519")
+	cmd.Flags().String("pub-key", "", "Hex public key attestations are signed with, instead of the account key")
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdAttest returns a CLI command for an attestor to sign and submit an attestation
+func CmdAttest(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attest [capsule-id] [claim]",
+		Short: "Attest the claim of a capsule with the key of the sending attestor",
+		Long: `Sign the claim of a capsule with the key of the sending account and submit the
+attestation. The claim must be the one the capsule's attestation condition names.
+
+Example:
+$ simd tx timecapsule attest 1 "death certificate of the owner issued" --from=registry`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			signature, err := signAttestation(clientCtx, capsuleID, args[1])
+			if err != nil {
+				return err
+			}
+
+			attestor := clientCtx.GetFromAddress().String()
+			msg := types.NewMsgSubmitAttestation(attestor, capsuleID, attestor, args[1], signature)
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdSignAttestation returns a CLI command for an attestor to sign an attestation offline
+func CmdSignAttestation(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign-attestation [capsule-id] [claim]",
+		Short: "Sign the claim of a capsule for another account to submit",
+		Long: `Sign the claim of a capsule with the key of the --from account and print the hex
+signature, without broadcasting anything. Anyone can then submit the attestation with
+'submit-attestation'. The signature is bound to the --chain-id.
+
+Example:
+$ simd tx timecapsule sign-attestation 1 "death certificate of the owner issued" \
+  --from=registry --chain-id=mychain`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			signature, err := signAttestation(clientCtx, capsuleID, args[1])
+			if err != nil {
+				return err
+			}
+
+			return clientCtx.PrintString(hex.EncodeToString(signature) + "\n")
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdSubmitAttestation returns a CLI command for relaying an attestation signed elsewhere
+func CmdSubmitAttestation(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "submit-attestation [capsule-id] [attestor] [claim] [signature]",
+		Short: "Submit an attestation an attestor signed",
+		Long: `Submit the hex signature an attestor made of the claim of a capsule, e.g. with
+'sign-attestation' or its own signing device. The submitter pays the fees, the
+attestor's signature proves the attestation.
+
+Example:
+$ simd tx timecapsule submit-attestation 1 cosmos1registry... \
+  "death certificate of the owner issued" 4f1c... --from=heir`,
+		Args: cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			signature, err := hex.DecodeString(args[3])
+			if err != nil {
+				return fmt.Errorf("invalid signature: %w", err)
+			}
+
+			msg := types.NewMsgSubmitAttestation(clientCtx.GetFromAddress().String(), capsuleID, args[1], args[2], signature)
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdRevokeCapsuleAttestor returns a CLI command for an owner to remove an attestor from a capsule
+func CmdRevokeCapsuleAttestor(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revoke-capsule-attestor [capsule-id] [attestor]",
+		Short: "Remove an attestor from the attestation condition of a capsule",
+		Long: `Remove an attestor from the attestation condition of a capsule and discard its
+attestation. A capsule left with fewer attestations than its threshold is locked again.
+
+Example:
+$ simd tx timecapsule revoke-capsule-attestor 1 cosmos1notary... --from=alice`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			msg := types.NewMsgRevokeCapsuleAttestor(clientCtx.GetFromAddress().String(), capsuleID, args[1])
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// CmdEraseCapsule returns a CLI command for an owner to purge the data of a finished capsule
+func CmdEraseCapsule(ac address.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "erase-capsule [capsule-id]",
+		Short: "Purge the data of an opened, cancelled or expired capsule",
+		Long: `Purge the data of an opened, cancelled or expired capsule before its retention
+period ends. The ciphertext and key shares are deleted and the purge event lists the
+IPFS content to unpin; a tombstone keeps the capsule's data hash and history. Cancel an active capsule first.
+
+Example:
+$ simd tx timecapsule erase-capsule 1 --from=alice`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientTxContext(cmd)
+			if err != nil {
+				return err
+			}
+
+			capsuleID, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid capsule ID: %w", err)
+			}
+
+			msg := types.NewMsgEraseCapsule(clientCtx.GetFromAddress().String(), capsuleID)
+
+			return tx.GenerateOrBroadcastTxCLI(clientCtx, cmd.Flags(), msg)
+		},
+	}
+
+	flags.AddTxFlagsToCmd(cmd)
+	return cmd
+}
+
+// signAttestation signs the claim of a capsule with the key of the --from account
+func signAttestation(clientCtx client.Context, capsuleID uint64, claim string) ([]byte, error) {
+	if clientCtx.ChainID == "" {
+		return nil, fmt.Errorf("--chain-id is required to sign an attestation")
+	}
+
+	signBytes := types.AttestationSignBytes(clientCtx.ChainID, capsuleID, claim)
+	signature, _, err := clientCtx.Keyring.Sign(clientCtx.FromName, signBytes, signing.SignMode_SIGN_MODE_DIRECT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign attestation: %w", err)
+	}
+	return signature, nil
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/codec"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/stream"
+)
+
+// CmdWatchCapsules streams capsule lifecycle events from the gRPC server of a node
+func CmdWatchCapsules() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Args:  cobra.NoArgs,
+		Short: "Stream the lifecycle events of capsules as blocks commit",
+		Long: `Stream the lifecycle events of the capsules with the given ids, owners or recipients,
+or of all capsules, as one JSON event per line. Event types are created, heartbeat,
+unlockable, opened, transferred and cancelled. Pass the cursor of the last received
+event to --cursor to resume the stream after a disconnect.
+
+The stream is served by the gRPC server of the node, set with --grpc-addr.
+
+Example:
+$ simd query timecapsule watch --recipients cosmos1... --event-types unlockable --grpc-addr localhost:9090 --grpc-insecure`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return err
+			}
+			if clientCtx.GRPCClient == nil {
+				return fmt.Errorf("watching capsules requires the gRPC server of a node, set --%s", flags.FlagGRPC)
+			}
+
+			req := &stream.WatchCapsulesRequest{}
+			capsuleIDs, err := cmd.Flags().GetUintSlice("capsule-ids")
+			if err != nil {
+				return err
+			}
+			for _, id := range capsuleIDs {
+				req.CapsuleIds = append(req.CapsuleIds, uint64(id))
+			}
+			if req.Owners, err = cmd.Flags().GetStringSlice("owners"); err != nil {
+				return err
+			}
+			if req.Recipients, err = cmd.Flags().GetStringSlice("recipients"); err != nil {
+				return err
+			}
+			eventTypes, err := cmd.Flags().GetStringSlice("event-types")
+			if err != nil {
+				return err
+			}
+			for _, name := range eventTypes {
+				eventType, err := stream.ParseCapsuleEventType(name)
+				if err != nil {
+					return err
+				}
+				req.EventTypes = append(req.EventTypes, eventType)
+			}
+			if req.Cursor, err = cmd.Flags().GetString("cursor"); err != nil {
+				return err
+			}
+			if req.StartHeight, err = cmd.Flags().GetInt64("start-height"); err != nil {
+				return err
+			}
+			if err := req.Validate(); err != nil {
+				return err
+			}
+
+			// The node serves the stream with the SDK codec, which encodes gogoproto messages
+			grpcCodec := codec.NewProtoCodec(clientCtx.InterfaceRegistry).GRPCCodec()
+			sub, err := stream.NewWatchClient(clientCtx.GRPCClient).WatchCapsules(cmd.Context(), req, grpc.ForceCodec(grpcCodec))
+			if err != nil {
+				return err
+			}
+
+			for {
+				event, err := sub.Recv()
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+
+				bz, err := json.Marshal(event)
+				if err != nil {
+					return err
+				}
+				if err := clientCtx.PrintBytes(bz); err != nil {
+					return err
+				}
+			}
+		},
+	}
+
+	cmd.Flags().UintSlice("capsule-ids", nil, "Capsule ids to watch")
+	cmd.Flags().StringSlice("owners", nil, "Watch the capsules of these owners, including the capsules they transfer away")
+	cmd.Flags().StringSlice("recipients", nil, "Watch the capsules of these recipients")
+	cmd.Flags().StringSlice("event-types", nil, "Only stream these event types (created, heartbeat, unlockable, opened, transferred, cancelled)")
+	cmd.Flags().String("cursor", "", "Resume the stream after the event with this cursor")
+	cmd.Flags().Int64("start-height", 0, "Replay the events from this height, if it is still retained by the node")
+	flags.AddQueryFlagsToCmd(cmd)
+
+	return cmd
+}
@@ -0,0 +1,48 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// Commitments of public reveal capsules. The salt hides low-entropy content such as a
+// prediction until it is revealed; it is length-prefixed so that no two (salt, data)
+// pairs hash alike.
+
+const revealDomain = "timecapsule/reveal/v1"
+
+// RevealSaltSize is the size of the salt generated for new commitments
+const RevealSaltSize = 32
+
+// MaxRevealSaltSize is the maximum size of a commitment salt
+const MaxRevealSaltSize = 64
+
+// CommitContent computes the hex commitment to salted content
+func CommitContent(data, salt []byte) string {
+	var saltLen [4]byte
+	binary.BigEndian.PutUint32(saltLen[:], uint32(len(salt)))
+
+	h := sha256.New()
+	h.Write([]byte(revealDomain))
+	h.Write(saltLen[:])
+	h.Write(salt)
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ValidateCommitment checks that a commitment is a hex-encoded SHA-256 digest
+func ValidateCommitment(commitment string) error {
+	bz, err := hex.DecodeString(commitment)
+	if err != nil {
+		return fmt.Errorf("commitment must be hex encoded: %w", err)
+	}
+	if len(bz) != sha256.Size {
+		return fmt.Errorf("commitment must be %d bytes, got %d", sha256.Size, len(bz))
+	}
+	if hex.EncodeToString(bz) != commitment {
+		return fmt.Errorf("commitment must be lowercase hex")
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package crypto
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression algorithms applied to capsule content before encryption
+const (
+	CompressionNone = ""
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// ValidateCompression checks that a compression algorithm is supported
+func ValidateCompression(algo string) error {
+	switch algo {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+		return nil
+	default:
+		return fmt.Errorf("unsupported compression: %s", algo)
+	}
+}
+
+// Compress compresses data with the given algorithm
+func Compress(data []byte, algo string) ([]byte, error) {
+	switch algo {
+	case CompressionNone:
+		return data, nil
+
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("failed to compress data: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress data: %w", err)
+		}
+		return buf.Bytes(), nil
+
+	case CompressionZstd:
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBetterCompression), zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		defer enc.Close()
+		return enc.EncodeAll(data, nil), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported compression: %s", algo)
+	}
+}
+
+// CompressIfSmaller compresses data, and returns it uncompressed when compression does
+// not make it smaller. It returns the algorithm the data ended up compressed with.
+func CompressIfSmaller(data []byte, algo string) ([]byte, string, error) {
+	if algo == CompressionNone {
+		return data, CompressionNone, nil
+	}
+
+	compressed, err := Compress(data, algo)
+	if err != nil {
+		return nil, CompressionNone, err
+	}
+	if len(compressed) >= len(data) {
+		return data, CompressionNone, nil
+	}
+
+	return compressed, algo, nil
+}
+
+// Decompress decompresses data with the given algorithm. Output larger than maxSize
+// bytes is rejected, so a small ciphertext cannot expand into a decompression bomb.
+func Decompress(data []byte, algo string, maxSize uint64) ([]byte, error) {
+	var r io.Reader
+	switch algo {
+	case CompressionNone:
+		if uint64(len(data)) > maxSize {
+			return nil, fmt.Errorf("data size %d exceeds maximum %d", len(data), maxSize)
+		}
+		return data, nil
+
+	case CompressionGzip:
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress data: %w", err)
+		}
+		defer gr.Close()
+		r = gr
+
+	case CompressionZstd:
+		dec, err := zstd.NewReader(bytes.NewReader(data), zstd.WithDecoderMaxMemory(maxSize), zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress data: %w", err)
+		}
+		defer dec.Close()
+		r = dec
+
+	default:
+		return nil, fmt.Errorf("unsupported compression: %s", algo)
+	}
+
+	out, err := io.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress data: %w", err)
+	}
+	if uint64(len(out)) > maxSize {
+		return nil, fmt.Errorf("decompressed data exceeds maximum %d bytes", maxSize)
+	}
+
+	return out, nil
+}
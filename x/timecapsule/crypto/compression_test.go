@@ -0,0 +1,106 @@
+package crypto_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+)
+
+func TestCompressionRoundTrip(t *testing.T) {
+	random, err := crypto.SecureRandom(4096)
+	require.NoError(t, err)
+
+	inputs := map[string][]byte{
+		"empty":        {},
+		"text":         bytes.Repeat([]byte("my last will and testament "), 200),
+		"random bytes": random,
+	}
+
+	for _, algo := range []string{crypto.CompressionNone, crypto.CompressionGzip, crypto.CompressionZstd} {
+		for name, data := range inputs {
+			t.Run(algo+"/"+name, func(t *testing.T) {
+				require.NoError(t, crypto.ValidateCompression(algo))
+
+				compressed, err := crypto.Compress(data, algo)
+				require.NoError(t, err)
+
+				decompressed, err := crypto.Decompress(compressed, algo, 1<<20)
+				require.NoError(t, err)
+				require.True(t, bytes.Equal(data, decompressed))
+			})
+		}
+	}
+}
+
+func TestCompressIfSmaller(t *testing.T) {
+	text := bytes.Repeat([]byte("compressible "), 100)
+	random, err := crypto.SecureRandom(1024)
+	require.NoError(t, err)
+
+	for _, algo := range []string{crypto.CompressionGzip, crypto.CompressionZstd} {
+		t.Run(algo, func(t *testing.T) {
+			compressed, used, err := crypto.CompressIfSmaller(text, algo)
+			require.NoError(t, err)
+			require.Equal(t, algo, used)
+			require.Less(t, len(compressed), len(text))
+
+			// Incompressible data is kept as is
+			kept, used, err := crypto.CompressIfSmaller(random, algo)
+			require.NoError(t, err)
+			require.Equal(t, crypto.CompressionNone, used)
+			require.Equal(t, random, kept)
+		})
+	}
+
+	kept, used, err := crypto.CompressIfSmaller(text, crypto.CompressionNone)
+	require.NoError(t, err)
+	require.Equal(t, crypto.CompressionNone, used)
+	require.Equal(t, text, kept)
+}
+
+func TestDecompressRejectsBomb(t *testing.T) {
+	// A megabyte of zeros compresses to a few hundred bytes
+	bomb := make([]byte, 1<<20)
+	const limit = 64 * 1024
+
+	for _, algo := range []string{crypto.CompressionGzip, crypto.CompressionZstd} {
+		t.Run(algo, func(t *testing.T) {
+			compressed, err := crypto.Compress(bomb, algo)
+			require.NoError(t, err)
+			require.Less(t, len(compressed), limit)
+
+			_, err = crypto.Decompress(compressed, algo, limit)
+			require.Error(t, err)
+
+			// Output of exactly the limit is accepted
+			exact, err := crypto.Compress(bomb[:limit], algo)
+			require.NoError(t, err)
+			out, err := crypto.Decompress(exact, algo, limit)
+			require.NoError(t, err)
+			require.Len(t, out, limit)
+		})
+	}
+
+	// Uncompressed data is held to the same limit
+	_, err := crypto.Decompress(bomb, crypto.CompressionNone, limit)
+	require.ErrorContains(t, err, "exceeds maximum")
+}
+
+func TestCompressionRejectsUnknownAlgorithm(t *testing.T) {
+	require.Error(t, crypto.ValidateCompression("lz4"))
+
+	_, err := crypto.Compress([]byte("data"), "lz4")
+	require.ErrorContains(t, err, "unsupported compression")
+
+	_, err = crypto.Decompress([]byte("data"), "lz4", 1024)
+	require.ErrorContains(t, err, "unsupported compression")
+
+	// Data that is not in the format of the algorithm is rejected
+	_, err = crypto.Decompress([]byte("not compressed"), crypto.CompressionGzip, 1024)
+	require.Error(t, err)
+	_, err = crypto.Decompress([]byte("not compressed"), crypto.CompressionZstd, 1024)
+	require.Error(t, err)
+}
@@ -24,10 +24,11 @@ func NewEncryptionManager() *EncryptionManager {
 
 // EncryptedData represents encrypted data with metadata
 type EncryptedData struct {
-	Data      []byte `json:"data"`
-	Nonce     []byte `json:"nonce"`
-	Salt      []byte `json:"salt"`
-	Algorithm string `json:"algorithm"`
+	Data        []byte `json:"data"`
+	Nonce       []byte `json:"nonce"`
+	Salt        []byte `json:"salt"`
+	Algorithm   string `json:"algorithm"`
+	Compression string `json:"compression,omitempty"` // Applied to the plaintext before encryption
 }
 
 // GenerateKey generates a cryptographically secure random key
@@ -48,7 +49,8 @@ func (em *EncryptionManager) DeriveKeyFromPassword(password string, salt []byte)
 		}
 	}
 	
-	key, err := scrypt.Key([]byte(password), salt, 32768, 8, 1, em.keySize)
+	params := DefaultScryptParams
+	key, err := scrypt.Key([]byte(password), salt, int(params.N), int(params.R), int(params.P), em.keySize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to derive key: %w", err)
 	}
@@ -121,6 +123,34 @@ func (em *EncryptionManager) Decrypt(encData *EncryptedData, key []byte) ([]byte
 	return plaintext, nil
 }
 
+// CompressAndEncrypt compresses data with the given algorithm and encrypts it using
+// AES-GCM. Data that does not shrink is encrypted uncompressed.
+func (em *EncryptionManager) CompressAndEncrypt(data []byte, key []byte, compression string) (*EncryptedData, error) {
+	compressed, compression, err := CompressIfSmaller(data, compression)
+	if err != nil {
+		return nil, err
+	}
+	
+	encData, err := em.Encrypt(compressed, key)
+	if err != nil {
+		return nil, err
+	}
+	encData.Compression = compression
+	
+	return encData, nil
+}
+
+// DecryptAndDecompress decrypts data using AES-GCM and decompresses it. Decompressed
+// data larger than maxSize bytes is rejected.
+func (em *EncryptionManager) DecryptAndDecompress(encData *EncryptedData, key []byte, maxSize uint64) ([]byte, error) {
+	plaintext, err := em.Decrypt(encData, key)
+	if err != nil {
+		return nil, err
+	}
+	
+	return Decompress(plaintext, encData.Compression, maxSize)
+}
+
 // EncryptWithPassword encrypts data using a password-derived key
 func (em *EncryptionManager) EncryptWithPassword(data []byte, password string) (*EncryptedData, error) {
 	// Generate salt
@@ -0,0 +1,76 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// timelockKeyFile is the on-disk format of a validator's timelock key
+type timelockKeyFile struct {
+	PrivateKey string `json:"private_key"`
+	PublicKey  string `json:"public_key"`
+}
+
+// LoadTimelockKey reads a validator's timelock key from disk
+func LoadTimelockKey(path string) (privKey []byte, pubKey []byte, err error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var file timelockKeyFile
+	if err := json.Unmarshal(bz, &file); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode timelock key file: %w", err)
+	}
+
+	privKey, err = hex.DecodeString(file.PrivateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid timelock private key: %w", err)
+	}
+
+	pubKey, err = TimelockPublicKey(privKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid timelock private key: %w", err)
+	}
+
+	if file.PublicKey != "" && file.PublicKey != hex.EncodeToString(pubKey) {
+		return nil, nil, errors.New("timelock key file public key does not match private key")
+	}
+
+	return privKey, pubKey, nil
+}
+
+// LoadOrGenerateTimelockKey reads a validator's timelock key, generating and saving a
+// new one if the file does not exist yet
+func LoadOrGenerateTimelockKey(path string) (privKey []byte, pubKey []byte, err error) {
+	privKey, pubKey, err = LoadTimelockKey(path)
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return privKey, pubKey, err
+	}
+
+	privKey, pubKey, err = GenerateTimelockKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bz, err := json.MarshalIndent(timelockKeyFile{
+		PrivateKey: hex.EncodeToString(privKey),
+		PublicKey:  hex.EncodeToString(pubKey),
+	}, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, nil, fmt.Errorf("failed to create key directory: %w", err)
+	}
+	if err := os.WriteFile(path, bz, 0o600); err != nil {
+		return nil, nil, fmt.Errorf("failed to write timelock key file: %w", err)
+	}
+
+	return privKey, pubKey, nil
+}
@@ -0,0 +1,83 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/scrypt"
+)
+
+// A passphrase factor turns a passphrase into a secp256k1 key pair: scrypt stretches
+// the passphrase into the secret scalar, and only the public key is published. The
+// chain can then encrypt to the passphrase like to a recipient key, without ever
+// learning the passphrase or anything that could be brute-forced faster than scrypt.
+
+const (
+	// KDFScrypt identifies scrypt as the key derivation function of a passphrase factor
+	KDFScrypt = "scrypt"
+
+	// PassphraseSaltSize is the size of the salt a passphrase is stretched with
+	PassphraseSaltSize = 16
+)
+
+// ScryptParams are the cost parameters of scrypt
+type ScryptParams struct {
+	N uint32 `json:"n"` // CPU and memory cost, a power of two
+	R uint32 `json:"r"` // Block size
+	P uint32 `json:"p"` // Parallelization
+}
+
+// DefaultScryptParams are the scrypt parameters passphrases are stretched with
+var DefaultScryptParams = ScryptParams{N: 32768, R: 8, P: 1}
+
+// Validate checks that the parameters are strong enough to slow down guessing, and
+// cheap enough that any client can derive the key
+func (p ScryptParams) Validate() error {
+	if p.N < 1<<14 || p.N > 1<<20 || p.N&(p.N-1) != 0 {
+		return fmt.Errorf("scrypt N must be a power of two between %d and %d, got %d", 1<<14, 1<<20, p.N)
+	}
+	if p.R < 1 || p.R > 32 {
+		return fmt.Errorf("scrypt r must be between 1 and 32, got %d", p.R)
+	}
+	if p.P < 1 || p.P > 16 {
+		return fmt.Errorf("scrypt p must be between 1 and 16, got %d", p.P)
+	}
+	return nil
+}
+
+// DerivePassphraseKey stretches a passphrase with scrypt into a secp256k1 private key
+// and returns it with its compressed public key
+func DerivePassphraseKey(passphrase string, salt []byte, params ScryptParams) (privKey []byte, pubKey []byte, err error) {
+	if passphrase == "" {
+		return nil, nil, errors.New("passphrase cannot be empty")
+	}
+	if len(salt) != PassphraseSaltSize {
+		return nil, nil, fmt.Errorf("passphrase salt must be %d bytes, got %d", PassphraseSaltSize, len(salt))
+	}
+	if err := params.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	stretched, err := scrypt.Key([]byte(passphrase), salt, int(params.N), int(params.R), int(params.P), TimelockScalarSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to derive passphrase key: %w", err)
+	}
+	defer WipeKey(stretched)
+
+	var s secp256k1.ModNScalar
+	defer s.Zero()
+	if overflow := s.SetByteSlice(stretched); overflow || s.IsZero() {
+		return nil, nil, errors.New("passphrase does not derive a valid key, choose another salt")
+	}
+
+	var P secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&s, &P)
+	pubKey, err = pointBytes(&P)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scalar := s.Bytes()
+	return scalar[:], pubKey, nil
+}
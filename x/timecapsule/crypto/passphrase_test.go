@@ -0,0 +1,104 @@
+package crypto_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+)
+
+// testScryptParams are the cheapest parameters Validate accepts, to keep the tests fast
+var testScryptParams = crypto.ScryptParams{N: 1 << 14, R: 8, P: 1}
+
+func TestDerivePassphraseKey(t *testing.T) {
+	salt := bytes.Repeat([]byte{0x42}, crypto.PassphraseSaltSize)
+
+	privKey, pubKey, err := crypto.DerivePassphraseKey("correct horse battery staple", salt, testScryptParams)
+	require.NoError(t, err)
+	require.Len(t, privKey, crypto.TimelockScalarSize)
+	require.NoError(t, crypto.ValidateRecipientKey(pubKey))
+
+	// The key is deterministic, so the passphrase alone recovers it
+	privKey2, pubKey2, err := crypto.DerivePassphraseKey("correct horse battery staple", salt, testScryptParams)
+	require.NoError(t, err)
+	require.Equal(t, privKey, privKey2)
+	require.Equal(t, pubKey, pubKey2)
+
+	// The public key opens what is encrypted to it with the derived private key
+	secret, ciphertext, err := crypto.NewDeliverySecret(pubKey)
+	require.NoError(t, err)
+	opened, err := crypto.OpenDelivery(privKey, ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, secret, opened)
+
+	// Another passphrase, salt or cost derives another key
+	_, otherPub, err := crypto.DerivePassphraseKey("correct horse battery stapler", salt, testScryptParams)
+	require.NoError(t, err)
+	require.NotEqual(t, pubKey, otherPub)
+
+	otherSalt := bytes.Repeat([]byte{0x43}, crypto.PassphraseSaltSize)
+	_, otherPub, err = crypto.DerivePassphraseKey("correct horse battery staple", otherSalt, testScryptParams)
+	require.NoError(t, err)
+	require.NotEqual(t, pubKey, otherPub)
+
+	_, otherPub, err = crypto.DerivePassphraseKey("correct horse battery staple", salt, crypto.ScryptParams{N: 1 << 14, R: 4, P: 1})
+	require.NoError(t, err)
+	require.NotEqual(t, pubKey, otherPub)
+}
+
+func TestDerivePassphraseKeyRejectsInvalidInput(t *testing.T) {
+	salt := bytes.Repeat([]byte{0x42}, crypto.PassphraseSaltSize)
+
+	testCases := []struct {
+		name       string
+		passphrase string
+		salt       []byte
+		params     crypto.ScryptParams
+		expErr     string
+	}{
+		{"empty passphrase", "", salt, testScryptParams, "cannot be empty"},
+		{"short salt", "passphrase", salt[:8], testScryptParams, "salt must be"},
+		{"long salt", "passphrase", append(salt, 0x42), testScryptParams, "salt must be"},
+		{"weak parameters", "passphrase", salt, crypto.ScryptParams{N: 1 << 10, R: 8, P: 1}, "scrypt N"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := crypto.DerivePassphraseKey(tc.passphrase, tc.salt, tc.params)
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestScryptParamsValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		params crypto.ScryptParams
+		expErr string
+	}{
+		{"default", crypto.DefaultScryptParams, ""},
+		{"cheapest", crypto.ScryptParams{N: 1 << 14, R: 1, P: 1}, ""},
+		{"costliest", crypto.ScryptParams{N: 1 << 20, R: 32, P: 16}, ""},
+		{"N below the minimum", crypto.ScryptParams{N: 1 << 13, R: 8, P: 1}, "scrypt N"},
+		{"N above the maximum", crypto.ScryptParams{N: 1 << 21, R: 8, P: 1}, "scrypt N"},
+		{"N not a power of two", crypto.ScryptParams{N: 1<<14 + 1, R: 8, P: 1}, "scrypt N"},
+		{"zero N", crypto.ScryptParams{R: 8, P: 1}, "scrypt N"},
+		{"zero r", crypto.ScryptParams{N: 1 << 14, P: 1}, "scrypt r"},
+		{"r above the maximum", crypto.ScryptParams{N: 1 << 14, R: 33, P: 1}, "scrypt r"},
+		{"zero p", crypto.ScryptParams{N: 1 << 14, R: 8}, "scrypt p"},
+		{"p above the maximum", crypto.ScryptParams{N: 1 << 14, R: 8, P: 17}, "scrypt p"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.params.Validate()
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// Recipient delivery by threshold proxy re-encryption. A capsule's data key is wrapped
+// with a delivery secret derived from a random point M, and M is ElGamal-encrypted to a
+// delivery key whose secret scalar a is only held as Shamir shares by the custodians:
+//
+//	R = r·G, C = M + r·A
+//
+// To deliver to a recipient key P, every custodian i picks s_i and publishes
+// S_i = s_i·G and Z_i = s_i·P - a_i·R. Interpolating them gives S = s·G and
+// Z = s·P - a·R, so (S, C + Z) = (s·G, M + s·P) is an encryption of M to P. No custodian
+// learns M or a, and the recipient can be changed until delivery without touching
+// the wrapped data key.
+
+const deliveryDomain = "timecapsule/delivery/v1"
+
+// DeliveryCiphertextSize is the size of a serialized delivery ciphertext (R || C)
+const DeliveryCiphertextSize = 2 * TimelockPointSize
+
+// ReEncryptionShare is a custodian's contribution to re-encrypting a delivery ciphertext
+type ReEncryptionShare struct {
+	Index uint32 `json:"index"`
+	S     []byte `json:"s"` // s_i·G
+	Z     []byte `json:"z"` // s_i·P - a_i·R
+}
+
+// ValidateRecipientKey checks that a recipient key is a compressed secp256k1 point
+func ValidateRecipientKey(pub []byte) error {
+	if len(pub) != TimelockPointSize {
+		return fmt.Errorf("recipient key must be %d bytes, got %d", TimelockPointSize, len(pub))
+	}
+	_, err := parsePoint(pub)
+	return err
+}
+
+// DealDeliveryKey samples a delivery key and splits its secret scalar into shares.
+// Each share holds its scalar as a single value, so shares can be refreshed and
+// recovered like key shares.
+func DealDeliveryKey(threshold, totalShares int) (pub []byte, shares []*Share, err error) {
+	if threshold < 1 || threshold > totalShares {
+		return nil, nil, fmt.Errorf("invalid threshold %d of %d shares", threshold, totalShares)
+	}
+
+	coefficients := make([]secp256k1.ModNScalar, threshold)
+	defer func() {
+		for i := range coefficients {
+			coefficients[i].Zero()
+		}
+	}()
+	for i := range coefficients {
+		if err := randomScalar(&coefficients[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var A secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&coefficients[0], &A)
+	pub, err = pointBytes(&A)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shares = make([]*Share, totalShares)
+	for i := range shares {
+		var x, y secp256k1.ModNScalar
+		x.SetInt(uint32(i + 1))
+		evaluatePolynomialN(coefficients, &x, &y)
+		shares[i] = &Share{
+			X:      big.NewInt(int64(i + 1)),
+			Values: []*big.Int{scalarToBig(&y)},
+		}
+	}
+
+	return pub, shares, nil
+}
+
+// NewDeliverySecret samples a random point M and encrypts it to pub. It returns the
+// 32-byte secret derived from M, to wrap a data key with, and the ciphertext R || C.
+func NewDeliverySecret(pub []byte) (secret []byte, ciphertext []byte, err error) {
+	P, err := parsePoint(pub)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid delivery key: %w", err)
+	}
+
+	var m, r secp256k1.ModNScalar
+	defer m.Zero()
+	defer r.Zero()
+	if err := randomScalar(&m); err != nil {
+		return nil, nil, err
+	}
+	if err := randomScalar(&r); err != nil {
+		return nil, nil, err
+	}
+
+	var M, R, rP, C secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&m, &M)
+	secp256k1.ScalarBaseMultNonConst(&r, &R)
+	secp256k1.ScalarMultNonConst(&r, P, &rP)
+	secp256k1.AddNonConst(&M, &rP, &C)
+
+	secret, err = deliverySecret(&M)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ciphertext, err = joinPoints(&R, &C)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return secret, ciphertext, nil
+}
+
+// ReEncryptDeliveryShare computes a custodian's re-encryption share of a delivery
+// ciphertext for the recipient key
+func ReEncryptDeliveryShare(share *Share, ciphertext []byte, recipientPub []byte) (*ReEncryptionShare, error) {
+	if share == nil || len(share.Values) != 1 {
+		return nil, errors.New("invalid delivery share")
+	}
+	index, err := shareIndex(share)
+	if err != nil {
+		return nil, err
+	}
+	a, err := bigToScalar(share.Values[0])
+	if err != nil {
+		return nil, err
+	}
+	defer a.Zero()
+
+	R, _, err := splitPoints(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	P, err := parsePoint(recipientPub)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient key: %w", err)
+	}
+
+	var s secp256k1.ModNScalar
+	defer s.Zero()
+	if err := randomScalar(&s); err != nil {
+		return nil, err
+	}
+
+	var S, sP, aR, Z secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&s, &S)
+	secp256k1.ScalarMultNonConst(&s, P, &sP)
+	secp256k1.ScalarMultNonConst(a.Negate(), R, &aR)
+	secp256k1.AddNonConst(&sP, &aR, &Z)
+
+	sBytes, err := pointBytes(&S)
+	if err != nil {
+		return nil, err
+	}
+	zBytes, err := pointBytes(&Z)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReEncryptionShare{Index: index, S: sBytes, Z: zBytes}, nil
+}
+
+// CombineReEncryption interpolates a threshold of re-encryption shares into a delivery
+// ciphertext for the recipient key
+func CombineReEncryption(ciphertext []byte, shares []*ReEncryptionShare) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("no re-encryption shares")
+	}
+
+	_, C, err := splitPoints(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make([]uint32, len(shares))
+	for i, share := range shares {
+		indices[i] = share.Index
+	}
+	lambdas, err := lagrangeCoefficients(indices)
+	if err != nil {
+		return nil, err
+	}
+
+	var S, newC secp256k1.JacobianPoint
+	newC.Set(C)
+	for i, share := range shares {
+		Si, err := parsePoint(share.S)
+		if err != nil {
+			return nil, fmt.Errorf("re-encryption share %d: %w", share.Index, err)
+		}
+		Zi, err := parsePoint(share.Z)
+		if err != nil {
+			return nil, fmt.Errorf("re-encryption share %d: %w", share.Index, err)
+		}
+
+		var weighted, sum secp256k1.JacobianPoint
+		secp256k1.ScalarMultNonConst(&lambdas[i], Si, &weighted)
+		secp256k1.AddNonConst(&S, &weighted, &sum)
+		S.Set(&sum)
+
+		secp256k1.ScalarMultNonConst(&lambdas[i], Zi, &weighted)
+		secp256k1.AddNonConst(&newC, &weighted, &sum)
+		newC.Set(&sum)
+	}
+
+	return joinPoints(&S, &newC)
+}
+
+// OpenDelivery decrypts a delivery ciphertext with the recipient's private key and
+// returns the delivery secret
+func OpenDelivery(recipientPriv []byte, ciphertext []byte) ([]byte, error) {
+	b, err := parseScalar(recipientPriv)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Zero()
+
+	R, C, err := splitPoints(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	var bR, M secp256k1.JacobianPoint
+	secp256k1.ScalarMultNonConst(b.Negate(), R, &bR)
+	secp256k1.AddNonConst(C, &bR, &M)
+
+	return deliverySecret(&M)
+}
+
+// deliverySecret derives the symmetric delivery secret from the point M
+func deliverySecret(M *secp256k1.JacobianPoint) ([]byte, error) {
+	mBytes, err := pointBytes(M)
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	h.Write([]byte(deliveryDomain))
+	h.Write(mBytes)
+	return h.Sum(nil), nil
+}
+
+func joinPoints(a, b *secp256k1.JacobianPoint) ([]byte, error) {
+	aBytes, err := pointBytes(a)
+	if err != nil {
+		return nil, err
+	}
+	bBytes, err := pointBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	return append(aBytes, bBytes...), nil
+}
+
+func splitPoints(ciphertext []byte) (*secp256k1.JacobianPoint, *secp256k1.JacobianPoint, error) {
+	if len(ciphertext) != DeliveryCiphertextSize {
+		return nil, nil, fmt.Errorf("delivery ciphertext must be %d bytes, got %d", DeliveryCiphertextSize, len(ciphertext))
+	}
+	R, err := parsePoint(ciphertext[:TimelockPointSize])
+	if err != nil {
+		return nil, nil, err
+	}
+	C, err := parsePoint(ciphertext[TimelockPointSize:])
+	if err != nil {
+		return nil, nil, err
+	}
+	return R, C, nil
+}
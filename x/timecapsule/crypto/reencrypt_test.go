@@ -0,0 +1,124 @@
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+)
+
+// reEncrypt re-encrypts a delivery ciphertext to a recipient with the given shares
+func reEncrypt(t *testing.T, ciphertext, recipientPub []byte, shares ...*crypto.Share) []byte {
+	t.Helper()
+
+	reShares := make([]*crypto.ReEncryptionShare, len(shares))
+	for i, share := range shares {
+		reShare, err := crypto.ReEncryptDeliveryShare(share, ciphertext, recipientPub)
+		require.NoError(t, err)
+		reShares[i] = reShare
+	}
+
+	delivered, err := crypto.CombineReEncryption(ciphertext, reShares)
+	require.NoError(t, err)
+	require.Len(t, delivered, crypto.DeliveryCiphertextSize)
+	return delivered
+}
+
+func TestReEncryptionDelivery(t *testing.T) {
+	pub, shares, err := crypto.DealDeliveryKey(2, 3)
+	require.NoError(t, err)
+	require.NoError(t, crypto.ValidateRecipientKey(pub))
+	require.Len(t, shares, 3)
+
+	secret, ciphertext, err := crypto.NewDeliverySecret(pub)
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+	require.Len(t, ciphertext, crypto.DeliveryCiphertextSize)
+
+	recipientPriv, recipientPub, err := crypto.GenerateTimelockKey()
+	require.NoError(t, err)
+
+	// Any threshold of custodians re-encrypts the secret to the recipient
+	for _, subset := range [][]int{{0, 1}, {0, 2}, {1, 2}} {
+		delivered := reEncrypt(t, ciphertext, recipientPub, shares[subset[0]], shares[subset[1]])
+		opened, err := crypto.OpenDelivery(recipientPriv, delivered)
+		require.NoError(t, err)
+		require.Equal(t, secret, opened, "subset %v", subset)
+	}
+
+	// Another key does not open the delivery
+	otherPriv, _, err := crypto.GenerateTimelockKey()
+	require.NoError(t, err)
+	delivered := reEncrypt(t, ciphertext, recipientPub, shares[0], shares[1])
+	opened, err := crypto.OpenDelivery(otherPriv, delivered)
+	require.NoError(t, err)
+	require.NotEqual(t, secret, opened)
+
+	// Nor do fewer shares than the threshold
+	delivered = reEncrypt(t, ciphertext, recipientPub, shares[0])
+	opened, err = crypto.OpenDelivery(recipientPriv, delivered)
+	require.NoError(t, err)
+	require.NotEqual(t, secret, opened)
+}
+
+func TestReEncryptionAfterRefresh(t *testing.T) {
+	sss := crypto.NewShamirSecretSharing()
+
+	pub, shares, err := crypto.DealDeliveryKey(2, 3)
+	require.NoError(t, err)
+	secret, ciphertext, err := crypto.NewDeliverySecret(pub)
+	require.NoError(t, err)
+	recipientPriv, recipientPub, err := crypto.GenerateTimelockKey()
+	require.NoError(t, err)
+
+	refreshed, _, err := sss.RefreshShares(shares, nil, 2, testRefreshSeed)
+	require.NoError(t, err)
+
+	// Refreshed shares still deliver the same secret
+	opened, err := crypto.OpenDelivery(recipientPriv, reEncrypt(t, ciphertext, recipientPub, refreshed[0], refreshed[2]))
+	require.NoError(t, err)
+	require.Equal(t, secret, opened)
+
+	// Mixing shares from before and after the refresh does not
+	opened, err = crypto.OpenDelivery(recipientPriv, reEncrypt(t, ciphertext, recipientPub, shares[0], refreshed[2]))
+	require.NoError(t, err)
+	require.NotEqual(t, secret, opened)
+
+	// A recovered share replaces a lost custodian's share
+	recovered, err := sss.RecoverShare([]*crypto.Share{refreshed[0], refreshed[1]}, 3)
+	require.NoError(t, err)
+	opened, err = crypto.OpenDelivery(recipientPriv, reEncrypt(t, ciphertext, recipientPub, refreshed[0], recovered))
+	require.NoError(t, err)
+	require.Equal(t, secret, opened)
+}
+
+func TestReEncryptionRejectsInvalidInput(t *testing.T) {
+	_, _, err := crypto.DealDeliveryKey(3, 2)
+	require.ErrorContains(t, err, "invalid threshold")
+
+	require.Error(t, crypto.ValidateRecipientKey(make([]byte, 32)))
+	require.Error(t, crypto.ValidateRecipientKey(make([]byte, 33)))
+
+	pub, shares, err := crypto.DealDeliveryKey(2, 2)
+	require.NoError(t, err)
+	_, ciphertext, err := crypto.NewDeliverySecret(pub)
+	require.NoError(t, err)
+	_, recipientPub, err := crypto.GenerateTimelockKey()
+	require.NoError(t, err)
+
+	_, err = crypto.ReEncryptDeliveryShare(shares[0], ciphertext[:len(ciphertext)-1], recipientPub)
+	require.Error(t, err)
+
+	_, err = crypto.ReEncryptDeliveryShare(shares[0], ciphertext, recipientPub[1:])
+	require.Error(t, err)
+
+	_, err = crypto.CombineReEncryption(ciphertext, nil)
+	require.ErrorContains(t, err, "no re-encryption shares")
+
+	// The same custodian cannot be counted twice
+	reShare, err := crypto.ReEncryptDeliveryShare(shares[0], ciphertext, recipientPub)
+	require.NoError(t, err)
+	_, err = crypto.CombineReEncryption(ciphertext, []*crypto.ReEncryptionShare{reShare, reShare})
+	require.Error(t, err)
+}
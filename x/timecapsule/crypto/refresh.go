@@ -0,0 +1,218 @@
+package crypto
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// Proactive share refresh. Custodians add the evaluations of a pseudo-random polynomial
+// with a zero constant term to their shares: the shared secret is unchanged, but shares
+// taken before the refresh no longer combine with shares taken after it. A lost share is
+// recovered by interpolating the polynomial at its index, which never evaluates it at
+// zero and so never reconstructs the secret. The polynomial is derived from a seed so
+// that all nodes agree on the refreshed shares; whoever knows the seed can also compute it.
+
+// RefreshShares re-randomizes chunked shares and the Pedersen commitments matching them.
+// Shares without blinding values are refreshed without commitments. The coefficients of
+// the refresh polynomial are derived from seed, so that every node refreshing the same
+// shares with the same seed ends up with the same state.
+func (sss *ShamirSecretSharing) RefreshShares(shares []*Share, commitments [][][]byte, threshold int, seed []byte) ([]*Share, [][][]byte, error) {
+	if len(shares) == 0 {
+		return nil, nil, fmt.Errorf("no shares to refresh")
+	}
+	if len(seed) == 0 {
+		return nil, nil, fmt.Errorf("refresh seed cannot be empty")
+	}
+	if threshold < 1 {
+		return nil, nil, fmt.Errorf("threshold must be at least 1")
+	}
+
+	chunks := len(shares[0].Values)
+	verifiable := len(commitments) > 0
+	for _, share := range shares {
+		if share.IsLegacy() {
+			return nil, nil, fmt.Errorf("legacy shares cannot be refreshed")
+		}
+		if len(share.Values) != chunks {
+			return nil, nil, fmt.Errorf("shares hold different numbers of chunks")
+		}
+		if verifiable && len(share.Blinding) != chunks {
+			return nil, nil, fmt.Errorf("share is missing blinding values")
+		}
+	}
+	if verifiable {
+		if err := ValidateShareCommitments(commitments, threshold); err != nil {
+			return nil, nil, err
+		}
+		if len(commitments) != chunks {
+			return nil, nil, fmt.Errorf("%d commitments for %d chunks", len(commitments), chunks)
+		}
+	}
+
+	refreshed := make([]*Share, len(shares))
+	for i, share := range shares {
+		refreshed[i] = &Share{
+			X:      new(big.Int).Set(share.X),
+			Values: make([]*big.Int, chunks),
+		}
+		if verifiable {
+			refreshed[i].Blinding = make([]*big.Int, chunks)
+		}
+	}
+
+	var newCommitments [][][]byte
+	if verifiable {
+		newCommitments = make([][][]byte, chunks)
+	}
+
+	// The constant terms stay zero so that the secret is preserved
+	delta := make([]secp256k1.ModNScalar, threshold)
+	deltaBlinding := make([]secp256k1.ModNScalar, threshold)
+
+	for c := 0; c < chunks; c++ {
+		for j := 1; j < threshold; j++ {
+			position := binary.BigEndian.AppendUint32(binary.BigEndian.AppendUint32(nil, uint32(c)), uint32(j))
+			delta[j] = *hashToScalar([]byte("refresh"), seed, position)
+			if verifiable {
+				deltaBlinding[j] = *hashToScalar([]byte("refresh blinding"), seed, position)
+			}
+		}
+
+		if verifiable {
+			newCommitments[c] = make([][]byte, threshold)
+			newCommitments[c][0] = commitments[c][0]
+			for j := 1; j < threshold; j++ {
+				point, err := addCommitment(commitments[c][j], &delta[j], &deltaBlinding[j])
+				if err != nil {
+					return nil, nil, err
+				}
+				newCommitments[c][j] = point
+			}
+		}
+
+		for i, share := range shares {
+			index, err := shareIndex(share)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			var x, d secp256k1.ModNScalar
+			x.SetInt(index)
+			evaluatePolynomialN(delta, &x, &d)
+			value, err := bigToScalar(share.Values[c])
+			if err != nil {
+				return nil, nil, err
+			}
+			refreshed[i].Values[c] = scalarToBig(value.Add(&d))
+
+			if verifiable {
+				evaluatePolynomialN(deltaBlinding, &x, &d)
+				blinding, err := bigToScalar(share.Blinding[c])
+				if err != nil {
+					return nil, nil, err
+				}
+				refreshed[i].Blinding[c] = scalarToBig(blinding.Add(&d))
+			}
+		}
+	}
+
+	return refreshed, newCommitments, nil
+}
+
+// ShareRefreshSeed derives the seed of a capsule's share refresh from the hash of the
+// block it runs in and the refresh round, the way SealToTimelock derives its ephemeral key
+func ShareRefreshSeed(blockHash []byte, capsuleID uint64, round string) []byte {
+	seed := hashToScalar([]byte("refresh seed"), blockHash, binary.BigEndian.AppendUint64(nil, capsuleID), []byte(round))
+	defer seed.Zero()
+
+	bz := seed.Bytes()
+	return bz[:]
+}
+
+// RecoverShare computes the share at index from threshold shares of other indices,
+// without reconstructing the secret
+func (sss *ShamirSecretSharing) RecoverShare(shares []*Share, index uint32) (*Share, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("no shares to recover from")
+	}
+	if index == 0 {
+		return nil, fmt.Errorf("share index cannot be zero")
+	}
+
+	chunks := len(shares[0].Values)
+	verifiable := len(shares[0].Blinding) > 0
+	indices := make([]uint32, len(shares))
+	for i, share := range shares {
+		if share.IsLegacy() {
+			return nil, fmt.Errorf("legacy shares cannot be recovered")
+		}
+		if len(share.Values) != chunks || (verifiable && len(share.Blinding) != chunks) {
+			return nil, fmt.Errorf("shares hold different numbers of chunks")
+		}
+		x, err := shareIndex(share)
+		if err != nil {
+			return nil, err
+		}
+		if x == index {
+			return nil, fmt.Errorf("share %d is already present", index)
+		}
+		indices[i] = x
+	}
+
+	lambdas, err := lagrangeCoefficientsAt(indices, index)
+	if err != nil {
+		return nil, err
+	}
+
+	recovered := &Share{
+		X:      big.NewInt(int64(index)),
+		Values: make([]*big.Int, chunks),
+	}
+	if verifiable {
+		recovered.Blinding = make([]*big.Int, chunks)
+	}
+
+	interpolate := func(values func(*Share) *big.Int) (*big.Int, error) {
+		var sum secp256k1.ModNScalar
+		for i, share := range shares {
+			v, err := bigToScalar(values(share))
+			if err != nil {
+				return nil, err
+			}
+			sum.Add(v.Mul(&lambdas[i]))
+		}
+		return scalarToBig(&sum), nil
+	}
+
+	for c := 0; c < chunks; c++ {
+		recovered.Values[c], err = interpolate(func(s *Share) *big.Int { return s.Values[c] })
+		if err != nil {
+			return nil, err
+		}
+		if verifiable {
+			recovered.Blinding[c], err = interpolate(func(s *Share) *big.Int { return s.Blinding[c] })
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return recovered, nil
+}
+
+// addCommitment adds a·G + b·H to a serialized commitment
+func addCommitment(commitment []byte, a, b *secp256k1.ModNScalar) ([]byte, error) {
+	point, err := parsePoint(commitment)
+	if err != nil {
+		return nil, err
+	}
+
+	var delta, sum secp256k1.JacobianPoint
+	pedersenPoint(a, b, &delta)
+	secp256k1.AddNonConst(point, &delta, &sum)
+
+	return pointBytes(&sum)
+}
@@ -0,0 +1,159 @@
+package crypto_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+)
+
+var testRefreshSeed = crypto.ShareRefreshSeed(bytes.Repeat([]byte{0x01}, 32), 1, "test")
+
+func TestRefreshSharesPreservesSecret(t *testing.T) {
+	sss := crypto.NewShamirSecretSharing()
+	secret := bytes.Repeat([]byte("refresh"), 10)
+
+	shares, commitments, err := sss.SplitSecretVerifiable(secret, 3, 5)
+	require.NoError(t, err)
+
+	refreshed, newCommitments, err := sss.RefreshShares(shares, commitments, 3, testRefreshSeed)
+	require.NoError(t, err)
+	require.Len(t, refreshed, len(shares))
+	require.NotEqual(t, commitments, newCommitments)
+
+	// The commitment to the secret itself is unchanged
+	for c := range commitments {
+		require.Equal(t, commitments[c][0], newCommitments[c][0])
+	}
+
+	// Refreshed shares match the new commitments only
+	for i, share := range refreshed {
+		require.Equal(t, 0, shares[i].X.Cmp(share.X))
+		require.NotEqual(t, shares[i].Values, share.Values)
+		require.NoError(t, crypto.VerifyShare(newCommitments, share))
+		require.Error(t, crypto.VerifyShare(commitments, share))
+		require.Error(t, crypto.VerifyShare(newCommitments, shares[i]))
+	}
+
+	// Old and refreshed shares each combine to the same secret
+	combined, err := sss.CombineShares(shares[:3])
+	require.NoError(t, err)
+	require.Equal(t, secret, combined)
+
+	combined, err = sss.CombineShares(refreshed[2:])
+	require.NoError(t, err)
+	require.Equal(t, secret, combined)
+
+	// Shares taken before the refresh do not combine with shares taken after it
+	mixed, err := sss.CombineShares([]*crypto.Share{shares[0], shares[1], refreshed[2]})
+	if err == nil {
+		require.NotEqual(t, secret, mixed)
+	}
+}
+
+func TestRefreshSharesWithoutCommitments(t *testing.T) {
+	sss := crypto.NewShamirSecretSharing()
+	secret := []byte("unverifiable secret")
+
+	shares, err := sss.SplitSecret(secret, 2, 3)
+	require.NoError(t, err)
+
+	refreshed, commitments, err := sss.RefreshShares(shares, nil, 2, testRefreshSeed)
+	require.NoError(t, err)
+	require.Nil(t, commitments)
+
+	combined, err := sss.CombineShares([]*crypto.Share{refreshed[0], refreshed[2]})
+	require.NoError(t, err)
+	require.Equal(t, secret, combined)
+
+	mixed, err := sss.CombineShares([]*crypto.Share{shares[0], refreshed[2]})
+	if err == nil {
+		require.NotEqual(t, secret, mixed)
+	}
+}
+
+func TestRefreshSharesRejectsInvalidInput(t *testing.T) {
+	sss := crypto.NewShamirSecretSharing()
+
+	shares, commitments, err := sss.SplitSecretVerifiable([]byte("secret"), 2, 3)
+	require.NoError(t, err)
+	long, _, err := sss.SplitSecretVerifiable(bytes.Repeat([]byte("long"), 20), 2, 3)
+	require.NoError(t, err)
+
+	_, _, err = sss.RefreshShares(nil, commitments, 2, testRefreshSeed)
+	require.ErrorContains(t, err, "no shares")
+
+	_, _, err = sss.RefreshShares(shares, commitments, 0, testRefreshSeed)
+	require.ErrorContains(t, err, "threshold")
+
+	_, _, err = sss.RefreshShares(shares, commitments, 3, testRefreshSeed)
+	require.ErrorContains(t, err, "commitments")
+
+	_, _, err = sss.RefreshShares([]*crypto.Share{shares[0], long[1]}, commitments, 2, testRefreshSeed)
+	require.ErrorContains(t, err, "different numbers of chunks")
+
+	legacy, err := crypto.BytesToShare([]byte{0x00, 0x01, 0x01, 0x00, 0x01, 0x07})
+	require.NoError(t, err)
+	_, _, err = sss.RefreshShares([]*crypto.Share{legacy}, nil, 1, testRefreshSeed)
+	require.ErrorContains(t, err, "legacy")
+
+	_, _, err = sss.RefreshShares(shares, commitments, 2, nil)
+	require.ErrorContains(t, err, "seed")
+}
+
+func TestRefreshSharesIsDeterministic(t *testing.T) {
+	sss := crypto.NewShamirSecretSharing()
+
+	shares, commitments, err := sss.SplitSecretVerifiable([]byte("deterministic refresh"), 2, 3)
+	require.NoError(t, err)
+
+	first, firstCommitments, err := sss.RefreshShares(shares, commitments, 2, testRefreshSeed)
+	require.NoError(t, err)
+	second, secondCommitments, err := sss.RefreshShares(shares, commitments, 2, testRefreshSeed)
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+	require.Equal(t, firstCommitments, secondCommitments)
+
+	// Another block, capsule or round refreshes differently
+	for _, seed := range [][]byte{
+		crypto.ShareRefreshSeed(bytes.Repeat([]byte{0x02}, 32), 1, "test"),
+		crypto.ShareRefreshSeed(bytes.Repeat([]byte{0x01}, 32), 2, "test"),
+		crypto.ShareRefreshSeed(bytes.Repeat([]byte{0x01}, 32), 1, "other"),
+	} {
+		other, _, err := sss.RefreshShares(shares, commitments, 2, seed)
+		require.NoError(t, err)
+		require.NotEqual(t, first[0].Values, other[0].Values)
+	}
+}
+
+func TestRecoverShare(t *testing.T) {
+	sss := crypto.NewShamirSecretSharing()
+	secret := bytes.Repeat([]byte("recover"), 8)
+
+	shares, commitments, err := sss.SplitSecretVerifiable(secret, 2, 4)
+	require.NoError(t, err)
+
+	// The share of a lost custodian is interpolated from a threshold of other shares
+	recovered, err := sss.RecoverShare([]*crypto.Share{shares[0], shares[3]}, 3)
+	require.NoError(t, err)
+	require.Equal(t, 0, shares[2].X.Cmp(recovered.X))
+	require.Equal(t, shares[2].Values, recovered.Values)
+	require.Equal(t, shares[2].Blinding, recovered.Blinding)
+	require.NoError(t, crypto.VerifyShare(commitments, recovered))
+
+	// It then takes part in a refresh like any other share
+	current := []*crypto.Share{shares[0], shares[1], recovered, shares[3]}
+	refreshed, _, err := sss.RefreshShares(current, commitments, 2, testRefreshSeed)
+	require.NoError(t, err)
+	combined, err := sss.CombineShares([]*crypto.Share{refreshed[1], refreshed[2]})
+	require.NoError(t, err)
+	require.Equal(t, secret, combined)
+
+	_, err = sss.RecoverShare([]*crypto.Share{shares[0], shares[3]}, 1)
+	require.ErrorContains(t, err, "already present")
+
+	_, err = sss.RecoverShare([]*crypto.Share{shares[0], shares[3]}, 0)
+	require.ErrorContains(t, err, "cannot be zero")
+}
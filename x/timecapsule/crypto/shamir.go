@@ -1,14 +1,19 @@
 package crypto
 
 import (
-	"crypto/rand"
+	"encoding/binary"
 	"fmt"
 	"math/big"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 )
 
-// ShamirSecretSharing implements Shamir's Secret Sharing algorithm
+// ShamirSecretSharing implements Shamir's Secret Sharing algorithm. Secrets of any
+// length are split into chunks shorter than the secp256k1 curve order and each chunk
+// is shared with its own polynomial. Shares of capsules created before chunking hold
+// a single evaluation over a 256-bit prime field and can still be combined.
 type ShamirSecretSharing struct {
-	prime *big.Int
+	prime *big.Int // Field of legacy single-element shares
 }
 
 // NewShamirSecretSharing creates a new instance with a suitable prime
@@ -20,59 +25,129 @@ func NewShamirSecretSharing() *ShamirSecretSharing {
 	}
 }
 
+const (
+	// shareChunkSize is the number of secret bytes per field element. A chunk is
+	// always smaller than the curve order, so no secret is out of range.
+	shareChunkSize = 31
+	// secretLengthSize is the size of the length prefix encoded before chunking,
+	// which preserves leading zero bytes of the secret
+	secretLengthSize = 4
+	// maxShareChunks bounds the chunks of a share, whose count is serialized on 16 bits
+	maxShareChunks = 1<<16 - 1
+)
+
 // Share represents a single share of the secret
 type Share struct {
-	X *big.Int `json:"x"`
-	Y *big.Int `json:"y"`
+	X        *big.Int   `json:"x"`
+	Y        *big.Int   `json:"y,omitempty"`        // Legacy single-element share
+	Values   []*big.Int `json:"values,omitempty"`   // One evaluation per secret chunk
+	Blinding []*big.Int `json:"blinding,omitempty"` // Pedersen blinding evaluations of verifiable shares
+}
+
+// IsLegacy checks whether the share predates chunked secret sharing
+func (s *Share) IsLegacy() bool {
+	return len(s.Values) == 0
 }
 
 // SplitSecret splits a secret into n shares with threshold t
 func (sss *ShamirSecretSharing) SplitSecret(secret []byte, threshold, totalShares int) ([]*Share, error) {
+	shares, _, err := sss.split(secret, threshold, totalShares, false)
+	return shares, err
+}
+
+// SplitSecretVerifiable splits a secret into n shares with threshold t and returns
+// Pedersen commitments to the polynomials, one list of threshold points per chunk.
+// Each share can be checked against the commitments with VerifyShare.
+func (sss *ShamirSecretSharing) SplitSecretVerifiable(secret []byte, threshold, totalShares int) ([]*Share, [][][]byte, error) {
+	return sss.split(secret, threshold, totalShares, true)
+}
+
+func (sss *ShamirSecretSharing) split(secret []byte, threshold, totalShares int, verifiable bool) ([]*Share, [][][]byte, error) {
 	if threshold > totalShares {
-		return nil, fmt.Errorf("threshold (%d) cannot be greater than total shares (%d)", threshold, totalShares)
+		return nil, nil, fmt.Errorf("threshold (%d) cannot be greater than total shares (%d)", threshold, totalShares)
 	}
 	
 	if threshold < 1 {
-		return nil, fmt.Errorf("threshold must be at least 1")
+		return nil, nil, fmt.Errorf("threshold must be at least 1")
 	}
 	
 	if totalShares < 1 {
-		return nil, fmt.Errorf("total shares must be at least 1")
+		return nil, nil, fmt.Errorf("total shares must be at least 1")
 	}
 	
-	// Convert secret to big integer
-	secretInt := new(big.Int).SetBytes(secret)
+	chunks := encodeSecretChunks(secret)
+	if len(chunks) > maxShareChunks {
+		return nil, nil, fmt.Errorf("secret of %d bytes is too large to share", len(secret))
+	}
 	
-	// Ensure secret is within the field
-	if secretInt.Cmp(sss.prime) >= 0 {
-		return nil, fmt.Errorf("secret is too large for the prime field")
+	shares := make([]*Share, totalShares)
+	for i := range shares {
+		shares[i] = &Share{
+			X:      big.NewInt(int64(i + 1)), // x coordinates start from 1
+			Values: make([]*big.Int, len(chunks)),
+		}
+		if verifiable {
+			shares[i].Blinding = make([]*big.Int, len(chunks))
+		}
 	}
 	
-	// Generate random coefficients for polynomial of degree (threshold - 1)
-	coefficients := make([]*big.Int, threshold)
-	coefficients[0] = secretInt // The constant term is the secret
+	var commitments [][][]byte
+	if verifiable {
+		commitments = make([][][]byte, len(chunks))
+	}
 	
-	for i := 1; i < threshold; i++ {
-		coeff, err := rand.Int(rand.Reader, sss.prime)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate coefficient: %w", err)
+	coefficients := make([]secp256k1.ModNScalar, threshold)
+	blinding := make([]secp256k1.ModNScalar, threshold)
+	defer func() {
+		for i := range coefficients {
+			coefficients[i].Zero()
+			blinding[i].Zero()
 		}
-		coefficients[i] = coeff
-	}
+	}()
 	
-	// Generate shares by evaluating polynomial at different points
-	shares := make([]*Share, totalShares)
-	for i := 0; i < totalShares; i++ {
-		x := big.NewInt(int64(i + 1)) // x coordinates start from 1
-		y := sss.evaluatePolynomial(coefficients, x)
+	for c := range chunks {
+		// The constant term is the secret chunk, the others are random
+		coefficients[0].Set(&chunks[c])
+		for j := 1; j < threshold; j++ {
+			if err := randomScalar(&coefficients[j]); err != nil {
+				return nil, nil, err
+			}
+		}
 		
-		shares[i] = &Share{
-			X: new(big.Int).Set(x),
-			Y: new(big.Int).Set(y),
+		if verifiable {
+			for j := range blinding {
+				if err := randomScalar(&blinding[j]); err != nil {
+					return nil, nil, err
+				}
+			}
+			
+			commitments[c] = make([][]byte, threshold)
+			for j := range coefficients {
+				point, err := pedersenCommit(&coefficients[j], &blinding[j])
+				if err != nil {
+					return nil, nil, err
+				}
+				commitments[c][j] = point
+			}
+		}
+		
+		// Generate shares by evaluating the polynomials at each x coordinate
+		for i, share := range shares {
+			var x, y secp256k1.ModNScalar
+			x.SetInt(uint32(i + 1))
+			evaluatePolynomialN(coefficients, &x, &y)
+			share.Values[c] = scalarToBig(&y)
+			
+			if verifiable {
+				var b secp256k1.ModNScalar
+				evaluatePolynomialN(blinding, &x, &b)
+				share.Blinding[c] = scalarToBig(&b)
+			}
 		}
+		chunks[c].Zero()
 	}
 	
-	return shares, nil
+	return shares, commitments, nil
 }
 
 // CombineShares reconstructs the secret from shares using Lagrange interpolation
@@ -81,9 +156,53 @@ func (sss *ShamirSecretSharing) CombineShares(shares []*Share) ([]byte, error) {
 		return nil, fmt.Errorf("need at least 1 share")
 	}
 	
+	if shares[0].IsLegacy() {
+		return sss.combineLegacyShares(shares)
+	}
+	
+	chunkCount := len(shares[0].Values)
+	indices := make([]uint32, len(shares))
+	for i, share := range shares {
+		if share.IsLegacy() || len(share.Values) != chunkCount {
+			return nil, fmt.Errorf("share %d does not match the other shares", i)
+		}
+		index, err := shareIndex(share)
+		if err != nil {
+			return nil, fmt.Errorf("share %d: %w", i, err)
+		}
+		indices[i] = index
+	}
+	
+	lambdas, err := lagrangeCoefficients(indices)
+	if err != nil {
+		return nil, err
+	}
+	
+	chunks := make([]secp256k1.ModNScalar, chunkCount)
+	for c := range chunks {
+		for i, share := range shares {
+			y, err := bigToScalar(share.Values[c])
+			if err != nil {
+				return nil, fmt.Errorf("share %d chunk %d: %w", i, c, err)
+			}
+			
+			var term secp256k1.ModNScalar
+			term.Mul2(y, &lambdas[i])
+			chunks[c].Add(&term)
+		}
+	}
+	
+	return decodeSecretChunks(chunks)
+}
+
+// combineLegacyShares reconstructs a secret from single-element shares over the 256-bit prime
+func (sss *ShamirSecretSharing) combineLegacyShares(shares []*Share) ([]byte, error) {
 	// Check for duplicate x coordinates
 	xCoords := make(map[string]bool)
-	for _, share := range shares {
+	for i, share := range shares {
+		if !share.IsLegacy() || share.X == nil || share.Y == nil {
+			return nil, fmt.Errorf("share %d does not match the other shares", i)
+		}
 		xStr := share.X.String()
 		if xCoords[xStr] {
 			return nil, fmt.Errorf("duplicate x coordinate found: %s", xStr)
@@ -132,25 +251,6 @@ func (sss *ShamirSecretSharing) CombineShares(shares []*Share) ([]byte, error) {
 	return secret.Bytes(), nil
 }
 
-// evaluatePolynomial evaluates the polynomial at a given x coordinate
-func (sss *ShamirSecretSharing) evaluatePolynomial(coefficients []*big.Int, x *big.Int) *big.Int {
-	result := big.NewInt(0)
-	xPower := big.NewInt(1)
-	
-	for _, coeff := range coefficients {
-		// result += coeff * x^i
-		term := new(big.Int).Mul(coeff, xPower)
-		result.Add(result, term)
-		result.Mod(result, sss.prime)
-		
-		// x^i *= x for next iteration
-		xPower.Mul(xPower, x)
-		xPower.Mod(xPower, sss.prime)
-	}
-	
-	return result
-}
-
 // ValidateShares validates that shares are properly formatted
 func (sss *ShamirSecretSharing) ValidateShares(shares []*Share) error {
 	if len(shares) == 0 {
@@ -158,7 +258,7 @@ func (sss *ShamirSecretSharing) ValidateShares(shares []*Share) error {
 	}
 	
 	for i, share := range shares {
-		if share.X == nil || share.Y == nil {
+		if share.X == nil {
 			return fmt.Errorf("share %d has nil coordinates", i)
 		}
 		
@@ -166,8 +266,31 @@ func (sss *ShamirSecretSharing) ValidateShares(shares []*Share) error {
 			return fmt.Errorf("share %d has invalid x coordinate (must be positive)", i)
 		}
 		
-		if share.X.Cmp(sss.prime) >= 0 || share.Y.Cmp(sss.prime) >= 0 {
-			return fmt.Errorf("share %d coordinates are outside the prime field", i)
+		if share.IsLegacy() {
+			if share.Y == nil {
+				return fmt.Errorf("share %d has nil coordinates", i)
+			}
+			if share.X.Cmp(sss.prime) >= 0 || share.Y.Cmp(sss.prime) >= 0 {
+				return fmt.Errorf("share %d coordinates are outside the prime field", i)
+			}
+			continue
+		}
+		
+		if _, err := shareIndex(share); err != nil {
+			return fmt.Errorf("share %d: %w", i, err)
+		}
+		if len(share.Blinding) != 0 && len(share.Blinding) != len(share.Values) {
+			return fmt.Errorf("share %d has %d blinding values for %d chunks", i, len(share.Blinding), len(share.Values))
+		}
+		for c := range share.Values {
+			if _, err := bigToScalar(share.Values[c]); err != nil {
+				return fmt.Errorf("share %d chunk %d: %w", i, c, err)
+			}
+			if len(share.Blinding) > 0 {
+				if _, err := bigToScalar(share.Blinding[c]); err != nil {
+					return fmt.Errorf("share %d blinding %d: %w", i, c, err)
+				}
+			}
 		}
 	}
 	
@@ -208,8 +331,20 @@ type EncryptedShare struct {
 	EncryptedData *EncryptedData `json:"encrypted_data"`
 }
 
+// chunkedShareFormat marks the serialization of chunked shares. Legacy shares start
+// with the high byte of the x coordinate length, which is always zero.
+const chunkedShareFormat = 0x01
+
 // shareToBytes converts a share to byte representation
 func (sss *ShamirSecretSharing) shareToBytes(share *Share) []byte {
+	if !share.IsLegacy() {
+		// Format: [format][x_len][x_bytes][count][values...][count][blinding...]
+		result := []byte{chunkedShareFormat}
+		result = appendLengthPrefixed(result, share.X.Bytes())
+		result = appendBigInts(result, share.Values)
+		return appendBigInts(result, share.Blinding)
+	}
+	
 	xBytes := share.X.Bytes()
 	yBytes := share.Y.Bytes()
 	
@@ -245,6 +380,10 @@ func (sss *ShamirSecretSharing) bytesToShare(data []byte) (*Share, error) {
 		return nil, fmt.Errorf("invalid share data: too short")
 	}
 	
+	if data[0] == chunkedShareFormat {
+		return bytesToChunkedShare(data[1:])
+	}
+	
 	// Read X coordinate
 	xLen := int(data[0])<<8 | int(data[1])
 	if len(data) < 2+xLen+2 {
@@ -264,4 +403,146 @@ func (sss *ShamirSecretSharing) bytesToShare(data []byte) (*Share, error) {
 	y := new(big.Int).SetBytes(yBytes)
 	
 	return &Share{X: x, Y: y}, nil
-}
\ No newline at end of file
+}
+
+// bytesToChunkedShare decodes a chunked share, without its format byte
+func bytesToChunkedShare(data []byte) (*Share, error) {
+	xBytes, rest, err := readLengthPrefixed(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid share data: x coordinate: %w", err)
+	}
+	
+	values, rest, err := readBigInts(rest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid share data: values: %w", err)
+	}
+	
+	blinding, rest, err := readBigInts(rest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid share data: blinding: %w", err)
+	}
+	
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("invalid share data: %d trailing bytes", len(rest))
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("invalid share data: no values")
+	}
+	
+	return &Share{
+		X:        new(big.Int).SetBytes(xBytes),
+		Values:   values,
+		Blinding: blinding,
+	}, nil
+}
+
+func appendLengthPrefixed(dst, b []byte) []byte {
+	dst = binary.BigEndian.AppendUint16(dst, uint16(len(b)))
+	return append(dst, b...)
+}
+
+func appendBigInts(dst []byte, values []*big.Int) []byte {
+	dst = binary.BigEndian.AppendUint16(dst, uint16(len(values)))
+	for _, v := range values {
+		dst = appendLengthPrefixed(dst, v.Bytes())
+	}
+	return dst
+}
+
+func readLengthPrefixed(data []byte) ([]byte, []byte, error) {
+	if len(data) < 2 {
+		return nil, nil, fmt.Errorf("missing length")
+	}
+	n := int(binary.BigEndian.Uint16(data))
+	if len(data) < 2+n {
+		return nil, nil, fmt.Errorf("need %d bytes, got %d", n, len(data)-2)
+	}
+	return data[2 : 2+n], data[2+n:], nil
+}
+
+func readBigInts(data []byte) ([]*big.Int, []byte, error) {
+	if len(data) < 2 {
+		return nil, nil, fmt.Errorf("missing count")
+	}
+	count := int(binary.BigEndian.Uint16(data))
+	data = data[2:]
+	
+	var values []*big.Int
+	for i := 0; i < count; i++ {
+		b, rest, err := readLengthPrefixed(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		values = append(values, new(big.Int).SetBytes(b))
+		data = rest
+	}
+	return values, data, nil
+}
+
+// encodeSecretChunks prefixes the secret with its length and splits it into
+// big-endian chunks of shareChunkSize bytes, zero-padding the last one
+func encodeSecretChunks(secret []byte) []secp256k1.ModNScalar {
+	encoded := make([]byte, secretLengthSize, secretLengthSize+len(secret)+shareChunkSize)
+	binary.BigEndian.PutUint32(encoded, uint32(len(secret)))
+	encoded = append(encoded, secret...)
+	if pad := len(encoded) % shareChunkSize; pad != 0 {
+		encoded = append(encoded, make([]byte, shareChunkSize-pad)...)
+	}
+	defer WipeKey(encoded)
+	
+	chunks := make([]secp256k1.ModNScalar, len(encoded)/shareChunkSize)
+	for c := range chunks {
+		chunks[c].SetByteSlice(encoded[c*shareChunkSize : (c+1)*shareChunkSize])
+	}
+	return chunks
+}
+
+// decodeSecretChunks reverses encodeSecretChunks
+func decodeSecretChunks(chunks []secp256k1.ModNScalar) ([]byte, error) {
+	encoded := make([]byte, 0, len(chunks)*shareChunkSize)
+	for c := range chunks {
+		bz := chunks[c].Bytes()
+		if bz[0] != 0 {
+			return nil, fmt.Errorf("chunk %d is out of range, shares are inconsistent", c)
+		}
+		encoded = append(encoded, bz[1:]...)
+		chunks[c].Zero()
+	}
+	defer WipeKey(encoded)
+	
+	if len(encoded) < secretLengthSize {
+		return nil, fmt.Errorf("no secret length")
+	}
+	length := int(binary.BigEndian.Uint32(encoded))
+	if length > len(encoded)-secretLengthSize {
+		return nil, fmt.Errorf("secret length %d exceeds the shared data, shares are inconsistent", length)
+	}
+	
+	secret := make([]byte, length)
+	copy(secret, encoded[secretLengthSize:])
+	return secret, nil
+}
+
+// shareIndex returns the x coordinate of a chunked share as a share index
+func shareIndex(share *Share) (uint32, error) {
+	if share.X == nil || share.X.Sign() <= 0 || !share.X.IsUint64() || share.X.Uint64() > uint64(^uint32(0)) {
+		return 0, fmt.Errorf("invalid x coordinate")
+	}
+	return uint32(share.X.Uint64()), nil
+}
+
+func scalarToBig(s *secp256k1.ModNScalar) *big.Int {
+	bz := s.Bytes()
+	return new(big.Int).SetBytes(bz[:])
+}
+
+func bigToScalar(b *big.Int) (*secp256k1.ModNScalar, error) {
+	if b == nil || b.Sign() < 0 || b.BitLen() > 256 {
+		return nil, fmt.Errorf("value is outside the curve order")
+	}
+	var s secp256k1.ModNScalar
+	if overflow := s.SetByteSlice(b.Bytes()); overflow {
+		return nil, fmt.Errorf("value is outside the curve order")
+	}
+	return &s, nil
+}
@@ -0,0 +1,191 @@
+package crypto_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+)
+
+func TestSplitCombineChunkedSecret(t *testing.T) {
+	sss := crypto.NewShamirSecretSharing()
+
+	testCases := []struct {
+		name   string
+		secret []byte
+	}{
+		{"empty secret", []byte{}},
+		{"single byte", []byte{0x2a}},
+		{"leading zero bytes", append(make([]byte, 5), 0x01, 0x02)},
+		{"one chunk minus the length prefix", bytes.Repeat([]byte{0xff}, 27)},
+		{"exactly one chunk with the length prefix", bytes.Repeat([]byte{0xff}, 31)},
+		{"32 byte key", bytes.Repeat([]byte{0xab}, 32)},
+		{"many chunks", bytes.Repeat([]byte{0x01, 0xfe, 0x00}, 400)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			shares, err := sss.SplitSecret(tc.secret, 3, 5)
+			require.NoError(t, err)
+			require.Len(t, shares, 5)
+			require.NoError(t, sss.ValidateShares(shares))
+
+			// Every share holds one value per chunk of the length-prefixed secret
+			chunks := (4 + len(tc.secret) + 30) / 31
+			for _, share := range shares {
+				require.False(t, share.IsLegacy())
+				require.Len(t, share.Values, chunks)
+				require.Empty(t, share.Blinding)
+			}
+
+			for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+				picked := make([]*crypto.Share, len(subset))
+				for i, s := range subset {
+					picked[i] = shares[s]
+				}
+				secret, err := sss.CombineShares(picked)
+				require.NoError(t, err)
+				require.Equal(t, tc.secret, secret, "subset %v", subset)
+			}
+
+			// Fewer shares than the threshold do not reveal the secret
+			secret, err := sss.CombineShares(shares[:2])
+			if err == nil {
+				require.NotEqual(t, tc.secret, secret)
+			}
+		})
+	}
+}
+
+func TestSplitSecretArguments(t *testing.T) {
+	sss := crypto.NewShamirSecretSharing()
+
+	_, err := sss.SplitSecret([]byte("secret"), 4, 3)
+	require.ErrorContains(t, err, "cannot be greater")
+
+	_, err = sss.SplitSecret([]byte("secret"), 0, 3)
+	require.ErrorContains(t, err, "threshold must be at least 1")
+
+	shares, err := sss.SplitSecret([]byte("secret"), 1, 1)
+	require.NoError(t, err)
+	secret, err := sss.CombineShares(shares)
+	require.NoError(t, err)
+	require.Equal(t, []byte("secret"), secret)
+}
+
+func TestCombineMismatchedShares(t *testing.T) {
+	sss := crypto.NewShamirSecretSharing()
+
+	short, err := sss.SplitSecret([]byte("short"), 2, 3)
+	require.NoError(t, err)
+	long, err := sss.SplitSecret(bytes.Repeat([]byte("long"), 20), 2, 3)
+	require.NoError(t, err)
+
+	// Shares of secrets with a different chunk count cannot be combined
+	_, err = sss.CombineShares([]*crypto.Share{short[0], long[1]})
+	require.ErrorContains(t, err, "does not match")
+
+	// Nor can a chunked share be mixed with a legacy one
+	legacy := &crypto.Share{X: big.NewInt(2), Y: big.NewInt(7)}
+	_, err = sss.CombineShares([]*crypto.Share{short[0], legacy})
+	require.ErrorContains(t, err, "does not match")
+
+	// A duplicated share gives no second point of the polynomial
+	_, err = sss.CombineShares([]*crypto.Share{short[0], short[0]})
+	require.Error(t, err)
+}
+
+func TestCombineLegacyShares(t *testing.T) {
+	sss := crypto.NewShamirSecretSharing()
+	prime, ok := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639747", 10)
+	require.True(t, ok)
+
+	// Shares of f(x) = secret + 5x over the legacy prime field
+	secret := big.NewInt(123456789)
+	legacyShare := func(x int64) *crypto.Share {
+		y := new(big.Int).Add(secret, big.NewInt(5*x))
+		return &crypto.Share{X: big.NewInt(x), Y: y.Mod(y, prime)}
+	}
+	shares := []*crypto.Share{legacyShare(1), legacyShare(3)}
+	require.True(t, shares[0].IsLegacy())
+	require.NoError(t, sss.ValidateShares(shares))
+
+	combined, err := sss.CombineShares(shares)
+	require.NoError(t, err)
+	require.Equal(t, secret.Bytes(), combined)
+
+	_, err = sss.CombineShares([]*crypto.Share{legacyShare(1), legacyShare(1)})
+	require.ErrorContains(t, err, "duplicate x coordinate")
+}
+
+func TestShareSerialization(t *testing.T) {
+	sss := crypto.NewShamirSecretSharing()
+
+	plain, err := sss.SplitSecret(bytes.Repeat([]byte{0x5a}, 70), 2, 3)
+	require.NoError(t, err)
+	verifiable, _, err := sss.SplitSecretVerifiable(bytes.Repeat([]byte{0x5a}, 70), 2, 3)
+	require.NoError(t, err)
+	legacy := &crypto.Share{X: big.NewInt(3), Y: big.NewInt(99)}
+
+	for _, share := range []*crypto.Share{plain[0], verifiable[1], legacy} {
+		decoded, err := crypto.BytesToShare(crypto.ShareToBytes(share))
+		require.NoError(t, err)
+		require.Equal(t, 0, share.X.Cmp(decoded.X))
+		require.Equal(t, share.IsLegacy(), decoded.IsLegacy())
+		if share.IsLegacy() {
+			require.Equal(t, 0, share.Y.Cmp(decoded.Y))
+			continue
+		}
+		require.Equal(t, share.Values, decoded.Values)
+		require.Equal(t, share.Blinding, decoded.Blinding)
+	}
+
+	encoded := crypto.ShareToBytes(verifiable[0])
+
+	_, err = crypto.BytesToShare(encoded[:len(encoded)-1])
+	require.Error(t, err)
+
+	_, err = crypto.BytesToShare(append(encoded, 0x00))
+	require.ErrorContains(t, err, "trailing bytes")
+
+	_, err = crypto.BytesToShare([]byte{0x01, 0x00})
+	require.ErrorContains(t, err, "too short")
+}
+
+func TestValidateShares(t *testing.T) {
+	sss := crypto.NewShamirSecretSharing()
+
+	shares, _, err := sss.SplitSecretVerifiable([]byte("secret"), 2, 3)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name   string
+		share  *crypto.Share
+		expErr string
+	}{
+		{"nil x coordinate", &crypto.Share{Values: shares[0].Values}, "nil coordinates"},
+		{"zero x coordinate", &crypto.Share{X: big.NewInt(0), Values: shares[0].Values}, "invalid x coordinate"},
+		{
+			"value outside the curve order",
+			&crypto.Share{X: big.NewInt(1), Values: []*big.Int{new(big.Int).Lsh(big.NewInt(1), 256)}},
+			"outside the curve order",
+		},
+		{
+			"blinding count mismatch",
+			&crypto.Share{X: big.NewInt(1), Values: shares[0].Values, Blinding: []*big.Int{big.NewInt(1), big.NewInt(2)}},
+			"blinding values",
+		},
+		{"legacy share without y", &crypto.Share{X: big.NewInt(1)}, "nil coordinates"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.ErrorContains(t, sss.ValidateShares([]*crypto.Share{tc.share}), tc.expErr)
+		})
+	}
+
+	require.ErrorContains(t, sss.ValidateShares(nil), "no shares")
+}
@@ -0,0 +1,184 @@
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+)
+
+// timelockValidator is a validator taking part in the key generation of an epoch
+type timelockValidator struct {
+	privKey []byte
+	pubKey  []byte
+	share   []byte
+}
+
+// runTimelockDKG runs a joint-Feldman key generation between n validators and
+// returns them with their epoch shares, and the aggregated commitments
+func runTimelockDKG(t *testing.T, threshold, n int) ([]timelockValidator, [][]byte) {
+	t.Helper()
+
+	validators := make([]timelockValidator, n)
+	recipients := make([][]byte, n)
+	for i := range validators {
+		priv, pub, err := crypto.GenerateTimelockKey()
+		require.NoError(t, err)
+		validators[i] = timelockValidator{privKey: priv, pubKey: pub}
+		recipients[i] = pub
+	}
+
+	dealings := make([][][]byte, n)
+	received := make([][][]byte, n)
+	for d := 0; d < n; d++ {
+		commitments, sealed, err := crypto.DealTimelockShares(threshold, recipients)
+		require.NoError(t, err)
+		require.Len(t, commitments, threshold)
+		dealings[d] = commitments
+
+		for i, v := range validators {
+			share, err := crypto.OpenTimelockShare(v.privKey, sealed[i])
+			require.NoError(t, err)
+			require.NoError(t, crypto.VerifyTimelockShare(commitments, uint32(i+1), share))
+			received[i] = append(received[i], share)
+		}
+	}
+
+	for i := range validators {
+		share, err := crypto.AddTimelockShares(received[i])
+		require.NoError(t, err)
+		validators[i].share = share
+	}
+
+	aggregated, err := crypto.AggregateTimelockCommitments(dealings)
+	require.NoError(t, err)
+
+	return validators, aggregated
+}
+
+func TestTimelockKeyGeneration(t *testing.T) {
+	validators, aggregated := runTimelockDKG(t, 2, 3)
+
+	// Every summed share matches the aggregated commitments
+	for i, v := range validators {
+		require.NoError(t, crypto.VerifyTimelockShare(aggregated, uint32(i+1), v.share))
+	}
+
+	// Any threshold of shares reconstructs the secret of the epoch public key
+	for _, subset := range [][]uint32{{1, 2}, {1, 3}, {2, 3}, {1, 2, 3}} {
+		shares := make([][]byte, len(subset))
+		for i, index := range subset {
+			shares[i] = validators[index-1].share
+		}
+		secret, err := crypto.CombineTimelockShares(subset, shares)
+		require.NoError(t, err)
+
+		pub, err := crypto.TimelockPublicKey(secret)
+		require.NoError(t, err)
+		require.Equal(t, aggregated[0], pub, "subset %v", subset)
+	}
+
+	// Fewer shares than the threshold do not
+	secret, err := crypto.CombineTimelockShares([]uint32{1}, [][]byte{validators[0].share})
+	require.NoError(t, err)
+	pub, err := crypto.TimelockPublicKey(secret)
+	require.NoError(t, err)
+	require.NotEqual(t, aggregated[0], pub)
+}
+
+func TestTimelockRoundTrip(t *testing.T) {
+	validators, aggregated := runTimelockDKG(t, 2, 3)
+	epochPub := aggregated[0]
+
+	key, err := crypto.SecureRandom(32)
+	require.NoError(t, err)
+
+	sealed, err := crypto.SealToTimelock(epochPub, 7, key)
+	require.NoError(t, err)
+
+	// Sealing is deterministic so every validator computes the same state
+	again, err := crypto.SealToTimelock(epochPub, 7, key)
+	require.NoError(t, err)
+	require.Equal(t, sealed, again)
+
+	secret, err := crypto.CombineTimelockShares(
+		[]uint32{1, 3},
+		[][]byte{validators[0].share, validators[2].share},
+	)
+	require.NoError(t, err)
+
+	opened, err := crypto.OpenTimelock(secret, 7, sealed)
+	require.NoError(t, err)
+	require.Equal(t, key, opened)
+
+	// The key is bound to its epoch
+	_, err = crypto.OpenTimelock(secret, 8, sealed)
+	require.Error(t, err)
+
+	// A secret that is not the epoch secret does not open it
+	other, _, err := crypto.GenerateTimelockKey()
+	require.NoError(t, err)
+	_, err = crypto.OpenTimelock(other, 7, sealed)
+	require.Error(t, err)
+}
+
+func TestVerifyTimelockShareRejectsTamperedShare(t *testing.T) {
+	priv, pub, err := crypto.GenerateTimelockKey()
+	require.NoError(t, err)
+	_, otherPub, err := crypto.GenerateTimelockKey()
+	require.NoError(t, err)
+
+	commitments, sealed, err := crypto.DealTimelockShares(2, [][]byte{pub, otherPub})
+	require.NoError(t, err)
+
+	share, err := crypto.OpenTimelockShare(priv, sealed[0])
+	require.NoError(t, err)
+	require.NoError(t, crypto.VerifyTimelockShare(commitments, 1, share))
+
+	// Checked against the wrong index
+	require.Error(t, crypto.VerifyTimelockShare(commitments, 2, share))
+	require.Error(t, crypto.VerifyTimelockShare(commitments, 0, share))
+
+	tampered := append([]byte(nil), share...)
+	tampered[len(tampered)-1] ^= 0x01
+	require.Error(t, crypto.VerifyTimelockShare(commitments, 1, tampered))
+
+	// A share sealed to another validator cannot be opened
+	_, err = crypto.OpenTimelockShare(priv, sealed[1])
+	require.Error(t, err)
+}
+
+func TestTimelockSharedKeyProof(t *testing.T) {
+	priv, pub, err := crypto.GenerateTimelockKey()
+	require.NoError(t, err)
+
+	share, err := crypto.SecureRandom(crypto.TimelockScalarSize)
+	require.NoError(t, err)
+	sealed, err := crypto.SealTimelockShare(pub, share)
+	require.NoError(t, err)
+
+	shared, proof, err := crypto.ProveTimelockSharedKey(priv, sealed)
+	require.NoError(t, err)
+	require.Len(t, proof, crypto.TimelockProofSize)
+	require.NoError(t, crypto.VerifyTimelockSharedKey(pub, sealed, shared, proof))
+
+	// The revealed shared key opens the share, so a complaint can be checked on chain
+	opened, err := crypto.OpenTimelockShareWithSharedKey(shared, sealed)
+	require.NoError(t, err)
+	require.Equal(t, share, opened)
+
+	// The proof does not hold for another recipient key
+	_, otherPub, err := crypto.GenerateTimelockKey()
+	require.NoError(t, err)
+	require.Error(t, crypto.VerifyTimelockSharedKey(otherPub, sealed, shared, proof))
+
+	// Nor for a forged shared key
+	_, forged, err := crypto.GenerateTimelockKey()
+	require.NoError(t, err)
+	require.Error(t, crypto.VerifyTimelockSharedKey(pub, sealed, forged, proof))
+
+	tampered := append([]byte(nil), proof...)
+	tampered[len(tampered)-1] ^= 0x01
+	require.Error(t, crypto.VerifyTimelockSharedKey(pub, sealed, shared, tampered))
+}
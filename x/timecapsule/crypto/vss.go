@@ -0,0 +1,117 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// Verifiable secret sharing uses Pedersen commitments on secp256k1. For every secret
+// chunk the dealer commits to each coefficient a_j of the secret polynomial and b_j of
+// a blinding polynomial as C_j = a_j·G + b_j·H. A share (x, v, b) is valid when
+// v·G + b·H == Σ C_j·x^j. Unlike Feldman commitments, C_0 reveals nothing about the
+// secret, which matters for secrets that are not uniformly random.
+
+const pedersenDomain = "timecapsule/vss/pedersen/v1"
+
+// pedersenH is the second generator, whose discrete logarithm to G is unknown
+var pedersenH = hashToPoint([]byte(pedersenDomain))
+
+// VerifyShare checks a verifiable share against the Pedersen commitments published
+// by SplitSecretVerifiable
+func VerifyShare(commitments [][][]byte, share *Share) error {
+	if share == nil || share.IsLegacy() {
+		return errors.New("share is not a chunked share")
+	}
+
+	index, err := shareIndex(share)
+	if err != nil {
+		return err
+	}
+
+	if len(share.Values) != len(commitments) || len(share.Blinding) != len(commitments) {
+		return fmt.Errorf("share %d has %d values and %d blinding values for %d commitments",
+			index, len(share.Values), len(share.Blinding), len(commitments))
+	}
+
+	for c := range commitments {
+		v, err := bigToScalar(share.Values[c])
+		if err != nil {
+			return fmt.Errorf("share %d chunk %d: %w", index, c, err)
+		}
+		b, err := bigToScalar(share.Blinding[c])
+		if err != nil {
+			return fmt.Errorf("share %d blinding %d: %w", index, c, err)
+		}
+
+		expected, err := evaluateCommitments(commitments[c], index)
+		if err != nil {
+			return fmt.Errorf("chunk %d: %w", c, err)
+		}
+
+		var actual secp256k1.JacobianPoint
+		pedersenPoint(v, b, &actual)
+
+		if !pointsEqual(&actual, expected) {
+			return fmt.Errorf("share %d does not match the commitments of chunk %d", index, c)
+		}
+	}
+
+	return nil
+}
+
+// ValidateShareCommitments checks that commitments are well-formed points for a threshold
+func ValidateShareCommitments(commitments [][][]byte, threshold int) error {
+	if len(commitments) == 0 {
+		return errors.New("no commitments")
+	}
+	for c, chunk := range commitments {
+		if len(chunk) != threshold {
+			return fmt.Errorf("chunk %d has %d commitments, expected %d", c, len(chunk), threshold)
+		}
+		for j, point := range chunk {
+			if _, err := parsePoint(point); err != nil {
+				return fmt.Errorf("chunk %d commitment %d: %w", c, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+// pedersenCommit returns the serialized commitment a·G + b·H
+func pedersenCommit(a, b *secp256k1.ModNScalar) ([]byte, error) {
+	var p secp256k1.JacobianPoint
+	pedersenPoint(a, b, &p)
+	return pointBytes(&p)
+}
+
+// pedersenPoint computes a·G + b·H
+func pedersenPoint(a, b *secp256k1.ModNScalar, result *secp256k1.JacobianPoint) {
+	var aG, bH secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(a, &aG)
+	secp256k1.ScalarMultNonConst(b, pedersenH, &bH)
+	secp256k1.AddNonConst(&aG, &bH, result)
+}
+
+// hashToPoint derives a curve point with unknown discrete logarithm by hashing the
+// label to x coordinates until one is on the curve
+func hashToPoint(label []byte) *secp256k1.JacobianPoint {
+	for counter := uint32(0); ; counter++ {
+		h := sha256.New()
+		h.Write(label)
+		h.Write(binary.BigEndian.AppendUint32(nil, counter))
+
+		candidate := append([]byte{0x02}, h.Sum(nil)...) // even y coordinate
+		pub, err := secp256k1.ParsePubKey(candidate)
+		if err != nil {
+			continue
+		}
+
+		var p secp256k1.JacobianPoint
+		pub.AsJacobian(&p)
+		return &p
+	}
+}
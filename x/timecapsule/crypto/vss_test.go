@@ -0,0 +1,146 @@
+package crypto_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+)
+
+func TestVerifiableSharesMatchCommitments(t *testing.T) {
+	sss := crypto.NewShamirSecretSharing()
+	secret := bytes.Repeat([]byte{0x42}, 100)
+
+	shares, commitments, err := sss.SplitSecretVerifiable(secret, 3, 5)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+
+	// One list of threshold commitments per chunk of the secret
+	require.Len(t, commitments, len(shares[0].Values))
+	require.NoError(t, crypto.ValidateShareCommitments(commitments, 3))
+	require.Error(t, crypto.ValidateShareCommitments(commitments, 2))
+	require.Error(t, crypto.ValidateShareCommitments(nil, 3))
+
+	for _, share := range shares {
+		require.Len(t, share.Blinding, len(share.Values))
+		require.NoError(t, crypto.VerifyShare(commitments, share))
+	}
+
+	// The blinding values do not affect the combined secret
+	combined, err := sss.CombineShares(shares[1:4])
+	require.NoError(t, err)
+	require.Equal(t, secret, combined)
+}
+
+func TestVerifyShareRejectsTamperedShare(t *testing.T) {
+	sss := crypto.NewShamirSecretSharing()
+
+	shares, commitments, err := sss.SplitSecretVerifiable([]byte("a secret spanning two chunks of shares"), 2, 3)
+	require.NoError(t, err)
+	require.Len(t, commitments, 2)
+
+	other, otherCommitments, err := sss.SplitSecretVerifiable([]byte("another secret spanning two chunks ...."), 2, 3)
+	require.NoError(t, err)
+
+	// copyShare returns a deep copy of the first share that can be modified
+	copyShare := func() *crypto.Share {
+		share := &crypto.Share{X: new(big.Int).Set(shares[0].X)}
+		for _, v := range shares[0].Values {
+			share.Values = append(share.Values, new(big.Int).Set(v))
+		}
+		for _, b := range shares[0].Blinding {
+			share.Blinding = append(share.Blinding, new(big.Int).Set(b))
+		}
+		return share
+	}
+
+	testCases := []struct {
+		name   string
+		share  func() *crypto.Share
+		expErr string
+	}{
+		{
+			name: "tampered value",
+			share: func() *crypto.Share {
+				share := copyShare()
+				share.Values[1].Add(share.Values[1], big.NewInt(1))
+				return share
+			},
+			expErr: "does not match the commitments of chunk 1",
+		},
+		{
+			name: "tampered blinding",
+			share: func() *crypto.Share {
+				share := copyShare()
+				share.Blinding[0].Add(share.Blinding[0], big.NewInt(1))
+				return share
+			},
+			expErr: "does not match the commitments of chunk 0",
+		},
+		{
+			name: "wrong index",
+			share: func() *crypto.Share {
+				share := copyShare()
+				share.X.SetInt64(2)
+				return share
+			},
+			expErr: "does not match",
+		},
+		{
+			name:   "share of another dealing",
+			share:  func() *crypto.Share { return other[0] },
+			expErr: "does not match",
+		},
+		{
+			name: "missing blinding",
+			share: func() *crypto.Share {
+				share := copyShare()
+				share.Blinding = nil
+				return share
+			},
+			expErr: "blinding values",
+		},
+		{
+			name: "missing chunk",
+			share: func() *crypto.Share {
+				share := copyShare()
+				share.Values = share.Values[:1]
+				share.Blinding = share.Blinding[:1]
+				return share
+			},
+			expErr: "commitments",
+		},
+		{
+			name:   "legacy share",
+			share:  func() *crypto.Share { return &crypto.Share{X: big.NewInt(1), Y: big.NewInt(1)} },
+			expErr: "not a chunked share",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.ErrorContains(t, crypto.VerifyShare(commitments, tc.share()), tc.expErr)
+		})
+	}
+
+	// The untouched shares still verify against their own commitments only
+	require.NoError(t, crypto.VerifyShare(commitments, shares[0]))
+	require.NoError(t, crypto.VerifyShare(otherCommitments, other[0]))
+	require.Error(t, crypto.VerifyShare(otherCommitments, shares[0]))
+}
+
+func TestValidateShareCommitmentsRejectsMalformedPoint(t *testing.T) {
+	sss := crypto.NewShamirSecretSharing()
+
+	_, commitments, err := sss.SplitSecretVerifiable([]byte("secret"), 2, 3)
+	require.NoError(t, err)
+
+	malformed := append([]byte(nil), commitments[0][1]...)
+	malformed[0] = 0x05
+	commitments[0][1] = malformed
+
+	require.ErrorContains(t, crypto.ValidateShareCommitments(commitments, 2), "chunk 0 commitment 1")
+}
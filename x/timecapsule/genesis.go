@@ -15,6 +15,23 @@ type GenesisState struct {
 	KeyShares      []types.KeyShare       `json:"key_shares"`
 	CapsuleCounter uint64                 `json:"capsule_counter"`
 	ConditionContracts []types.ConditionContract `json:"condition_contracts"`
+	TimelockKeys       []types.TimelockValidatorKey `json:"timelock_keys"`
+	TimelockEpochs     []types.TimelockEpoch        `json:"timelock_epochs"`
+	TimelockDealings   []types.TimelockDealing      `json:"timelock_dealings"`
+	EmergencyActions   []types.EmergencyAction      `json:"emergency_actions"`
+	AccessLog          []types.CapsuleAccess        `json:"access_log"`
+	AccessLogSequence  uint64                       `json:"access_log_sequence"`
+	CapsuleVersions    []types.CapsuleVersion       `json:"capsule_versions"`
+	ShareRefreshState  types.ShareRefreshState      `json:"share_refresh_state"`
+	CustodianDepartures []string                    `json:"custodian_departures"`
+	CapsuleAllowances  []types.CapsuleAllowance     `json:"capsule_allowances"`
+	Attestors          []types.Attestor             `json:"attestors"`
+	Attestations       []types.Attestation          `json:"attestations"`
+	CreationQuotas     []types.AccountCreationQuota `json:"creation_quotas"`
+	CreationVolume     *types.CreationVolume        `json:"creation_volume,omitempty"`
+	TransferHistory    []types.TransferHistory      `json:"transfer_history"`
+	PendingTransfers   []types.PendingTransfer      `json:"pending_transfers"`
+	TransferStats      *types.TransferStats         `json:"transfer_stats,omitempty"`
 }
 
 // DefaultGenesis returns the default time capsule genesis state
@@ -25,6 +42,21 @@ func DefaultGenesis() *GenesisState {
 		KeyShares:          []types.KeyShare{},
 		CapsuleCounter:     0,
 		ConditionContracts: []types.ConditionContract{},
+		TimelockKeys:       []types.TimelockValidatorKey{},
+		TimelockEpochs:     []types.TimelockEpoch{},
+		TimelockDealings:   []types.TimelockDealing{},
+		EmergencyActions:   []types.EmergencyAction{},
+		AccessLog:          []types.CapsuleAccess{},
+		AccessLogSequence:  0,
+		CapsuleVersions:    []types.CapsuleVersion{},
+		ShareRefreshState:  types.ShareRefreshState{},
+		CustodianDepartures: []string{},
+		CapsuleAllowances:  []types.CapsuleAllowance{},
+		Attestors:          []types.Attestor{},
+		Attestations:       []types.Attestation{},
+		CreationQuotas:     []types.AccountCreationQuota{},
+		TransferHistory:    []types.TransferHistory{},
+		PendingTransfers:   []types.PendingTransfer{},
 	}
 }
 
@@ -95,6 +127,205 @@ func ValidateGenesis(genState *GenesisState) error {
 		}
 	}
 
+	// Validate emergency actions
+	actionIDs := make(map[string]bool)
+	for i, action := range genState.EmergencyActions {
+		if action.ID == "" {
+			return fmt.Errorf("emergency action at index %d has empty ID", i)
+		}
+		
+		if actionIDs[action.ID] {
+			return fmt.Errorf("duplicate emergency action ID %s", action.ID)
+		}
+		actionIDs[action.ID] = true
+		
+		if !capsuleIDs[action.CapsuleID] {
+			return fmt.Errorf("emergency action %s references non-existent capsule ID %d", action.ID, action.CapsuleID)
+		}
+		
+		if err := types.ValidateEmergencyActionType(action.ActionType); err != nil {
+			return fmt.Errorf("invalid emergency action %s: %w", action.ID, err)
+		}
+	}
+
+	// Validate capsule version history
+	capsuleVersions := make(map[uint64]uint32)
+	for _, capsule := range genState.Capsules {
+		capsuleVersions[capsule.ID] = capsule.CurrentVersion()
+	}
+	seenVersions := make(map[string]bool)
+	for i, version := range genState.CapsuleVersions {
+		current, found := capsuleVersions[version.CapsuleID]
+		if !found {
+			return fmt.Errorf("capsule version at index %d references non-existent capsule ID %d", i, version.CapsuleID)
+		}
+		
+		if version.Version == 0 || version.Version >= current {
+			return fmt.Errorf("capsule %d version %d is not a superseded version", version.CapsuleID, version.Version)
+		}
+		
+		key := fmt.Sprintf("%d/%d", version.CapsuleID, version.Version)
+		if seenVersions[key] {
+			return fmt.Errorf("duplicate capsule %d version %d", version.CapsuleID, version.Version)
+		}
+		seenVersions[key] = true
+		
+		if version.DataHash == "" {
+			return fmt.Errorf("capsule %d version %d has empty data hash", version.CapsuleID, version.Version)
+		}
+	}
+
+	// Validate custodians awaiting re-distribution
+	seenDepartures := make(map[string]bool)
+	for i, custodian := range genState.CustodianDepartures {
+		if custodian == "" {
+			return fmt.Errorf("custodian departure at index %d has empty custodian", i)
+		}
+		if seenDepartures[custodian] {
+			return fmt.Errorf("duplicate custodian departure %s", custodian)
+		}
+		seenDepartures[custodian] = true
+	}
+	if departing := genState.ShareRefreshState.DepartingCustodian; departing != "" && !seenDepartures[departing] {
+		return fmt.Errorf("departing custodian %s is not awaiting re-distribution", departing)
+	}
+
+	// Validate the access log
+	accessSequences := make(map[uint64]bool)
+	for i, access := range genState.AccessLog {
+		if !capsuleIDs[access.CapsuleID] {
+			return fmt.Errorf("access log entry at index %d references non-existent capsule ID %d", i, access.CapsuleID)
+		}
+		
+		if access.Accessor == "" {
+			return fmt.Errorf("access log entry at index %d has empty accessor", i)
+		}
+		
+		if accessSequences[access.Sequence] {
+			return fmt.Errorf("duplicate access log sequence %d", access.Sequence)
+		}
+		accessSequences[access.Sequence] = true
+		
+		if access.Sequence >= genState.AccessLogSequence {
+			return fmt.Errorf("access log sequence %d exceeds next sequence %d", access.Sequence, genState.AccessLogSequence)
+		}
+	}
+
+	// Validate timelock epochs and their dealings
+	epochs := make(map[uint64]types.TimelockEpoch)
+	for i, epoch := range genState.TimelockEpochs {
+		if _, found := epochs[epoch.Epoch]; found {
+			return fmt.Errorf("duplicate timelock epoch %d", epoch.Epoch)
+		}
+		if len(epoch.Participants) == 0 || epoch.Threshold == 0 || int(epoch.Threshold) > len(epoch.Participants) {
+			return fmt.Errorf("timelock epoch at index %d has invalid threshold", i)
+		}
+		epochs[epoch.Epoch] = epoch
+	}
+
+	for i, dealing := range genState.TimelockDealings {
+		epoch, found := epochs[dealing.Epoch]
+		if !found {
+			return fmt.Errorf("timelock dealing at index %d references non-existent epoch %d", i, dealing.Epoch)
+		}
+		if err := dealing.Validate(epoch); err != nil {
+			return fmt.Errorf("invalid timelock dealing at index %d: %w", i, err)
+		}
+	}
+
+	// Validate capsule allowances
+	seenAllowances := make(map[string]bool)
+	for i, allowance := range genState.CapsuleAllowances {
+		if err := allowance.Validate(); err != nil {
+			return fmt.Errorf("invalid capsule allowance at index %d: %w", i, err)
+		}
+
+		key := allowance.Sponsor + "/" + allowance.Grantee
+		if seenAllowances[key] {
+			return fmt.Errorf("duplicate capsule allowance from %s to %s", allowance.Sponsor, allowance.Grantee)
+		}
+		seenAllowances[key] = true
+	}
+
+	// Validate attestors
+	attestors := make(map[string]bool)
+	for i, attestor := range genState.Attestors {
+		if err := attestor.Validate(); err != nil {
+			return fmt.Errorf("invalid attestor at index %d: %w", i, err)
+		}
+		if attestors[attestor.Address] {
+			return fmt.Errorf("duplicate attestor %s", attestor.Address)
+		}
+		attestors[attestor.Address] = true
+	}
+
+	// Validate attestations, which must come from an attestor listed on their capsule
+	attestationConditions := make(map[uint64]*types.AttestationCondition)
+	for _, capsule := range genState.Capsules {
+		if capsule.Attestation != nil {
+			attestationConditions[capsule.ID] = capsule.Attestation
+		}
+	}
+	seenAttestations := make(map[string]bool)
+	for i, attestation := range genState.Attestations {
+		if err := attestation.Validate(); err != nil {
+			return fmt.Errorf("invalid attestation at index %d: %w", i, err)
+		}
+
+		condition, ok := attestationConditions[attestation.CapsuleID]
+		if !ok {
+			return fmt.Errorf("attestation at index %d references capsule %d without attestation condition", i, attestation.CapsuleID)
+		}
+		if !condition.HasAttestor(attestation.Attestor) || !attestors[attestation.Attestor] {
+			return fmt.Errorf("attestation at index %d is not from a registered attestor of capsule %d", i, attestation.CapsuleID)
+		}
+
+		key := fmt.Sprintf("%d/%s", attestation.CapsuleID, attestation.Attestor)
+		if seenAttestations[key] {
+			return fmt.Errorf("duplicate attestation of capsule %d by %s", attestation.CapsuleID, attestation.Attestor)
+		}
+		seenAttestations[key] = true
+	}
+
+	// Validate creation quotas and the creation volume
+	seenQuotas := make(map[string]bool)
+	for i, quota := range genState.CreationQuotas {
+		if err := quota.Validate(); err != nil {
+			return fmt.Errorf("invalid creation quota at index %d: %w", i, err)
+		}
+		if seenQuotas[quota.Creator] {
+			return fmt.Errorf("duplicate creation quota of %s", quota.Creator)
+		}
+		seenQuotas[quota.Creator] = true
+	}
+	if genState.CreationVolume != nil {
+		if err := genState.CreationVolume.Validate(); err != nil {
+			return fmt.Errorf("invalid creation volume: %w", err)
+		}
+	}
+
+	// Validate the transfer history and pending transfers
+	seenTransfers := make(map[string]bool)
+	for i, history := range genState.TransferHistory {
+		if history.TransferID == "" {
+			return fmt.Errorf("transfer history at index %d has empty transfer ID", i)
+		}
+		if seenTransfers[history.TransferID] {
+			return fmt.Errorf("duplicate transfer history %s", history.TransferID)
+		}
+		seenTransfers[history.TransferID] = true
+	}
+	seenPending := make(map[string]bool)
+	for i, transfer := range genState.PendingTransfers {
+		if transfer.TransferID == "" {
+			return fmt.Errorf("pending transfer at index %d has empty transfer ID", i)
+		}
+		if seenPending[transfer.TransferID] {
+			return fmt.Errorf("duplicate pending transfer %s", transfer.TransferID)
+		}
+		seenPending[transfer.TransferID] = true
+	}
+
 	return nil
 }
 
@@ -120,6 +351,16 @@ func InitGenesis(ctx sdk.Context, k keeper.Keeper, genState *GenesisState) {
 		if err := k.IndexUserCapsule(ctx, capsule.Owner, capsule.ID); err != nil {
 			panic(fmt.Errorf("failed to index user capsule: %w", err))
 		}
+		
+		// Rebuild the unlock queue
+		if err := k.ScheduleUnlock(ctx, &capsule); err != nil {
+			panic(fmt.Errorf("failed to schedule unlock of capsule %d: %w", capsule.ID, err))
+		}
+		
+		// Rebuild the expiry and purge queues
+		if err := k.ScheduleRetention(ctx, &capsule); err != nil {
+			panic(fmt.Errorf("failed to schedule retention of capsule %d: %w", capsule.ID, err))
+		}
 	}
 
 	// Initialize key shares
@@ -136,6 +377,109 @@ func InitGenesis(ctx sdk.Context, k keeper.Keeper, genState *GenesisState) {
 		}
 	}
 
+	// Initialize emergency actions
+	for _, action := range genState.EmergencyActions {
+		if err := k.SetEmergencyAction(ctx, &action); err != nil {
+			panic(fmt.Errorf("failed to set emergency action %s: %w", action.ID, err))
+		}
+	}
+
+	// Initialize capsule version history
+	for _, version := range genState.CapsuleVersions {
+		if err := k.SetCapsuleVersion(ctx, version); err != nil {
+			panic(fmt.Errorf("failed to set capsule %d version %d: %w", version.CapsuleID, version.Version, err))
+		}
+	}
+
+	// Initialize the share refresh state
+	if err := k.SetShareRefreshState(ctx, genState.ShareRefreshState); err != nil {
+		panic(fmt.Errorf("failed to set share refresh state: %w", err))
+	}
+
+	for _, custodian := range genState.CustodianDepartures {
+		if err := k.SetCustodianDeparture(ctx, custodian); err != nil {
+			panic(fmt.Errorf("failed to set custodian departure %s: %w", custodian, err))
+		}
+	}
+
+	// Initialize the access log
+	if err := k.SetAccessLogSequence(ctx, genState.AccessLogSequence); err != nil {
+		panic(fmt.Errorf("failed to set access log sequence: %w", err))
+	}
+
+	for _, access := range genState.AccessLog {
+		if err := k.SetAccessLogEntry(ctx, access); err != nil {
+			panic(fmt.Errorf("failed to set access log entry %d: %w", access.Sequence, err))
+		}
+	}
+
+	// Initialize timelock key generation state
+	for _, key := range genState.TimelockKeys {
+		if err := k.SetTimelockKey(ctx, &key); err != nil {
+			panic(fmt.Errorf("failed to set timelock key %s: %w", key.ConsAddress, err))
+		}
+	}
+
+	for _, epoch := range genState.TimelockEpochs {
+		if err := k.SetTimelockEpoch(ctx, &epoch); err != nil {
+			panic(fmt.Errorf("failed to set timelock epoch %d: %w", epoch.Epoch, err))
+		}
+	}
+
+	for _, dealing := range genState.TimelockDealings {
+		if err := k.SetTimelockDealing(ctx, &dealing); err != nil {
+			panic(fmt.Errorf("failed to set timelock dealing %d/%d: %w", dealing.Epoch, dealing.Dealer, err))
+		}
+	}
+
+	// Initialize capsule allowances
+	for _, allowance := range genState.CapsuleAllowances {
+		if err := k.SetCapsuleAllowance(ctx, allowance); err != nil {
+			panic(fmt.Errorf("failed to set capsule allowance from %s to %s: %w", allowance.Sponsor, allowance.Grantee, err))
+		}
+	}
+
+	// Initialize attestors and their attestations
+	for _, attestor := range genState.Attestors {
+		if err := k.SetAttestor(ctx, attestor); err != nil {
+			panic(fmt.Errorf("failed to set attestor %s: %w", attestor.Address, err))
+		}
+	}
+	for _, attestation := range genState.Attestations {
+		if err := k.SetAttestation(ctx, attestation); err != nil {
+			panic(fmt.Errorf("failed to set attestation of capsule %d by %s: %w", attestation.CapsuleID, attestation.Attestor, err))
+		}
+	}
+
+	// Initialize creation quotas and the creation volume
+	for _, quota := range genState.CreationQuotas {
+		if err := k.SetCreationQuota(ctx, quota.Creator, quota.Quota); err != nil {
+			panic(fmt.Errorf("failed to set creation quota of %s: %w", quota.Creator, err))
+		}
+	}
+	if genState.CreationVolume != nil {
+		if err := k.SetCreationVolume(ctx, *genState.CreationVolume); err != nil {
+			panic(fmt.Errorf("failed to set creation volume: %w", err))
+		}
+	}
+
+	// Initialize the transfer history, pending transfers and transfer statistics
+	for _, history := range genState.TransferHistory {
+		if err := k.SetTransferHistory(ctx, history); err != nil {
+			panic(fmt.Errorf("failed to set transfer history %s: %w", history.TransferID, err))
+		}
+	}
+	for _, transfer := range genState.PendingTransfers {
+		if err := k.SetPendingTransfer(ctx, transfer.TransferID, transfer); err != nil {
+			panic(fmt.Errorf("failed to set pending transfer %s: %w", transfer.TransferID, err))
+		}
+	}
+	if genState.TransferStats != nil {
+		if err := k.SetTransferStats(ctx, *genState.TransferStats); err != nil {
+			panic(fmt.Errorf("failed to set transfer stats: %w", err))
+		}
+	}
+
 	k.Logger(ctx).Info("Time capsule module genesis initialized",
 		"capsules", len(genState.Capsules),
 		"key_shares", len(genState.KeyShares),
@@ -183,5 +527,123 @@ func ExportGenesis(ctx sdk.Context, k keeper.Keeper) *GenesisState {
 	}
 	genesis.ConditionContracts = contracts
 
+	// Export emergency actions
+	emergencyActions, err := k.GetAllEmergencyActions(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get all emergency actions: %w", err))
+	}
+	genesis.EmergencyActions = emergencyActions
+
+	// Export capsule version history
+	capsuleVersions, err := k.GetAllCapsuleVersions(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get all capsule versions: %w", err))
+	}
+	genesis.CapsuleVersions = capsuleVersions
+
+	// Export the share refresh state
+	refreshState, err := k.GetShareRefreshState(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get share refresh state: %w", err))
+	}
+	genesis.ShareRefreshState = refreshState
+
+	departures, err := k.GetAllCustodianDepartures(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get custodian departures: %w", err))
+	}
+	genesis.CustodianDepartures = departures
+
+	// Export the access log
+	accessLog, err := k.GetAllAccessLog(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get access log: %w", err))
+	}
+	genesis.AccessLog = accessLog
+
+	accessLogSeq, err := k.GetAccessLogSequence(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get access log sequence: %w", err))
+	}
+	genesis.AccessLogSequence = accessLogSeq
+
+	// Export timelock key generation state
+	timelockKeys, err := k.GetAllTimelockKeys(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get all timelock keys: %w", err))
+	}
+	genesis.TimelockKeys = timelockKeys
+
+	timelockEpochs, err := k.GetAllTimelockEpochs(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get all timelock epochs: %w", err))
+	}
+	genesis.TimelockEpochs = timelockEpochs
+
+	timelockDealings, err := k.GetAllTimelockDealings(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get all timelock dealings: %w", err))
+	}
+	genesis.TimelockDealings = timelockDealings
+
+	// Export capsule allowances
+	allowances, err := k.GetAllCapsuleAllowances(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get all capsule allowances: %w", err))
+	}
+	genesis.CapsuleAllowances = allowances
+
+	// Export attestors and attestations
+	attestors, err := k.GetAllAttestors(ctx, false)
+	if err != nil {
+		panic(fmt.Errorf("failed to get all attestors: %w", err))
+	}
+	genesis.Attestors = attestors
+
+	attestations, err := k.GetAllAttestations(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get all attestations: %w", err))
+	}
+	genesis.Attestations = attestations
+
+	// Export creation quotas and the creation volume
+	quotas, err := k.GetAllCreationQuotas(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get all creation quotas: %w", err))
+	}
+	genesis.CreationQuotas = quotas
+
+	// The creation volume is only stored once capsules were created
+	hasVolume, err := k.HasCreationVolume(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get creation volume: %w", err))
+	}
+	if hasVolume {
+		volume, err := k.GetCreationVolume(ctx)
+		if err != nil {
+			panic(fmt.Errorf("failed to get creation volume: %w", err))
+		}
+		genesis.CreationVolume = &volume
+	}
+
+	// Export the transfer history, pending transfers and transfer statistics
+	transferHistory, err := k.GetAllTransferHistory(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get transfer history: %w", err))
+	}
+	genesis.TransferHistory = transferHistory
+
+	pendingTransfers, err := k.GetAllPendingTransfers(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get pending transfers: %w", err))
+	}
+	genesis.PendingTransfers = pendingTransfers
+
+	transferStats, err := k.GetTransferStats(ctx)
+	if err != nil {
+		panic(fmt.Errorf("failed to get transfer stats: %w", err))
+	}
+	genesis.TransferStats = transferStats
+
 	return genesis
 }
\ No newline at end of file
@@ -101,6 +101,19 @@ func (m *IPFSManager) RetrieveCapsuleData(ctx context.Context, metadata *IPFSMet
 	return data, nil
 }
 
+// UnpinCapsuleData unpins the data of a purged capsule and forgets its metadata
+func (m *IPFSManager) UnpinCapsuleData(ctx context.Context, hash string) error {
+	if err := m.client.UnpinData(ctx, hash); err != nil {
+		return fmt.Errorf("failed to unpin capsule data %s: %w", hash, err)
+	}
+
+	m.cacheMutex.Lock()
+	delete(m.cache, hash)
+	m.cacheMutex.Unlock()
+
+	return nil
+}
+
 // CleanupExpiredData removes expired capsule data from IPFS
 func (m *IPFSManager) CleanupExpiredData(ctx context.Context) error {
 	// Get all pinned content
@@ -0,0 +1,153 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"cosmossdk.io/collections"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// maxAccessReasonLength bounds the failure reason stored with an access log entry
+const maxAccessReasonLength = 256
+
+// failedAccessBuffer holds the failed open attempts of the block being executed.
+// A failed transaction is reverted together with everything it wrote, so failed
+// attempts are kept aside and written to the access log by the EndBlocker.
+type failedAccessBuffer struct {
+	mu       sync.Mutex
+	accesses []types.CapsuleAccess
+}
+
+// GetAccessLog returns the retained access attempts of a capsule, oldest first
+func (k Keeper) GetAccessLog(ctx context.Context, capsuleID uint64) ([]types.CapsuleAccess, error) {
+	var accesses []types.CapsuleAccess
+
+	rng := collections.NewPrefixedTripleRange[uint64, uint64, uint64](capsuleID)
+	err := k.accessLog.Walk(ctx, rng, func(_ collections.Triple[uint64, uint64, uint64], access types.CapsuleAccess) (bool, error) {
+		accesses = append(accesses, access)
+		return false, nil
+	})
+
+	return accesses, err
+}
+
+// recordAccess appends a successful access to the capsule access log
+func (k Keeper) recordAccess(ctx context.Context, capsuleID uint64, accessor string) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	return k.appendAccessLog(ctx, types.CapsuleAccess{
+		CapsuleID:   capsuleID,
+		Accessor:    accessor,
+		AccessTime:  sdkCtx.BlockTime(),
+		BlockHeight: sdkCtx.BlockHeight(),
+		Success:     true,
+	})
+}
+
+// recordFailedAccess buffers a failed access until the end of the block
+func (k Keeper) recordFailedAccess(ctx context.Context, capsuleID uint64, accessor string, cause error) {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	// Only attempts included in a block are logged, not CheckTx or simulations
+	if sdkCtx.ExecMode() != sdk.ExecModeFinalize {
+		return
+	}
+
+	reason := cause.Error()
+	if len(reason) > maxAccessReasonLength {
+		reason = reason[:maxAccessReasonLength]
+	}
+
+	k.failedAccesses.mu.Lock()
+	defer k.failedAccesses.mu.Unlock()
+
+	k.failedAccesses.accesses = append(k.failedAccesses.accesses, types.CapsuleAccess{
+		CapsuleID:   capsuleID,
+		Accessor:    accessor,
+		AccessTime:  sdkCtx.BlockTime(),
+		BlockHeight: sdkCtx.BlockHeight(),
+		Success:     false,
+		Reason:      reason,
+	})
+}
+
+// resetFailedAccesses drops buffered failed accesses, e.g. those of an aborted
+// optimistic execution of the block
+func (k Keeper) resetFailedAccesses() {
+	k.failedAccesses.mu.Lock()
+	defer k.failedAccesses.mu.Unlock()
+
+	k.failedAccesses.accesses = nil
+}
+
+// flushFailedAccesses writes the failed accesses of the block to the access log
+func (k Keeper) flushFailedAccesses(ctx context.Context) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	k.failedAccesses.mu.Lock()
+	accesses := k.failedAccesses.accesses
+	k.failedAccesses.accesses = nil
+	k.failedAccesses.mu.Unlock()
+
+	for _, access := range accesses {
+		if err := k.appendAccessLog(ctx, access); err != nil {
+			return err
+		}
+
+		sdkCtx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeCapsuleAccessFailed,
+				sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", access.CapsuleID)),
+				sdk.NewAttribute("accessor", access.Accessor),
+			),
+		)
+	}
+
+	return nil
+}
+
+// appendAccessLog stores an access log entry and prunes the capsule log to the retention limit
+func (k Keeper) appendAccessLog(ctx context.Context, access types.CapsuleAccess) error {
+	seq, err := k.accessLogSeq.Next(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get access log sequence: %w", err)
+	}
+	access.Sequence = seq
+
+	if err := k.SetAccessLogEntry(ctx, access); err != nil {
+		return err
+	}
+
+	return k.pruneAccessLog(ctx, access.CapsuleID)
+}
+
+// pruneAccessLog removes the oldest entries of a capsule log beyond the retention limit
+func (k Keeper) pruneAccessLog(ctx context.Context, capsuleID uint64) error {
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	var keys []collections.Triple[uint64, uint64, uint64]
+	rng := collections.NewPrefixedTripleRange[uint64, uint64, uint64](capsuleID)
+	err = k.accessLog.Walk(ctx, rng, func(key collections.Triple[uint64, uint64, uint64], _ types.CapsuleAccess) (bool, error) {
+		keys = append(keys, key)
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	excess := len(keys) - int(params.AccessLogRetention)
+	for i := 0; i < excess; i++ {
+		if err := k.accessLog.Remove(ctx, keys[i]); err != nil {
+			return fmt.Errorf("failed to prune access log of capsule %d: %w", capsuleID, err)
+		}
+	}
+
+	return nil
+}
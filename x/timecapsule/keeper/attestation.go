@@ -0,0 +1,317 @@
+package keeper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"cosmossdk.io/collections"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// RegisterAttestor registers an attestor, or updates the key and description of an
+// attestor registered before. An update keeps the attestor's curation unless it changes
+// the key, which governance has to curate again.
+func (k Keeper) RegisterAttestor(ctx context.Context, address, keyType string, pubKey []byte, description string) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	attestor := types.Attestor{
+		Address:      address,
+		KeyType:      keyType,
+		PubKey:       pubKey,
+		Description:  description,
+		RegisteredAt: sdkCtx.BlockTime(),
+	}
+	existing, err := k.attestors.Get(ctx, address)
+	switch {
+	case err == nil:
+		attestor.Curated = existing.Curated && existing.KeyType == keyType && bytes.Equal(existing.PubKey, pubKey)
+		attestor.RegisteredAt = existing.RegisteredAt
+	case !errors.Is(err, collections.ErrNotFound):
+		return err
+	}
+
+	if err := attestor.Validate(); err != nil {
+		return types.ErrInvalidAttestor.Wrap(err.Error())
+	}
+	if err := k.SetAttestor(ctx, attestor); err != nil {
+		return err
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeAttestorRegistered,
+			sdk.NewAttribute(types.AttributeKeyAttestor, address),
+		),
+	)
+
+	return nil
+}
+
+// CurateAttestor sets whether governance curates an attestor. Capsules requiring
+// curated attestors check the curation when an attestation is submitted.
+func (k Keeper) CurateAttestor(ctx context.Context, authority, address string, curated bool) error {
+	if authority != k.authority {
+		return types.ErrUnauthorized.Wrapf("expected %s, got %s", k.authority, authority)
+	}
+
+	attestor, err := k.GetAttestor(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	attestor.Curated = curated
+	if err := k.SetAttestor(ctx, *attestor); err != nil {
+		return err
+	}
+
+	sdk.UnwrapSDKContext(ctx).EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeAttestorCurated,
+			sdk.NewAttribute(types.AttributeKeyAttestor, address),
+			sdk.NewAttribute(types.AttributeKeyCurated, fmt.Sprintf("%t", curated)),
+		),
+	)
+
+	return nil
+}
+
+// SubmitAttestation verifies and records an attestor's signed claim about a capsule.
+// Once the capsule's attestation threshold is reached the capsule becomes unlockable.
+// It returns the number of attestations of the capsule and whether it is attested.
+func (k Keeper) SubmitAttestation(ctx context.Context, submitter string, capsuleID uint64, address, claim string, signature []byte) (uint32, bool, error) {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	capsule, err := k.GetCapsule(ctx, capsuleID)
+	if err != nil {
+		return 0, false, err
+	}
+	condition := capsule.Attestation
+	if condition == nil {
+		return 0, false, types.ErrInvalidAttestation.Wrapf("capsule %d has no attestation condition", capsuleID)
+	}
+	if capsule.Status != types.CapsuleStatus_ACTIVE {
+		return 0, false, types.ErrInvalidAttestation.Wrapf("capsule %d is %s", capsuleID, capsule.Status.String())
+	}
+	if !condition.HasAttestor(address) {
+		return 0, false, types.ErrUnauthorized.Wrapf("%s is not an attestor of capsule %d", address, capsuleID)
+	}
+	if claim != condition.Claim {
+		return 0, false, types.ErrInvalidAttestation.Wrapf("capsule %d attests %q, not %q", capsuleID, condition.Claim, claim)
+	}
+
+	attestor, err := k.GetAttestor(ctx, address)
+	if err != nil {
+		return 0, false, err
+	}
+	if condition.CuratedOnly && !attestor.Curated {
+		return 0, false, types.ErrInvalidAttestation.Wrapf("capsule %d requires curated attestors, %s is not curated", capsuleID, address)
+	}
+
+	key := collections.Join(capsuleID, address)
+	has, err := k.attestations.Has(ctx, key)
+	if err != nil {
+		return 0, false, err
+	}
+	if has {
+		return 0, false, types.ErrInvalidAttestation.Wrapf("%s already attested capsule %d", address, capsuleID)
+	}
+
+	if err := attestor.VerifyAttestation(sdkCtx.ChainID(), capsuleID, claim, signature); err != nil {
+		return 0, false, types.ErrInvalidSignature.Wrap(err.Error())
+	}
+
+	attestation := types.Attestation{
+		CapsuleID:   capsuleID,
+		Attestor:    address,
+		Signature:   signature,
+		Submitter:   submitter,
+		SubmittedAt: sdkCtx.BlockTime(),
+	}
+	if err := k.attestations.Set(ctx, key, attestation); err != nil {
+		return 0, false, err
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeAttestationSubmitted,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsuleID)),
+			sdk.NewAttribute(types.AttributeKeyAttestor, address),
+			sdk.NewAttribute(types.AttributeKeySender, submitter),
+		),
+	)
+
+	count, err := k.countAttestations(ctx, capsuleID)
+	if err != nil {
+		return 0, false, err
+	}
+	if condition.IsMet() || count < condition.Threshold {
+		return count, condition.IsMet(), nil
+	}
+
+	blockTime := sdkCtx.BlockTime()
+	condition.AttestedAt = &blockTime
+	capsule.UpdatedAt = blockTime
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return 0, false, fmt.Errorf("failed to store attested capsule: %w", err)
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCapsuleAttested,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsuleID)),
+			sdk.NewAttribute(types.AttributeKeyOwner, capsule.Owner),
+			sdk.NewAttribute(types.AttributeKeyRecipient, capsule.Recipient),
+			sdk.NewAttribute(types.AttributeKeyClaim, condition.Claim),
+		),
+	)
+
+	return count, true, nil
+}
+
+// RevokeCapsuleAttestor removes an attestor from the attestation condition of a capsule
+// and discards its attestation. A capsule that no longer has enough attestations is
+// locked again, so the owner can contest a false claim while the capsule is unopened.
+func (k Keeper) RevokeCapsuleAttestor(ctx context.Context, owner string, capsuleID uint64, address string) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	capsule, err := k.GetCapsule(ctx, capsuleID)
+	if err != nil {
+		return err
+	}
+	if capsule.Owner != owner {
+		return types.ErrUnauthorized.Wrapf("only the owner can revoke attestors of capsule %d", capsuleID)
+	}
+	if capsule.Status != types.CapsuleStatus_ACTIVE {
+		return types.ErrInvalidAttestation.Wrapf("capsule %d is %s", capsuleID, capsule.Status.String())
+	}
+
+	condition := capsule.Attestation
+	if condition == nil {
+		return types.ErrInvalidAttestation.Wrapf("capsule %d has no attestation condition", capsuleID)
+	}
+	if !condition.HasAttestor(address) {
+		return types.ErrAttestorNotFound.Wrapf("%s is not an attestor of capsule %d", address, capsuleID)
+	}
+	if len(condition.Attestors)-1 < int(condition.Threshold) {
+		return types.ErrInvalidAttestation.Wrapf("capsule %d would keep fewer attestors than its threshold of %d, cancel it instead", capsuleID, condition.Threshold)
+	}
+
+	attestors := make([]string, 0, len(condition.Attestors)-1)
+	for _, attestor := range condition.Attestors {
+		if attestor != address {
+			attestors = append(attestors, attestor)
+		}
+	}
+	condition.Attestors = attestors
+
+	if err := k.attestations.Remove(ctx, collections.Join(capsuleID, address)); err != nil {
+		return err
+	}
+
+	count, err := k.countAttestations(ctx, capsuleID)
+	if err != nil {
+		return err
+	}
+	if count < condition.Threshold {
+		condition.AttestedAt = nil
+	}
+
+	capsule.UpdatedAt = sdkCtx.BlockTime()
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return fmt.Errorf("failed to store capsule attestors: %w", err)
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCapsuleAttestorRevoked,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsuleID)),
+			sdk.NewAttribute(types.AttributeKeyAttestor, address),
+		),
+	)
+
+	return nil
+}
+
+// checkAttestationCondition checks that the attestors of a new attestation condition
+// are registered, and curated when the condition requires it
+func (k Keeper) checkAttestationCondition(ctx context.Context, condition *types.AttestationCondition) error {
+	for _, address := range condition.Attestors {
+		attestor, err := k.GetAttestor(ctx, address)
+		if err != nil {
+			return err
+		}
+		if condition.CuratedOnly && !attestor.Curated {
+			return types.ErrInvalidAttestation.Wrapf("attestor %s is not curated", address)
+		}
+	}
+	return nil
+}
+
+// countAttestations returns the number of attestations submitted for a capsule
+func (k Keeper) countAttestations(ctx context.Context, capsuleID uint64) (uint32, error) {
+	var count uint32
+	err := k.attestations.Walk(ctx, collections.NewPrefixedPairRange[uint64, string](capsuleID), func(_ collections.Pair[uint64, string], _ types.Attestation) (bool, error) {
+		count++
+		return false, nil
+	})
+	return count, err
+}
+
+// GetAttestor returns a registered attestor
+func (k Keeper) GetAttestor(ctx context.Context, address string) (*types.Attestor, error) {
+	attestor, err := k.attestors.Get(ctx, address)
+	if errors.Is(err, collections.ErrNotFound) {
+		return nil, types.ErrAttestorNotFound.Wrapf("attestor %s is not registered", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attestor, nil
+}
+
+// SetAttestor stores an attestor
+func (k Keeper) SetAttestor(ctx context.Context, attestor types.Attestor) error {
+	return k.attestors.Set(ctx, attestor.Address, attestor)
+}
+
+// GetAllAttestors returns the registered attestors, or only the curated ones
+func (k Keeper) GetAllAttestors(ctx context.Context, curatedOnly bool) ([]types.Attestor, error) {
+	var attestors []types.Attestor
+	err := k.attestors.Walk(ctx, nil, func(_ string, attestor types.Attestor) (bool, error) {
+		if !curatedOnly || attestor.Curated {
+			attestors = append(attestors, attestor)
+		}
+		return false, nil
+	})
+	return attestors, err
+}
+
+// GetCapsuleAttestations returns the attestations submitted for a capsule
+func (k Keeper) GetCapsuleAttestations(ctx context.Context, capsuleID uint64) ([]types.Attestation, error) {
+	var attestations []types.Attestation
+	err := k.attestations.Walk(ctx, collections.NewPrefixedPairRange[uint64, string](capsuleID), func(_ collections.Pair[uint64, string], attestation types.Attestation) (bool, error) {
+		attestations = append(attestations, attestation)
+		return false, nil
+	})
+	return attestations, err
+}
+
+// SetAttestation stores an attestation
+func (k Keeper) SetAttestation(ctx context.Context, attestation types.Attestation) error {
+	return k.attestations.Set(ctx, collections.Join(attestation.CapsuleID, attestation.Attestor), attestation)
+}
+
+// GetAllAttestations returns all attestations
+func (k Keeper) GetAllAttestations(ctx context.Context) ([]types.Attestation, error) {
+	var attestations []types.Attestation
+	err := k.attestations.Walk(ctx, nil, func(_ collections.Pair[uint64, string], attestation types.Attestation) (bool, error) {
+		attestations = append(attestations, attestation)
+		return false, nil
+	})
+	return attestations, err
+}
@@ -0,0 +1,44 @@
+package keeper_test
+
+import (
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+func (s *KeeperTestSuite) TestRegisterAttestorKeepsCurationOfTheSameKey() {
+	attestor := sdk.AccAddress("notary______________").String()
+	secpKey := secp256k1.GenPrivKey().PubKey().Bytes()
+	otherSecpKey := secp256k1.GenPrivKey().PubKey().Bytes()
+	edKey := ed25519.GenPrivKey().PubKey().Bytes()
+
+	testCases := []struct {
+		name    string
+		keyType string
+		pubKey  []byte
+		curated bool
+	}{
+		{"same key, new description", types.AttestorKeyTypeSecp256k1, secpKey, true},
+		{"new key of the same type", types.AttestorKeyTypeSecp256k1, otherSecpKey, false},
+		{"key of another type", types.AttestorKeyTypeEd25519, edKey, false},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			s.SetupTest()
+
+			s.Require().NoError(s.keeper.RegisterAttestor(s.ctx, attestor, types.AttestorKeyTypeSecp256k1, secpKey, "Notary"))
+			s.Require().NoError(s.keeper.CurateAttestor(s.ctx, s.authority, attestor, true))
+
+			s.Require().NoError(s.keeper.RegisterAttestor(s.ctx, attestor, tc.keyType, tc.pubKey, "Notary public"))
+
+			registered, err := s.keeper.GetAttestor(s.ctx, attestor)
+			s.Require().NoError(err)
+			s.Require().Equal(tc.curated, registered.Curated)
+			s.Require().Equal(tc.pubKey, registered.PubKey)
+			s.Require().Equal("Notary public", registered.Description)
+		})
+	}
+}
@@ -0,0 +1,493 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// maxShareRefreshesPerBlock bounds the capsules whose key shares are refreshed per block,
+// for departed custodians first and then for the periodic refresh round
+const maxShareRefreshesPerBlock = 50
+
+// Hooks reacts to validators leaving the bonded set, since they may hold key shares
+type Hooks struct {
+	k Keeper
+}
+
+var _ stakingtypes.StakingHooks = Hooks{}
+
+// Hooks returns the staking hooks of the time capsule module
+func (k Keeper) Hooks() Hooks {
+	return Hooks{k}
+}
+
+// AfterValidatorBeginUnbonding schedules the re-distribution of the validator's key shares
+func (h Hooks) AfterValidatorBeginUnbonding(ctx context.Context, _ sdk.ConsAddress, valAddr sdk.ValAddress) error {
+	return h.k.queueCustodianDeparture(ctx, valAddr)
+}
+
+// AfterValidatorRemoved schedules the re-distribution of the validator's key shares
+func (h Hooks) AfterValidatorRemoved(ctx context.Context, _ sdk.ConsAddress, valAddr sdk.ValAddress) error {
+	return h.k.queueCustodianDeparture(ctx, valAddr)
+}
+
+// BeforeValidatorSlashed schedules the re-distribution of the validator's key shares.
+// Slashed validators are jailed or tombstoned, so they stop being live custodians.
+func (h Hooks) BeforeValidatorSlashed(ctx context.Context, valAddr sdk.ValAddress, _ math.LegacyDec) error {
+	return h.k.queueCustodianDeparture(ctx, valAddr)
+}
+
+func (h Hooks) AfterValidatorCreated(_ context.Context, _ sdk.ValAddress) error { return nil }
+
+func (h Hooks) BeforeValidatorModified(_ context.Context, _ sdk.ValAddress) error { return nil }
+
+func (h Hooks) AfterValidatorBonded(_ context.Context, _ sdk.ConsAddress, _ sdk.ValAddress) error {
+	return nil
+}
+
+func (h Hooks) BeforeDelegationCreated(_ context.Context, _ sdk.AccAddress, _ sdk.ValAddress) error {
+	return nil
+}
+
+func (h Hooks) BeforeDelegationSharesModified(_ context.Context, _ sdk.AccAddress, _ sdk.ValAddress) error {
+	return nil
+}
+
+func (h Hooks) BeforeDelegationRemoved(_ context.Context, _ sdk.AccAddress, _ sdk.ValAddress) error {
+	return nil
+}
+
+func (h Hooks) AfterDelegationModified(_ context.Context, _ sdk.AccAddress, _ sdk.ValAddress) error {
+	return nil
+}
+
+func (h Hooks) AfterUnbondingInitiated(_ context.Context, _ uint64) error { return nil }
+
+// queueCustodianDeparture marks a validator's key shares for re-distribution at the end of the block
+func (k Keeper) queueCustodianDeparture(ctx context.Context, valAddr sdk.ValAddress) error {
+	custodian := valAddr.String()
+
+	holdsShares, err := k.holdsKeyShares(ctx, custodian)
+	if err != nil || !holdsShares {
+		return err
+	}
+
+	return k.custodianDepartures.Set(ctx, custodian)
+}
+
+// holdsKeyShares checks whether a custodian holds a key share of any capsule
+func (k Keeper) holdsKeyShares(ctx context.Context, custodian string) (bool, error) {
+	rng := collections.NewPrefixedTripleRange[string, uint64, uint32](custodian)
+	iter, err := k.custodianShares.Iterate(ctx, rng)
+	if err != nil {
+		return false, err
+	}
+	defer iter.Close()
+
+	return iter.Valid(), nil
+}
+
+// processCustodianDepartures re-distributes the key shares held by departed custodians,
+// refreshing at most limit capsules. Custodians are handled one at a time and the
+// capsule to resume from is kept in the share refresh state, so a custodian holding
+// many shares is spread over several blocks. It returns the capsules refreshed.
+func (k Keeper) processCustodianDepartures(ctx context.Context, limit int) (int, error) {
+	state, err := k.GetShareRefreshState(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	refreshed := 0
+	for refreshed < limit {
+		if state.DepartingCustodian == "" {
+			custodian, found, err := k.nextCustodianDeparture(ctx)
+			if err != nil {
+				return 0, err
+			}
+			if !found {
+				break
+			}
+			state.DepartingCustodian = custodian
+			state.NextDepartureCapsuleID = 0
+		}
+		custodian := state.DepartingCustodian
+
+		var batch []uint64
+		next := uint64(0)
+		rng := new(collections.Range[collections.Triple[string, uint64, uint32]]).
+			Prefix(collections.TriplePrefix[string, uint64, uint32](custodian)).
+			StartInclusive(collections.Join3(custodian, state.NextDepartureCapsuleID, uint32(0)))
+		err := k.custodianShares.Walk(ctx, rng, func(key collections.Triple[string, uint64, uint32]) (bool, error) {
+			capsuleID := key.K2()
+			if len(batch) > 0 && batch[len(batch)-1] == capsuleID {
+				return false, nil // Another share of the same capsule
+			}
+			if refreshed+len(batch) == limit {
+				next = capsuleID
+				return true, nil
+			}
+			batch = append(batch, capsuleID)
+			return false, nil
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		for _, capsuleID := range batch {
+			if err := k.refreshCapsuleShares(ctx, capsuleID, "departure/"+custodian); err != nil {
+				return 0, err
+			}
+		}
+		refreshed += len(batch)
+
+		if next != 0 {
+			state.NextDepartureCapsuleID = next
+			break
+		}
+
+		// Every capsule of the custodian was handled
+		if err := k.custodianDepartures.Remove(ctx, custodian); err != nil {
+			return 0, err
+		}
+		state.DepartingCustodian = ""
+		state.NextDepartureCapsuleID = 0
+	}
+
+	return refreshed, k.SetShareRefreshState(ctx, state)
+}
+
+// nextCustodianDeparture returns the first custodian awaiting re-distribution
+func (k Keeper) nextCustodianDeparture(ctx context.Context) (string, bool, error) {
+	iter, err := k.custodianDepartures.Iterate(ctx, nil)
+	if err != nil {
+		return "", false, err
+	}
+	defer iter.Close()
+
+	if !iter.Valid() {
+		return "", false, nil
+	}
+	custodian, err := iter.Key()
+	return custodian, err == nil, err
+}
+
+// advanceShareRefresh runs the periodic refresh of all key shares, in batches of at most
+// limit capsules
+func (k Keeper) advanceShareRefresh(ctx context.Context, limit int) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	if limit <= 0 {
+		return nil
+	}
+
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	state, err := k.GetShareRefreshState(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := sdkCtx.BlockTime()
+	if state.NextCapsuleID == 0 {
+		if now.Sub(state.LastRefreshAt) < params.ShareRefreshInterval {
+			return nil
+		}
+		state.LastRefreshAt = now
+		state.NextCapsuleID = 1
+	}
+
+	var batch []uint64
+	next := uint64(0)
+	rng := new(collections.Range[uint64]).StartInclusive(state.NextCapsuleID)
+	err = k.capsules.Walk(ctx, rng, func(capsuleID uint64, capsule types.TimeCapsule) (bool, error) {
+		if len(batch) == limit {
+			next = capsuleID
+			return true, nil
+		}
+		if capsule.Status == types.CapsuleStatus_ACTIVE && !capsule.IsTimelocked() {
+			batch = append(batch, capsuleID)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	round := fmt.Sprintf("periodic/%d", state.LastRefreshAt.UnixNano())
+	for _, capsuleID := range batch {
+		if err := k.refreshCapsuleShares(ctx, capsuleID, round); err != nil {
+			return err
+		}
+	}
+
+	state.NextCapsuleID = next
+	return k.SetShareRefreshState(ctx, state)
+}
+
+// refreshCapsuleShares re-randomizes the key shares of a capsule and hands the shares of
+// custodians that left the bonded set to replacement validators. The key is never
+// reconstructed: lost shares are interpolated from a threshold of live shares at their
+// own index, then every share is refreshed so that shares taken before no longer combine.
+// Capsules that cannot be refreshed are logged and skipped rather than failing the block.
+// The refresh polynomials are derived from the block header hash, the capsule and the
+// round, so every validator computes the same shares.
+func (k Keeper) refreshCapsuleShares(ctx context.Context, capsuleID uint64, round string) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	capsule, err := k.GetCapsule(ctx, capsuleID)
+	if err != nil {
+		return err
+	}
+	if capsule.Status != types.CapsuleStatus_ACTIVE || capsule.IsTimelocked() {
+		return nil
+	}
+
+	keyShares, err := k.getCapsuleKeyShares(ctx, capsuleID)
+	if err != nil || len(keyShares) == 0 {
+		return err
+	}
+
+	shares := make([]*crypto.Share, len(keyShares))
+	holders := make(map[string]bool)
+	var live, lost []int
+	for i, keyShare := range keyShares {
+		share, err := crypto.BytesToShare(keyShare.EncryptedShare)
+		if err != nil || share.IsLegacy() {
+			k.logger.Info("Skipping key share refresh of capsule with legacy shares", "capsule_id", capsuleID)
+			return nil
+		}
+		shares[i] = share
+
+		if k.isLiveCustodian(ctx, keyShare.NodeID) {
+			live = append(live, i)
+			holders[keyShare.NodeID] = true
+		} else {
+			lost = append(lost, i)
+		}
+	}
+
+	if len(live) < int(capsule.Threshold) {
+		k.logger.Error("Capsule has too few live key shares to refresh",
+			"capsule_id", capsuleID,
+			"live_shares", len(live),
+			"threshold", capsule.Threshold,
+		)
+		return nil
+	}
+
+	// Recover the shares of lost custodians for their replacements
+	source := make([]*crypto.Share, capsule.Threshold)
+	for j := range source {
+		source[j] = shares[live[j]]
+	}
+
+	previous := make(map[int]string)
+	for _, i := range lost {
+		replacement, found, err := k.replacementCustodian(ctx, holders)
+		if err != nil {
+			return err
+		}
+		if !found {
+			k.logger.Error("No replacement custodian for key share",
+				"capsule_id", capsuleID,
+				"share_index", keyShares[i].ShareIndex,
+			)
+			continue
+		}
+
+		recovered, err := k.shamirSecretSharing.RecoverShare(source, keyShares[i].ShareIndex+1)
+		if err != nil {
+			k.logger.Error("Failed to recover key share", "capsule_id", capsuleID, "error", err)
+			return nil
+		}
+
+		shares[i] = recovered
+		previous[i] = keyShares[i].NodeID
+		keyShares[i].NodeID = replacement
+		keyShares[i].CreatedAt = sdkCtx.BlockTime()
+		holders[replacement] = true
+	}
+
+	seed := crypto.ShareRefreshSeed(sdkCtx.HeaderHash(), capsuleID, round)
+	refreshed, commitments, err := k.shamirSecretSharing.RefreshShares(shares, capsule.ShareCommitments, int(capsule.Threshold), seed)
+	if err != nil {
+		k.logger.Error("Failed to refresh key shares", "capsule_id", capsuleID, "error", err)
+		return nil
+	}
+
+	// The same custodians hold the delivery key shares
+	deliverySeed := crypto.ShareRefreshSeed(sdkCtx.HeaderHash(), capsuleID, round+"/delivery")
+	if err := k.refreshDeliveryShares(capsule, keyShares, live, previous, deliverySeed); err != nil {
+		k.logger.Error("Failed to refresh delivery shares", "capsule_id", capsuleID, "error", err)
+		return nil
+	}
+
+	for i := range keyShares {
+		keyShares[i].EncryptedShare = crypto.ShareToBytes(refreshed[i])
+
+		prev, reassigned := previous[i]
+		if reassigned {
+			if err := k.custodianShares.Remove(ctx, collections.Join3(prev, capsuleID, keyShares[i].ShareIndex)); err != nil {
+				return err
+			}
+		}
+
+		if err := k.SetKeyShare(ctx, &keyShares[i]); err != nil {
+			return fmt.Errorf("failed to store key share %d: %w", keyShares[i].ShareIndex, err)
+		}
+
+		if reassigned {
+			sdkCtx.EventManager().EmitEvent(
+				sdk.NewEvent(
+					types.EventTypeKeyShareReassigned,
+					sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsuleID)),
+					sdk.NewAttribute(types.AttributeKeyShareIndex, fmt.Sprintf("%d", keyShares[i].ShareIndex)),
+					sdk.NewAttribute(types.AttributeKeyNodeID, keyShares[i].NodeID),
+					sdk.NewAttribute(types.AttributeKeyPreviousNodeID, prev),
+				),
+			)
+		}
+	}
+
+	capsule.ShareCommitments = commitments
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return fmt.Errorf("failed to store capsule: %w", err)
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeKeySharesRefreshed,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsuleID)),
+			sdk.NewAttribute(types.AttributeKeyLiveShares, fmt.Sprintf("%d", len(live)+len(previous))),
+		),
+	)
+
+	return nil
+}
+
+// GetShareHealth reports how many key shares of a capsule are held by live custodians
+func (k Keeper) GetShareHealth(ctx context.Context, capsule *types.TimeCapsule) (types.CapsuleShareHealth, error) {
+	health := types.CapsuleShareHealth{
+		CapsuleID:   capsule.ID,
+		Threshold:   capsule.Threshold,
+		TotalShares: capsule.TotalShares,
+	}
+
+	keyShares, err := k.getCapsuleKeyShares(ctx, capsule.ID)
+	if err != nil {
+		return health, err
+	}
+
+	lost := make(map[string]bool)
+	for _, keyShare := range keyShares {
+		if k.isLiveCustodian(ctx, keyShare.NodeID) {
+			health.LiveShares++
+		} else if !lost[keyShare.NodeID] {
+			lost[keyShare.NodeID] = true
+			health.LostCustodians = append(health.LostCustodians, keyShare.NodeID)
+		}
+	}
+	health.Margin = int64(health.LiveShares) - int64(health.Threshold)
+
+	return health, nil
+}
+
+// selectCustodians assigns key shares to bonded validators by voting power. Validators
+// hold several shares when there are fewer of them than shares; without any bonded
+// validator the shares go to placeholder nodes.
+func (k Keeper) selectCustodians(ctx context.Context, count int) ([]string, error) {
+	validators, err := k.stakingKeeper.GetBondedValidatorsByPower(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var operators []string
+	for _, val := range validators {
+		if !val.IsJailed() {
+			operators = append(operators, val.GetOperator())
+		}
+	}
+
+	custodians := make([]string, count)
+	for i := range custodians {
+		if len(operators) == 0 {
+			custodians[i] = fmt.Sprintf("masternode-%d", i) // Placeholder
+			continue
+		}
+		custodians[i] = operators[i%len(operators)]
+	}
+
+	return custodians, nil
+}
+
+// replacementCustodian returns the most powerful live validator that is not excluded
+func (k Keeper) replacementCustodian(ctx context.Context, exclude map[string]bool) (string, bool, error) {
+	validators, err := k.stakingKeeper.GetBondedValidatorsByPower(ctx)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, val := range validators {
+		if !val.IsJailed() && !exclude[val.GetOperator()] {
+			return val.GetOperator(), true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// isLiveCustodian checks whether a custodian is a bonded validator that is not jailed.
+// Custodians outside the validator set, such as placeholder nodes, are not tracked.
+func (k Keeper) isLiveCustodian(ctx context.Context, custodian string) bool {
+	valAddr, err := sdk.ValAddressFromBech32(custodian)
+	if err != nil {
+		return true
+	}
+
+	val, err := k.stakingKeeper.GetValidator(ctx, valAddr)
+	if err != nil {
+		return false
+	}
+
+	return val.IsBonded() && !val.IsJailed()
+}
+
+// getCapsuleKeyShares returns the stored key shares of a capsule by share index
+func (k Keeper) getCapsuleKeyShares(ctx context.Context, capsuleID uint64) ([]types.KeyShare, error) {
+	var keyShares []types.KeyShare
+
+	rng := collections.NewPrefixedPairRange[uint64, uint32](capsuleID)
+	err := k.keyShares.Walk(ctx, rng, func(_ collections.Pair[uint64, uint32], share types.KeyShare) (bool, error) {
+		keyShares = append(keyShares, share)
+		return false, nil
+	})
+
+	return keyShares, err
+}
+
+// GetShareRefreshState returns the state of the periodic share refresh
+func (k Keeper) GetShareRefreshState(ctx context.Context) (types.ShareRefreshState, error) {
+	state, err := k.shareRefreshState.Get(ctx)
+	if errors.Is(err, collections.ErrNotFound) {
+		return types.ShareRefreshState{}, nil
+	}
+
+	return state, err
+}
+
+// SetShareRefreshState stores the state of the periodic share refresh
+func (k Keeper) SetShareRefreshState(ctx context.Context, state types.ShareRefreshState) error {
+	return k.shareRefreshState.Set(ctx, state)
+}
@@ -0,0 +1,160 @@
+package keeper_test
+
+import (
+	"bytes"
+
+	"cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/keeper"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+func (s *KeeperTestSuite) TestCustodianDepartureIsBatched() {
+	custodian := sdk.ValAddress("departed_validator__")
+
+	// More capsules than a block refreshes, with the departed custodian holding two
+	// shares of each
+	const capsules = 120
+	for id := uint64(1); id <= capsules; id++ {
+		s.Require().NoError(s.keeper.SetCapsule(s.ctx, &types.TimeCapsule{
+			ID:     id,
+			Owner:  sdk.AccAddress("owner_______________").String(),
+			Status: types.CapsuleStatus_UNLOCKED,
+		}))
+		for index := uint32(0); index < 2; index++ {
+			s.Require().NoError(s.keeper.SetKeyShare(s.ctx, &types.KeyShare{
+				CapsuleID:  id,
+				ShareIndex: index,
+				NodeID:     custodian.String(),
+			}))
+		}
+	}
+
+	s.Require().NoError(s.keeper.Hooks().AfterValidatorRemoved(s.ctx, nil, custodian))
+	departures, err := s.keeper.GetAllCustodianDepartures(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal([]string{custodian.String()}, departures)
+
+	// Each block resumes after the capsules of the previous one
+	for _, next := range []uint64{51, 101} {
+		s.Require().NoError(s.keeper.EndBlocker(s.ctx))
+
+		state, err := s.keeper.GetShareRefreshState(s.ctx)
+		s.Require().NoError(err)
+		s.Require().Equal(custodian.String(), state.DepartingCustodian)
+		s.Require().Equal(next, state.NextDepartureCapsuleID)
+
+		departures, err := s.keeper.GetAllCustodianDepartures(s.ctx)
+		s.Require().NoError(err)
+		s.Require().Len(departures, 1)
+	}
+
+	s.Require().NoError(s.keeper.EndBlocker(s.ctx))
+
+	state, err := s.keeper.GetShareRefreshState(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Empty(state.DepartingCustodian)
+	s.Require().Zero(state.NextDepartureCapsuleID)
+
+	departures, err = s.keeper.GetAllCustodianDepartures(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Empty(departures)
+}
+
+func (s *KeeperTestSuite) TestCustodianWithoutSharesIsNotQueued() {
+	custodian := sdk.ValAddress("idle_validator______")
+
+	s.Require().NoError(s.keeper.Hooks().AfterValidatorBeginUnbonding(s.ctx, nil, custodian))
+
+	departures, err := s.keeper.GetAllCustodianDepartures(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Empty(departures)
+}
+
+// TestShareRefreshIsDeterministic checks that two nodes refreshing the same key shares in
+// the same block, including the share of a departed custodian, end with the same state
+func (s *KeeperTestSuite) TestShareRefreshIsDeterministic() {
+	// The third custodian left the validator set, the fourth is a spare
+	custodians := make([]string, 4)
+	for i := range custodians {
+		consKey := ed25519.GenPrivKey().PubKey()
+		custodians[i] = sdk.ValAddress(consKey.Address()).String()
+		if i == 2 {
+			continue
+		}
+
+		val, err := stakingtypes.NewValidator(custodians[i], consKey, stakingtypes.Description{})
+		s.Require().NoError(err)
+		val.Status = stakingtypes.Bonded
+		val.Tokens = math.NewInt(100)
+		s.stakingKeeper.validators[custodians[i]] = val
+		s.stakingKeeper.bonded = append(s.stakingKeeper.bonded, val)
+	}
+
+	secret := []byte("refreshed on every node alike")
+	sss := crypto.NewShamirSecretSharing()
+	shares, commitments, err := sss.SplitSecretVerifiable(secret, 2, 3)
+	s.Require().NoError(err)
+
+	headerHash := bytes.Repeat([]byte{0xab}, 32)
+	refresh := func() (sdk.Context, keeper.Keeper) {
+		ctx, k, _ := s.importGenesis(timecapsule.DefaultGenesis())
+		ctx = ctx.WithHeaderHash(headerHash)
+
+		s.Require().NoError(k.SetCapsule(ctx, &types.TimeCapsule{
+			ID:               1,
+			Owner:            sdk.AccAddress("owner_______________").String(),
+			Status:           types.CapsuleStatus_ACTIVE,
+			Threshold:        2,
+			TotalShares:      3,
+			ShareCommitments: commitments,
+		}))
+		for i, share := range shares {
+			s.Require().NoError(k.SetKeyShare(ctx, &types.KeyShare{
+				CapsuleID:      1,
+				ShareIndex:     uint32(i),
+				NodeID:         custodians[i],
+				EncryptedShare: crypto.ShareToBytes(share),
+			}))
+		}
+
+		s.Require().NoError(k.EndBlocker(ctx))
+		return ctx, k
+	}
+
+	ctxA, keeperA := refresh()
+	ctxB, keeperB := refresh()
+
+	capsuleA, err := keeperA.GetCapsule(ctxA, 1)
+	s.Require().NoError(err)
+	capsuleB, err := keeperB.GetCapsule(ctxB, 1)
+	s.Require().NoError(err)
+	s.Require().Equal(capsuleA.ShareCommitments, capsuleB.ShareCommitments)
+	s.Require().NotEqual(commitments, capsuleA.ShareCommitments)
+
+	sharesA, err := keeperA.GetAllKeyShares(ctxA)
+	s.Require().NoError(err)
+	sharesB, err := keeperB.GetAllKeyShares(ctxB)
+	s.Require().NoError(err)
+	s.Require().Equal(sharesA, sharesB)
+
+	// The departed custodian's share moved to the spare validator, and the refreshed
+	// shares still combine to the secret
+	s.Require().Equal(custodians[3], sharesA[2].NodeID)
+	refreshed := make([]*crypto.Share, len(sharesA))
+	for i, keyShare := range sharesA {
+		s.Require().NotEqual(crypto.ShareToBytes(shares[i]), keyShare.EncryptedShare)
+		refreshed[i], err = crypto.BytesToShare(keyShare.EncryptedShare)
+		s.Require().NoError(err)
+		s.Require().NoError(crypto.VerifyShare(capsuleA.ShareCommitments, refreshed[i]))
+	}
+	combined, err := sss.CombineShares(refreshed[1:])
+	s.Require().NoError(err)
+	s.Require().Equal(secret, combined)
+}
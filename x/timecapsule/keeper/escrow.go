@@ -0,0 +1,200 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// LockEscrow deposits the escrow of a freshly created capsule into the module account.
+// The depositor is the capsule's creator, who differs from the owner of group policy capsules.
+func (k Keeper) LockEscrow(ctx context.Context, capsule *types.TimeCapsule, depositor string, escrow sdk.Coins, beneficiaries []types.Beneficiary) error {
+	if escrow.IsZero() {
+		return nil
+	}
+
+	if err := types.ValidateEscrow(escrow, beneficiaries); err != nil {
+		return types.ErrInvalidEscrow.Wrap(err.Error())
+	}
+
+	depositorAddr, err := k.addressCodec.StringToBytes(depositor)
+	if err != nil {
+		return types.ErrInvalidAddress.Wrapf("invalid depositor address: %s", err)
+	}
+
+	if err := k.bankKeeper.SendCoinsFromAccountToModule(ctx, depositorAddr, types.ModuleName, escrow); err != nil {
+		return types.ErrInvalidEscrow.Wrapf("failed to lock escrow: %s", err)
+	}
+
+	capsule.Escrow = escrow
+	capsule.Beneficiaries = beneficiaries
+
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return fmt.Errorf("failed to store capsule escrow: %w", err)
+	}
+
+	sdk.UnwrapSDKContext(ctx).EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeEscrowLocked,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsule.ID)),
+			sdk.NewAttribute(types.AttributeKeyOwner, capsule.Owner),
+			sdk.NewAttribute(types.AttributeKeyAmount, escrow.String()),
+		),
+	)
+
+	return nil
+}
+
+// releaseEscrow pays the escrow of an unlocked capsule out to its payees.
+// Beneficiaries take precedence, then the recipient, then the owner.
+func (k Keeper) releaseEscrow(ctx context.Context, capsule *types.TimeCapsule) (sdk.Coins, error) {
+	if !capsule.HasEscrow() {
+		return nil, nil
+	}
+
+	escrow := capsule.Escrow
+	payouts, err := k.escrowPayouts(capsule)
+	if err != nil {
+		return nil, err
+	}
+
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+	for _, payout := range payouts {
+		if payout.amount.IsZero() {
+			continue
+		}
+
+		payeeAddr, err := k.addressCodec.StringToBytes(payout.address)
+		if err != nil {
+			return nil, types.ErrInvalidAddress.Wrapf("invalid escrow payee %s: %s", payout.address, err)
+		}
+
+		if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, payeeAddr, payout.amount); err != nil {
+			return nil, types.ErrInvalidEscrow.Wrapf("failed to release escrow to %s: %s", payout.address, err)
+		}
+
+		sdkCtx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeEscrowReleased,
+				sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsule.ID)),
+				sdk.NewAttribute(types.AttributeKeyPayee, payout.address),
+				sdk.NewAttribute(types.AttributeKeyAmount, payout.amount.String()),
+			),
+		)
+	}
+
+	// The split only applies while the escrow is held
+	capsule.Escrow = nil
+	capsule.Beneficiaries = nil
+
+	return escrow, nil
+}
+
+// refundEscrow returns the escrow of a cancelled or expired capsule to its owner
+func (k Keeper) refundEscrow(ctx context.Context, capsule *types.TimeCapsule) (sdk.Coins, error) {
+	if !capsule.HasEscrow() {
+		return nil, nil
+	}
+
+	escrow := capsule.Escrow
+
+	ownerAddr, err := k.addressCodec.StringToBytes(capsule.Owner)
+	if err != nil {
+		return nil, types.ErrInvalidAddress.Wrapf("invalid owner address: %s", err)
+	}
+
+	if err := k.bankKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, ownerAddr, escrow); err != nil {
+		return nil, types.ErrInvalidEscrow.Wrapf("failed to refund escrow: %s", err)
+	}
+
+	sdk.UnwrapSDKContext(ctx).EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeEscrowRefunded,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsule.ID)),
+			sdk.NewAttribute(types.AttributeKeyOwner, capsule.Owner),
+			sdk.NewAttribute(types.AttributeKeyAmount, escrow.String()),
+		),
+	)
+
+	capsule.Escrow = nil
+	capsule.Beneficiaries = nil
+
+	return escrow, nil
+}
+
+// canRefundEscrow checks the cancellation policy for capsules holding an escrow.
+// Once a time lock or dead man's switch is due, the escrow belongs to the payees
+// and the owner can no longer claw it back by cancelling.
+func (k Keeper) canRefundEscrow(ctx context.Context, capsule *types.TimeCapsule) error {
+	if !capsule.HasEscrow() {
+		return nil
+	}
+
+	switch capsule.CapsuleType {
+	case types.CapsuleType_TIME_LOCK, types.CapsuleType_DEAD_MANS_SWITCH:
+		if capsule.IsUnlockable(sdk.UnwrapSDKContext(ctx)) {
+			return types.ErrInvalidEscrow.Wrapf("capsule %d is already due, escrow cannot be refunded", capsule.ID)
+		}
+	}
+
+	return nil
+}
+
+// escrowPayout is a single transfer out of a capsule's escrow
+type escrowPayout struct {
+	address string
+	amount  sdk.Coins
+}
+
+// escrowPayouts splits the escrow according to the capsule's beneficiaries.
+// Rounding dust is assigned to the first beneficiary so the escrow is paid out in full.
+func (k Keeper) escrowPayouts(capsule *types.TimeCapsule) ([]escrowPayout, error) {
+	if len(capsule.Beneficiaries) == 0 {
+		payee := capsule.Recipient
+		if payee == "" {
+			payee = capsule.Owner
+		}
+		return []escrowPayout{{address: payee, amount: capsule.Escrow}}, nil
+	}
+
+	payouts := make([]escrowPayout, len(capsule.Beneficiaries))
+	distributed := sdk.NewCoins()
+	total := math.NewIntFromUint64(uint64(types.BasisPoints))
+
+	for i, beneficiary := range capsule.Beneficiaries {
+		var share sdk.Coins
+		for _, coin := range capsule.Escrow {
+			amount := coin.Amount.Mul(math.NewIntFromUint64(uint64(beneficiary.Weight))).Quo(total)
+			if amount.IsPositive() {
+				share = share.Add(sdk.NewCoin(coin.Denom, amount))
+			}
+		}
+		payouts[i] = escrowPayout{address: beneficiary.Address, amount: share}
+		distributed = distributed.Add(share...)
+	}
+
+	dust, negative := capsule.Escrow.SafeSub(distributed...)
+	if negative {
+		return nil, types.ErrInvalidEscrow.Wrapf("capsule %d escrow split exceeds deposit", capsule.ID)
+	}
+	payouts[0].amount = payouts[0].amount.Add(dust...)
+
+	return payouts, nil
+}
+
+// GetTotalEscrow returns the sum of escrows held for active capsules
+func (k Keeper) GetTotalEscrow(ctx context.Context) (sdk.Coins, error) {
+	total := sdk.NewCoins()
+
+	err := k.capsules.Walk(ctx, nil, func(id uint64, capsule types.TimeCapsule) (bool, error) {
+		if capsule.Status == types.CapsuleStatus_ACTIVE && capsule.HasEscrow() {
+			total = total.Add(capsule.Escrow...)
+		}
+		return false, nil
+	})
+
+	return total, err
+}
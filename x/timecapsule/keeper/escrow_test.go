@@ -0,0 +1,76 @@
+package keeper_test
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// TestSettledEscrowRoundTripsGenesis checks that capsules whose escrow was released or
+// refunded export a valid genesis, which imports back to the same state
+func (s *KeeperTestSuite) TestSettledEscrowRoundTripsGenesis() {
+	owner := sdk.AccAddress("owner_______________")
+	alice := sdk.AccAddress("alice_______________").String()
+	bob := sdk.AccAddress("bob_________________").String()
+	escrow := sdk.NewCoins(sdk.NewInt64Coin("stake", 1000))
+	beneficiaries := []types.Beneficiary{{Address: alice, Weight: 6000}, {Address: bob, Weight: 4000}}
+	s.bankKeeper.balances[owner.String()] = escrow.Add(escrow...)
+
+	// Capsule IDs start at one
+	s.Require().NoError(s.keeper.SetCapsuleCounter(s.ctx, 1))
+
+	createCapsule := func() *types.TimeCapsule {
+		capsule, err := s.keeper.CreateCapsule(s.ctx, owner.String(), "", []byte("capsule data"), "",
+			types.CapsuleType_SAFE, 2, 3, nil, 0, nil, "", 0, 0, "", nil, nil, nil, nil)
+		s.Require().NoError(err)
+		s.Require().NoError(s.keeper.LockEscrow(s.ctx, capsule, owner.String(), escrow, beneficiaries))
+		return capsule
+	}
+
+	requireRoundTrip := func() {
+		genesis := timecapsule.ExportGenesis(s.ctx, s.keeper)
+		s.Require().NoError(timecapsule.ValidateGenesis(genesis))
+
+		ctx, k, _ := s.importGenesis(genesis)
+		s.Require().Equal(genesis.Capsules, timecapsule.ExportGenesis(ctx, k).Capsules)
+	}
+
+	// Released on open, split between the beneficiaries
+	released := createCapsule()
+	requireRoundTrip()
+
+	keyShares, err := s.keeper.GetAllKeyShares(s.ctx)
+	s.Require().NoError(err)
+	var shares []*crypto.Share
+	for _, keyShare := range keyShares {
+		share, err := crypto.BytesToShare(keyShare.EncryptedShare)
+		s.Require().NoError(err)
+		shares = append(shares, share)
+	}
+	_, _, err = s.keeper.OpenCapsule(s.ctx, released.ID, owner.String(), shares, nil)
+	s.Require().NoError(err)
+
+	released, err = s.keeper.GetCapsule(s.ctx, released.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(types.CapsuleStatus_UNLOCKED, released.Status)
+	s.Require().False(released.HasEscrow())
+	s.Require().Empty(released.Beneficiaries)
+	s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("stake", 600)), s.bankKeeper.balances[alice])
+	s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("stake", 400)), s.bankKeeper.balances[bob])
+	requireRoundTrip()
+
+	// Refunded to the owner on cancel
+	refunded := createCapsule()
+	_, err = s.msgServer.CancelCapsule(s.ctx, types.NewMsgCancelCapsule(owner.String(), refunded.ID, ""))
+	s.Require().NoError(err)
+
+	refunded, err = s.keeper.GetCapsule(s.ctx, refunded.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(types.CapsuleStatus_CANCELLED, refunded.Status)
+	s.Require().False(refunded.HasEscrow())
+	s.Require().Empty(refunded.Beneficiaries)
+	s.Require().Equal(escrow, s.bankKeeper.balances[owner.String()])
+	requireRoundTrip()
+}
@@ -0,0 +1,188 @@
+package keeper_test
+
+import (
+	"time"
+
+	corestore "cosmossdk.io/core/store"
+	storetypes "cosmossdk.io/store/types"
+
+	addresscodec "github.com/cosmos/cosmos-sdk/codec/address"
+	"github.com/cosmos/cosmos-sdk/runtime"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/keeper"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// genesisPrefixes are the store prefixes of the quota and transfer collections, which
+// must be restored byte for byte by importing an exported genesis
+var genesisPrefixes = map[string][]byte{
+	"creation quotas":   types.CreationQuotasKeyPrefix,
+	"creation volume":   types.CreationVolumeKey,
+	"transfer history":  types.TransferHistoryKeyPrefix,
+	"pending transfers": types.PendingTransfersKeyPrefix,
+	"transfer stats":    types.TransferStatsKey,
+}
+
+// importGenesis initializes a keeper on an empty store with genesis
+func (s *KeeperTestSuite) importGenesis(genesis *timecapsule.GenesisState) (sdk.Context, keeper.Keeper, corestore.KVStoreService) {
+	key := storetypes.NewKVStoreKey(types.StoreKey)
+	testCtx := testutil.DefaultContextWithDB(s.T(), key, storetypes.NewTransientStoreKey("transient_test"))
+	ctx := testCtx.Ctx.WithBlockHeader(s.ctx.BlockHeader()).WithHeaderInfo(s.ctx.HeaderInfo())
+	storeService := runtime.NewKVStoreService(key)
+
+	k := keeper.NewKeeper(
+		s.encCfg.Codec,
+		addresscodec.NewBech32Codec("cosmos"),
+		storeService,
+		ctx.Logger(),
+		s.bankKeeper,
+		s.accountKeeper,
+		s.stakingKeeper,
+		nil,
+		s.authority,
+	)
+	timecapsule.InitGenesis(ctx, k, genesis)
+
+	return ctx, k, storeService
+}
+
+// requireSameStores checks that the genesis collections of an imported store hold the
+// same keys and values as the store of the suite
+func (s *KeeperTestSuite) requireSameStores(ctx sdk.Context, storeService corestore.KVStoreService) {
+	imported := storeService.OpenKVStore(ctx)
+	exported := s.storeService.OpenKVStore(s.ctx)
+
+	for name, prefix := range genesisPrefixes {
+		iterA, err := exported.Iterator(prefix, storetypes.PrefixEndBytes(prefix))
+		s.Require().NoError(err)
+		iterB, err := imported.Iterator(prefix, storetypes.PrefixEndBytes(prefix))
+		s.Require().NoError(err)
+
+		for ; iterA.Valid(); iterA.Next() {
+			s.Require().True(iterB.Valid(), "%s: key %X missing after import", name, iterA.Key())
+			s.Require().Equal(iterA.Key(), iterB.Key(), name)
+			s.Require().Equal(iterA.Value(), iterB.Value(), name)
+			iterB.Next()
+		}
+		s.Require().False(iterB.Valid(), "%s: extra key %X after import", name, iterB.Key())
+
+		s.Require().NoError(iterA.Close())
+		s.Require().NoError(iterB.Close())
+	}
+}
+
+func (s *KeeperTestSuite) TestGenesisExportsQuotasAndTransfers() {
+	alice := sdk.AccAddress("alice_______________").String()
+	bob := sdk.AccAddress("bob_________________").String()
+	now := s.ctx.BlockTime()
+
+	// Nothing was created or transferred yet, so nothing is exported or imported
+	genesis := timecapsule.ExportGenesis(s.ctx, s.keeper)
+	s.Require().Empty(genesis.CreationQuotas)
+	s.Require().Nil(genesis.CreationVolume)
+	s.Require().Empty(genesis.TransferHistory)
+	s.Require().Nil(genesis.TransferStats)
+
+	ctx, _, storeService := s.importGenesis(genesis)
+	s.requireSameStores(ctx, storeService)
+
+	s.Require().NoError(s.keeper.ConsumeCreationQuota(s.ctx, alice, 2))
+	s.Require().NoError(s.keeper.ConsumeCreationQuota(s.ctx, bob, 1))
+	s.Require().NoError(s.keeper.SetTransferHistory(s.ctx, types.TransferHistory{
+		CapsuleID:    1,
+		TransferID:   "1-1-transfer",
+		FromOwner:    alice,
+		ToOwner:      bob,
+		TransferType: "direct",
+		Status:       "completed",
+		TransferTime: now,
+		BlockHeight:  1,
+	}))
+	s.Require().NoError(s.keeper.SetPendingTransfer(s.ctx, "2-1-transfer", types.PendingTransfer{
+		TransferID:      "2-1-transfer",
+		CapsuleID:       2,
+		FromOwner:       bob,
+		ToOwner:         alice,
+		RequestTime:     now,
+		ExpiryTime:      now.Add(24 * time.Hour),
+		RequireApproval: true,
+		Status:          "pending",
+	}))
+	s.Require().NoError(s.keeper.SetTransferStats(s.ctx, types.TransferStats{
+		TotalTransfers:     1,
+		CompletedTransfers: 1,
+		LastTransferTime:   &now,
+	}))
+
+	genesis = timecapsule.ExportGenesis(s.ctx, s.keeper)
+	s.Require().NoError(timecapsule.ValidateGenesis(genesis))
+	s.Require().Len(genesis.CreationQuotas, 2)
+	s.Require().NotNil(genesis.CreationVolume)
+	s.Require().Equal(uint32(3), genesis.CreationVolume.BlockCreations)
+	s.Require().Len(genesis.TransferHistory, 1)
+	s.Require().Len(genesis.PendingTransfers, 1)
+	s.Require().NotNil(genesis.TransferStats)
+
+	ctx, k, storeService := s.importGenesis(genesis)
+	s.requireSameStores(ctx, storeService)
+	s.Require().Equal(genesis, timecapsule.ExportGenesis(ctx, k))
+
+	// The quotas keep counting from the imported state
+	quota, err := k.GetCreationQuota(ctx, alice)
+	s.Require().NoError(err)
+	s.Require().Equal(uint32(2), quota.Count)
+}
+
+func (s *KeeperTestSuite) TestValidateGenesisRejectsInvalidQuotasAndTransfers() {
+	alice := sdk.AccAddress("alice_______________").String()
+
+	testCases := []struct {
+		name     string
+		malleate func(*timecapsule.GenesisState)
+	}{
+		{
+			"invalid creator",
+			func(gs *timecapsule.GenesisState) {
+				gs.CreationQuotas = []types.AccountCreationQuota{{Creator: "alice"}}
+			},
+		},
+		{
+			"duplicate creation quota",
+			func(gs *timecapsule.GenesisState) {
+				gs.CreationQuotas = []types.AccountCreationQuota{{Creator: alice}, {Creator: alice}}
+			},
+		},
+		{
+			"fee multiplier below one",
+			func(gs *timecapsule.GenesisState) {
+				volume := types.NewCreationVolume()
+				volume.Multiplier = volume.Multiplier.QuoInt64(2)
+				gs.CreationVolume = &volume
+			},
+		},
+		{
+			"transfer history without ID",
+			func(gs *timecapsule.GenesisState) {
+				gs.TransferHistory = []types.TransferHistory{{CapsuleID: 1}}
+			},
+		},
+		{
+			"duplicate pending transfer",
+			func(gs *timecapsule.GenesisState) {
+				gs.PendingTransfers = []types.PendingTransfer{{TransferID: "1-1"}, {TransferID: "1-1"}}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			genesis := timecapsule.DefaultGenesis()
+			s.Require().NoError(timecapsule.ValidateGenesis(genesis))
+			tc.malleate(genesis)
+			s.Require().Error(timecapsule.ValidateGenesis(genesis))
+		})
+	}
+}
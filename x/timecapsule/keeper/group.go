@@ -0,0 +1,35 @@
+package keeper
+
+import (
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/x/group"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// checkGroupPolicy checks that an address is an x/group policy account
+func (k Keeper) checkGroupPolicy(ctx context.Context, address string) error {
+	if k.groupKeeper == nil {
+		return types.ErrInvalidGroupPolicy.Wrap("group policy capsules need the group module")
+	}
+
+	if _, err := k.groupKeeper.GroupPolicyInfo(ctx, &group.QueryGroupPolicyInfoRequest{Address: address}); err != nil {
+		return types.ErrInvalidGroupPolicy.Wrapf("%s is not a group policy: %s", address, err)
+	}
+	return nil
+}
+
+// transferGroupPolicy hands a group policy capsule over to the group policy it is
+// transferred to. Capsules owned by an account are left untouched.
+func (k Keeper) transferGroupPolicy(ctx context.Context, capsule *types.TimeCapsule, newOwner string) error {
+	if capsule.GroupPolicy == "" {
+		return nil
+	}
+
+	if err := k.checkGroupPolicy(ctx, newOwner); err != nil {
+		return types.ErrInvalidTransfer.Wrapf("group policy capsules can only be transferred to a group policy: %s", err)
+	}
+	capsule.GroupPolicy = newOwner
+	return nil
+}
@@ -0,0 +1,480 @@
+package keeper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"cosmossdk.io/collections"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// SetCapsuleGuardians nominates the guardians that approve emergency actions on a capsule
+func (k Keeper) SetCapsuleGuardians(ctx context.Context, capsule *types.TimeCapsule, guardians []string, threshold uint32) error {
+	if len(guardians) == 0 {
+		return nil
+	}
+
+	if err := types.ValidateGuardians(capsule.Owner, guardians, threshold); err != nil {
+		return types.ErrInvalidGuardians.Wrap(err.Error())
+	}
+
+	capsule.Guardians = guardians
+	capsule.GuardianThreshold = threshold
+
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return fmt.Errorf("failed to store capsule guardians: %w", err)
+	}
+
+	return nil
+}
+
+// GetEmergencyAction retrieves an emergency action by ID
+func (k Keeper) GetEmergencyAction(ctx context.Context, actionID string) (*types.EmergencyAction, error) {
+	action, err := k.emergencyActions.Get(ctx, actionID)
+	if err != nil {
+		return nil, types.ErrEmergencyActionNotFound.Wrapf("emergency action %s not found", actionID)
+	}
+	return &action, nil
+}
+
+// GetCapsuleEmergencyActions retrieves all emergency actions proposed for a capsule
+func (k Keeper) GetCapsuleEmergencyActions(ctx context.Context, capsuleID uint64) ([]types.EmergencyAction, error) {
+	var actions []types.EmergencyAction
+
+	rng := collections.NewPrefixedPairRange[uint64, string](capsuleID)
+	err := k.capsuleEmergencyActions.Walk(ctx, rng, func(key collections.Pair[uint64, string]) (bool, error) {
+		action, err := k.emergencyActions.Get(ctx, key.K2())
+		if err != nil {
+			return true, err
+		}
+		actions = append(actions, action)
+		return false, nil
+	})
+
+	return actions, err
+}
+
+// ProposeEmergencyAction records a guardian's emergency action proposal. The proposal
+// counts as the guardian's approval; the action can only execute once the capsule's
+// guardian threshold is met and the emergency delay has elapsed.
+func (k Keeper) ProposeEmergencyAction(ctx context.Context, guardian string, capsuleID uint64, actionType, reason, newRecipient string, newRecipientKey []byte) (*types.EmergencyAction, error) {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	capsule, err := k.GetCapsule(ctx, capsuleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if capsule.GuardianThreshold == 0 {
+		return nil, types.ErrInvalidGuardians.Wrapf("capsule %d has no guardians", capsuleID)
+	}
+
+	if !capsule.IsGuardian(guardian) {
+		return nil, types.ErrUnauthorized.Wrap("only guardians can propose emergency actions")
+	}
+
+	existing, err := k.GetCapsuleEmergencyActions(ctx, capsuleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only one pending action of each type per capsule
+	for _, other := range existing {
+		if other.Status == types.EmergencyStatusPending && other.ActionType == actionType {
+			return nil, types.ErrInvalidEmergencyAction.Wrapf("%s action %s is already pending", actionType, other.ID)
+		}
+	}
+
+	action := types.EmergencyAction{
+		ID:              fmt.Sprintf("emergency_%d_%d", capsuleID, len(existing)+1),
+		CapsuleID:       capsuleID,
+		Creator:         guardian,
+		ActionType:      actionType,
+		Reason:          reason,
+		NewRecipient:    newRecipient,
+		NewRecipientKey: newRecipientKey,
+		Approvals:       []string{guardian},
+		Status:          types.EmergencyStatusPending,
+		ActionTime:      sdkCtx.BlockTime(),
+		BlockHeight:     sdkCtx.BlockHeight(),
+	}
+
+	if err := checkEmergencyAction(capsule, &action); err != nil {
+		return nil, err
+	}
+
+	if err := k.startEmergencyDelay(ctx, capsule, &action); err != nil {
+		return nil, err
+	}
+
+	if err := k.SetEmergencyAction(ctx, &action); err != nil {
+		return nil, err
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeEmergencyActionProposed,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsuleID)),
+			sdk.NewAttribute(types.AttributeKeyActionID, action.ID),
+			sdk.NewAttribute(types.AttributeKeyEmergencyAction, actionType),
+			sdk.NewAttribute(types.AttributeKeyGuardian, guardian),
+			sdk.NewAttribute(types.AttributeKeyEmergencyReason, reason),
+		),
+	)
+
+	return &action, nil
+}
+
+// ApproveEmergencyAction adds a guardian's approval to a pending emergency action
+func (k Keeper) ApproveEmergencyAction(ctx context.Context, guardian, actionID string) (*types.EmergencyAction, error) {
+	action, capsule, err := k.pendingEmergencyAction(ctx, actionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !capsule.IsGuardian(guardian) {
+		return nil, types.ErrUnauthorized.Wrap("only guardians can approve emergency actions")
+	}
+
+	if action.HasApproved(guardian) {
+		return nil, types.ErrInvalidEmergencyAction.Wrapf("guardian %s already approved %s", guardian, actionID)
+	}
+
+	action.Approvals = append(action.Approvals, guardian)
+
+	if err := k.startEmergencyDelay(ctx, capsule, action); err != nil {
+		return nil, err
+	}
+
+	if err := k.SetEmergencyAction(ctx, action); err != nil {
+		return nil, err
+	}
+
+	sdk.UnwrapSDKContext(ctx).EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeEmergencyActionApproved,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", action.CapsuleID)),
+			sdk.NewAttribute(types.AttributeKeyActionID, actionID),
+			sdk.NewAttribute(types.AttributeKeyGuardian, guardian),
+			sdk.NewAttribute("approvals", fmt.Sprintf("%d/%d", len(action.Approvals), capsule.GuardianThreshold)),
+		),
+	)
+
+	return action, nil
+}
+
+// VetoEmergencyAction rejects a pending emergency action. The recipient and every
+// guardian can veto until the action is executed.
+func (k Keeper) VetoEmergencyAction(ctx context.Context, sender, actionID, reason string) error {
+	action, capsule, err := k.pendingEmergencyAction(ctx, actionID)
+	if err != nil {
+		return err
+	}
+
+	if !canChallengeEmergencyAction(capsule, action, sender) {
+		return types.ErrUnauthorized.Wrap("only the recipient or a guardian can veto emergency actions")
+	}
+
+	action.Status = types.EmergencyStatusVetoed
+	action.VetoedBy = sender
+
+	if err := k.SetEmergencyAction(ctx, action); err != nil {
+		return err
+	}
+
+	sdk.UnwrapSDKContext(ctx).EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeEmergencyActionVetoed,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", action.CapsuleID)),
+			sdk.NewAttribute(types.AttributeKeyActionID, actionID),
+			sdk.NewAttribute("vetoed_by", sender),
+			sdk.NewAttribute("reason", reason),
+		),
+	)
+
+	return nil
+}
+
+// ExecuteEmergencyAction applies an approved emergency action to its capsule once the
+// emergency delay has elapsed
+func (k Keeper) ExecuteEmergencyAction(ctx context.Context, guardian, actionID string) (*types.EmergencyAction, error) {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	action, capsule, err := k.pendingEmergencyAction(ctx, actionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !capsule.IsGuardian(guardian) {
+		return nil, types.ErrUnauthorized.Wrap("only guardians can execute emergency actions")
+	}
+
+	if action.ExecuteAfter == nil {
+		return nil, types.ErrInvalidEmergencyAction.Wrapf("action has %d of %d required approvals",
+			len(action.Approvals), capsule.GuardianThreshold)
+	}
+
+	blockTime := sdkCtx.BlockTime()
+	if blockTime.Before(*action.ExecuteAfter) {
+		return nil, types.ErrEmergencyDelayActive.Wrapf("action can execute after %s", action.ExecuteAfter)
+	}
+
+	// The capsule may have changed while the action was pending
+	if err := checkEmergencyAction(capsule, action); err != nil {
+		return nil, err
+	}
+
+	switch action.ActionType {
+	case types.EmergencyActionContractDeletion:
+		action.PreviousContract = capsule.ConditionContract
+		capsule.ConditionContract = ""
+		capsule.ForceUnlocked = true
+	case types.EmergencyActionForceUnlock:
+		capsule.ForceUnlocked = true
+	case types.EmergencyActionFreeze:
+		capsule.Frozen = true
+	case types.EmergencyActionRotateRecipient:
+		action.PreviousRecipient = capsule.Recipient
+		action.PreviousRecipientKey = capsule.RecipientKey
+		capsule.Recipient = action.NewRecipient
+		capsule.RecipientKey = action.NewRecipientKey
+	}
+	capsule.UpdatedAt = blockTime
+
+	action.Status = types.EmergencyStatusExecuted
+	action.ExecutedAt = &blockTime
+	action.IsReversible = true
+
+	// A freeze can be lifted at any time, other actions only within the reversal window
+	if action.ActionType != types.EmergencyActionFreeze {
+		params, err := k.GetParams(ctx)
+		if err != nil {
+			return nil, err
+		}
+		reversibleUntil := blockTime.Add(params.EmergencyActionDelay)
+		action.ReversibleUntil = &reversibleUntil
+	}
+
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return nil, fmt.Errorf("failed to update capsule: %w", err)
+	}
+
+	if err := k.SetEmergencyAction(ctx, action); err != nil {
+		return nil, err
+	}
+
+	k.logger.Info("Emergency action executed",
+		"capsule_id", capsule.ID,
+		"action_id", actionID,
+		"action_type", action.ActionType,
+		"approvals", len(action.Approvals),
+	)
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeEmergencyActionExecuted,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsule.ID)),
+			sdk.NewAttribute(types.AttributeKeyActionID, actionID),
+			sdk.NewAttribute(types.AttributeKeyEmergencyAction, action.ActionType),
+			sdk.NewAttribute(types.AttributeKeyGuardian, guardian),
+			sdk.NewAttribute("is_reversible", "true"),
+		),
+	)
+
+	return action, nil
+}
+
+// ReverseEmergencyAction restores the capsule state an executed emergency action
+// replaced. The capsule must not have been opened or cancelled since.
+func (k Keeper) ReverseEmergencyAction(ctx context.Context, sender, actionID, reason string) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	action, err := k.GetEmergencyAction(ctx, actionID)
+	if err != nil {
+		return err
+	}
+
+	if action.Status != types.EmergencyStatusExecuted || !action.IsReversible {
+		return types.ErrInvalidEmergencyAction.Wrapf("action %s is %s and cannot be reversed", actionID, action.Status)
+	}
+
+	blockTime := sdkCtx.BlockTime()
+	if action.ReversibleUntil != nil && blockTime.After(*action.ReversibleUntil) {
+		return types.ErrInvalidEmergencyAction.Wrapf("reversal window closed at %s", action.ReversibleUntil)
+	}
+
+	capsule, err := k.GetCapsule(ctx, action.CapsuleID)
+	if err != nil {
+		return err
+	}
+
+	if !canChallengeEmergencyAction(capsule, action, sender) {
+		return types.ErrUnauthorized.Wrap("only the recipient or a guardian can reverse emergency actions")
+	}
+
+	if capsule.Status != types.CapsuleStatus_ACTIVE {
+		return types.ErrInvalidCapsule.Wrapf("cannot reverse action on capsule with status %s", capsule.Status.String())
+	}
+
+	switch action.ActionType {
+	case types.EmergencyActionContractDeletion:
+		capsule.ConditionContract = action.PreviousContract
+		capsule.ForceUnlocked = false
+	case types.EmergencyActionForceUnlock:
+		capsule.ForceUnlocked = false
+	case types.EmergencyActionFreeze:
+		capsule.Frozen = false
+	case types.EmergencyActionRotateRecipient:
+		if capsule.Recipient != action.NewRecipient {
+			return types.ErrInvalidEmergencyAction.Wrap("recipient changed since the rotation")
+		}
+		capsule.Recipient = action.PreviousRecipient
+		capsule.RecipientKey = action.PreviousRecipientKey
+	}
+	capsule.UpdatedAt = blockTime
+
+	action.Status = types.EmergencyStatusReversed
+	action.IsReversible = false
+	action.ReversedAt = &blockTime
+	action.ReversedBy = sender
+
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return fmt.Errorf("failed to update capsule: %w", err)
+	}
+
+	// The retention queues dropped the capsule while it was frozen
+	if action.ActionType == types.EmergencyActionFreeze {
+		if err := k.ScheduleRetention(ctx, capsule); err != nil {
+			return err
+		}
+	}
+
+	if err := k.SetEmergencyAction(ctx, action); err != nil {
+		return err
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeEmergencyActionReversed,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsule.ID)),
+			sdk.NewAttribute(types.AttributeKeyActionID, actionID),
+			sdk.NewAttribute(types.AttributeKeyEmergencyAction, action.ActionType),
+			sdk.NewAttribute("reversed_by", sender),
+			sdk.NewAttribute("reason", reason),
+		),
+	)
+
+	return nil
+}
+
+// pendingEmergencyAction loads a pending emergency action and its capsule
+func (k Keeper) pendingEmergencyAction(ctx context.Context, actionID string) (*types.EmergencyAction, *types.TimeCapsule, error) {
+	action, err := k.GetEmergencyAction(ctx, actionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if action.Status != types.EmergencyStatusPending {
+		return nil, nil, types.ErrInvalidEmergencyAction.Wrapf("action %s is %s", actionID, action.Status)
+	}
+
+	capsule, err := k.GetCapsule(ctx, action.CapsuleID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return action, capsule, nil
+}
+
+// startEmergencyDelay starts the emergency delay once an action has enough approvals
+func (k Keeper) startEmergencyDelay(ctx context.Context, capsule *types.TimeCapsule, action *types.EmergencyAction) error {
+	if action.ExecuteAfter != nil || uint32(len(action.Approvals)) < capsule.GuardianThreshold {
+		return nil
+	}
+
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	executeAfter := sdk.UnwrapSDKContext(ctx).BlockTime().Add(params.EmergencyActionDelay)
+	action.ExecuteAfter = &executeAfter
+	return nil
+}
+
+// checkEmergencyAction checks that an emergency action still applies to the capsule
+func checkEmergencyAction(capsule *types.TimeCapsule, action *types.EmergencyAction) error {
+	if capsule.Status != types.CapsuleStatus_ACTIVE {
+		return types.ErrInvalidCapsule.Wrapf("capsule status is %s", capsule.Status.String())
+	}
+
+	if capsule.Frozen && action.ActionType != types.EmergencyActionFreeze {
+		return types.ErrCapsuleFrozen.Wrapf("capsule %d must be unfrozen first", capsule.ID)
+	}
+
+	switch action.ActionType {
+	case types.EmergencyActionContractDeletion:
+		if capsule.CapsuleType != types.CapsuleType_CONDITIONAL || capsule.ConditionContract == "" {
+			return types.ErrInvalidEmergencyAction.Wrap("no condition contract to delete")
+		}
+		if err := checkForceUnlock(capsule); err != nil {
+			return err
+		}
+
+	case types.EmergencyActionForceUnlock:
+		if capsule.CapsuleType == types.CapsuleType_SAFE {
+			return types.ErrInvalidEmergencyAction.Wrap("safe capsules are always unlockable by their owner")
+		}
+		if err := checkForceUnlock(capsule); err != nil {
+			return err
+		}
+
+	case types.EmergencyActionFreeze:
+		if capsule.Frozen {
+			return types.ErrCapsuleFrozen.Wrapf("capsule %d is already frozen", capsule.ID)
+		}
+
+	case types.EmergencyActionRotateRecipient:
+		if action.NewRecipient == capsule.Recipient && bytes.Equal(action.NewRecipientKey, capsule.RecipientKey) {
+			return types.ErrInvalidRecipient.Wrap("new recipient is already the capsule recipient")
+		}
+		if err := checkRecipientKey(capsule, action.NewRecipientKey); err != nil {
+			return err
+		}
+		if capsule.PassphraseFactor != nil && len(action.NewRecipientKey) == 0 {
+			return types.ErrInvalidRecipient.Wrapf("capsule %d is protected by a passphrase and must keep a recipient key", capsule.ID)
+		}
+
+	default:
+		return types.ErrInvalidEmergencyAction.Wrapf("unknown emergency action type %q", action.ActionType)
+	}
+
+	return nil
+}
+
+// checkForceUnlock checks that waiving the unlock conditions lets someone open the capsule
+func checkForceUnlock(capsule *types.TimeCapsule) error {
+	if capsule.ForceUnlocked {
+		return types.ErrInvalidEmergencyAction.Wrapf("capsule %d is already force unlocked", capsule.ID)
+	}
+	if capsule.Recipient == "" {
+		return types.ErrInvalidEmergencyAction.Wrap("capsule has no recipient to open it")
+	}
+	// Nobody holds the data key of a timelocked capsule before its epoch is released
+	if capsule.IsTimelocked() {
+		return types.ErrInvalidEmergencyAction.Wrapf("capsule key is sealed to timelock epoch %d", capsule.TimelockEpoch)
+	}
+	return nil
+}
+
+// canChallengeEmergencyAction checks whether an address can veto or reverse an action.
+// The recipient replaced by a rotation keeps that right.
+func canChallengeEmergencyAction(capsule *types.TimeCapsule, action *types.EmergencyAction, sender string) bool {
+	if capsule.IsGuardian(sender) || capsule.Recipient == sender {
+		return true
+	}
+	return action.ActionType == types.EmergencyActionRotateRecipient && action.PreviousRecipient == sender
+}
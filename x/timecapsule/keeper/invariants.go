@@ -14,6 +14,8 @@ func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
 	ir.RegisterRoute(types.ModuleName, "capsule-key-shares", CapsuleKeySharesInvariant(k))
 	ir.RegisterRoute(types.ModuleName, "capsule-user-index", CapsuleUserIndexInvariant(k))
 	ir.RegisterRoute(types.ModuleName, "capsule-status-consistency", CapsuleStatusConsistencyInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "escrow-balance", EscrowBalanceInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "capsule-stats", CapsuleStatsInvariant(k))
 }
 
 // CapsuleKeySharesInvariant checks that each capsule has the correct number of key shares
@@ -39,11 +41,16 @@ func CapsuleKeySharesInvariant(k Keeper) sdk.Invariant {
 				return false, walkErr
 			}
 
-			// Check if share count matches expected
-			if shareCount != capsule.TotalShares {
+			// Check if share count matches expected, the key of timelocked capsules is not shared
+			// and the shares of purged capsules are deleted
+			expected := capsule.TotalShares
+			if capsule.IsTimelocked() || capsule.IsPurged() {
+				expected = 0
+			}
+			if shareCount != expected {
 				broken = true
 				msg += fmt.Sprintf("capsule %d: expected %d key shares, found %d\n", 
-					capsuleID, capsule.TotalShares, shareCount)
+					capsuleID, expected, shareCount)
 			}
 
 			return false, nil // Continue iteration
@@ -176,19 +183,25 @@ func CapsuleStatusConsistencyInvariant(k Keeper) sdk.Invariant {
 			// Check that required fields are set for each capsule type
 			switch capsule.CapsuleType {
 			case types.CapsuleType_TIME_LOCK:
-				if capsule.UnlockTime == nil {
+				if !capsule.HasUnlockTrigger() {
 					broken = true
-					msg += fmt.Sprintf("time-locked capsule %d missing unlock time\n", capsuleID)
+					msg += fmt.Sprintf("time-locked capsule %d missing unlock trigger\n", capsuleID)
 				}
 			case types.CapsuleType_CONDITIONAL:
-				if capsule.ConditionContract == "" {
+				// The guardians may delete the contract and waive the condition
+				if capsule.ConditionContract == "" && capsule.Attestation == nil && !capsule.ForceUnlocked {
 					broken = true
-					msg += fmt.Sprintf("conditional capsule %d missing condition contract\n", capsuleID)
+					msg += fmt.Sprintf("conditional capsule %d missing condition contract or attestation condition\n", capsuleID)
 				}
 			case types.CapsuleType_MULTI_SIG:
-				if capsule.RequiredSigs == 0 {
+				if capsule.RequiredSigs == 0 && capsule.GroupPolicy == "" {
+					broken = true
+					msg += fmt.Sprintf("multi-sig capsule %d missing required signatures or group policy\n", capsuleID)
+				}
+				if capsule.GroupPolicy != "" && capsule.GroupPolicy != capsule.Owner {
 					broken = true
-					msg += fmt.Sprintf("multi-sig capsule %d missing required signatures\n", capsuleID)
+					msg += fmt.Sprintf("multi-sig capsule %d owned by %s instead of its group policy %s\n",
+						capsuleID, capsule.Owner, capsule.GroupPolicy)
 				}
 			case types.CapsuleType_DEAD_MANS_SWITCH:
 				if capsule.InactivityPeriod == 0 {
@@ -211,4 +224,78 @@ func CapsuleStatusConsistencyInvariant(k Keeper) sdk.Invariant {
 
 		return sdk.FormatInvariant(types.ModuleName, "capsule-status-consistency", msg), broken
 	}
-}
\ No newline at end of file
+}
+
+// EscrowBalanceInvariant checks that the module account holds at least the sum of all escrows
+func EscrowBalanceInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var (
+			broken bool
+			msg    string
+		)
+
+		totalEscrow, err := k.GetTotalEscrow(ctx)
+		if err != nil {
+			broken = true
+			msg += fmt.Sprintf("error summing capsule escrows: %s\n", err.Error())
+			return sdk.FormatInvariant(types.ModuleName, "escrow-balance", msg), broken
+		}
+
+		// Escrow must only be held by capsules that have not been settled yet
+		err = k.capsules.Walk(ctx, nil, func(capsuleID uint64, capsule types.TimeCapsule) (bool, error) {
+			if capsule.Status != types.CapsuleStatus_ACTIVE && capsule.HasEscrow() {
+				broken = true
+				msg += fmt.Sprintf("capsule %d has status %s but still holds escrow %s\n",
+					capsuleID, capsule.Status.String(), capsule.Escrow)
+			}
+			return false, nil // Continue iteration
+		})
+
+		if err != nil {
+			broken = true
+			msg += fmt.Sprintf("error walking capsules: %s\n", err.Error())
+		}
+
+		moduleAddr := k.accountKeeper.GetModuleAddress(types.ModuleName)
+		balance := k.bankKeeper.GetAllBalances(ctx, moduleAddr)
+
+		if !balance.IsAllGTE(totalEscrow) {
+			broken = true
+			msg += fmt.Sprintf("module balance %s is less than total escrow %s\n", balance, totalEscrow)
+		}
+
+		return sdk.FormatInvariant(types.ModuleName, "escrow-balance", msg), broken
+	}
+}
+
+// CapsuleStatsInvariant checks that the incrementally maintained capsule statistics
+// match the statistics re-derived from the stored capsules
+func CapsuleStatsInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var (
+			broken bool
+			msg    string
+		)
+
+		stats, err := k.GetCapsuleAggregates(ctx)
+		if err != nil {
+			broken = true
+			msg += fmt.Sprintf("error reading capsule stats: %s\n", err.Error())
+			return sdk.FormatInvariant(types.ModuleName, "capsule-stats", msg), broken
+		}
+
+		expected, err := k.computeCapsuleAggregates(ctx)
+		if err != nil {
+			broken = true
+			msg += fmt.Sprintf("error walking capsules: %s\n", err.Error())
+			return sdk.FormatInvariant(types.ModuleName, "capsule-stats", msg), broken
+		}
+
+		if diff := stats.Diff(expected); diff != "" {
+			broken = true
+			msg += diff
+		}
+
+		return sdk.FormatInvariant(types.ModuleName, "capsule-stats", msg), broken
+	}
+}
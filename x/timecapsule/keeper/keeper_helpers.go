@@ -2,6 +2,7 @@ package keeper
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"cosmossdk.io/collections"
@@ -37,8 +38,23 @@ func (k Keeper) SetCapsuleCounter(ctx context.Context, counter uint64) error {
 	return k.capsuleCounter.Set(ctx, counter)
 }
 
-// SetCapsule stores a capsule
+// SetCapsule stores a capsule and updates the capsule statistics and the tag index
 func (k Keeper) SetCapsule(ctx context.Context, capsule *types.TimeCapsule) error {
+	var previous *types.TimeCapsule
+	stored, err := k.capsules.Get(ctx, capsule.ID)
+	switch {
+	case err == nil:
+		previous = &stored
+	case !errors.Is(err, collections.ErrNotFound):
+		return err
+	}
+
+	if err := k.trackCapsuleWrite(ctx, previous, *capsule); err != nil {
+		return err
+	}
+	if err := k.indexCapsuleTags(ctx, previous, *capsule); err != nil {
+		return err
+	}
 	return k.capsules.Set(ctx, capsule.ID, *capsule)
 }
 
@@ -47,10 +63,27 @@ func (k Keeper) IndexUserCapsule(ctx context.Context, owner string, capsuleID ui
 	return k.userCapsules.Set(ctx, collections.Join(owner, capsuleID))
 }
 
-// SetKeyShare stores a key share
+// SetKeyShare stores a key share and indexes it by custodian
 func (k Keeper) SetKeyShare(ctx context.Context, keyShare *types.KeyShare) error {
 	key := collections.Join(keyShare.CapsuleID, keyShare.ShareIndex)
-	return k.keyShares.Set(ctx, key, *keyShare)
+	if err := k.keyShares.Set(ctx, key, *keyShare); err != nil {
+		return err
+	}
+	return k.custodianShares.Set(ctx, collections.Join3(keyShare.NodeID, keyShare.CapsuleID, keyShare.ShareIndex))
+}
+
+// SetCustodianDeparture schedules the re-distribution of a custodian's key shares
+func (k Keeper) SetCustodianDeparture(ctx context.Context, custodian string) error {
+	return k.custodianDepartures.Set(ctx, custodian)
+}
+
+// GetAllCustodianDepartures retrieves the custodians whose key shares await re-distribution
+func (k Keeper) GetAllCustodianDepartures(ctx context.Context) ([]string, error) {
+	iter, err := k.custodianDepartures.Iterate(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return iter.Keys()
 }
 
 // SetConditionContract stores a condition contract
@@ -58,6 +91,56 @@ func (k Keeper) SetConditionContract(ctx context.Context, contract *types.Condit
 	return k.conditionContracts.Set(ctx, contract.Address, *contract)
 }
 
+// SetTimelockKey stores a validator timelock key
+func (k Keeper) SetTimelockKey(ctx context.Context, key *types.TimelockValidatorKey) error {
+	return k.timelockKeys.Set(ctx, key.ConsAddress, *key)
+}
+
+// SetTimelockEpoch stores a timelock epoch
+func (k Keeper) SetTimelockEpoch(ctx context.Context, epoch *types.TimelockEpoch) error {
+	return k.timelockEpochs.Set(ctx, epoch.Epoch, *epoch)
+}
+
+// SetTimelockDealing stores a timelock key generation dealing
+func (k Keeper) SetTimelockDealing(ctx context.Context, dealing *types.TimelockDealing) error {
+	return k.timelockDealings.Set(ctx, collections.Join(dealing.Epoch, dealing.Dealer), *dealing)
+}
+
+// SetEmergencyAction stores an emergency action and indexes it by capsule
+func (k Keeper) SetEmergencyAction(ctx context.Context, action *types.EmergencyAction) error {
+	if err := k.emergencyActions.Set(ctx, action.ID, *action); err != nil {
+		return fmt.Errorf("failed to store emergency action: %w", err)
+	}
+	return k.capsuleEmergencyActions.Set(ctx, collections.Join(action.CapsuleID, action.ID))
+}
+
+// SetAccessLogEntry stores a capsule access log entry
+func (k Keeper) SetAccessLogEntry(ctx context.Context, access types.CapsuleAccess) error {
+	key := collections.Join3(access.CapsuleID, uint64(access.BlockHeight), access.Sequence)
+	if err := k.accessLog.Set(ctx, key, access); err != nil {
+		return fmt.Errorf("failed to store access log entry: %w", err)
+	}
+	return nil
+}
+
+// SetCapsuleVersion stores a superseded capsule version
+func (k Keeper) SetCapsuleVersion(ctx context.Context, version types.CapsuleVersion) error {
+	if err := k.capsuleVersions.Set(ctx, collections.Join(version.CapsuleID, version.Version), version); err != nil {
+		return fmt.Errorf("failed to store capsule version: %w", err)
+	}
+	return nil
+}
+
+// GetAccessLogSequence retrieves the next access log sequence
+func (k Keeper) GetAccessLogSequence(ctx context.Context) (uint64, error) {
+	return k.accessLogSeq.Peek(ctx)
+}
+
+// SetAccessLogSequence sets the next access log sequence
+func (k Keeper) SetAccessLogSequence(ctx context.Context, seq uint64) error {
+	return k.accessLogSeq.Set(ctx, seq)
+}
+
 // GetAllCapsules retrieves all capsules
 func (k Keeper) GetAllCapsules(ctx context.Context) ([]types.TimeCapsule, error) {
 	var capsules []types.TimeCapsule
@@ -94,6 +177,90 @@ func (k Keeper) GetAllConditionContracts(ctx context.Context) ([]types.Condition
 	return contracts, err
 }
 
+// GetAllTimelockKeys retrieves all validator timelock keys
+func (k Keeper) GetAllTimelockKeys(ctx context.Context) ([]types.TimelockValidatorKey, error) {
+	var keys []types.TimelockValidatorKey
+	
+	err := k.timelockKeys.Walk(ctx, nil, func(consAddr string, key types.TimelockValidatorKey) (bool, error) {
+		keys = append(keys, key)
+		return false, nil // Continue iteration
+	})
+	
+	return keys, err
+}
+
+// GetAllTimelockEpochs retrieves all timelock epochs
+func (k Keeper) GetAllTimelockEpochs(ctx context.Context) ([]types.TimelockEpoch, error) {
+	var epochs []types.TimelockEpoch
+	
+	err := k.timelockEpochs.Walk(ctx, nil, func(key uint64, epoch types.TimelockEpoch) (bool, error) {
+		epochs = append(epochs, epoch)
+		return false, nil // Continue iteration
+	})
+	
+	return epochs, err
+}
+
+// GetAllTimelockDealings retrieves all timelock key generation dealings
+func (k Keeper) GetAllTimelockDealings(ctx context.Context) ([]types.TimelockDealing, error) {
+	var dealings []types.TimelockDealing
+	
+	err := k.timelockDealings.Walk(ctx, nil, func(key collections.Pair[uint64, uint32], dealing types.TimelockDealing) (bool, error) {
+		dealings = append(dealings, dealing)
+		return false, nil // Continue iteration
+	})
+	
+	return dealings, err
+}
+
+// GetAllEmergencyActions retrieves all emergency actions
+func (k Keeper) GetAllEmergencyActions(ctx context.Context) ([]types.EmergencyAction, error) {
+	var actions []types.EmergencyAction
+	
+	err := k.emergencyActions.Walk(ctx, nil, func(id string, action types.EmergencyAction) (bool, error) {
+		actions = append(actions, action)
+		return false, nil // Continue iteration
+	})
+	
+	return actions, err
+}
+
+// GetAllAccessLog retrieves the access log of all capsules
+func (k Keeper) GetAllAccessLog(ctx context.Context) ([]types.CapsuleAccess, error) {
+	var accesses []types.CapsuleAccess
+	
+	err := k.accessLog.Walk(ctx, nil, func(key collections.Triple[uint64, uint64, uint64], access types.CapsuleAccess) (bool, error) {
+		accesses = append(accesses, access)
+		return false, nil // Continue iteration
+	})
+	
+	return accesses, err
+}
+
+// GetAllCapsuleVersions retrieves the superseded versions of all capsules
+func (k Keeper) GetAllCapsuleVersions(ctx context.Context) ([]types.CapsuleVersion, error) {
+	var versions []types.CapsuleVersion
+	
+	err := k.capsuleVersions.Walk(ctx, nil, func(key collections.Pair[uint64, uint32], version types.CapsuleVersion) (bool, error) {
+		versions = append(versions, version)
+		return false, nil // Continue iteration
+	})
+	
+	return versions, err
+}
+
+// GetAllPendingTransfers retrieves all pending capsule transfers
+func (k Keeper) GetAllPendingTransfers(ctx context.Context) ([]types.PendingTransfer, error) {
+	var transfers []types.PendingTransfer
+	
+	err := k.pendingTransfers.Walk(ctx, nil, func(id string, transfer types.PendingTransfer) (bool, error) {
+		transfers = append(transfers, transfer)
+		return false, nil // Continue iteration
+	})
+	
+	return transfers, err
+}
+
 // Logger returns a module-specific logger
 func (k Keeper) Logger(ctx context.Context) log.Logger {
 	sdkCtx := sdk.UnwrapSDKContext(ctx)
@@ -102,61 +269,49 @@ func (k Keeper) Logger(ctx context.Context) log.Logger {
 
 // BeginBlocker processes module logic at the beginning of each block
 func (k Keeper) BeginBlocker(ctx context.Context) error {
-	// Check for expired capsules and update their status
-	return k.processExpiredCapsules(ctx)
+	// Start the block with an empty failed access buffer
+	k.resetFailedAccesses()
+
+	// Announce capsules reaching their unlock height or epoch
+	if err := k.processUnlockQueue(ctx); err != nil {
+		return err
+	}
+
+	// Expire capsules reaching their expiry, then purge the data of finished capsules
+	if err := k.processExpiryQueue(ctx); err != nil {
+		return err
+	}
+	return k.processPurgeQueue(ctx)
 }
 
 // EndBlocker processes module logic at the end of each block  
 func (k Keeper) EndBlocker(ctx context.Context) error {
-	// Process any end-block logic here
-	return nil
-}
+	// Persist the failed capsule accesses of the block
+	if err := k.flushFailedAccesses(ctx); err != nil {
+		return err
+	}
 
-// processExpiredCapsules checks for and processes expired capsules
-func (k Keeper) processExpiredCapsules(ctx context.Context) error {
-	sdkCtx := sdk.UnwrapSDKContext(ctx)
-	currentTime := sdkCtx.BlockTime()
-	
-	return k.capsules.Walk(ctx, nil, func(key uint64, capsule types.TimeCapsule) (bool, error) {
-		// Check if capsule should be expired
-		if capsule.Status == types.CapsuleStatus_ACTIVE {
-			shouldExpire := false
-			
-			// Check expiration conditions based on capsule type
-			switch capsule.CapsuleType {
-			case types.CapsuleType_TIME_LOCK:
-				if capsule.UnlockTime != nil && currentTime.After(*capsule.UnlockTime) {
-					// For time-locked capsules, they don't expire but become unlockable
-					// This is handled in the IsUnlockable method
-				}
-			case types.CapsuleType_DEAD_MANS_SWITCH:
-				if capsule.ExpiresAt != nil && currentTime.After(*capsule.ExpiresAt) {
-					shouldExpire = true
-				}
-			}
-			
-			// Update status if expired
-			if shouldExpire {
-				capsule.Status = types.CapsuleStatus_EXPIRED
-				capsule.UpdatedAt = currentTime
-				
-				if err := k.capsules.Set(ctx, key, capsule); err != nil {
-					return false, fmt.Errorf("failed to update expired capsule %d: %w", key, err)
-				}
-				
-				// Emit expiration event
-				sdkCtx.EventManager().EmitEvent(
-					sdk.NewEvent(
-						"capsule_expired",
-						sdk.NewAttribute("capsule_id", fmt.Sprintf("%d", key)),
-						sdk.NewAttribute("owner", capsule.Owner),
-					),
-				)
-			}
-		}
-		
-		return false, nil // Continue iteration
-	})
+	// Move the key shares of departed custodians and refresh shares periodically
+	refreshed, err := k.processCustodianDepartures(ctx, maxShareRefreshesPerBlock)
+	if err != nil {
+		return err
+	}
+	if err := k.advanceShareRefresh(ctx, maxShareRefreshesPerBlock-refreshed); err != nil {
+		return err
+	}
+
+	// Adjust the creation fee to the creations of the block
+	if err := k.updateCreationFee(ctx); err != nil {
+		return err
+	}
+
+	// Export the capsule statistics as gauges
+	if err := k.exportCapsuleMetrics(ctx); err != nil {
+		return err
+	}
+
+	// Schedule and key timelock epochs
+	return k.advanceTimelockEpochs(ctx)
 }
 
 // shareToBytes is a helper method to make the method public
@@ -0,0 +1,148 @@
+package keeper_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/stretchr/testify/suite"
+
+	"cosmossdk.io/core/header"
+	corestore "cosmossdk.io/core/store"
+	storetypes "cosmossdk.io/store/types"
+
+	addresscodec "github.com/cosmos/cosmos-sdk/codec/address"
+	"github.com/cosmos/cosmos-sdk/runtime"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	moduletestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/keeper"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+type KeeperTestSuite struct {
+	suite.Suite
+
+	ctx           sdk.Context
+	encCfg        moduletestutil.TestEncodingConfig
+	storeService  corestore.KVStoreService
+	keeper        keeper.Keeper
+	msgServer     types.MsgServer
+	bankKeeper    *mockBankKeeper
+	accountKeeper *mockAccountKeeper
+	stakingKeeper *mockStakingKeeper
+	authority     string
+}
+
+func TestKeeperTestSuite(t *testing.T) {
+	suite.Run(t, new(KeeperTestSuite))
+}
+
+func (s *KeeperTestSuite) SetupTest() {
+	key := storetypes.NewKVStoreKey(types.StoreKey)
+	testCtx := testutil.DefaultContextWithDB(s.T(), key, storetypes.NewTransientStoreKey("transient_test"))
+	blockTime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.ctx = testCtx.Ctx.
+		WithBlockHeader(cmtproto.Header{Height: 1, Time: blockTime}).
+		WithHeaderInfo(header.Info{Height: 1, Time: blockTime})
+
+	s.encCfg = moduletestutil.MakeTestEncodingConfig()
+	s.storeService = runtime.NewKVStoreService(key)
+	s.bankKeeper = &mockBankKeeper{balances: make(map[string]sdk.Coins)}
+	s.accountKeeper = &mockAccountKeeper{}
+	s.stakingKeeper = &mockStakingKeeper{validators: make(map[string]stakingtypes.Validator)}
+	s.authority = authtypes.NewModuleAddress(govtypes.ModuleName).String()
+
+	s.keeper = keeper.NewKeeper(
+		s.encCfg.Codec,
+		addresscodec.NewBech32Codec("cosmos"),
+		s.storeService,
+		s.ctx.Logger(),
+		s.bankKeeper,
+		s.accountKeeper,
+		s.stakingKeeper,
+		nil,
+		s.authority,
+	)
+	s.msgServer = keeper.NewMsgServerImpl(s.keeper)
+
+	s.Require().NoError(s.keeper.SetParams(s.ctx, types.DefaultParams()))
+}
+
+// mockStakingKeeper serves a fixed validator set
+type mockStakingKeeper struct {
+	validators map[string]stakingtypes.Validator
+	bonded     []stakingtypes.Validator
+}
+
+func (m *mockStakingKeeper) GetValidator(_ context.Context, addr sdk.ValAddress) (stakingtypes.Validator, error) {
+	val, ok := m.validators[addr.String()]
+	if !ok {
+		return stakingtypes.Validator{}, stakingtypes.ErrNoValidatorFound
+	}
+	return val, nil
+}
+
+func (m *mockStakingKeeper) GetValidatorByConsAddr(_ context.Context, _ sdk.ConsAddress) (stakingtypes.Validator, error) {
+	return stakingtypes.Validator{}, stakingtypes.ErrNoValidatorFound
+}
+
+func (m *mockStakingKeeper) GetBondedValidatorsByPower(_ context.Context) ([]stakingtypes.Validator, error) {
+	return m.bonded, nil
+}
+
+// bondValidator adds a bonded validator to the validator set
+func (m *mockStakingKeeper) bondValidator(operator string) {
+	val := stakingtypes.Validator{OperatorAddress: operator, Status: stakingtypes.Bonded}
+	m.validators[operator] = val
+	m.bonded = append(m.bonded, val)
+}
+
+// mockBankKeeper keeps balances in memory. Methods the tests do not use are left to
+// the embedded nil interface.
+type mockBankKeeper struct {
+	types.BankKeeper
+	balances map[string]sdk.Coins
+}
+
+func (m *mockBankKeeper) GetAllBalances(_ context.Context, addr sdk.AccAddress) sdk.Coins {
+	return m.balances[addr.String()]
+}
+
+func (m *mockBankKeeper) SpendableCoins(_ context.Context, addr sdk.AccAddress) sdk.Coins {
+	return m.balances[addr.String()]
+}
+
+func (m *mockBankKeeper) SendCoins(_ context.Context, from, to sdk.AccAddress, amt sdk.Coins) error {
+	balance, hasNeg := m.balances[from.String()].SafeSub(amt...)
+	if hasNeg {
+		return sdkerrors.ErrInsufficientFunds.Wrapf("%s is smaller than %s", m.balances[from.String()], amt)
+	}
+	m.balances[from.String()] = balance
+	m.balances[to.String()] = m.balances[to.String()].Add(amt...)
+	return nil
+}
+
+func (m *mockBankKeeper) SendCoinsFromAccountToModule(ctx context.Context, from sdk.AccAddress, module string, amt sdk.Coins) error {
+	return m.SendCoins(ctx, from, authtypes.NewModuleAddress(module), amt)
+}
+
+func (m *mockBankKeeper) SendCoinsFromModuleToAccount(ctx context.Context, module string, to sdk.AccAddress, amt sdk.Coins) error {
+	return m.SendCoins(ctx, authtypes.NewModuleAddress(module), to, amt)
+}
+
+// mockAccountKeeper derives module addresses. Methods the tests do not use are left
+// to the embedded nil interface.
+type mockAccountKeeper struct {
+	types.AccountKeeper
+}
+
+func (m *mockAccountKeeper) GetModuleAddress(name string) sdk.AccAddress {
+	return authtypes.NewModuleAddress(name)
+}
@@ -0,0 +1,54 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	v2 "github.com/cosmos/cosmos-sdk/x/timecapsule/migrations/v2"
+	v3 "github.com/cosmos/cosmos-sdk/x/timecapsule/migrations/v3"
+	v4 "github.com/cosmos/cosmos-sdk/x/timecapsule/migrations/v4"
+	v5 "github.com/cosmos/cosmos-sdk/x/timecapsule/migrations/v5"
+	v6 "github.com/cosmos/cosmos-sdk/x/timecapsule/migrations/v6"
+)
+
+// Migrator is a struct for handling in-place store migrations.
+type Migrator struct {
+	keeper Keeper
+}
+
+// NewMigrator returns a new Migrator.
+func NewMigrator(keeper Keeper) Migrator {
+	return Migrator{keeper: keeper}
+}
+
+// Migrate1to2 migrates from version 1 to 2.
+func (m Migrator) Migrate1to2(ctx sdk.Context) error {
+	return v2.MigrateStore(ctx, m.keeper.keyShares, m.keeper.custodianShares, m.keeper.emergencyActions, m.keeper.capsuleEmergencyActions)
+}
+
+// Migrate2to3 migrates from version 2 to 3.
+func (m Migrator) Migrate2to3(ctx sdk.Context) error {
+	return v3.MigrateStore(ctx, m.keeper.capsules, m.keeper.capsuleStats)
+}
+
+// Migrate3to4 migrates from version 3 to 4.
+func (m Migrator) Migrate3to4(ctx sdk.Context) error {
+	params, err := m.keeper.GetParams(ctx)
+	if err != nil {
+		return err
+	}
+	return v4.MigrateStore(ctx, m.keeper.capsules, m.keeper.expiryQueue, m.keeper.purgeQueue, params.UnlockedDataRetention)
+}
+
+// Migrate4to5 migrates from version 4 to 5.
+func (m Migrator) Migrate4to5(ctx sdk.Context) error {
+	return v5.MigrateStore(ctx, m.keeper.capsules, m.keeper.unlockTimeQueue)
+}
+
+// Migrate5to6 migrates from version 5 to 6.
+func (m Migrator) Migrate5to6(ctx sdk.Context) error {
+	params, err := m.keeper.GetParams(ctx)
+	if err != nil {
+		return err
+	}
+	return v6.MigrateStore(ctx, m.keeper.capsules, m.keeper.capsuleTags, m.keeper.capsuleMetadata, params.IndexedMetadataKeys)
+}
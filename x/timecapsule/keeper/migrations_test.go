@@ -0,0 +1,106 @@
+package keeper_test
+
+import (
+	"time"
+
+	"cosmossdk.io/collections"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/codec"
+	addresscodec "github.com/cosmos/cosmos-sdk/codec/address"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/keeper"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// TestRunMigrationsFromV1 upgrades a store laid out by the first version of the module
+// through every registered migration, and checks the module invariants on the result
+func (s *KeeperTestSuite) TestRunMigrationsFromV1() {
+	cdc := s.encCfg.Codec
+	owner := sdk.AccAddress("owner_______________").String()
+	recipient := sdk.AccAddress("recipient___________").String()
+	custodian := sdk.ValAddress("custodian___________")
+
+	// The v1 collections, without any of the indexes and queues added since
+	sb := collections.NewSchemaBuilder(s.storeService)
+	capsules := collections.NewMap(sb, types.CapsuleKeyPrefix, "capsules", collections.Uint64Key, codec.CollValue[types.TimeCapsule](cdc))
+	userCapsules := collections.NewKeySet(sb, types.UserCapsulesKeyPrefix, "user_capsules", collections.PairKeyCodec(collections.StringKey, collections.Uint64Key))
+	keyShares := collections.NewMap(sb, types.KeySharesKeyPrefix, "key_shares", collections.PairKeyCodec(collections.Uint64Key, collections.Uint32Key), codec.CollValue[types.KeyShare](cdc))
+	emergencyActions := collections.NewMap(sb, types.EmergencyActionsKeyPrefix, "emergency_actions", collections.StringKey, codec.CollValue[types.EmergencyAction](cdc))
+	_, err := sb.Build()
+	s.Require().NoError(err)
+
+	now := s.ctx.BlockTime()
+	unlocks := now.Add(48 * time.Hour)
+	expires := now.Add(72 * time.Hour)
+	opened := now.Add(-time.Hour)
+	stored := []types.TimeCapsule{
+		{
+			ID: 1, Owner: owner, Recipient: recipient, CapsuleType: types.CapsuleType_TIME_LOCK, Status: types.CapsuleStatus_ACTIVE,
+			UnlockTime: &unlocks, Threshold: 2, TotalShares: 3, StorageType: "blockchain", DataSize: 64,
+			Tags: []string{"family"}, CreatedAt: now, UpdatedAt: now,
+		},
+		{
+			ID: 2, Owner: owner, CapsuleType: types.CapsuleType_SAFE, Status: types.CapsuleStatus_ACTIVE,
+			ExpiresAt: &expires, Threshold: 1, TotalShares: 1, StorageType: "blockchain", DataSize: 32,
+			CreatedAt: now, UpdatedAt: now,
+		},
+		{
+			ID: 3, Owner: owner, Recipient: recipient, CapsuleType: types.CapsuleType_TIME_LOCK, Status: types.CapsuleStatus_UNLOCKED,
+			UnlockTime: &opened, OpenedAt: &opened, Threshold: 1, TotalShares: 1, StorageType: "blockchain", DataSize: 16,
+			CreatedAt: now.Add(-2 * time.Hour), UpdatedAt: opened,
+		},
+	}
+	for _, capsule := range stored {
+		s.Require().NoError(capsules.Set(s.ctx, capsule.ID, capsule))
+		s.Require().NoError(userCapsules.Set(s.ctx, collections.Join(capsule.Owner, capsule.ID)))
+		for index := uint32(0); index < capsule.TotalShares; index++ {
+			s.Require().NoError(keyShares.Set(s.ctx, collections.Join(capsule.ID, index), types.KeyShare{
+				CapsuleID:  capsule.ID,
+				ShareIndex: index,
+				NodeID:     custodian.String(),
+			}))
+		}
+	}
+	s.Require().NoError(emergencyActions.Set(s.ctx, "1-1", types.EmergencyAction{ID: "1-1", CapsuleID: 1, ActionType: "freeze"}))
+
+	// Migrate with the handlers the module registers
+	cfg := module.NewConfigurator(cdc, baseapp.NewMsgServiceRouter(), baseapp.NewGRPCQueryRouter())
+	mm := module.NewManager(timecapsule.NewAppModule(cdc, s.keeper, s.accountKeeper, s.bankKeeper, s.stakingKeeper, addresscodec.NewBech32Codec("cosmos")))
+	s.Require().NoError(mm.RegisterServices(cfg))
+
+	toVM, err := mm.RunMigrations(s.ctx, cfg, module.VersionMap{types.ModuleName: 1})
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(timecapsule.ConsensusVersion), toVM[types.ModuleName])
+
+	for name, invariant := range map[string]sdk.Invariant{
+		"capsule-key-shares":         keeper.CapsuleKeySharesInvariant(s.keeper),
+		"capsule-user-index":         keeper.CapsuleUserIndexInvariant(s.keeper),
+		"capsule-status-consistency": keeper.CapsuleStatusConsistencyInvariant(s.keeper),
+		"escrow-balance":             keeper.EscrowBalanceInvariant(s.keeper),
+		"capsule-stats":              keeper.CapsuleStatsInvariant(s.keeper),
+	} {
+		msg, broken := invariant(s.ctx)
+		s.Require().False(broken, "%s: %s", name, msg)
+	}
+
+	// v2 indexed the key shares by custodian, so its departure is queued
+	s.Require().NoError(s.keeper.Hooks().AfterValidatorRemoved(s.ctx, nil, custodian))
+	departures, err := s.keeper.GetAllCustodianDepartures(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal([]string{custodian.String()}, departures)
+
+	// v3 derived the statistics from the stored capsules
+	stats, err := s.keeper.GetCapsuleAggregates(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(len(stored)), stats.Total)
+
+	// v6 indexed the tags by owner
+	tagged, _, err := s.keeper.GetCapsulesByTag(s.ctx, &types.QueryCapsulesByTagRequest{Owner: owner, Tag: "family"})
+	s.Require().NoError(err)
+	s.Require().Len(tagged, 1)
+	s.Require().Equal(uint64(1), tagged[0].ID)
+}
@@ -33,6 +33,15 @@ func (ms MsgServer) CreateCapsule(goCtx context.Context, msg *types.MsgCreateCap
 		return nil, err
 	}
 
+	// A sponsor pays the fees instead, under the allowance it granted the creator
+	payer := creator
+	if msg.Sponsor != "" {
+		payer, err = sdk.AccAddressFromBech32(msg.Sponsor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Get module parameters
 	params, err := ms.keeper.GetParams(ctx)
 	if err != nil {
@@ -56,18 +65,24 @@ func (ms MsgServer) CreateCapsule(goCtx context.Context, msg *types.MsgCreateCap
 		return nil, types.ErrDataTooLarge.Wrapf("data size %d exceeds maximum %d", len(msg.Data), params.MaxDataSize)
 	}
 
-	// Validate threshold and shares
-	if msg.Threshold < params.MinThreshold {
-		return nil, types.ErrInvalidThreshold.Wrapf("threshold %d below minimum %d", msg.Threshold, params.MinThreshold)
-	}
-	if msg.TotalShares > params.MaxShares {
-		return nil, types.ErrInvalidThreshold.Wrapf("total shares %d exceeds maximum %d", msg.TotalShares, params.MaxShares)
+	// Validate threshold and shares, public reveal capsules have no key shares
+	if msg.CapsuleType != types.CapsuleType_PUBLIC_REVEAL {
+		if msg.Threshold < params.MinThreshold {
+			return nil, types.ErrInvalidThreshold.Wrapf("threshold %d below minimum %d", msg.Threshold, params.MinThreshold)
+		}
+		if msg.TotalShares > params.MaxShares {
+			return nil, types.ErrInvalidThreshold.Wrapf("total shares %d exceeds maximum %d", msg.TotalShares, params.MaxShares)
+		}
 	}
 
-	// Charge creation fee
-	if !params.CreationFee.IsZero() {
+	// Charge the creation fee, which rises with recent module-wide creations
+	creationFee, err := ms.keeper.CreationFee(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !creationFee.IsZero() {
 		if err := ms.keeper.bankKeeper.SendCoinsFromAccountToModule(
-			ctx, creator, types.ModuleName, params.CreationFee,
+			ctx, payer, types.ModuleName, creationFee,
 		); err != nil {
 			return nil, err
 		}
@@ -85,23 +100,103 @@ func (ms MsgServer) CreateCapsule(goCtx context.Context, msg *types.MsgCreateCap
 		metadata[k] = v
 	}
 
+	// Validate tags, and the indexed metadata, against the bounds of the tag index
+	if err := params.ValidateTagBounds(msg.Tags, metadata); err != nil {
+		return nil, types.ErrInvalidTags.Wrap(err.Error())
+	}
+
+	// Public reveal capsules only commit to their content
+	if msg.CapsuleType == types.CapsuleType_PUBLIC_REVEAL {
+		capsule, err := ms.keeper.CommitPublicCapsule(ctx, msg.Creator, msg.Commitment, msg.UnlockTime, msg.Tags, metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		if msg.Sponsor != "" {
+			if err := ms.keeper.SponsorCapsule(ctx, capsule, msg.Sponsor, msg.Creator, creationFee); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := ms.keeper.SetCapsuleGuardians(ctx, capsule, msg.Guardians, msg.GuardianThreshold); err != nil {
+			return nil, err
+		}
+
+		return &types.MsgCreateCapsuleResponse{
+			CapsuleId: capsule.ID,
+		}, nil
+	}
+
+	// A group policy owns the capsules created for it, the creator only pays for them
+	owner := msg.Creator
+	if msg.GroupPolicy != "" {
+		owner = msg.GroupPolicy
+	}
+
 	// Create the capsule
 	capsule, err := ms.keeper.CreateCapsule(
 		ctx,
-		msg.Creator,
+		owner,
 		msg.Recipient,
 		msg.Data,
+		msg.Compression,
 		msg.CapsuleType,
 		msg.Threshold,
 		msg.TotalShares,
 		msg.UnlockTime,
+		msg.UnlockHeight,
+		msg.UnlockEpoch,
 		msg.ConditionContract,
+		msg.InactivityPeriod,
+		msg.RequiredSigs,
+		msg.GroupPolicy,
+		msg.Attestation,
+		msg.ExpiresAt,
+		msg.Tags,
 		metadata,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	// Charge the capsule and both of its fees to the sponsor's allowance
+	if msg.Sponsor != "" {
+		fees := creationFee.Add(params.StorageFee(capsule.DataSize)...)
+		if err := ms.keeper.SponsorCapsule(ctx, capsule, msg.Sponsor, msg.Creator, fees); err != nil {
+			return nil, err
+		}
+	}
+
+	// Charge the storage fee on the stored, compressed size
+	if err := ms.keeper.ChargeStorageFee(ctx, payer, capsule.DataSize); err != nil {
+		return nil, err
+	}
+
+	// Nominate the guardians of emergency actions
+	if err := ms.keeper.SetCapsuleGuardians(ctx, capsule, msg.Guardians, msg.GuardianThreshold); err != nil {
+		return nil, err
+	}
+
+	// Decide which versions the recipient receives once the capsule is amended
+	if err := ms.keeper.SetVersionPolicy(ctx, capsule, msg.VersionPolicy); err != nil {
+		return nil, err
+	}
+
+	// Deliver to the recipient's key on open, and let the executor rotate the recipient
+	if err := ms.keeper.SetRecipientDelivery(ctx, capsule, msg.RecipientKey, msg.Executor); err != nil {
+		return nil, err
+	}
+
+	// Require the shared passphrase, besides the recipient key, to open the delivery
+	if err := ms.keeper.SetPassphraseFactor(ctx, capsule, msg.PassphraseFactor); err != nil {
+		return nil, err
+	}
+
+	// Lock the escrow alongside the capsule data
+	if err := ms.keeper.LockEscrow(ctx, capsule, msg.Creator, msg.Escrow, msg.Beneficiaries); err != nil {
+		return nil, err
+	}
+
 	return &types.MsgCreateCapsuleResponse{
 		CapsuleId: capsule.ID,
 	}, nil
@@ -112,16 +207,9 @@ func (ms MsgServer) OpenCapsule(goCtx context.Context, msg *types.MsgOpenCapsule
 	ctx := sdk.UnwrapSDKContext(goCtx)
 
 	// Parse key shares if provided
-	var shares []*crypto.Share
-	if len(msg.KeyShares) > 0 {
-		shares = make([]*crypto.Share, len(msg.KeyShares))
-		for i, shareStr := range msg.KeyShares {
-			var share crypto.Share
-			if err := json.Unmarshal([]byte(shareStr), &share); err != nil {
-				return nil, types.ErrInvalidKeyShare.Wrapf("invalid key share at index %d: %s", i, err)
-			}
-			shares[i] = &share
-		}
+	shares, err := parseKeyShares(msg.KeyShares)
+	if err != nil {
+		return nil, err
 	}
 
 	// Prepare condition parameters
@@ -133,14 +221,29 @@ func (ms MsgServer) OpenCapsule(goCtx context.Context, msg *types.MsgOpenCapsule
 		conditionParams[k] = v
 	}
 
+	// Remember the escrow so the response can report what was released
+	var escrow sdk.Coins
+	if capsule, err := ms.keeper.GetCapsule(ctx, msg.CapsuleID); err == nil {
+		escrow = capsule.Escrow
+	}
+
 	// Open the capsule
-	data, err := ms.keeper.OpenCapsule(ctx, msg.CapsuleID, msg.Accessor, shares, conditionParams)
+	data, previousVersions, err := ms.keeper.OpenCapsule(ctx, msg.CapsuleID, msg.Accessor, shares, conditionParams)
 	if err != nil {
 		return nil, err
 	}
 
+	// Capsules with a recipient key return their delivery instead of data
+	var delivery *types.RecipientDelivery
+	if capsule, err := ms.keeper.GetCapsule(ctx, msg.CapsuleID); err == nil {
+		delivery = capsule.Delivery
+	}
+
 	return &types.MsgOpenCapsuleResponse{
-		Data: data,
+		Data:             data,
+		ReleasedEscrow:   escrow,
+		PreviousVersions: previousVersions,
+		Delivery:         delivery,
 	}, nil
 }
 
@@ -176,25 +279,53 @@ func (ms MsgServer) CancelCapsule(goCtx context.Context, msg *types.MsgCancelCap
 		return nil, types.ErrInvalidCapsule.Wrapf("cannot cancel capsule with status %s", capsule.Status.String())
 	}
 
+	if capsule.Frozen {
+		return nil, types.ErrCapsuleFrozen.Wrapf("capsule %d cannot be cancelled", msg.CapsuleID)
+	}
+
+	// A commitment stays on record until it is revealed
+	if capsule.IsPublicReveal() {
+		return nil, types.ErrInvalidCapsuleType.Wrap("public reveal commitments cannot be cancelled")
+	}
+
+	// Enforce the escrow cancellation policy
+	if err := ms.keeper.canRefundEscrow(ctx, capsule); err != nil {
+		return nil, err
+	}
+
 	// Update status to cancelled
 	capsule.Status = types.CapsuleStatus_CANCELLED
 	capsule.UpdatedAt = ctx.BlockTime()
 
-	if err := ms.keeper.capsules.Set(ctx, msg.CapsuleID, *capsule); err != nil {
+	// Return the escrow to the owner
+	refunded, err := ms.keeper.refundEscrow(ctx, capsule)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ms.keeper.SetCapsule(ctx, capsule); err != nil {
 		return nil, fmt.Errorf("failed to update capsule status: %w", err)
 	}
 
+	// The data of a cancelled capsule is purged at once
+	if err := ms.keeper.ScheduleRetention(ctx, capsule); err != nil {
+		return nil, err
+	}
+
 	// Emit event
 	ctx.EventManager().EmitEvent(
 		sdk.NewEvent(
-			"capsule_cancelled",
-			sdk.NewAttribute("capsule_id", fmt.Sprintf("%d", msg.CapsuleID)),
-			sdk.NewAttribute("owner", msg.Owner),
-			sdk.NewAttribute("reason", msg.Reason),
+			types.EventTypeCapsuleCancelled,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", msg.CapsuleID)),
+			sdk.NewAttribute(types.AttributeKeyOwner, msg.Owner),
+			sdk.NewAttribute(types.AttributeKeyRecipient, capsule.Recipient),
+			sdk.NewAttribute(types.AttributeKeyReason, msg.Reason),
 		),
 	)
 
-	return &types.MsgCancelCapsuleResponse{}, nil
+	return &types.MsgCancelCapsuleResponse{
+		RefundedEscrow: refunded,
+	}, nil
 }
 
 // TransferCapsule transfers ownership of a capsule
@@ -217,11 +348,25 @@ func (ms MsgServer) TransferCapsule(goCtx context.Context, msg *types.MsgTransfe
 		return nil, types.ErrInvalidCapsule.Wrapf("cannot transfer capsule with status %s", capsule.Status.String())
 	}
 
+	if capsule.Frozen {
+		return nil, types.ErrCapsuleFrozen.Wrapf("capsule %d cannot be transferred", msg.CapsuleID)
+	}
+
+	// Guardians act on behalf of the owner and cannot become the owner
+	if capsule.IsGuardian(msg.NewOwner) {
+		return nil, types.ErrInvalidTransfer.Wrap("new owner is a guardian of the capsule")
+	}
+
+	// Group policy capsules stay owned by a group policy
+	if err := ms.keeper.transferGroupPolicy(ctx, capsule, msg.NewOwner); err != nil {
+		return nil, err
+	}
+
 	// Update ownership
 	capsule.Owner = msg.NewOwner
 	capsule.UpdatedAt = ctx.BlockTime()
 
-	if err := ms.keeper.capsules.Set(ctx, msg.CapsuleID, *capsule); err != nil {
+	if err := ms.keeper.SetCapsule(ctx, capsule); err != nil {
 		return nil, fmt.Errorf("failed to update capsule ownership: %w", err)
 	}
 
@@ -237,10 +382,11 @@ func (ms MsgServer) TransferCapsule(goCtx context.Context, msg *types.MsgTransfe
 	// Emit event
 	ctx.EventManager().EmitEvent(
 		sdk.NewEvent(
-			"capsule_transferred",
-			sdk.NewAttribute("capsule_id", fmt.Sprintf("%d", msg.CapsuleID)),
-			sdk.NewAttribute("from", msg.CurrentOwner),
-			sdk.NewAttribute("to", msg.NewOwner),
+			types.EventTypeCapsuleTransferred,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", msg.CapsuleID)),
+			sdk.NewAttribute(types.AttributeKeyFrom, msg.CurrentOwner),
+			sdk.NewAttribute(types.AttributeKeyTo, msg.NewOwner),
+			sdk.NewAttribute(types.AttributeKeyRecipient, capsule.Recipient),
 		),
 	)
 
@@ -372,24 +518,248 @@ func (ms MsgServer) ApproveTransfer(goCtx context.Context, msg *types.MsgApprove
 	}, nil
 }
 
-// EmergencyDeleteContract permanently deletes a capsule's smart contract in emergency situations
-func (ms MsgServer) EmergencyDeleteContract(goCtx context.Context, msg *types.MsgEmergencyDeleteContract) (*types.MsgEmergencyDeleteContractResponse, error) {
+// RegisterTimelockKey registers the key a validator takes part in timelock key generation with
+func (ms MsgServer) RegisterTimelockKey(goCtx context.Context, msg *types.MsgRegisterTimelockKey) (*types.MsgRegisterTimelockKeyResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if err := ms.keeper.RegisterTimelockKey(ctx, msg.Validator, msg.PublicKey); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgRegisterTimelockKeyResponse{}, nil
+}
+
+// ProposeEmergencyAction proposes an emergency action on behalf of a capsule guardian
+func (ms MsgServer) ProposeEmergencyAction(goCtx context.Context, msg *types.MsgProposeEmergencyAction) (*types.MsgProposeEmergencyActionResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	action, err := ms.keeper.ProposeEmergencyAction(ctx, msg.Guardian, msg.CapsuleID, msg.ActionType, msg.Reason, msg.NewRecipient, msg.NewRecipientKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MsgProposeEmergencyActionResponse{
+		ActionId: action.ID,
+	}, nil
+}
+
+// ApproveEmergencyAction adds a guardian approval to a pending emergency action
+func (ms MsgServer) ApproveEmergencyAction(goCtx context.Context, msg *types.MsgApproveEmergencyAction) (*types.MsgApproveEmergencyActionResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	action, err := ms.keeper.ApproveEmergencyAction(ctx, msg.Guardian, msg.ActionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MsgApproveEmergencyActionResponse{
+		Approvals:    uint32(len(action.Approvals)),
+		ExecuteAfter: action.ExecuteAfter,
+	}, nil
+}
+
+// VetoEmergencyAction rejects a pending emergency action
+func (ms MsgServer) VetoEmergencyAction(goCtx context.Context, msg *types.MsgVetoEmergencyAction) (*types.MsgVetoEmergencyActionResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if err := ms.keeper.VetoEmergencyAction(ctx, msg.Sender, msg.ActionID, msg.Reason); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgVetoEmergencyActionResponse{}, nil
+}
+
+// ExecuteEmergencyAction executes an approved emergency action after its delay
+func (ms MsgServer) ExecuteEmergencyAction(goCtx context.Context, msg *types.MsgExecuteEmergencyAction) (*types.MsgExecuteEmergencyActionResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	action, err := ms.keeper.ExecuteEmergencyAction(ctx, msg.Guardian, msg.ActionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MsgExecuteEmergencyActionResponse{
+		ReversibleUntil: action.ReversibleUntil,
+	}, nil
+}
+
+// ReverseEmergencyAction undoes an executed emergency action
+func (ms MsgServer) ReverseEmergencyAction(goCtx context.Context, msg *types.MsgReverseEmergencyAction) (*types.MsgReverseEmergencyActionResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if err := ms.keeper.ReverseEmergencyAction(ctx, msg.Sender, msg.ActionID, msg.Reason); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgReverseEmergencyActionResponse{}, nil
+}
+
+// AmendCapsule replaces the content of an active capsule with a new version
+func (ms MsgServer) AmendCapsule(goCtx context.Context, msg *types.MsgAmendCapsule) (*types.MsgAmendCapsuleResponse, error) {
 	ctx := sdk.UnwrapSDKContext(goCtx)
 
-	// Execute emergency deletion with comprehensive validation
-	err := ms.keeper.EmergencyDeleteContract(
+	params, err := ms.keeper.GetParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if uint64(len(msg.Data)) > params.MaxDataSize {
+		return nil, types.ErrDataTooLarge.Wrapf("data size %d exceeds maximum %d", len(msg.Data), params.MaxDataSize)
+	}
+
+	capsule, err := ms.keeper.AmendCapsule(
 		ctx,
+		msg.Owner,
 		msg.CapsuleID,
-		msg.Creator,
-		msg.EmergencyReason,
-		msg.ConfirmationCode,
+		msg.Data,
+		msg.Compression,
+		msg.Title,
+		msg.Description,
+		msg.Tags,
+		msg.Metadata,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return &types.MsgEmergencyDeleteContractResponse{
-		Success: true,
-		Message: "Smart contract deleted permanently",
+	// Charge the storage fee of the new version
+	owner, err := sdk.AccAddressFromBech32(msg.Owner)
+	if err != nil {
+		return nil, err
+	}
+	if err := ms.keeper.ChargeStorageFee(ctx, owner, capsule.DataSize); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgAmendCapsuleResponse{Version: capsule.Version}, nil
+}
+
+// RotateRecipient re-targets a capsule to a new recipient and recipient key
+func (ms MsgServer) RotateRecipient(goCtx context.Context, msg *types.MsgRotateRecipient) (*types.MsgRotateRecipientResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if err := ms.keeper.RotateRecipient(ctx, msg.Sender, msg.CapsuleID, msg.NewRecipient, msg.NewRecipientKey); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgRotateRecipientResponse{}, nil
+}
+
+// RevealCapsule publishes the content of a public reveal capsule
+func (ms MsgServer) RevealCapsule(goCtx context.Context, msg *types.MsgRevealCapsule) (*types.MsgRevealCapsuleResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	capsule, err := ms.keeper.RevealCapsule(ctx, msg.Revealer, msg.CapsuleID, msg.Data, msg.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MsgRevealCapsuleResponse{CommitHeight: capsule.CommitHeight}, nil
+}
+
+// parseKeyShares decodes JSON-serialized key shares
+func parseKeyShares(keyShares []string) ([]*crypto.Share, error) {
+	if len(keyShares) == 0 {
+		return nil, nil
+	}
+
+	shares := make([]*crypto.Share, len(keyShares))
+	for i, shareStr := range keyShares {
+		var share crypto.Share
+		if err := json.Unmarshal([]byte(shareStr), &share); err != nil {
+			return nil, types.ErrInvalidKeyShare.Wrapf("invalid key share at index %d: %s", i, err)
+		}
+		shares[i] = &share
+	}
+
+	return shares, nil
+}
+
+// GrantCapsuleAllowance lets a sponsor pay the capsule fees of a grantee
+func (ms MsgServer) GrantCapsuleAllowance(goCtx context.Context, msg *types.MsgGrantCapsuleAllowance) (*types.MsgGrantCapsuleAllowanceResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	allowance := types.CapsuleAllowance{
+		Sponsor:     msg.Sponsor,
+		Grantee:     msg.Grantee,
+		MaxCapsules: msg.MaxCapsules,
+		MaxBytes:    msg.MaxBytes,
+		SpendLimit:  msg.SpendLimit,
+		Expiration:  msg.Expiration,
+	}
+	if err := ms.keeper.GrantCapsuleAllowance(ctx, allowance); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgGrantCapsuleAllowanceResponse{}, nil
+}
+
+// RevokeCapsuleAllowance revokes the capsule allowance of a grantee
+func (ms MsgServer) RevokeCapsuleAllowance(goCtx context.Context, msg *types.MsgRevokeCapsuleAllowance) (*types.MsgRevokeCapsuleAllowanceResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if err := ms.keeper.RevokeCapsuleAllowance(ctx, msg.Sponsor, msg.Grantee); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgRevokeCapsuleAllowanceResponse{}, nil
+}
+
+// RegisterAttestor registers an attestor or updates its key and description
+func (ms MsgServer) RegisterAttestor(goCtx context.Context, msg *types.MsgRegisterAttestor) (*types.MsgRegisterAttestorResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if err := ms.keeper.RegisterAttestor(ctx, msg.Attestor, msg.KeyType, msg.PubKey, msg.Description); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgRegisterAttestorResponse{}, nil
+}
+
+// CurateAttestor sets whether governance curates an attestor
+func (ms MsgServer) CurateAttestor(goCtx context.Context, msg *types.MsgCurateAttestor) (*types.MsgCurateAttestorResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if err := ms.keeper.CurateAttestor(ctx, msg.Authority, msg.Attestor, msg.Curated); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgCurateAttestorResponse{}, nil
+}
+
+// SubmitAttestation submits an attestor's signed claim about a capsule
+func (ms MsgServer) SubmitAttestation(goCtx context.Context, msg *types.MsgSubmitAttestation) (*types.MsgSubmitAttestationResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	count, attested, err := ms.keeper.SubmitAttestation(ctx, msg.Submitter, msg.CapsuleID, msg.Attestor, msg.Claim, msg.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MsgSubmitAttestationResponse{
+		Attestations: count,
+		Attested:     attested,
 	}, nil
-}
\ No newline at end of file
+}
+
+// RevokeCapsuleAttestor removes an attestor from the attestation condition of a capsule
+func (ms MsgServer) RevokeCapsuleAttestor(goCtx context.Context, msg *types.MsgRevokeCapsuleAttestor) (*types.MsgRevokeCapsuleAttestorResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if err := ms.keeper.RevokeCapsuleAttestor(ctx, msg.Owner, msg.CapsuleID, msg.Attestor); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgRevokeCapsuleAttestorResponse{}, nil
+}
+
+// EraseCapsule purges the data of a finished capsule at its owner's request
+func (ms MsgServer) EraseCapsule(goCtx context.Context, msg *types.MsgEraseCapsule) (*types.MsgEraseCapsuleResponse, error) {
+	ctx := sdk.UnwrapSDKContext(goCtx)
+
+	if err := ms.keeper.EraseCapsule(ctx, msg.Owner, msg.CapsuleID); err != nil {
+		return nil, err
+	}
+
+	return &types.MsgEraseCapsuleResponse{}, nil
+}
@@ -11,6 +11,9 @@ import (
 )
 
 // MultiSigManager handles multi-signature operations for capsules
+//
+// Deprecated: its signatures are not verified. Create multi-sig capsules owned by an
+// x/group policy instead; they are opened, transferred and amended by group proposals.
 type MultiSigManager struct {
 	keeper *Keeper
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
 	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
 )
 
@@ -314,14 +315,24 @@ func (oe *OptimizationEngine) AutoOptimize(ctx context.Context) error {
 	return nil
 }
 
-// optimizeDataCompression performs automatic data compression optimization
+// optimizeDataCompression reports the capsules stored without compression. Their data
+// is encrypted, so it can only be compressed when the owner amends the capsule.
 func (oe *OptimizationEngine) optimizeDataCompression(ctx context.Context) error {
-	// In production, this would:
-	// - Analyze data patterns
-	// - Apply better compression algorithms
-	// - Deduplicate similar data
+	var uncompressedCount, uncompressedSize int64
+	err := oe.keeper.capsules.Walk(ctx, nil, func(id uint64, capsule types.TimeCapsule) (bool, error) {
+		if capsule.Compression == crypto.CompressionNone && !capsule.IsPublicReveal() && capsule.DataSize > 0 {
+			uncompressedCount++
+			uncompressedSize += capsule.DataSize
+		}
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
 	
-	fmt.Println("Applying data compression optimizations...")
+	oe.keeper.logger.Info("Capsules stored without compression",
+		"capsule_count", uncompressedCount,
+		"data_size", uncompressedSize)
 	return nil
 }
 
@@ -352,10 +363,13 @@ func (oe *OptimizationEngine) GetStorageOptimizationReport(ctx context.Context)
 	report := make(map[string]interface{})
 	
 	var totalSize, blockchainSize, ipfsSize int64
-	var blockchainCount, ipfsCount int64
+	var blockchainCount, ipfsCount, compressedCount int64
 	
 	err := oe.keeper.capsules.Walk(ctx, nil, func(id uint64, capsule types.TimeCapsule) (bool, error) {
 		totalSize += capsule.DataSize
+		if capsule.Compression != crypto.CompressionNone {
+			compressedCount++
+		}
 		
 		if capsule.StorageType == "blockchain" {
 			blockchainSize += capsule.DataSize
@@ -377,6 +391,7 @@ func (oe *OptimizationEngine) GetStorageOptimizationReport(ctx context.Context)
 	report["ipfs_size"] = ipfsSize
 	report["blockchain_count"] = blockchainCount
 	report["ipfs_count"] = ipfsCount
+	report["compressed_count"] = compressedCount
 	
 	if totalSize > 0 {
 		report["blockchain_percentage"] = float64(blockchainSize) / float64(totalSize) * 100
@@ -0,0 +1,43 @@
+package keeper
+
+import (
+	"encoding/json"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// SetOracleKeeper sets the on-chain oracle oracle conditions are evaluated against. It
+// must be set when the app is wired, before any block is processed.
+func (k Keeper) SetOracleKeeper(oracleKeeper types.OracleKeeper) {
+	k.conditionFactory.SetOracleKeeper(oracleKeeper)
+}
+
+// evaluateOracleContract evaluates an oracle condition contract. Its parameters are the
+// fields of an oracle condition, e.g. query "BTC", operator "gte", expected_value "100000".
+func (k Keeper) evaluateOracleContract(ctx sdk.Context, contract types.ConditionContract) (bool, string, error) {
+	bz, err := json.Marshal(contract.Parameters)
+	if err != nil {
+		return false, "", err
+	}
+
+	condition, err := k.conditionFactory.CreateCondition(types.ConditionType_ORACLE, bz)
+	if err != nil {
+		return false, fmt.Sprintf("invalid oracle condition: %s", err), nil
+	}
+	if err := condition.Validate(); err != nil {
+		return false, fmt.Sprintf("invalid oracle condition: %s", err), nil
+	}
+
+	met, err := condition.Evaluate(ctx, nil)
+	if err != nil {
+		return false, fmt.Sprintf("oracle condition cannot be evaluated: %s", err), nil
+	}
+	if !met {
+		return false, "oracle condition not met", nil
+	}
+
+	return true, "oracle condition met", nil
+}
@@ -142,39 +142,43 @@ func (qs QueryServer) CapsulesByStatus(c context.Context, req *types.QueryCapsul
 	}, nil
 }
 
-// Stats returns statistics about the time capsule module
-func (qs QueryServer) Stats(c context.Context, req *types.QueryStatsRequest) (*types.QueryStatsResponse, error) {
+// CapsulesByTag returns a page of an owner's capsules by tag or indexed metadata entry
+func (qs QueryServer) CapsulesByTag(c context.Context, req *types.QueryCapsulesByTagRequest) (*types.QueryCapsulesByTagResponse, error) {
 	ctx := sdk.UnwrapSDKContext(c)
 
-	stats := &types.ModuleStats{
-		TotalCapsules:   0,
-		ActiveCapsules:  0,
-		OpenedCapsules:  0,
-		ExpiredCapsules: 0,
-		CancelledCapsules: 0,
+	if _, err := sdk.AccAddressFromBech32(req.Owner); err != nil {
+		return nil, types.ErrInvalidAddress.Wrapf("invalid owner address: %s", err)
 	}
 
-	// Count capsules by status
-	err := qs.keeper.capsules.Walk(ctx, nil, func(key uint64, capsule types.TimeCapsule) (bool, error) {
-		stats.TotalCapsules++
-		
-		switch capsule.Status {
-		case types.CapsuleStatus_ACTIVE:
-			stats.ActiveCapsules++
-		case types.CapsuleStatus_UNLOCKED:
-			stats.OpenedCapsules++
-		case types.CapsuleStatus_EXPIRED:
-			stats.ExpiredCapsules++
-		case types.CapsuleStatus_CANCELLED:
-			stats.CancelledCapsules++
-		}
-		
-		return false, nil // Continue iteration
-	})
+	capsules, pageRes, err := qs.keeper.GetCapsulesByTag(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
+	return &types.QueryCapsulesByTagResponse{
+		Capsules:   capsules,
+		Pagination: pageRes,
+	}, nil
+}
+
+// Stats returns statistics about the time capsule module
+func (qs QueryServer) Stats(c context.Context, req *types.QueryStatsRequest) (*types.QueryStatsResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+
+	aggregates, err := qs.keeper.GetCapsuleAggregates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &types.ModuleStats{
+		TotalCapsules:     aggregates.Total,
+		ActiveCapsules:    aggregates.ByStatus[types.CapsuleStatus_ACTIVE.String()],
+		OpenedCapsules:    aggregates.ByStatus[types.CapsuleStatus_UNLOCKED.String()],
+		ExpiredCapsules:   aggregates.ByStatus[types.CapsuleStatus_EXPIRED.String()],
+		CancelledCapsules: aggregates.ByStatus[types.CapsuleStatus_CANCELLED.String()],
+		TotalDataSize:     aggregates.TotalBytes,
+	}
+
 	return &types.QueryStatsResponse{Stats: stats}, nil
 }
 
@@ -242,4 +246,219 @@ func (qs QueryServer) ConditionContracts(c context.Context, req *types.QueryCond
 	return &types.QueryConditionContractsResponse{
 		Contracts: contracts,
 	}, nil
-}
\ No newline at end of file
+}
+
+// TimelockEpoch returns a timelock epoch key, including its secret once released
+func (qs QueryServer) TimelockEpoch(c context.Context, req *types.QueryTimelockEpochRequest) (*types.QueryTimelockEpochResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+
+	epoch, err := qs.keeper.GetTimelockEpoch(ctx, req.Epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryTimelockEpochResponse{Epoch: epoch}, nil
+}
+
+// EmergencyAction returns an emergency action
+func (qs QueryServer) EmergencyAction(c context.Context, req *types.QueryEmergencyActionRequest) (*types.QueryEmergencyActionResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+
+	action, err := qs.keeper.GetEmergencyAction(ctx, req.ActionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryEmergencyActionResponse{Action: action}, nil
+}
+
+// CapsuleEmergencyActions returns all emergency actions proposed for a capsule
+func (qs QueryServer) CapsuleEmergencyActions(c context.Context, req *types.QueryCapsuleEmergencyActionsRequest) (*types.QueryCapsuleEmergencyActionsResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+
+	actions, err := qs.keeper.GetCapsuleEmergencyActions(ctx, req.CapsuleId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryCapsuleEmergencyActionsResponse{Actions: actions}, nil
+}
+
+// AccessLog returns the retained open attempts on a capsule
+func (qs QueryServer) AccessLog(c context.Context, req *types.QueryAccessLogRequest) (*types.QueryAccessLogResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+
+	if _, err := qs.keeper.GetCapsule(ctx, req.CapsuleId); err != nil {
+		return nil, err
+	}
+
+	accesses, err := qs.keeper.GetAccessLog(ctx, req.CapsuleId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryAccessLogResponse{Accesses: accesses}, nil
+}
+
+// CapsuleVersions returns the version history of a capsule
+func (qs QueryServer) CapsuleVersions(c context.Context, req *types.QueryCapsuleVersionsRequest) (*types.QueryCapsuleVersionsResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+
+	capsule, err := qs.keeper.GetCapsule(ctx, req.CapsuleId)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := qs.keeper.GetCapsuleVersions(ctx, req.CapsuleId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryCapsuleVersionsResponse{
+		CurrentVersion: capsule.CurrentVersion(),
+		DataHash:       capsule.DataHash,
+		Versions:       versions,
+	}, nil
+}
+
+// ShareHealth returns the live key share margin of one capsule, or of every active capsule
+func (qs QueryServer) ShareHealth(c context.Context, req *types.QueryShareHealthRequest) (*types.QueryShareHealthResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+
+	if req.CapsuleId != 0 {
+		capsule, err := qs.keeper.GetCapsule(ctx, req.CapsuleId)
+		if err != nil {
+			return nil, err
+		}
+		if capsule.IsTimelocked() {
+			return nil, types.ErrInvalidRequest.Wrapf("capsule %d is timelocked and has no key shares", req.CapsuleId)
+		}
+		if capsule.IsPublicReveal() {
+			return nil, types.ErrInvalidRequest.Wrapf("capsule %d is a public reveal capsule and has no key shares", req.CapsuleId)
+		}
+
+		health, err := qs.keeper.GetShareHealth(ctx, capsule)
+		if err != nil {
+			return nil, err
+		}
+		return &types.QueryShareHealthResponse{Capsules: []types.CapsuleShareHealth{health}}, nil
+	}
+
+	var capsules []types.CapsuleShareHealth
+	err := qs.keeper.capsules.Walk(ctx, nil, func(_ uint64, capsule types.TimeCapsule) (bool, error) {
+		if capsule.Status != types.CapsuleStatus_ACTIVE || capsule.IsTimelocked() || capsule.IsPublicReveal() {
+			return false, nil
+		}
+
+		health, err := qs.keeper.GetShareHealth(ctx, &capsule)
+		if err != nil {
+			return true, err
+		}
+		capsules = append(capsules, health)
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryShareHealthResponse{Capsules: capsules}, nil
+}
+
+// Reveal returns the commitment of a public reveal capsule and its content once revealed
+func (qs QueryServer) Reveal(c context.Context, req *types.QueryRevealRequest) (*types.QueryRevealResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+
+	capsule, err := qs.keeper.GetCapsule(ctx, req.CapsuleId)
+	if err != nil {
+		return nil, err
+	}
+	if !capsule.IsPublicReveal() {
+		return nil, types.ErrInvalidCapsuleType.Wrapf("capsule %d is not a public reveal capsule", req.CapsuleId)
+	}
+
+	return &types.QueryRevealResponse{
+		CapsuleId:       capsule.ID,
+		Commitment:      capsule.DataHash,
+		CommitHeight:    capsule.CommitHeight,
+		CommitBlockHash: capsule.CommitBlockHash,
+		CommittedAt:     capsule.CreatedAt,
+		UnlockTime:      capsule.UnlockTime,
+		Revealed:        capsule.Reveal != nil,
+		Reveal:          capsule.Reveal,
+	}, nil
+}
+
+// CapsuleAllowance returns the capsule allowance a sponsor granted a grantee
+func (qs QueryServer) CapsuleAllowance(c context.Context, req *types.QueryCapsuleAllowanceRequest) (*types.QueryCapsuleAllowanceResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+
+	allowance, err := qs.keeper.GetCapsuleAllowance(ctx, req.Sponsor, req.Grantee)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryCapsuleAllowanceResponse{Allowance: allowance}, nil
+}
+
+// CapsuleAllowances returns all capsule allowances granted by a sponsor
+func (qs QueryServer) CapsuleAllowances(c context.Context, req *types.QueryCapsuleAllowancesRequest) (*types.QueryCapsuleAllowancesResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+
+	if _, err := sdk.AccAddressFromBech32(req.Sponsor); err != nil {
+		return nil, types.ErrInvalidAddress.Wrapf("invalid sponsor address: %s", err)
+	}
+
+	allowances, err := qs.keeper.GetSponsorAllowances(ctx, req.Sponsor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryCapsuleAllowancesResponse{Allowances: allowances}, nil
+}
+
+// Attestor returns a registered attestor
+func (qs QueryServer) Attestor(c context.Context, req *types.QueryAttestorRequest) (*types.QueryAttestorResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+
+	attestor, err := qs.keeper.GetAttestor(ctx, req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryAttestorResponse{Attestor: attestor}, nil
+}
+
+// Attestors returns the registered attestors, or only the curated ones
+func (qs QueryServer) Attestors(c context.Context, req *types.QueryAttestorsRequest) (*types.QueryAttestorsResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+
+	attestors, err := qs.keeper.GetAllAttestors(ctx, req.CuratedOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryAttestorsResponse{Attestors: attestors}, nil
+}
+
+// CapsuleAttestations returns the attestation condition of a capsule with the attestations submitted for it
+func (qs QueryServer) CapsuleAttestations(c context.Context, req *types.QueryCapsuleAttestationsRequest) (*types.QueryCapsuleAttestationsResponse, error) {
+	ctx := sdk.UnwrapSDKContext(c)
+
+	capsule, err := qs.keeper.GetCapsule(ctx, req.CapsuleId)
+	if err != nil {
+		return nil, err
+	}
+	if capsule.Attestation == nil {
+		return nil, types.ErrInvalidAttestation.Wrapf("capsule %d has no attestation condition", req.CapsuleId)
+	}
+
+	attestations, err := qs.keeper.GetCapsuleAttestations(ctx, req.CapsuleId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryCapsuleAttestationsResponse{
+		Condition:    capsule.Attestation,
+		Attestations: attestations,
+	}, nil
+}
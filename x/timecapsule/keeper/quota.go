@@ -0,0 +1,121 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+
+	"cosmossdk.io/collections"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// ConsumeCreationQuota records count capsule creations of a creator in the current
+// block. It fails when the creator would exceed its quota for the quota window.
+func (k Keeper) ConsumeCreationQuota(ctx context.Context, creator string, count uint32) error {
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	quota, err := k.creationQuotas.Get(ctx, creator)
+	if err != nil && !errors.Is(err, collections.ErrNotFound) {
+		return err
+	}
+
+	quota, err = quota.Consume(sdk.UnwrapSDKContext(ctx).BlockHeight(), count, params)
+	if err != nil {
+		return types.ErrCreationQuotaExceeded.Wrapf("%s: %s", creator, err)
+	}
+	if err := k.creationQuotas.Set(ctx, creator, quota); err != nil {
+		return err
+	}
+
+	volume, err := k.GetCreationVolume(ctx)
+	if err != nil {
+		return err
+	}
+	volume.BlockCreations += count
+	return k.creationVolume.Set(ctx, volume)
+}
+
+// GetCreationQuota returns the creation quota of a creator
+func (k Keeper) GetCreationQuota(ctx context.Context, creator string) (types.CreationQuota, error) {
+	quota, err := k.creationQuotas.Get(ctx, creator)
+	if errors.Is(err, collections.ErrNotFound) {
+		return types.CreationQuota{}, nil
+	}
+	return quota, err
+}
+
+// SetCreationQuota stores the creation quota of a creator
+func (k Keeper) SetCreationQuota(ctx context.Context, creator string, quota types.CreationQuota) error {
+	return k.creationQuotas.Set(ctx, creator, quota)
+}
+
+// GetAllCreationQuotas returns the creation quotas of all creators
+func (k Keeper) GetAllCreationQuotas(ctx context.Context) ([]types.AccountCreationQuota, error) {
+	var quotas []types.AccountCreationQuota
+	err := k.creationQuotas.Walk(ctx, nil, func(creator string, quota types.CreationQuota) (bool, error) {
+		quotas = append(quotas, types.AccountCreationQuota{Creator: creator, Quota: quota})
+		return false, nil
+	})
+	return quotas, err
+}
+
+// CreationFee returns the current creation fee, the base creation fee under the
+// multiplier driven by recent module-wide creations
+func (k Keeper) CreationFee(ctx context.Context) (sdk.Coins, error) {
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	volume, err := k.GetCreationVolume(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return params.CreationFeeAt(volume.Multiplier), nil
+}
+
+// updateCreationFee adjusts the creation fee multiplier to the creations of the block
+func (k Keeper) updateCreationFee(ctx context.Context) error {
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	volume, err := k.GetCreationVolume(ctx)
+	if err != nil {
+		return err
+	}
+
+	next := types.CreationVolume{Multiplier: volume.NextMultiplier(params)}
+	if next.Multiplier.Equal(volume.Multiplier) && volume.BlockCreations == 0 {
+		return nil
+	}
+
+	return k.creationVolume.Set(ctx, next)
+}
+
+// GetCreationVolume returns the module-wide creation volume
+func (k Keeper) GetCreationVolume(ctx context.Context) (types.CreationVolume, error) {
+	volume, err := k.creationVolume.Get(ctx)
+	if errors.Is(err, collections.ErrNotFound) {
+		return types.NewCreationVolume(), nil
+	}
+	return volume, err
+}
+
+// HasCreationVolume returns true if the creation volume is stored, which it is once
+// capsules were created
+func (k Keeper) HasCreationVolume(ctx context.Context) (bool, error) {
+	return k.creationVolume.Has(ctx)
+}
+
+// SetCreationVolume stores the module-wide creation volume
+func (k Keeper) SetCreationVolume(ctx context.Context, volume types.CreationVolume) error {
+	return k.creationVolume.Set(ctx, volume)
+}
@@ -0,0 +1,366 @@
+package keeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// sealDelivery wraps the data key of a capsule under a fresh delivery secret encrypted
+// to a delivery key dealt among the custodians, and returns the delivery key shares.
+// Timelocked capsules need no delivery key: their data key is delivered from the
+// released epoch secret.
+func (k Keeper) sealDelivery(capsule *types.TimeCapsule, encryptionKey []byte) ([]*crypto.Share, error) {
+	capsule.DeliveryKey = nil
+	capsule.DeliveryCiphertext = nil
+	capsule.DeliveryWrappedKey = nil
+	capsule.DeliveryKeyNonce = nil
+	if capsule.IsTimelocked() {
+		return nil, nil
+	}
+
+	deliveryKey, shares, err := crypto.DealDeliveryKey(int(capsule.Threshold), int(capsule.TotalShares))
+	if err != nil {
+		return nil, types.ErrInvalidKeyShare.Wrapf("failed to deal delivery key: %s", err)
+	}
+
+	secret, ciphertext, err := crypto.NewDeliverySecret(deliveryKey)
+	if err != nil {
+		return nil, types.ErrInvalidEncryption.Wrapf("failed to create delivery secret: %s", err)
+	}
+	defer crypto.WipeKey(secret)
+
+	wrapped, err := k.encryptionManager.Encrypt(encryptionKey, secret)
+	if err != nil {
+		return nil, types.ErrInvalidEncryption.Wrapf("failed to wrap data key: %s", err)
+	}
+
+	wrappedKey, err := k.sealPassphraseLayer(capsule, wrapped.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	capsule.DeliveryKey = deliveryKey
+	capsule.DeliveryCiphertext = ciphertext
+	capsule.DeliveryWrappedKey = wrappedKey
+	capsule.DeliveryKeyNonce = wrapped.Nonce
+
+	return shares, nil
+}
+
+// sealPassphraseLayer wraps the wrapped data key of a capsule protected by a passphrase
+// factor again, under a fresh secret encrypted to the factor's public key. Capsules
+// without a passphrase factor are left as is.
+func (k Keeper) sealPassphraseLayer(capsule *types.TimeCapsule, wrappedKey []byte) ([]byte, error) {
+	factor := capsule.PassphraseFactor
+	if factor == nil {
+		return wrappedKey, nil
+	}
+
+	secret, ciphertext, err := crypto.NewDeliverySecret(factor.PublicKey)
+	if err != nil {
+		return nil, types.ErrInvalidPassphrase.Wrapf("failed to encrypt to passphrase key: %s", err)
+	}
+	defer crypto.WipeKey(secret)
+
+	wrapped, err := k.encryptionManager.Encrypt(wrappedKey, secret)
+	if err != nil {
+		return nil, types.ErrInvalidEncryption.Wrapf("failed to wrap data key under passphrase: %s", err)
+	}
+
+	factor.Ciphertext = ciphertext
+	factor.Nonce = wrapped.Nonce
+	factor.EncryptionAlgo = wrapped.Algorithm
+
+	return wrapped.Data, nil
+}
+
+// SetPassphraseFactor protects the delivery of a new capsule to its recipient key with
+// a passphrase. The wrapped data key is sealed under the passphrase right away; the
+// data key of a timelocked capsule is sealed when it is delivered.
+func (k Keeper) SetPassphraseFactor(ctx context.Context, capsule *types.TimeCapsule, factor *types.PassphraseFactor) error {
+	if factor == nil {
+		return nil
+	}
+
+	if len(capsule.RecipientKey) == 0 {
+		return types.ErrInvalidRecipient.Wrap("passphrase factor needs a recipient key to protect")
+	}
+	if capsule.PassphraseFactor != nil {
+		return types.ErrInvalidPassphrase.Wrapf("capsule %d already has a passphrase factor", capsule.ID)
+	}
+	if err := factor.Validate(); err != nil {
+		return types.ErrInvalidPassphrase.Wrap(err.Error())
+	}
+
+	capsule.PassphraseFactor = &types.PassphraseFactor{
+		KDF:       factor.KDF,
+		Salt:      factor.Salt,
+		N:         factor.N,
+		R:         factor.R,
+		P:         factor.P,
+		PublicKey: factor.PublicKey,
+	}
+
+	if capsule.HasDelivery() {
+		wrappedKey, err := k.sealPassphraseLayer(capsule, capsule.DeliveryWrappedKey)
+		if err != nil {
+			return err
+		}
+		capsule.DeliveryWrappedKey = wrappedKey
+	}
+
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return fmt.Errorf("failed to store capsule passphrase factor: %w", err)
+	}
+
+	return nil
+}
+
+// SetRecipientDelivery sets the key a capsule is delivered to and the executor that
+// may rotate its recipient
+func (k Keeper) SetRecipientDelivery(ctx context.Context, capsule *types.TimeCapsule, recipientKey []byte, executor string) error {
+	if len(recipientKey) == 0 && executor == "" {
+		return nil
+	}
+
+	if err := checkRecipientKey(capsule, recipientKey); err != nil {
+		return err
+	}
+	if err := types.ValidateExecutor(capsule.Owner, executor); err != nil {
+		return types.ErrInvalidAddress.Wrap(err.Error())
+	}
+
+	capsule.RecipientKey = recipientKey
+	capsule.Executor = executor
+
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return fmt.Errorf("failed to store capsule recipient key: %w", err)
+	}
+
+	return nil
+}
+
+// RotateRecipient re-targets an active capsule to a new recipient and recipient key.
+// Only the owner or the capsule's executor can rotate the recipient; the data key is
+// re-encrypted to the new key on delivery, so the plaintext is never needed.
+func (k Keeper) RotateRecipient(ctx context.Context, sender string, capsuleID uint64, newRecipient string, newRecipientKey []byte) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	capsule, err := k.GetCapsule(ctx, capsuleID)
+	if err != nil {
+		return err
+	}
+
+	if sender != capsule.Owner && (capsule.Executor == "" || sender != capsule.Executor) {
+		return types.ErrUnauthorized.Wrap("only the owner or executor can rotate the recipient")
+	}
+
+	if capsule.Status != types.CapsuleStatus_ACTIVE {
+		return types.ErrInvalidCapsule.Wrapf("cannot rotate recipient of capsule with status %s", capsule.Status.String())
+	}
+
+	if capsule.Frozen {
+		return types.ErrCapsuleFrozen.Wrapf("capsule %d cannot be rotated", capsuleID)
+	}
+
+	if newRecipient == capsule.Recipient && bytes.Equal(newRecipientKey, capsule.RecipientKey) {
+		return types.ErrInvalidRecipient.Wrap("new recipient is already the capsule recipient")
+	}
+
+	if err := checkRecipientKey(capsule, newRecipientKey); err != nil {
+		return err
+	}
+
+	// Without a recipient key the capsule would be decrypted on chain, bypassing the passphrase
+	if capsule.PassphraseFactor != nil && len(newRecipientKey) == 0 {
+		return types.ErrInvalidRecipient.Wrapf("capsule %d is protected by a passphrase and must keep a recipient key", capsuleID)
+	}
+
+	previous := capsule.Recipient
+	capsule.Recipient = newRecipient
+	capsule.RecipientKey = newRecipientKey
+	capsule.UpdatedAt = sdkCtx.BlockTime()
+
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return fmt.Errorf("failed to update capsule: %w", err)
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeRecipientRotated,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsuleID)),
+			sdk.NewAttribute(types.AttributeKeySender, sender),
+			sdk.NewAttribute(types.AttributeKeyRecipient, newRecipient),
+			sdk.NewAttribute(types.AttributeKeyPreviousRecipient, previous),
+			sdk.NewAttribute(types.AttributeKeyRecipientKey, hex.EncodeToString(newRecipientKey)),
+		),
+	)
+
+	k.logger.Info("Capsule recipient rotated",
+		"capsule_id", capsuleID,
+		"sender", sender,
+		"recipient", newRecipient,
+	)
+
+	return nil
+}
+
+// checkRecipientKey checks that a capsule can be delivered to a recipient key
+func checkRecipientKey(capsule *types.TimeCapsule, recipientKey []byte) error {
+	if len(recipientKey) == 0 {
+		return nil
+	}
+	if err := types.ValidateRecipientKey(recipientKey); err != nil {
+		return types.ErrInvalidRecipient.Wrap(err.Error())
+	}
+	if !capsule.HasDelivery() && !capsule.IsTimelocked() {
+		return types.ErrInvalidRecipient.Wrapf("capsule %d was sealed without a delivery key", capsule.ID)
+	}
+	return nil
+}
+
+// deliverToRecipient re-encrypts the data key of an unlocked capsule to its recipient key.
+// Custodians holding delivery key shares each contribute a re-encryption share, so the
+// delivery secret is transformed for the recipient without being reconstructed.
+func (k Keeper) deliverToRecipient(ctx context.Context, capsule *types.TimeCapsule) (*types.RecipientDelivery, error) {
+	delivery := &types.RecipientDelivery{
+		RecipientKey: capsule.RecipientKey,
+		DeliveredAt:  sdk.UnwrapSDKContext(ctx).BlockTime(),
+	}
+
+	// The data key of a timelocked capsule is public once its epoch is released
+	if capsule.IsTimelocked() {
+		encryptionKey, err := k.openTimelockKey(ctx, capsule)
+		if err != nil {
+			return nil, err
+		}
+		defer crypto.WipeKey(encryptionKey)
+
+		secret, ciphertext, err := crypto.NewDeliverySecret(capsule.RecipientKey)
+		if err != nil {
+			return nil, types.ErrInvalidRecipient.Wrapf("failed to encrypt to recipient key: %s", err)
+		}
+		defer crypto.WipeKey(secret)
+
+		wrapped, err := k.encryptionManager.Encrypt(encryptionKey, secret)
+		if err != nil {
+			return nil, types.ErrInvalidEncryption.Wrapf("failed to wrap data key: %s", err)
+		}
+
+		wrappedKey, err := k.sealPassphraseLayer(capsule, wrapped.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		delivery.Ciphertext = ciphertext
+		delivery.WrappedKey = wrappedKey
+		delivery.WrappedKeyNonce = wrapped.Nonce
+		delivery.EncryptionAlgo = wrapped.Algorithm
+		return delivery, nil
+	}
+
+	if !capsule.HasDelivery() {
+		return nil, types.ErrInvalidRecipient.Wrapf("capsule %d was sealed without a delivery key", capsule.ID)
+	}
+
+	keyShares, err := k.getCapsuleKeyShares(ctx, capsule.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Live custodians respond first; shares of departed custodians are a fallback
+	var live, departed []types.KeyShare
+	for _, keyShare := range keyShares {
+		if len(keyShare.DeliveryShare) == 0 {
+			continue
+		}
+		if k.isLiveCustodian(ctx, keyShare.NodeID) {
+			live = append(live, keyShare)
+		} else {
+			departed = append(departed, keyShare)
+		}
+	}
+
+	var reencrypted []*crypto.ReEncryptionShare
+	for _, keyShare := range append(live, departed...) {
+		if len(reencrypted) == int(capsule.Threshold) {
+			break
+		}
+
+		share, err := crypto.BytesToShare(keyShare.DeliveryShare)
+		if err != nil {
+			k.logger.Error("Invalid delivery share", "capsule_id", capsule.ID, "share_index", keyShare.ShareIndex, "error", err)
+			continue
+		}
+
+		reencryptedShare, err := crypto.ReEncryptDeliveryShare(share, capsule.DeliveryCiphertext, capsule.RecipientKey)
+		if err != nil {
+			k.logger.Error("Failed to re-encrypt delivery share", "capsule_id", capsule.ID, "share_index", keyShare.ShareIndex, "error", err)
+			continue
+		}
+		reencrypted = append(reencrypted, reencryptedShare)
+	}
+
+	if len(reencrypted) < int(capsule.Threshold) {
+		return nil, types.ErrInsufficientShares.Wrapf("need %d delivery shares, got %d", capsule.Threshold, len(reencrypted))
+	}
+
+	ciphertext, err := crypto.CombineReEncryption(capsule.DeliveryCiphertext, reencrypted)
+	if err != nil {
+		return nil, types.ErrInvalidKeyShare.Wrapf("failed to re-encrypt delivery secret: %s", err)
+	}
+
+	delivery.Ciphertext = ciphertext
+	delivery.WrappedKey = capsule.DeliveryWrappedKey
+	delivery.WrappedKeyNonce = capsule.DeliveryKeyNonce
+	delivery.EncryptionAlgo = capsule.EncryptionAlgo
+	return delivery, nil
+}
+
+// refreshDeliveryShares recovers the delivery shares of reassigned custodians and
+// refreshes all delivery shares of a capsule alongside its key shares. Capsules sealed
+// without a delivery key are left untouched.
+func (k Keeper) refreshDeliveryShares(capsule *types.TimeCapsule, keyShares []types.KeyShare, live []int, reassigned map[int]string, seed []byte) error {
+	shares := make([]*crypto.Share, len(keyShares))
+	for i, keyShare := range keyShares {
+		if len(keyShare.DeliveryShare) == 0 {
+			return nil
+		}
+		share, err := crypto.BytesToShare(keyShare.DeliveryShare)
+		if err != nil {
+			return err
+		}
+		shares[i] = share
+	}
+
+	source := make([]*crypto.Share, capsule.Threshold)
+	for j := range source {
+		source[j] = shares[live[j]]
+	}
+
+	for i := range reassigned {
+		recovered, err := k.shamirSecretSharing.RecoverShare(source, keyShares[i].ShareIndex+1)
+		if err != nil {
+			return err
+		}
+		shares[i] = recovered
+	}
+
+	refreshed, _, err := k.shamirSecretSharing.RefreshShares(shares, nil, int(capsule.Threshold), seed)
+	if err != nil {
+		return err
+	}
+
+	for i := range keyShares {
+		keyShares[i].DeliveryShare = crypto.ShareToBytes(refreshed[i])
+	}
+
+	return nil
+}
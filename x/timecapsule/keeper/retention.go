@@ -0,0 +1,280 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cosmossdk.io/collections"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+const (
+	// maxExpiriesPerBlock bounds the capsules expired per block, the others stay queued
+	// for the following blocks
+	maxExpiriesPerBlock = 50
+
+	// maxPurgesPerBlock bounds the capsules whose data is purged per block
+	maxPurgesPerBlock = 50
+)
+
+// checkExpiry checks that a capsule expires in the future, within the maximum capsule duration
+func (k Keeper) checkExpiry(ctx context.Context, expiresAt *time.Time) error {
+	if expiresAt == nil {
+		return nil
+	}
+
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	blockTime := sdk.UnwrapSDKContext(ctx).BlockTime()
+	if !expiresAt.After(blockTime) {
+		return types.ErrInvalidExpiry.Wrapf("expiry %s must be after the block time %s", expiresAt.Format(time.RFC3339), blockTime.Format(time.RFC3339))
+	}
+	if expiresAt.After(blockTime.Add(params.MaxCapsuleDuration)) {
+		return types.ErrInvalidExpiry.Wrapf("expiry %s exceeds the maximum capsule duration %s", expiresAt.Format(time.RFC3339), params.MaxCapsuleDuration)
+	}
+
+	return nil
+}
+
+// ScheduleRetention queues an active capsule for its expiry, and a finished capsule for
+// the purge of its data. Capsules without an expiry, public reveal capsules and purged
+// capsules are not queued.
+func (k Keeper) ScheduleRetention(ctx context.Context, capsule *types.TimeCapsule) error {
+	if capsule.Status == types.CapsuleStatus_ACTIVE {
+		if capsule.ExpiresAt == nil {
+			return nil
+		}
+		return k.expiryQueue.Set(ctx, collections.Join(*capsule.ExpiresAt, capsule.ID))
+	}
+
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	purgeTime, ok := capsule.PurgeTime(params.UnlockedDataRetention)
+	if !ok {
+		return nil
+	}
+	return k.purgeQueue.Set(ctx, collections.Join(purgeTime, capsule.ID))
+}
+
+// dueQueueKeys returns and removes, earliest first, at most limit entries of a time queue
+// that are due at the block time, 0 for no limit
+func (k Keeper) dueQueueKeys(ctx context.Context, queue collections.KeySet[collections.Pair[time.Time, uint64]], limit int) ([]collections.Pair[time.Time, uint64], error) {
+	blockTime := sdk.UnwrapSDKContext(ctx).BlockTime()
+	rng := collections.NewPrefixUntilPairRange[time.Time, uint64](blockTime)
+	iter, err := queue.Iterate(ctx, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close the iterator before removing the entries it returned
+	var due []collections.Pair[time.Time, uint64]
+	for ; iter.Valid() && (limit == 0 || len(due) < limit); iter.Next() {
+		key, err := iter.Key()
+		if err != nil {
+			iter.Close()
+			return nil, err
+		}
+		due = append(due, key)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, key := range due {
+		if err := queue.Remove(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+
+	return due, nil
+}
+
+// processExpiryQueue expires the active capsules whose expiry is reached, refunds their
+// escrow and queues the purge of their data. Frozen capsules leave the queue and are
+// queued again once the guardians unfreeze them.
+func (k Keeper) processExpiryQueue(ctx context.Context) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	due, err := k.dueQueueKeys(ctx, k.expiryQueue, maxExpiriesPerBlock)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range due {
+		capsule, err := k.capsules.Get(ctx, key.K2())
+		if errors.Is(err, collections.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// Skip entries left behind by capsules that finished or changed their expiry
+		if capsule.Status != types.CapsuleStatus_ACTIVE || capsule.ExpiresAt == nil || !capsule.ExpiresAt.Equal(key.K1()) {
+			continue
+		}
+
+		// Frozen capsules are rescheduled when they are unfrozen, so they cannot hold up
+		// the queue
+		if capsule.Frozen {
+			continue
+		}
+
+		capsule.Status = types.CapsuleStatus_EXPIRED
+		capsule.UpdatedAt = sdkCtx.BlockTime()
+
+		// Return the escrow of an expired capsule to its owner
+		if _, err := k.refundEscrow(ctx, &capsule); err != nil {
+			return fmt.Errorf("failed to refund escrow of capsule %d: %w", capsule.ID, err)
+		}
+
+		if err := k.SetCapsule(ctx, &capsule); err != nil {
+			return fmt.Errorf("failed to update expired capsule %d: %w", capsule.ID, err)
+		}
+
+		if err := k.ScheduleRetention(ctx, &capsule); err != nil {
+			return err
+		}
+
+		sdkCtx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeCapsuleExpired,
+				sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsule.ID)),
+				sdk.NewAttribute(types.AttributeKeyOwner, capsule.Owner),
+				sdk.NewAttribute(types.AttributeKeyExpiresAt, capsule.ExpiresAt.Format(time.RFC3339)),
+			),
+		)
+	}
+
+	return nil
+}
+
+// processPurgeQueue purges the data of the finished capsules whose purge time is reached
+func (k Keeper) processPurgeQueue(ctx context.Context) error {
+	due, err := k.dueQueueKeys(ctx, k.purgeQueue, maxPurgesPerBlock)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range due {
+		capsule, err := k.capsules.Get(ctx, key.K2())
+		if errors.Is(err, collections.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if capsule.IsPurged() || capsule.Status == types.CapsuleStatus_ACTIVE {
+			continue
+		}
+
+		// Frozen capsules are under guardian review, keep their data until they are
+		// unfrozen and rescheduled
+		if capsule.Frozen {
+			continue
+		}
+
+		if err := k.purgeCapsule(ctx, &capsule, capsule.PurgeReason()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// EraseCapsule purges the data of a finished capsule at its owner's request, before its
+// retention period ends. Active capsules must be cancelled first.
+func (k Keeper) EraseCapsule(ctx context.Context, owner string, capsuleID uint64) error {
+	capsule, err := k.GetCapsule(ctx, capsuleID)
+	if err != nil {
+		return err
+	}
+
+	if capsule.Owner != owner {
+		return types.ErrUnauthorized.Wrap("only owner can erase capsule")
+	}
+
+	if capsule.Status == types.CapsuleStatus_ACTIVE {
+		return types.ErrInvalidCapsule.Wrap("active capsules must be cancelled before they are erased")
+	}
+
+	if capsule.IsPublicReveal() {
+		return types.ErrInvalidCapsuleType.Wrap("revealed content is public and cannot be erased")
+	}
+
+	if capsule.IsPurged() {
+		return types.ErrCapsulePurged.Wrapf("capsule %d was purged at height %d", capsuleID, capsule.Tombstone.PurgedHeight)
+	}
+
+	if capsule.Frozen {
+		return types.ErrCapsuleFrozen.Wrapf("capsule %d cannot be erased", capsuleID)
+	}
+
+	return k.purgeCapsule(ctx, capsule, types.PurgeReasonErasure)
+}
+
+// purgeCapsule deletes the ciphertext, key shares and superseded versions of a capsule and
+// leaves a tombstone keeping its data hash and history. It runs in the block hooks, so it
+// does not call the IPFS node: the purge event lists the IPFS content to unpin for the
+// pinning services that follow it.
+func (k Keeper) purgeCapsule(ctx context.Context, capsule *types.TimeCapsule, reason string) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	if err := k.removeKeyShares(ctx, capsule.ID); err != nil {
+		return err
+	}
+
+	// Purge the superseded versions, keeping their data hashes
+	unpin := []string{}
+	if capsule.IPFSHash != "" {
+		unpin = append(unpin, capsule.IPFSHash)
+	}
+	iter, err := k.capsuleVersions.Iterate(ctx, collections.NewPrefixedPairRange[uint64, uint32](capsule.ID))
+	if err != nil {
+		return err
+	}
+	versions, err := iter.KeyValues()
+	if err != nil {
+		return err
+	}
+	for _, entry := range versions {
+		version := entry.Value
+		if version.IPFSHash != "" {
+			unpin = append(unpin, version.IPFSHash)
+		}
+		version.Purge()
+		if err := k.capsuleVersions.Set(ctx, entry.Key, version); err != nil {
+			return fmt.Errorf("failed to purge version %d of capsule %d: %w", entry.Key.K2(), capsule.ID, err)
+		}
+	}
+
+	capsule.Purge(reason, sdkCtx.BlockTime(), sdkCtx.BlockHeight())
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return fmt.Errorf("failed to purge capsule %d: %w", capsule.ID, err)
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCapsulePurged,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsule.ID)),
+			sdk.NewAttribute(types.AttributeKeyOwner, capsule.Owner),
+			sdk.NewAttribute(types.AttributeKeyPurgeReason, reason),
+			sdk.NewAttribute(types.AttributeKeyDataHash, capsule.DataHash),
+			sdk.NewAttribute(types.AttributeKeyIPFSHashes, strings.Join(unpin, ",")),
+		),
+	)
+
+	return nil
+}
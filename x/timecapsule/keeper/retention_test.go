@@ -0,0 +1,97 @@
+package keeper_test
+
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+func (s *KeeperTestSuite) TestRetentionQueuesAreBatched() {
+	owner := sdk.AccAddress("owner_______________").String()
+	expiresAt := s.ctx.BlockTime().Add(-time.Hour)
+
+	// A frozen capsule due first must not hold up the capsules due after it
+	frozenExpiry := expiresAt.Add(-time.Hour)
+	frozen := &types.TimeCapsule{ID: 1, Owner: owner, Status: types.CapsuleStatus_ACTIVE, ExpiresAt: &frozenExpiry, Frozen: true}
+	s.Require().NoError(s.keeper.SetCapsule(s.ctx, frozen))
+	s.Require().NoError(s.keeper.ScheduleRetention(s.ctx, frozen))
+
+	const capsules = 120
+	for id := uint64(2); id <= capsules+1; id++ {
+		capsule := &types.TimeCapsule{
+			ID:        id,
+			Owner:     owner,
+			Status:    types.CapsuleStatus_ACTIVE,
+			ExpiresAt: &expiresAt,
+			IPFSHash:  fmt.Sprintf("Qm%d", id),
+		}
+		s.Require().NoError(s.keeper.SetCapsule(s.ctx, capsule))
+		s.Require().NoError(s.keeper.ScheduleRetention(s.ctx, capsule))
+	}
+
+	countFinished := func() (expired, purged int) {
+		for id := uint64(2); id <= capsules+1; id++ {
+			capsule, err := s.keeper.GetCapsule(s.ctx, id)
+			s.Require().NoError(err)
+			if capsule.Status == types.CapsuleStatus_EXPIRED {
+				expired++
+			}
+			if capsule.IsPurged() {
+				purged++
+			}
+		}
+		return expired, purged
+	}
+
+	// Each block expires, then purges, at most 50 capsules. The frozen capsule takes one
+	// expiry of the first block, and only that one.
+	for _, want := range []int{49, 99, 120} {
+		s.ctx = s.ctx.WithEventManager(sdk.NewEventManager())
+		s.Require().NoError(s.keeper.BeginBlocker(s.ctx))
+
+		expired, purged := countFinished()
+		s.Require().Equal(want, expired)
+		s.Require().Equal(want, purged)
+	}
+
+	// The frozen capsule left the queue and waits to be unfrozen
+	capsule, err := s.keeper.GetCapsule(s.ctx, 1)
+	s.Require().NoError(err)
+	s.Require().Equal(types.CapsuleStatus_ACTIVE, capsule.Status)
+}
+
+func (s *KeeperTestSuite) TestPurgeEventListsIPFSContent() {
+	owner := sdk.AccAddress("owner_______________").String()
+	capsule := &types.TimeCapsule{
+		ID:        1,
+		Owner:     owner,
+		Status:    types.CapsuleStatus_CANCELLED,
+		UpdatedAt: s.ctx.BlockTime(),
+		DataHash:  "abcd",
+		IPFSHash:  "QmCapsule",
+	}
+	s.Require().NoError(s.keeper.SetCapsule(s.ctx, capsule))
+	s.Require().NoError(s.keeper.ScheduleRetention(s.ctx, capsule))
+
+	s.ctx = s.ctx.WithEventManager(sdk.NewEventManager())
+	s.Require().NoError(s.keeper.BeginBlocker(s.ctx))
+
+	var found bool
+	for _, event := range s.ctx.EventManager().Events() {
+		if event.Type != types.EventTypeCapsulePurged {
+			continue
+		}
+		found = true
+		hashes, ok := event.GetAttribute(types.AttributeKeyIPFSHashes)
+		s.Require().True(ok)
+		s.Require().Equal("QmCapsule", hashes.Value)
+	}
+	s.Require().True(found)
+
+	purged, err := s.keeper.GetCapsule(s.ctx, 1)
+	s.Require().NoError(err)
+	s.Require().True(purged.IsPurged())
+}
@@ -0,0 +1,180 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cosmossdk.io/collections"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// CommitPublicCapsule creates a public reveal capsule. Only the commitment to its
+// content is stored, timestamped by the block it is included in, until anyone
+// reveals the content after the unlock time.
+func (k Keeper) CommitPublicCapsule(
+	ctx context.Context,
+	owner string,
+	commitment string,
+	unlockTime *time.Time,
+	tags []string,
+	metadata map[string]string,
+) (*types.TimeCapsule, error) {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	if _, err := k.addressCodec.StringToBytes(owner); err != nil {
+		return nil, types.ErrUnauthorized.Wrapf("invalid owner address: %s", err)
+	}
+
+	if err := crypto.ValidateCommitment(commitment); err != nil {
+		return nil, types.ErrInvalidCapsule.Wrap(err.Error())
+	}
+
+	if unlockTime == nil || !unlockTime.After(sdkCtx.BlockTime()) {
+		return nil, types.ErrInvalidTimelock.Wrap("public reveal capsule must unlock in the future")
+	}
+
+	capsuleID, err := k.capsuleCounter.Next(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next capsule ID: %w", err)
+	}
+
+	capsule := &types.TimeCapsule{
+		ID:              capsuleID,
+		Owner:           owner,
+		CapsuleType:     types.CapsuleType_PUBLIC_REVEAL,
+		Status:          types.CapsuleStatus_ACTIVE,
+		Version:         1,
+		VersionPolicy:   types.VersionPolicyLatest,
+		DataHash:        commitment,
+		StorageType:     types.StorageTypePublic,
+		UnlockTime:      unlockTime,
+		CommitHeight:    sdkCtx.BlockHeight(),
+		CommitBlockHash: sdkCtx.HeaderHash(),
+		CreatedAt:       sdkCtx.BlockTime(),
+		UpdatedAt:       sdkCtx.BlockTime(),
+		Tags:            tags,
+		Metadata:        metadata,
+	}
+
+	if err := capsule.Validate(); err != nil {
+		return nil, types.ErrInvalidCapsule.Wrapf("capsule validation failed: %s", err)
+	}
+
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return nil, fmt.Errorf("failed to store capsule: %w", err)
+	}
+
+	if err := k.userCapsules.Set(ctx, collections.Join(owner, capsuleID)); err != nil {
+		return nil, fmt.Errorf("failed to index user capsule: %w", err)
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCapsuleCreated,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsuleID)),
+			sdk.NewAttribute(types.AttributeKeyOwner, owner),
+			sdk.NewAttribute(types.AttributeKeyCapsuleType, capsule.CapsuleType.String()),
+			sdk.NewAttribute(types.AttributeKeyDataHash, commitment),
+		),
+	)
+
+	k.logger.Info("Public reveal capsule committed",
+		"capsule_id", capsuleID,
+		"owner", owner,
+		"commit_height", capsule.CommitHeight,
+	)
+
+	return capsule, nil
+}
+
+// RevealCapsule publishes the content of a public reveal capsule once its unlock time
+// has passed. Anyone may reveal; the content is only accepted if it opens the
+// capsule's commitment.
+func (k Keeper) RevealCapsule(ctx context.Context, revealer string, capsuleID uint64, data, salt []byte) (_ *types.TimeCapsule, err error) {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	capsule, err := k.GetCapsule(ctx, capsuleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Audit log: record every failed attempt on an existing capsule
+	defer func() {
+		if err != nil {
+			k.recordFailedAccess(ctx, capsuleID, revealer, err)
+		}
+	}()
+
+	if !capsule.IsPublicReveal() {
+		return nil, types.ErrInvalidCapsuleType.Wrapf("capsule %d is not a public reveal capsule", capsuleID)
+	}
+
+	if capsule.Status != types.CapsuleStatus_ACTIVE {
+		return nil, types.ErrCapsuleAlreadyOpened.Wrapf("capsule status is %s", capsule.Status.String())
+	}
+
+	if capsule.Frozen {
+		return nil, types.ErrCapsuleFrozen.Wrapf("capsule %d cannot be revealed", capsuleID)
+	}
+
+	if !capsule.IsUnlockable(sdkCtx) {
+		return nil, types.ErrConditionNotMet.Wrapf("capsule %d cannot be revealed before %s", capsuleID, capsule.UnlockTime)
+	}
+
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := types.ValidateReveal(data, salt, params.MaxDataSize); err != nil {
+		return nil, types.ErrInvalidReveal.Wrap(err.Error())
+	}
+
+	if crypto.CommitContent(data, salt) != capsule.DataHash {
+		return nil, types.ErrInvalidReveal.Wrap("content does not match the commitment")
+	}
+
+	capsule.Reveal = &types.PublicReveal{
+		Data:         data,
+		Salt:         salt,
+		RevealedBy:   revealer,
+		RevealHeight: sdkCtx.BlockHeight(),
+		RevealedAt:   sdkCtx.BlockTime(),
+	}
+	capsule.DataSize = int64(len(data))
+	capsule.Status = types.CapsuleStatus_UNLOCKED
+	capsule.UpdatedAt = sdkCtx.BlockTime()
+	openedAt := capsule.UpdatedAt
+	capsule.OpenedAt = &openedAt
+
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return nil, fmt.Errorf("failed to store revealed capsule: %w", err)
+	}
+
+	// Audit log: record the successful access
+	if err := k.recordAccess(ctx, capsuleID, revealer); err != nil {
+		return nil, err
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCapsuleRevealed,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsuleID)),
+			sdk.NewAttribute(types.AttributeKeyOwner, capsule.Owner),
+			sdk.NewAttribute(types.AttributeKeyRevealer, revealer),
+			sdk.NewAttribute(types.AttributeKeyDataHash, capsule.DataHash),
+			sdk.NewAttribute(types.AttributeKeyCommitHeight, fmt.Sprintf("%d", capsule.CommitHeight)),
+		),
+	)
+
+	k.logger.Info("Public reveal capsule revealed",
+		"capsule_id", capsuleID,
+		"revealer", revealer,
+		"data_size", len(data),
+	)
+
+	return capsule, nil
+}
@@ -0,0 +1,146 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cosmossdk.io/collections"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// GrantCapsuleAllowance stores the capsule allowance of a sponsor for a grantee,
+// replacing any earlier allowance of the pair along with its usage
+func (k Keeper) GrantCapsuleAllowance(ctx context.Context, allowance types.CapsuleAllowance) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	allowance.UsedCapsules = 0
+	allowance.UsedBytes = 0
+	if err := allowance.Validate(); err != nil {
+		return types.ErrInvalidAllowance.Wrap(err.Error())
+	}
+	if allowance.IsExpired(sdkCtx.BlockTime()) {
+		return types.ErrInvalidAllowance.Wrap("expiration must be in the future")
+	}
+
+	if err := k.SetCapsuleAllowance(ctx, allowance); err != nil {
+		return err
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCapsuleAllowanceGranted,
+			sdk.NewAttribute(types.AttributeKeySponsor, allowance.Sponsor),
+			sdk.NewAttribute(types.AttributeKeyGrantee, allowance.Grantee),
+		),
+	)
+
+	return nil
+}
+
+// RevokeCapsuleAllowance removes the capsule allowance of a sponsor for a grantee.
+// Capsules created under the allowance keep their sponsor.
+func (k Keeper) RevokeCapsuleAllowance(ctx context.Context, sponsor, grantee string) error {
+	key := collections.Join(sponsor, grantee)
+	has, err := k.capsuleAllowances.Has(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return types.ErrAllowanceNotFound.Wrapf("%s has no capsule allowance from %s", grantee, sponsor)
+	}
+
+	if err := k.capsuleAllowances.Remove(ctx, key); err != nil {
+		return err
+	}
+
+	sdk.UnwrapSDKContext(ctx).EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCapsuleAllowanceRevoked,
+			sdk.NewAttribute(types.AttributeKeySponsor, sponsor),
+			sdk.NewAttribute(types.AttributeKeyGrantee, grantee),
+		),
+	)
+
+	return nil
+}
+
+// SponsorCapsule charges a new capsule and its fees to the allowance its sponsor granted
+// the capsule creator and records the sponsor on the capsule. The sponsor pays the fees.
+// An allowance whose spend limit the fees used up is removed, as x/feegrant does.
+func (k Keeper) SponsorCapsule(ctx context.Context, capsule *types.TimeCapsule, sponsor, creator string, fees sdk.Coins) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	allowance, err := k.GetCapsuleAllowance(ctx, sponsor, creator)
+	if err != nil {
+		return err
+	}
+
+	spent, err := allowance.Use(sdkCtx.BlockTime(), capsule.DataSize, fees)
+	if err != nil {
+		return types.ErrAllowanceExceeded.Wrapf("allowance of %s from %s: %s", creator, sponsor, err)
+	}
+	if spent {
+		if err := k.RevokeCapsuleAllowance(ctx, sponsor, creator); err != nil {
+			return err
+		}
+	} else if err := k.SetCapsuleAllowance(ctx, *allowance); err != nil {
+		return err
+	}
+
+	capsule.Sponsor = sponsor
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return fmt.Errorf("failed to store capsule sponsor: %w", err)
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCapsuleSponsored,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsule.ID)),
+			sdk.NewAttribute(types.AttributeKeySponsor, sponsor),
+			sdk.NewAttribute(types.AttributeKeyGrantee, creator),
+		),
+	)
+
+	return nil
+}
+
+// GetCapsuleAllowance returns the capsule allowance a sponsor granted a grantee
+func (k Keeper) GetCapsuleAllowance(ctx context.Context, sponsor, grantee string) (*types.CapsuleAllowance, error) {
+	allowance, err := k.capsuleAllowances.Get(ctx, collections.Join(sponsor, grantee))
+	if errors.Is(err, collections.ErrNotFound) {
+		return nil, types.ErrAllowanceNotFound.Wrapf("%s has no capsule allowance from %s", grantee, sponsor)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &allowance, nil
+}
+
+// SetCapsuleAllowance stores a capsule allowance
+func (k Keeper) SetCapsuleAllowance(ctx context.Context, allowance types.CapsuleAllowance) error {
+	return k.capsuleAllowances.Set(ctx, collections.Join(allowance.Sponsor, allowance.Grantee), allowance)
+}
+
+// GetSponsorAllowances returns the capsule allowances granted by a sponsor
+func (k Keeper) GetSponsorAllowances(ctx context.Context, sponsor string) ([]types.CapsuleAllowance, error) {
+	var allowances []types.CapsuleAllowance
+	err := k.capsuleAllowances.Walk(ctx, collections.NewPrefixedPairRange[string, string](sponsor), func(_ collections.Pair[string, string], allowance types.CapsuleAllowance) (bool, error) {
+		allowances = append(allowances, allowance)
+		return false, nil
+	})
+	return allowances, err
+}
+
+// GetAllCapsuleAllowances returns all capsule allowances
+func (k Keeper) GetAllCapsuleAllowances(ctx context.Context) ([]types.CapsuleAllowance, error) {
+	var allowances []types.CapsuleAllowance
+	err := k.capsuleAllowances.Walk(ctx, nil, func(_ collections.Pair[string, string], allowance types.CapsuleAllowance) (bool, error) {
+		allowances = append(allowances, allowance)
+		return false, nil
+	})
+	return allowances, err
+}
@@ -0,0 +1,82 @@
+package keeper_test
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+func (s *KeeperTestSuite) TestSponsorCapsuleSpendsTheSpendLimit() {
+	sponsor := sdk.AccAddress("sponsor_____________").String()
+	creator := sdk.AccAddress("creator_____________").String()
+
+	s.Require().NoError(s.keeper.GrantCapsuleAllowance(s.ctx, types.CapsuleAllowance{
+		Sponsor:    sponsor,
+		Grantee:    creator,
+		SpendLimit: sdk.NewCoins(sdk.NewInt64Coin("stake", 250)),
+	}))
+
+	sponsorCapsule := func(id uint64, fees sdk.Coins) error {
+		capsule := &types.TimeCapsule{ID: id, Owner: creator, DataSize: 100}
+		s.Require().NoError(s.keeper.SetCapsule(s.ctx, capsule))
+		return s.keeper.SponsorCapsule(s.ctx, capsule, sponsor, creator, fees)
+	}
+
+	// The fees are subtracted from the spend limit
+	s.Require().NoError(sponsorCapsule(1, sdk.NewCoins(sdk.NewInt64Coin("stake", 100))))
+	allowance, err := s.keeper.GetCapsuleAllowance(s.ctx, sponsor, creator)
+	s.Require().NoError(err)
+	s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("stake", 150)), allowance.SpendLimit)
+	s.Require().Equal(uint32(1), allowance.UsedCapsules)
+
+	// Fees exceeding what is left, or in another denom, are rejected without using the allowance
+	err = sponsorCapsule(2, sdk.NewCoins(sdk.NewInt64Coin("stake", 200)))
+	s.Require().ErrorIs(err, types.ErrAllowanceExceeded)
+	err = sponsorCapsule(2, sdk.NewCoins(sdk.NewInt64Coin("atom", 1)))
+	s.Require().ErrorIs(err, types.ErrAllowanceExceeded)
+
+	allowance, err = s.keeper.GetCapsuleAllowance(s.ctx, sponsor, creator)
+	s.Require().NoError(err)
+	s.Require().Equal(sdk.NewCoins(sdk.NewInt64Coin("stake", 150)), allowance.SpendLimit)
+	s.Require().Equal(uint32(1), allowance.UsedCapsules)
+
+	// Spending the rest of the limit removes the allowance
+	s.Require().NoError(sponsorCapsule(2, sdk.NewCoins(sdk.NewInt64Coin("stake", 150))))
+	_, err = s.keeper.GetCapsuleAllowance(s.ctx, sponsor, creator)
+	s.Require().ErrorIs(err, types.ErrAllowanceNotFound)
+
+	capsule, err := s.keeper.GetCapsule(s.ctx, 2)
+	s.Require().NoError(err)
+	s.Require().Equal(sponsor, capsule.Sponsor)
+
+	err = sponsorCapsule(3, nil)
+	s.Require().ErrorIs(err, types.ErrAllowanceNotFound)
+}
+
+func (s *KeeperTestSuite) TestSponsorCapsuleWithoutSpendLimit() {
+	sponsor := sdk.AccAddress("sponsor_____________").String()
+	creator := sdk.AccAddress("creator_____________").String()
+
+	s.Require().NoError(s.keeper.GrantCapsuleAllowance(s.ctx, types.CapsuleAllowance{
+		Sponsor:     sponsor,
+		Grantee:     creator,
+		MaxCapsules: 2,
+	}))
+
+	for id := uint64(1); id <= 2; id++ {
+		capsule := &types.TimeCapsule{ID: id, Owner: creator}
+		s.Require().NoError(s.keeper.SetCapsule(s.ctx, capsule))
+		s.Require().NoError(s.keeper.SponsorCapsule(s.ctx, capsule, sponsor, creator, sdk.NewCoins(sdk.NewInt64Coin("stake", 1_000_000))))
+	}
+
+	// The allowance is kept once used up by its capsule limit
+	allowance, err := s.keeper.GetCapsuleAllowance(s.ctx, sponsor, creator)
+	s.Require().NoError(err)
+	s.Require().Empty(allowance.SpendLimit)
+	s.Require().Equal(uint32(2), allowance.UsedCapsules)
+
+	capsule := &types.TimeCapsule{ID: 3, Owner: creator}
+	s.Require().NoError(s.keeper.SetCapsule(s.ctx, capsule))
+	err = s.keeper.SponsorCapsule(s.ctx, capsule, sponsor, creator, nil)
+	s.Require().ErrorIs(err, types.ErrAllowanceExceeded)
+}
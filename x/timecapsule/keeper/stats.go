@@ -0,0 +1,131 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+
+	"cosmossdk.io/collections"
+	"github.com/hashicorp/go-metrics"
+
+	"github.com/cosmos/cosmos-sdk/telemetry"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// GetCapsuleAggregates returns the module-wide capsule statistics
+func (k Keeper) GetCapsuleAggregates(ctx context.Context) (types.CapsuleAggregates, error) {
+	stats, err := k.capsuleStats.Get(ctx)
+	if errors.Is(err, collections.ErrNotFound) {
+		return types.NewCapsuleAggregates(), nil
+	}
+	return stats, err
+}
+
+// SetCapsuleAggregates stores the module-wide capsule statistics
+func (k Keeper) SetCapsuleAggregates(ctx context.Context, stats types.CapsuleAggregates) error {
+	return k.capsuleStats.Set(ctx, stats)
+}
+
+// trackCapsuleWrite replaces the previous capsule's contribution to the capsule statistics
+// with the one of the capsule about to be written, and counts the write in the metrics.
+// Every capsule write goes through it, via SetCapsule.
+func (k Keeper) trackCapsuleWrite(ctx context.Context, previous *types.TimeCapsule, capsule types.TimeCapsule) error {
+	stats, err := k.GetCapsuleAggregates(ctx)
+	if err != nil {
+		return err
+	}
+
+	if previous != nil {
+		stats.Remove(*previous)
+	}
+	stats.Add(capsule)
+	if err := k.capsuleStats.Set(ctx, stats); err != nil {
+		return err
+	}
+
+	typeLabel := telemetry.NewLabel("type", capsule.CapsuleType.String())
+	switch {
+	case previous == nil:
+		telemetry.IncrCounterWithLabels(
+			[]string{types.ModuleName, "capsules", "created"}, 1,
+			[]metrics.Label{typeLabel, telemetry.NewLabel("storage_type", capsule.StorageType)},
+		)
+	case previous.Status != capsule.Status:
+		telemetry.IncrCounterWithLabels(
+			[]string{types.ModuleName, "capsules", "transitions"}, 1,
+			[]metrics.Label{typeLabel, telemetry.NewLabel("from", previous.Status.String()), telemetry.NewLabel("to", capsule.Status.String())},
+		)
+	}
+	return nil
+}
+
+// exportCapsuleMetrics exports the capsule statistics as gauges. The unlock latency
+// histogram is exported with cumulative "le" buckets, like a Prometheus histogram.
+func (k Keeper) exportCapsuleMetrics(ctx context.Context) error {
+	stats, err := k.GetCapsuleAggregates(ctx)
+	if err != nil {
+		return err
+	}
+
+	telemetry.SetGauge(float32(stats.Total), types.ModuleName, "capsules", "total")
+	telemetry.SetGauge(float32(stats.TotalBytes), types.ModuleName, "capsules", "data_bytes")
+
+	// Every type and status is exported, so counts that dropped to zero are reset
+	for t := types.CapsuleType_UNKNOWN; t <= types.CapsuleType_PUBLIC_REVEAL; t++ {
+		telemetry.SetGaugeWithLabels(
+			[]string{types.ModuleName, "capsules", "by_type"}, float32(stats.ByType[t.String()]),
+			[]metrics.Label{telemetry.NewLabel("type", t.String())},
+		)
+	}
+	for s := types.CapsuleStatus_UNKNOWN; s <= types.CapsuleStatus_CANCELLED; s++ {
+		telemetry.SetGaugeWithLabels(
+			[]string{types.ModuleName, "capsules", "by_status"}, float32(stats.ByStatus[s.String()]),
+			[]metrics.Label{telemetry.NewLabel("status", s.String())},
+		)
+	}
+	for _, storageType := range storageTypes(stats) {
+		telemetry.SetGaugeWithLabels(
+			[]string{types.ModuleName, "capsules", "by_storage"}, float32(stats.ByStorage[storageType]),
+			[]metrics.Label{telemetry.NewLabel("storage_type", storageType)},
+		)
+	}
+
+	var cumulative uint64
+	for i, count := range stats.UnlockLatency.Buckets {
+		cumulative += count
+		telemetry.SetGaugeWithLabels(
+			[]string{types.ModuleName, "unlock_latency_seconds", "bucket"}, float32(cumulative),
+			[]metrics.Label{telemetry.NewLabel("le", types.LatencyBucketLabel(i))},
+		)
+	}
+	telemetry.SetGauge(float32(stats.UnlockLatency.Count), types.ModuleName, "unlock_latency_seconds", "count")
+	telemetry.SetGauge(float32(stats.UnlockLatency.Sum), types.ModuleName, "unlock_latency_seconds", "sum")
+	return nil
+}
+
+// storageTypes returns the known storage types followed by any other stored one
+func storageTypes(stats types.CapsuleAggregates) []string {
+	known := []string{"blockchain", "ipfs", types.StorageTypePublic}
+	seen := make(map[string]bool, len(known))
+	for _, storageType := range known {
+		seen[storageType] = true
+	}
+
+	result := known
+	for storageType := range stats.ByStorage {
+		if !seen[storageType] {
+			result = append(result, storageType)
+		}
+	}
+	return result
+}
+
+// computeCapsuleAggregates derives the capsule statistics from the stored capsules
+func (k Keeper) computeCapsuleAggregates(ctx context.Context) (types.CapsuleAggregates, error) {
+	stats := types.NewCapsuleAggregates()
+	err := k.capsules.Walk(ctx, nil, func(_ uint64, capsule types.TimeCapsule) (bool, error) {
+		stats.Add(capsule)
+		return false, nil
+	})
+	return stats, err
+}
@@ -0,0 +1,119 @@
+package keeper
+
+import (
+	"context"
+
+	"cosmossdk.io/collections"
+
+	"github.com/cosmos/cosmos-sdk/types/query"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// indexCapsuleTags replaces the previous capsule's entries in the tag and metadata
+// indexes with the ones of the capsule about to be written. Every capsule write goes
+// through it, via SetCapsule, so ownership transfers move the entries to the new owner.
+func (k Keeper) indexCapsuleTags(ctx context.Context, previous *types.TimeCapsule, capsule types.TimeCapsule) error {
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	var previousOwner string
+	var previousTags, previousTerms []string
+	if previous != nil {
+		previousOwner = previous.Owner
+		previousTags = previous.Tags
+		previousTerms = previous.IndexedMetadata(params.IndexedMetadataKeys)
+	}
+
+	if err := updateOwnerIndex(ctx, k.capsuleTags, capsule.ID, previousOwner, previousTags, capsule.Owner, capsule.Tags); err != nil {
+		return err
+	}
+	return updateOwnerIndex(ctx, k.capsuleMetadata, capsule.ID, previousOwner, previousTerms, capsule.Owner, capsule.IndexedMetadata(params.IndexedMetadataKeys))
+}
+
+// updateOwnerIndex removes the (owner, term) entries of a capsule that are no longer
+// current and adds the new ones
+func updateOwnerIndex(
+	ctx context.Context,
+	index collections.KeySet[collections.Triple[string, string, uint64]],
+	capsuleID uint64,
+	previousOwner string,
+	previousTerms []string,
+	owner string,
+	terms []string,
+) error {
+	current := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		current[term] = true
+	}
+
+	for _, term := range previousTerms {
+		if previousOwner == owner && current[term] {
+			continue
+		}
+		if err := index.Remove(ctx, collections.Join3(previousOwner, term, capsuleID)); err != nil {
+			return err
+		}
+	}
+	for _, term := range terms {
+		if err := index.Set(ctx, collections.Join3(owner, term, capsuleID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetCapsulesByTag returns a page of an owner's capsules carrying a tag, or an indexed
+// metadata entry, that match the status and unlock time filters of the request
+func (k Keeper) GetCapsulesByTag(ctx context.Context, req *types.QueryCapsulesByTagRequest) ([]types.TimeCapsule, *query.PageResponse, error) {
+	if req.Owner == "" {
+		return nil, nil, types.ErrInvalidRequest.Wrap("owner cannot be empty")
+	}
+	if req.Tag == "" && req.MetadataKey == "" {
+		return nil, nil, types.ErrInvalidRequest.Wrap("either a tag or a metadata entry is required")
+	}
+	if (req.MetadataKey == "") != (req.MetadataValue == "") {
+		return nil, nil, types.ErrInvalidRequest.Wrap("metadata filter needs both a key and a value")
+	}
+	if req.UnlockAfter != nil && req.UnlockBefore != nil && !req.UnlockAfter.Before(*req.UnlockBefore) {
+		return nil, nil, types.ErrInvalidRequest.Wrap("unlock after must be before unlock before")
+	}
+
+	// Walk the tag index when a tag is given, the metadata is then filtered on the capsule
+	index, term := k.capsuleTags, req.Tag
+	if req.Tag == "" {
+		params, err := k.GetParams(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !params.IsIndexedMetadataKey(req.MetadataKey) {
+			return nil, nil, types.ErrInvalidRequest.Wrapf("metadata key %s is not indexed", req.MetadataKey)
+		}
+		index, term = k.capsuleMetadata, types.MetadataIndexTerm(req.MetadataKey, req.MetadataValue)
+	}
+
+	return query.CollectionFilteredPaginate(
+		ctx,
+		index,
+		req.Pagination,
+		func(key collections.Triple[string, string, uint64], _ collections.NoValue) (bool, error) {
+			capsule, err := k.capsules.Get(ctx, key.K3())
+			if err != nil {
+				return false, err
+			}
+			if req.Status != types.CapsuleStatus_UNKNOWN && capsule.Status != req.Status {
+				return false, nil
+			}
+			if req.MetadataKey != "" && capsule.Metadata[req.MetadataKey] != req.MetadataValue {
+				return false, nil
+			}
+			return capsule.UnlockTimeWithin(req.UnlockAfter, req.UnlockBefore), nil
+		},
+		func(key collections.Triple[string, string, uint64], _ collections.NoValue) (types.TimeCapsule, error) {
+			return k.capsules.Get(ctx, key.K3())
+		},
+		query.WithCollectionPaginationTripleSuperPrefix[string, string, uint64](req.Owner, term),
+	)
+}
@@ -0,0 +1,614 @@
+package keeper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// RegisterTimelockKey registers the key a validator receives timelock key-generation shares with
+func (k Keeper) RegisterTimelockKey(ctx context.Context, validator string, pubKey []byte) error {
+	if err := crypto.ValidateTimelockPoint(pubKey); err != nil {
+		return types.ErrInvalidTimelockKey.Wrapf("invalid public key: %s", err)
+	}
+
+	valAddr, err := sdk.ValAddressFromBech32(validator)
+	if err != nil {
+		return types.ErrInvalidAddress.Wrapf("invalid validator address: %s", err)
+	}
+
+	val, err := k.stakingKeeper.GetValidator(ctx, valAddr)
+	if err != nil {
+		return types.ErrInvalidTimelockKey.Wrapf("validator %s not found: %s", validator, err)
+	}
+
+	consAddr, err := val.GetConsAddr()
+	if err != nil {
+		return types.ErrInvalidTimelockKey.Wrapf("validator %s has no consensus key: %s", validator, err)
+	}
+
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+	key := types.TimelockValidatorKey{
+		Validator:    validator,
+		ConsAddress:  sdk.ConsAddress(consAddr).String(),
+		PublicKey:    pubKey,
+		RegisteredAt: sdkCtx.BlockTime(),
+	}
+
+	if err := k.timelockKeys.Set(ctx, key.ConsAddress, key); err != nil {
+		return fmt.Errorf("failed to store timelock key: %w", err)
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeTimelockKeyRegistered,
+			sdk.NewAttribute(types.AttributeKeyValidator, validator),
+		),
+	)
+
+	return nil
+}
+
+// GetTimelockEpoch retrieves a timelock epoch
+func (k Keeper) GetTimelockEpoch(ctx context.Context, epoch uint64) (*types.TimelockEpoch, error) {
+	e, err := k.timelockEpochs.Get(ctx, epoch)
+	if err != nil {
+		if errors.Is(err, collections.ErrNotFound) {
+			return nil, types.ErrTimelockEpochNotFound.Wrapf("epoch %d not found", epoch)
+		}
+		return nil, fmt.Errorf("failed to get timelock epoch: %w", err)
+	}
+	return &e, nil
+}
+
+// sealTimelockKey seals the data key of a time-locked capsule to the epoch covering its
+// unlock time. It reports false when no epoch key is ready yet, in which case the
+// capsule falls back to Shamir shares.
+func (k Keeper) sealTimelockKey(ctx context.Context, capsule *types.TimeCapsule, key []byte) (bool, error) {
+	if capsule.CapsuleType != types.CapsuleType_TIME_LOCK || capsule.UnlockTime == nil {
+		return false, nil
+	}
+
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	epochNum := types.TimelockEpochFor(*capsule.UnlockTime, params.TimelockEpochDuration)
+	epoch, err := k.timelockEpochs.Get(ctx, epochNum)
+	if errors.Is(err, collections.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get timelock epoch: %w", err)
+	}
+	if epoch.Status != types.TimelockEpochStatus_KEYED {
+		return false, nil
+	}
+
+	sealed, err := crypto.SealToTimelock(epoch.PublicKey, epochNum, key)
+	if err != nil {
+		return false, types.ErrInvalidEncryption.Wrapf("failed to seal key to timelock epoch %d: %s", epochNum, err)
+	}
+
+	capsule.TimelockEpoch = epochNum
+	capsule.TimelockKey = sealed
+
+	return true, nil
+}
+
+// openTimelockKey recovers the data key of a timelocked capsule from the released epoch secret
+func (k Keeper) openTimelockKey(ctx context.Context, capsule *types.TimeCapsule) ([]byte, error) {
+	return k.openSealedKey(ctx, capsule.TimelockEpoch, capsule.TimelockKey)
+}
+
+// openSealedKey recovers a data key sealed to a timelock epoch once the epoch secret is released
+func (k Keeper) openSealedKey(ctx context.Context, epochNum uint64, sealed []byte) ([]byte, error) {
+	epoch, err := k.GetTimelockEpoch(ctx, epochNum)
+	if err != nil {
+		return nil, err
+	}
+
+	if epoch.Status != types.TimelockEpochStatus_RELEASED {
+		return nil, types.ErrTimelockNotReleased.Wrapf("epoch %d is %s", epoch.Epoch, epoch.Status)
+	}
+
+	key, err := crypto.OpenTimelock(epoch.SecretKey, epoch.Epoch, sealed)
+	if err != nil {
+		return nil, types.ErrInvalidEncryption.Wrapf("failed to open timelock key: %s", err)
+	}
+
+	return key, nil
+}
+
+// advanceTimelockEpochs schedules key generation for upcoming epochs and closes the
+// dealing phase of epochs that collected enough dealings
+func (k Keeper) advanceTimelockEpochs(ctx context.Context) error {
+	if err := k.scheduleTimelockEpoch(ctx); err != nil {
+		return err
+	}
+	return k.finalizeTimelockEpochs(ctx)
+}
+
+// scheduleTimelockEpoch opens key generation for the next epoch within the maximum
+// capsule duration. One epoch is scheduled per block to bound the dealing load.
+func (k Keeper) scheduleTimelockEpoch(ctx context.Context) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := sdkCtx.BlockTime()
+	next := types.TimelockEpochFor(now, params.TimelockEpochDuration) + 1
+	horizon := types.TimelockEpochFor(now.Add(params.MaxCapsuleDuration), params.TimelockEpochDuration)
+
+	iter, err := k.timelockEpochs.Iterate(ctx, new(collections.Range[uint64]).Descending())
+	if err != nil {
+		return err
+	}
+	if iter.Valid() {
+		last, err := iter.Key()
+		if err != nil {
+			iter.Close()
+			return err
+		}
+		if last >= next {
+			next = last + 1
+		}
+	}
+	iter.Close()
+
+	if next > horizon {
+		return nil
+	}
+
+	participants, err := k.timelockParticipants(ctx)
+	if err != nil || len(participants) == 0 {
+		return err
+	}
+
+	epoch := types.TimelockEpoch{
+		Epoch:         next,
+		UnlockTime:    types.TimelockEpochTime(next, params.TimelockEpochDuration),
+		Status:        types.TimelockEpochStatus_DEALING,
+		Threshold:     types.TimelockThreshold(len(participants)),
+		Participants:  participants,
+		CreatedHeight: sdkCtx.BlockHeight(),
+	}
+
+	if err := k.timelockEpochs.Set(ctx, next, epoch); err != nil {
+		return fmt.Errorf("failed to store timelock epoch: %w", err)
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeTimelockEpochScheduled,
+			sdk.NewAttribute(types.AttributeKeyEpoch, fmt.Sprintf("%d", next)),
+			sdk.NewAttribute(types.AttributeKeyUnlockTime, epoch.UnlockTime.String()),
+		),
+	)
+
+	return nil
+}
+
+// timelockParticipants returns the bonded validators that registered a timelock key.
+// Nothing is returned until they hold more than two thirds of the bonded stake, so a
+// small subset of the validator set can never control an epoch key.
+func (k Keeper) timelockParticipants(ctx context.Context) ([]types.TimelockParticipant, error) {
+	validators, err := k.stakingKeeper.GetBondedValidatorsByPower(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var participants []types.TimelockParticipant
+	totalStake, registeredStake := math.ZeroInt(), math.ZeroInt()
+
+	for _, val := range validators {
+		totalStake = totalStake.Add(val.GetTokens())
+
+		consAddr, err := val.GetConsAddr()
+		if err != nil {
+			continue
+		}
+
+		key, err := k.timelockKeys.Get(ctx, sdk.ConsAddress(consAddr).String())
+		if errors.Is(err, collections.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		registeredStake = registeredStake.Add(val.GetTokens())
+		participants = append(participants, types.TimelockParticipant{
+			ConsAddress: key.ConsAddress,
+			Index:       uint32(len(participants) + 1),
+			PublicKey:   key.PublicKey,
+		})
+	}
+
+	if !registeredStake.MulRaw(3).GT(totalStake.MulRaw(2)) {
+		return nil, nil
+	}
+
+	return participants, nil
+}
+
+// finalizeTimelockEpochs keys epochs whose complaint window closed with a threshold of
+// qualified dealings, and fails epochs that reached their unlock time without a key
+func (k Keeper) finalizeTimelockEpochs(ctx context.Context) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := sdkCtx.BlockTime()
+	start := types.TimelockEpochFor(now, params.TimelockEpochDuration)
+	if start > 0 {
+		start--
+	}
+
+	var updated []types.TimelockEpoch
+	rng := new(collections.Range[uint64]).StartInclusive(start)
+	err = k.timelockEpochs.Walk(ctx, rng, func(_ uint64, epoch types.TimelockEpoch) (bool, error) {
+		if epoch.Status != types.TimelockEpochStatus_DEALING {
+			return false, nil
+		}
+
+		if !epoch.UnlockTime.After(now) {
+			epoch.Status = types.TimelockEpochStatus_FAILED
+			updated = append(updated, epoch)
+			return false, nil
+		}
+
+		dealings, err := k.qualifiedTimelockDealings(ctx, epoch.Epoch)
+		if err != nil {
+			return true, err
+		}
+
+		switch {
+		case len(dealings) < int(epoch.Threshold):
+			if epoch.DealingDeadline != 0 {
+				epoch.DealingDeadline = 0
+				updated = append(updated, epoch)
+			}
+		case epoch.DealingDeadline == 0:
+			epoch.DealingDeadline = sdkCtx.BlockHeight() + types.TimelockDealingWindow
+			updated = append(updated, epoch)
+		case sdkCtx.BlockHeight() >= epoch.DealingDeadline:
+			if err := keyTimelockEpoch(&epoch, dealings); err != nil {
+				k.logger.Error("failed to aggregate timelock dealings", "epoch", epoch.Epoch, "error", err)
+				return false, nil
+			}
+			epoch.KeyedHeight = sdkCtx.BlockHeight()
+			updated = append(updated, epoch)
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, epoch := range updated {
+		if err := k.timelockEpochs.Set(ctx, epoch.Epoch, epoch); err != nil {
+			return fmt.Errorf("failed to update timelock epoch: %w", err)
+		}
+
+		switch epoch.Status {
+		case types.TimelockEpochStatus_KEYED:
+			sdkCtx.EventManager().EmitEvent(
+				sdk.NewEvent(
+					types.EventTypeTimelockEpochKeyed,
+					sdk.NewAttribute(types.AttributeKeyEpoch, fmt.Sprintf("%d", epoch.Epoch)),
+				),
+			)
+		case types.TimelockEpochStatus_FAILED:
+			sdkCtx.EventManager().EmitEvent(
+				sdk.NewEvent(
+					types.EventTypeTimelockEpochFailed,
+					sdk.NewAttribute(types.AttributeKeyEpoch, fmt.Sprintf("%d", epoch.Epoch)),
+				),
+			)
+		}
+	}
+
+	return nil
+}
+
+// keyTimelockEpoch aggregates the qualified dealings into the epoch public key
+func keyTimelockEpoch(epoch *types.TimelockEpoch, dealings []types.TimelockDealing) error {
+	commitments := make([][][]byte, len(dealings))
+	dealers := make([]uint32, len(dealings))
+	for i, dealing := range dealings {
+		commitments[i] = dealing.Commitments
+		dealers[i] = dealing.Dealer
+	}
+
+	aggregated, err := crypto.AggregateTimelockCommitments(commitments)
+	if err != nil {
+		return err
+	}
+
+	epoch.Commitments = aggregated
+	epoch.PublicKey = aggregated[0]
+	epoch.Dealers = dealers
+	epoch.Status = types.TimelockEpochStatus_KEYED
+
+	return nil
+}
+
+// qualifiedTimelockDealings returns the dealings of an epoch that were not disqualified
+func (k Keeper) qualifiedTimelockDealings(ctx context.Context, epoch uint64) ([]types.TimelockDealing, error) {
+	var dealings []types.TimelockDealing
+
+	rng := collections.NewPrefixedPairRange[uint64, uint32](epoch)
+	err := k.timelockDealings.Walk(ctx, rng, func(_ collections.Pair[uint64, uint32], dealing types.TimelockDealing) (bool, error) {
+		if !dealing.Disqualified {
+			dealings = append(dealings, dealing)
+		}
+		return false, nil
+	})
+
+	return dealings, err
+}
+
+// VerifyTimelockVoteExtension checks the content of a validator's vote extension
+// against the current epoch state
+func (k Keeper) VerifyTimelockVoteExtension(ctx context.Context, consAddr string, ve types.TimelockVoteExtension) error {
+	for _, dealing := range ve.Dealings {
+		epoch, participant, err := k.timelockParticipant(ctx, dealing.Epoch, consAddr)
+		if err != nil {
+			return err
+		}
+		if epoch.Status != types.TimelockEpochStatus_DEALING {
+			return types.ErrInvalidTimelock.Wrapf("epoch %d is not dealing", epoch.Epoch)
+		}
+		if dealing.Dealer != participant.Index {
+			return types.ErrInvalidTimelock.Wrapf("dealer index %d does not match validator", dealing.Dealer)
+		}
+		if err := dealing.Validate(*epoch); err != nil {
+			return types.ErrInvalidTimelock.Wrap(err.Error())
+		}
+	}
+
+	for _, complaint := range ve.Complaints {
+		epoch, participant, err := k.timelockParticipant(ctx, complaint.Epoch, consAddr)
+		if err != nil {
+			return err
+		}
+		if complaint.Complainer != participant.Index {
+			return types.ErrInvalidTimelock.Wrapf("complainer index %d does not match validator", complaint.Complainer)
+		}
+		dealing, err := k.timelockDealings.Get(ctx, collections.Join(epoch.Epoch, complaint.Dealer))
+		if err != nil {
+			return types.ErrInvalidTimelock.Wrapf("no dealing from %d in epoch %d", complaint.Dealer, epoch.Epoch)
+		}
+		sealed := dealing.SealedShares[participant.Index-1]
+		if err := crypto.VerifyTimelockSharedKey(participant.PublicKey, sealed, complaint.SharedKey, complaint.Proof); err != nil {
+			return types.ErrInvalidTimelock.Wrapf("invalid complaint proof: %s", err)
+		}
+	}
+
+	for _, share := range ve.Shares {
+		epoch, participant, err := k.timelockParticipant(ctx, share.Epoch, consAddr)
+		if err != nil {
+			return err
+		}
+		if epoch.Status != types.TimelockEpochStatus_KEYED {
+			return types.ErrInvalidTimelock.Wrapf("epoch %d is not keyed", epoch.Epoch)
+		}
+		if share.Index != participant.Index {
+			return types.ErrInvalidTimelock.Wrapf("share index %d does not match validator", share.Index)
+		}
+		if err := crypto.VerifyTimelockShare(epoch.Commitments, share.Index, share.Share); err != nil {
+			return types.ErrInvalidKeyShare.Wrap(err.Error())
+		}
+	}
+
+	return nil
+}
+
+// timelockParticipant returns an epoch together with the participant entry of a validator
+func (k Keeper) timelockParticipant(ctx context.Context, epochNum uint64, consAddr string) (*types.TimelockEpoch, types.TimelockParticipant, error) {
+	epoch, err := k.GetTimelockEpoch(ctx, epochNum)
+	if err != nil {
+		return nil, types.TimelockParticipant{}, err
+	}
+
+	participant, found := epoch.Participant(consAddr)
+	if !found {
+		return nil, types.TimelockParticipant{}, types.ErrUnauthorized.Wrapf("%s is not a participant of epoch %d", consAddr, epochNum)
+	}
+
+	return epoch, participant, nil
+}
+
+// ApplyTimelockVoteExtension records the dealings and complaints a validator published in
+// its vote extension and returns its valid secret shares. Invalid entries are skipped so
+// a single faulty validator cannot halt the chain.
+func (k Keeper) ApplyTimelockVoteExtension(ctx context.Context, consAddr string, ve types.TimelockVoteExtension) ([]types.TimelockShare, error) {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	for _, dealing := range ve.Dealings {
+		epoch, participant, err := k.timelockParticipant(ctx, dealing.Epoch, consAddr)
+		if err != nil || epoch.Status != types.TimelockEpochStatus_DEALING || dealing.Dealer != participant.Index {
+			continue
+		}
+		if err := dealing.Validate(*epoch); err != nil {
+			k.logger.Debug("skipping invalid timelock dealing", "epoch", dealing.Epoch, "dealer", consAddr, "error", err)
+			continue
+		}
+
+		key := collections.Join(dealing.Epoch, dealing.Dealer)
+		has, err := k.timelockDealings.Has(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if has {
+			continue
+		}
+
+		dealing.Disqualified = false
+		dealing.Height = sdkCtx.BlockHeight()
+		if err := k.timelockDealings.Set(ctx, key, dealing); err != nil {
+			return nil, fmt.Errorf("failed to store timelock dealing: %w", err)
+		}
+	}
+
+	for _, complaint := range ve.Complaints {
+		if err := k.applyTimelockComplaint(ctx, consAddr, complaint); err != nil {
+			return nil, err
+		}
+	}
+
+	var shares []types.TimelockShare
+	for _, share := range ve.Shares {
+		epoch, participant, err := k.timelockParticipant(ctx, share.Epoch, consAddr)
+		if err != nil || epoch.Status != types.TimelockEpochStatus_KEYED || share.Index != participant.Index {
+			continue
+		}
+		if epoch.UnlockTime.After(sdkCtx.BlockTime()) {
+			continue
+		}
+		if err := crypto.VerifyTimelockShare(epoch.Commitments, share.Index, share.Share); err != nil {
+			continue
+		}
+		shares = append(shares, share)
+	}
+
+	return shares, nil
+}
+
+// applyTimelockComplaint disqualifies a dealer when the complainer proves that the share
+// it received does not match the dealer's commitments
+func (k Keeper) applyTimelockComplaint(ctx context.Context, consAddr string, complaint types.TimelockComplaint) error {
+	epoch, participant, err := k.timelockParticipant(ctx, complaint.Epoch, consAddr)
+	if err != nil || epoch.Status != types.TimelockEpochStatus_DEALING || complaint.Complainer != participant.Index {
+		return nil
+	}
+
+	key := collections.Join(complaint.Epoch, complaint.Dealer)
+	dealing, err := k.timelockDealings.Get(ctx, key)
+	if errors.Is(err, collections.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if dealing.Disqualified {
+		return nil
+	}
+
+	sealed := dealing.SealedShares[participant.Index-1]
+	if err := crypto.VerifyTimelockSharedKey(participant.PublicKey, sealed, complaint.SharedKey, complaint.Proof); err != nil {
+		return nil
+	}
+
+	share, err := crypto.OpenTimelockShareWithSharedKey(complaint.SharedKey, sealed)
+	if err == nil && crypto.VerifyTimelockShare(dealing.Commitments, participant.Index, share) == nil {
+		// The share was valid, the complaint is unfounded
+		return nil
+	}
+
+	dealing.Disqualified = true
+	if err := k.timelockDealings.Set(ctx, key, dealing); err != nil {
+		return fmt.Errorf("failed to disqualify timelock dealer: %w", err)
+	}
+
+	sdk.UnwrapSDKContext(ctx).EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeTimelockDealerDisqualified,
+			sdk.NewAttribute(types.AttributeKeyEpoch, fmt.Sprintf("%d", complaint.Epoch)),
+			sdk.NewAttribute(types.AttributeKeyDealer, fmt.Sprintf("%d", complaint.Dealer)),
+		),
+	)
+
+	return nil
+}
+
+// releaseTimelockEpochs reconstructs the secret of every epoch with a threshold of valid
+// shares and publishes it, making capsules sealed to that epoch decryptable by anyone
+func (k Keeper) releaseTimelockEpochs(ctx context.Context, shares []types.TimelockShare) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	byEpoch := make(map[uint64]map[uint32][]byte)
+	for _, share := range shares {
+		if byEpoch[share.Epoch] == nil {
+			byEpoch[share.Epoch] = make(map[uint32][]byte)
+		}
+		byEpoch[share.Epoch][share.Index] = share.Share
+	}
+
+	epochNums := make([]uint64, 0, len(byEpoch))
+	for epochNum := range byEpoch {
+		epochNums = append(epochNums, epochNum)
+	}
+	sort.Slice(epochNums, func(i, j int) bool { return epochNums[i] < epochNums[j] })
+
+	for _, epochNum := range epochNums {
+		epoch, err := k.GetTimelockEpoch(ctx, epochNum)
+		if err != nil {
+			return err
+		}
+		if epoch.Status != types.TimelockEpochStatus_KEYED || len(byEpoch[epochNum]) < int(epoch.Threshold) {
+			continue
+		}
+
+		indices := make([]uint32, 0, len(byEpoch[epochNum]))
+		for index := range byEpoch[epochNum] {
+			indices = append(indices, index)
+		}
+		sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+		indices = indices[:epoch.Threshold]
+
+		values := make([][]byte, len(indices))
+		for i, index := range indices {
+			values[i] = byEpoch[epochNum][index]
+		}
+
+		secret, err := crypto.CombineTimelockShares(indices, values)
+		if err != nil {
+			k.logger.Error("failed to combine timelock shares", "epoch", epochNum, "error", err)
+			continue
+		}
+
+		pubKey, err := crypto.TimelockPublicKey(secret)
+		if err != nil || !bytes.Equal(pubKey, epoch.PublicKey) {
+			k.logger.Error("reconstructed timelock secret does not match epoch key", "epoch", epochNum)
+			continue
+		}
+
+		epoch.SecretKey = secret
+		epoch.Status = types.TimelockEpochStatus_RELEASED
+		epoch.ReleasedHeight = sdkCtx.BlockHeight()
+
+		if err := k.timelockEpochs.Set(ctx, epochNum, *epoch); err != nil {
+			return fmt.Errorf("failed to release timelock epoch: %w", err)
+		}
+
+		sdkCtx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeTimelockEpochReleased,
+				sdk.NewAttribute(types.AttributeKeyEpoch, fmt.Sprintf("%d", epochNum)),
+			),
+		)
+
+		k.logger.Info("Timelock epoch released", "epoch", epochNum, "shares", len(byEpoch[epochNum]))
+	}
+
+	return nil
+}
@@ -0,0 +1,341 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"cosmossdk.io/collections"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// UpgradeEpochSource is an epoch source with one clock per upgrade plan. The clock of a
+// plan starts at the height the plan was applied, and each epoch lasts a fixed number
+// of blocks.
+type UpgradeEpochSource struct {
+	upgradeKeeper  types.UpgradeKeeper
+	blocksPerEpoch int64
+}
+
+var _ types.EpochSource = UpgradeEpochSource{}
+
+// NewUpgradeEpochSource creates an epoch source on the upgrade plans applied by the upgrade keeper
+func NewUpgradeEpochSource(upgradeKeeper types.UpgradeKeeper, blocksPerEpoch int64) UpgradeEpochSource {
+	if blocksPerEpoch <= 0 {
+		panic(fmt.Sprintf("blocks per epoch must be positive, got %d", blocksPerEpoch))
+	}
+	return UpgradeEpochSource{
+		upgradeKeeper:  upgradeKeeper,
+		blocksPerEpoch: blocksPerEpoch,
+	}
+}
+
+// EpochStartHeight implements types.EpochSource. The height is known once the plan is applied.
+func (s UpgradeEpochSource) EpochStartHeight(ctx context.Context, plan string, epoch uint64) (int64, bool, error) {
+	doneHeight, err := s.upgradeKeeper.GetDoneHeight(ctx, plan)
+	if err != nil {
+		return 0, false, err
+	}
+	if doneHeight == 0 {
+		return 0, false, nil
+	}
+
+	if epoch > uint64((math.MaxInt64-doneHeight)/s.blocksPerEpoch) {
+		return 0, false, fmt.Errorf("epoch %d of plan %s is out of range", epoch, plan)
+	}
+
+	return doneHeight + int64(epoch)*s.blocksPerEpoch, true, nil
+}
+
+// SetEpochSource registers an epoch source time-locked capsules can unlock on. Sources
+// must be registered when the app is wired, before any block is processed.
+func (k Keeper) SetEpochSource(name string, source types.EpochSource) {
+	if name == "" {
+		panic("epoch source name cannot be empty")
+	}
+	if _, ok := k.epochSources[name]; ok {
+		panic(fmt.Sprintf("epoch source %s already registered", name))
+	}
+	k.epochSources[name] = source
+}
+
+// checkUnlockTrigger checks the height or epoch trigger of a new time-locked capsule and
+// returns the height it unlocks at, or zero while its epoch trigger cannot be resolved
+func (k Keeper) checkUnlockTrigger(ctx context.Context, unlockHeight int64, unlockEpoch *types.EpochTrigger) (int64, error) {
+	currentHeight := sdk.UnwrapSDKContext(ctx).BlockHeight()
+
+	if unlockHeight != 0 && unlockHeight <= currentHeight {
+		return 0, types.ErrInvalidTimelock.Wrapf("unlock height %d must be above the current height %d", unlockHeight, currentHeight)
+	}
+
+	if unlockEpoch == nil {
+		return unlockHeight, nil
+	}
+
+	source, ok := k.epochSources[unlockEpoch.Source]
+	if !ok {
+		return 0, types.ErrInvalidTimelock.Wrapf("unknown epoch source %s", unlockEpoch.Source)
+	}
+
+	height, known, err := source.EpochStartHeight(ctx, unlockEpoch.Identifier, unlockEpoch.Epochs)
+	if err != nil {
+		return 0, types.ErrInvalidTimelock.Wrapf("failed to resolve epoch %s: %s", unlockEpoch, err)
+	}
+	if !known {
+		return 0, nil
+	}
+	if height <= currentHeight {
+		return 0, types.ErrInvalidTimelock.Wrapf("epoch %s already started at height %d", unlockEpoch, height)
+	}
+
+	return height, nil
+}
+
+// ScheduleUnlock queues an active time-locked capsule for its unlock height or time, or
+// waits for its epoch trigger to resolve to a height. Dead man's switches are queued for
+// the end of their inactivity period, and re-queued on every heartbeat.
+func (k Keeper) ScheduleUnlock(ctx context.Context, capsule *types.TimeCapsule) error {
+	if capsule.Status != types.CapsuleStatus_ACTIVE {
+		return nil
+	}
+
+	if unlockTime, ok := capsule.UnlockTimeTrigger(); ok {
+		return k.unlockTimeQueue.Set(ctx, collections.Join(unlockTime, capsule.ID))
+	}
+
+	if capsule.CapsuleType != types.CapsuleType_TIME_LOCK {
+		return nil
+	}
+
+	switch {
+	case capsule.UnlockHeight > 0:
+		return k.unlockQueue.Set(ctx, collections.Join(capsule.UnlockHeight, capsule.ID))
+	case capsule.UnlockEpoch != nil:
+		return k.epochUnlocks.Set(ctx, collections.Join3(capsule.UnlockEpoch.Source, capsule.UnlockEpoch.Identifier, capsule.ID))
+	}
+
+	return nil
+}
+
+// processUnlockQueue resolves pending epoch triggers and announces the capsules whose
+// unlock height or time is reached
+func (k Keeper) processUnlockQueue(ctx context.Context) error {
+	if err := k.resolveEpochUnlocks(ctx); err != nil {
+		return err
+	}
+
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+	rng := collections.NewPrefixUntilPairRange[int64, uint64](sdkCtx.BlockHeight())
+	iter, err := k.unlockQueue.Iterate(ctx, rng)
+	if err != nil {
+		return err
+	}
+	due, err := iter.Keys()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range due {
+		if err := k.unlockQueue.Remove(ctx, key); err != nil {
+			return err
+		}
+
+		capsule, err := k.capsules.Get(ctx, key.K2())
+		if errors.Is(err, collections.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !capsule.IsUnlockable(sdkCtx) {
+			continue
+		}
+
+		k.emitUnlockable(sdkCtx, &capsule)
+	}
+
+	return k.processUnlockTimeQueue(ctx)
+}
+
+// processUnlockTimeQueue announces the capsules whose unlock time or inactivity period
+// is reached. Unlock times are exclusive, so a capsule due at the block time, or frozen,
+// is retried in the next block.
+func (k Keeper) processUnlockTimeQueue(ctx context.Context) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	due, err := k.dueQueueKeys(ctx, k.unlockTimeQueue, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range due {
+		capsule, err := k.capsules.Get(ctx, key.K2())
+		if errors.Is(err, collections.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// Skip entries left behind by finished capsules and heartbeats
+		unlockTime, ok := capsule.UnlockTimeTrigger()
+		if !ok || !unlockTime.Equal(key.K1()) {
+			continue
+		}
+
+		if !capsule.IsUnlockable(sdkCtx) {
+			if err := k.unlockTimeQueue.Set(ctx, key); err != nil {
+				return err
+			}
+			continue
+		}
+
+		k.emitUnlockable(sdkCtx, &capsule)
+	}
+
+	return nil
+}
+
+// emitUnlockable announces that a capsule reached its unlock trigger
+func (k Keeper) emitUnlockable(ctx sdk.Context, capsule *types.TimeCapsule) {
+	attrs := []sdk.Attribute{
+		sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsule.ID)),
+		sdk.NewAttribute(types.AttributeKeyOwner, capsule.Owner),
+		sdk.NewAttribute(types.AttributeKeyRecipient, capsule.Recipient),
+	}
+	if unlockTime, ok := capsule.UnlockTimeTrigger(); ok {
+		attrs = append(attrs, sdk.NewAttribute(types.AttributeKeyUnlockTime, unlockTime.Format(time.RFC3339)))
+	} else {
+		attrs = append(attrs, sdk.NewAttribute(types.AttributeKeyUnlockHeight, fmt.Sprintf("%d", capsule.UnlockHeight)))
+	}
+
+	ctx.EventManager().EmitEvent(sdk.NewEvent(types.EventTypeCapsuleUnlockable, attrs...))
+}
+
+// maxEpochUnlocksPerBlock bounds the epoch triggers resolved per block. Triggers left
+// over are resolved in the next blocks.
+const maxEpochUnlocksPerBlock = 100
+
+// epochClocksAfter ranges over the pending epoch triggers of the clocks after a clock
+type epochClocksAfter struct {
+	clock collections.Triple[string, string, uint64]
+}
+
+func (r epochClocksAfter) RangeValues() (start, end *collections.RangeKey[collections.Triple[string, string, uint64]], order collections.Order, err error) {
+	return collections.RangeKeyPrefixEnd(r.clock), nil, collections.OrderAscending, nil
+}
+
+// resolveEpochUnlocks records the unlock height of capsules whose epoch source can tell
+// the height their epoch starts at, and moves them to the unlock queue. Pending triggers
+// are keyed by epoch source and clock, so a clock that has not started, such as an
+// upgrade plan that is not applied yet, is checked once per block and skipped as a whole.
+func (k Keeper) resolveEpochUnlocks(ctx context.Context) error {
+	var rng collections.Ranger[collections.Triple[string, string, uint64]]
+
+	budget := maxEpochUnlocksPerBlock
+	for budget > 0 {
+		iter, err := k.epochUnlocks.Iterate(ctx, rng)
+		if err != nil {
+			return err
+		}
+		if !iter.Valid() {
+			iter.Close()
+			return nil
+		}
+		key, err := iter.Key()
+		iter.Close()
+		if err != nil {
+			return err
+		}
+
+		sourceName, identifier := key.K1(), key.K2()
+		clock := collections.TripleSuperPrefix[string, string, uint64](sourceName, identifier)
+		rng = epochClocksAfter{clock: clock}
+
+		source, ok := k.epochSources[sourceName]
+		if !ok {
+			continue
+		}
+		if _, started, err := source.EpochStartHeight(ctx, identifier, 0); err != nil || !started {
+			if err != nil {
+				k.logger.Error("Failed to check epoch clock", "source", sourceName, "identifier", identifier, "error", err)
+			}
+			continue
+		}
+
+		var pending []collections.Triple[string, string, uint64]
+		err = k.epochUnlocks.Walk(ctx, collections.NewSuperPrefixedTripleRange[string, string, uint64](sourceName, identifier), func(key collections.Triple[string, string, uint64]) (bool, error) {
+			pending = append(pending, key)
+			return len(pending) == budget, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range pending {
+			if err := k.resolveEpochUnlock(ctx, source, key); err != nil {
+				return err
+			}
+		}
+		budget -= len(pending)
+	}
+
+	return nil
+}
+
+// resolveEpochUnlock resolves the epoch trigger of a capsule on a started clock
+func (k Keeper) resolveEpochUnlock(ctx context.Context, source types.EpochSource, key collections.Triple[string, string, uint64]) error {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	capsule, err := k.capsules.Get(ctx, key.K3())
+	if err != nil && !errors.Is(err, collections.ErrNotFound) {
+		return err
+	}
+	if err != nil || capsule.Status != types.CapsuleStatus_ACTIVE || capsule.UnlockEpoch == nil {
+		return k.epochUnlocks.Remove(ctx, key)
+	}
+
+	height, known, err := source.EpochStartHeight(ctx, key.K2(), capsule.UnlockEpoch.Epochs)
+	if err != nil {
+		// The clock started, so the epoch will never resolve
+		k.logger.Error("Failed to resolve unlock epoch", "capsule_id", capsule.ID, "epoch", capsule.UnlockEpoch.String(), "error", err)
+		return k.epochUnlocks.Remove(ctx, key)
+	}
+	if !known {
+		return nil
+	}
+
+	capsule.UnlockHeight = height
+	capsule.UpdatedAt = sdkCtx.BlockTime()
+	if err := k.SetCapsule(ctx, &capsule); err != nil {
+		return fmt.Errorf("failed to update capsule %d: %w", capsule.ID, err)
+	}
+
+	if err := k.epochUnlocks.Remove(ctx, key); err != nil {
+		return err
+	}
+
+	// An epoch that already started unlocks the capsule in this block
+	queueHeight := height
+	if queueHeight < sdkCtx.BlockHeight() {
+		queueHeight = sdkCtx.BlockHeight()
+	}
+	if err := k.unlockQueue.Set(ctx, collections.Join(queueHeight, capsule.ID)); err != nil {
+		return err
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeUnlockEpochResolved,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsule.ID)),
+			sdk.NewAttribute(types.AttributeKeyUnlockEpoch, capsule.UnlockEpoch.String()),
+			sdk.NewAttribute(types.AttributeKeyUnlockHeight, fmt.Sprintf("%d", height)),
+		),
+	)
+
+	return nil
+}
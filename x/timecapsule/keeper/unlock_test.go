@@ -0,0 +1,66 @@
+package keeper_test
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/keeper"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// mockUpgradeKeeper serves the done heights of applied plans and counts the lookups
+type mockUpgradeKeeper struct {
+	doneHeights map[string]int64
+	lookups     int
+}
+
+func (m *mockUpgradeKeeper) GetDoneHeight(_ context.Context, name string) (int64, error) {
+	m.lookups++
+	return m.doneHeights[name], nil
+}
+
+func (s *KeeperTestSuite) TestEpochUnlocksResolveOnAppliedPlan() {
+	upgradeKeeper := &mockUpgradeKeeper{doneHeights: make(map[string]int64)}
+	s.keeper.SetEpochSource(types.EpochSourceUpgrade, keeper.NewUpgradeEpochSource(upgradeKeeper, 10))
+
+	// More capsules waiting on a plan than a block resolves, and one on another plan
+	const waiting = 150
+	schedule := func(id uint64, plan string) {
+		capsule := &types.TimeCapsule{
+			ID:          id,
+			Owner:       sdk.AccAddress("owner_______________").String(),
+			CapsuleType: types.CapsuleType_TIME_LOCK,
+			Status:      types.CapsuleStatus_ACTIVE,
+			UnlockEpoch: &types.EpochTrigger{Source: types.EpochSourceUpgrade, Identifier: plan, Epochs: 2},
+		}
+		s.Require().NoError(s.keeper.SetCapsule(s.ctx, capsule))
+		s.Require().NoError(s.keeper.ScheduleUnlock(s.ctx, capsule))
+	}
+	for id := uint64(1); id <= waiting; id++ {
+		schedule(id, "v2")
+	}
+	schedule(waiting+1, "v3")
+
+	unlockHeight := func(id uint64) int64 {
+		capsule, err := s.keeper.GetCapsule(s.ctx, id)
+		s.Require().NoError(err)
+		return capsule.UnlockHeight
+	}
+
+	// Plans that are not applied are checked once per block, not once per capsule
+	s.Require().NoError(s.keeper.BeginBlocker(s.ctx))
+	s.Require().Equal(2, upgradeKeeper.lookups)
+	s.Require().Zero(unlockHeight(1))
+
+	// Once applied, the capsules of the plan are resolved over several blocks
+	upgradeKeeper.doneHeights["v2"] = 5
+	s.Require().NoError(s.keeper.BeginBlocker(s.ctx))
+	s.Require().Equal(int64(25), unlockHeight(1))
+	s.Require().Equal(int64(25), unlockHeight(100))
+	s.Require().Zero(unlockHeight(101))
+
+	s.Require().NoError(s.keeper.BeginBlocker(s.ctx))
+	s.Require().Equal(int64(25), unlockHeight(waiting))
+	s.Require().Zero(unlockHeight(waiting + 1))
+}
@@ -0,0 +1,368 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+
+	"cosmossdk.io/collections"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/ipfs"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// SetVersionPolicy sets which versions the recipient of a capsule receives on open
+func (k Keeper) SetVersionPolicy(ctx context.Context, capsule *types.TimeCapsule, policy string) error {
+	if policy == "" || policy == capsule.VersionPolicy {
+		return nil
+	}
+
+	if err := types.ValidateVersionPolicy(policy); err != nil {
+		return types.ErrInvalidVersion.Wrap(err.Error())
+	}
+
+	capsule.VersionPolicy = policy
+
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return fmt.Errorf("failed to store capsule version policy: %w", err)
+	}
+
+	return nil
+}
+
+// GetCapsuleVersions returns the superseded versions of a capsule, oldest first
+func (k Keeper) GetCapsuleVersions(ctx context.Context, capsuleID uint64) ([]types.CapsuleVersion, error) {
+	var versions []types.CapsuleVersion
+
+	rng := collections.NewPrefixedPairRange[uint64, uint32](capsuleID)
+	err := k.capsuleVersions.Walk(ctx, rng, func(_ collections.Pair[uint64, uint32], version types.CapsuleVersion) (bool, error) {
+		versions = append(versions, version)
+		return false, nil
+	})
+
+	return versions, err
+}
+
+// AmendCapsule replaces the content of an active capsule with a new version encrypted
+// under a new data key. The superseded version stays hash-committed in the history.
+// Under the "all" version policy it also keeps its ciphertext and key material: a
+// sealed timelock key, or the key shares held for it, which are only combined when
+// the capsule opens.
+func (k Keeper) AmendCapsule(
+	ctx context.Context,
+	owner string,
+	capsuleID uint64,
+	data []byte,
+	compression string,
+	title string,
+	description string,
+	tags []string,
+	metadata map[string]string,
+) (*types.TimeCapsule, error) {
+	sdkCtx := sdk.UnwrapSDKContext(ctx)
+
+	capsule, err := k.GetCapsule(ctx, capsuleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if capsule.Owner != owner {
+		return nil, types.ErrUnauthorized.Wrap("only the capsule owner can amend it")
+	}
+
+	if capsule.IsPublicReveal() {
+		return nil, types.ErrInvalidCapsuleType.Wrap("public reveal commitments cannot be amended")
+	}
+
+	if capsule.Status != types.CapsuleStatus_ACTIVE {
+		return nil, types.ErrInvalidCapsule.Wrapf("cannot amend capsule with status %s", capsule.Status.String())
+	}
+
+	if capsule.Frozen {
+		return nil, types.ErrCapsuleFrozen.Wrapf("capsule %d cannot be amended", capsuleID)
+	}
+
+	currentVersion := capsule.CurrentVersion()
+	if currentVersion >= types.MaxCapsuleVersions {
+		return nil, types.ErrInvalidVersion.Wrapf("capsule %d reached the maximum of %d versions", capsuleID, types.MaxCapsuleVersions)
+	}
+
+	// Archive the current version
+	blockTime := sdkCtx.BlockTime()
+	archived := types.CapsuleVersion{
+		CapsuleID:        capsuleID,
+		Version:          currentVersion,
+		DataHash:         capsule.DataHash,
+		DataSize:         capsule.DataSize,
+		StorageType:      capsule.StorageType,
+		Title:            capsule.Title,
+		Description:      capsule.Description,
+		Tags:             capsule.Tags,
+		Metadata:         capsule.Metadata,
+		CreatedAt:        capsule.UpdatedAt,
+		SupersededAt:     blockTime,
+		SupersededHeight: sdkCtx.BlockHeight(),
+	}
+
+	if capsule.DeliversAllVersions() {
+		archived.EncryptedData = capsule.EncryptedData
+		archived.IPFSHash = capsule.IPFSHash
+		archived.EncryptionAlgo = capsule.EncryptionAlgo
+		archived.DataNonce = capsule.DataNonce
+		archived.Compression = capsule.Compression
+
+		if capsule.IsTimelocked() {
+			// The sealed key opens with the epoch secret, like the latest version
+			archived.TimelockEpoch = capsule.TimelockEpoch
+			archived.TimelockKey = capsule.TimelockKey
+		} else {
+			// The shares move with the version, so its key is never reconstructed before open
+			keyShares, err := k.getCapsuleKeyShares(ctx, capsuleID)
+			if err != nil {
+				return nil, err
+			}
+			for _, keyShare := range keyShares {
+				keyShare.DeliveryShare = nil
+				archived.KeyShares = append(archived.KeyShares, keyShare)
+			}
+			archived.ShareCommitments = capsule.ShareCommitments
+		}
+	}
+
+	// Encrypt the new version under a new data key
+	encryptionKey, err := k.encryptionManager.GenerateKey()
+	if err != nil {
+		return nil, types.ErrInvalidEncryption.Wrapf("failed to generate encryption key: %s", err)
+	}
+	defer crypto.WipeKey(encryptionKey)
+
+	encryptedData, err := k.encryptionManager.CompressAndEncrypt(data, encryptionKey, compression)
+	if err != nil {
+		return nil, types.ErrInvalidEncryption.Wrapf("failed to encrypt data: %s", err)
+	}
+
+	// Store the new ciphertext on-chain or on IPFS, like at creation
+	capsule.EncryptedData = nil
+	capsule.IPFSHash = ""
+	capsule.DataSize = int64(len(encryptedData.Data))
+	capsule.StorageType, _ = k.OptimizeStorageAllocation(capsule.DataSize)
+	if capsule.StorageType == "ipfs" {
+		storedMetadata, err := k.ipfsManager.StoreCapsuleData(ctx, capsuleID, encryptedData.Data, capsule)
+		if err != nil {
+			return nil, types.ErrDataStorageFailed.Wrapf("failed to store data on IPFS: %s", err)
+		}
+		capsule.IPFSHash = storedMetadata.Hash
+	} else {
+		capsule.EncryptedData = encryptedData.Data
+	}
+
+	capsule.DataHash = crypto.HashData(data)
+	capsule.EncryptionAlgo = encryptedData.Algorithm
+	capsule.DataNonce = encryptedData.Nonce
+	capsule.Compression = encryptedData.Compression
+	capsule.Version = currentVersion + 1
+	capsule.UpdatedAt = blockTime
+
+	// Empty fields keep the current values
+	if title != "" {
+		capsule.Title = title
+	}
+	if description != "" {
+		capsule.Description = description
+	}
+	if len(tags) > 0 {
+		capsule.Tags = tags
+	}
+	if len(metadata) > 0 {
+		capsule.Metadata = metadata
+	}
+
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := params.ValidateTagBounds(capsule.Tags, capsule.Metadata); err != nil {
+		return nil, types.ErrInvalidTags.Wrap(err.Error())
+	}
+
+	// Replace the key material of the superseded version
+	if err := k.removeKeyShares(ctx, capsuleID); err != nil {
+		return nil, err
+	}
+
+	capsule.TimelockEpoch = 0
+	capsule.TimelockKey = nil
+	capsule.ShareCommitments = nil
+	timelocked, err := k.sealTimelockKey(ctx, capsule, encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// The delivery key is re-dealt with the new data key, keeping the recipient key
+	deliveryShares, err := k.sealDelivery(capsule, encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if !timelocked {
+		shares, commitments, err := k.shamirSecretSharing.SplitSecretVerifiable(encryptionKey, int(capsule.Threshold), int(capsule.TotalShares))
+		if err != nil {
+			return nil, types.ErrInvalidKeyShare.Wrapf("failed to create key shares: %s", err)
+		}
+		capsule.ShareCommitments = commitments
+		if err := k.distributeKeyShares(ctx, capsuleID, shares, deliveryShares, encryptedData.Nonce); err != nil {
+			return nil, fmt.Errorf("failed to distribute key shares: %w", err)
+		}
+	}
+
+	if err := capsule.Validate(); err != nil {
+		return nil, types.ErrInvalidCapsule.Wrapf("capsule validation failed: %s", err)
+	}
+
+	if err := k.SetCapsuleVersion(ctx, archived); err != nil {
+		return nil, err
+	}
+
+	if err := k.SetCapsule(ctx, capsule); err != nil {
+		return nil, fmt.Errorf("failed to store amended capsule: %w", err)
+	}
+
+	sdkCtx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCapsuleUpdated,
+			sdk.NewAttribute(types.AttributeKeyCapsuleID, fmt.Sprintf("%d", capsuleID)),
+			sdk.NewAttribute(types.AttributeKeyOwner, owner),
+			sdk.NewAttribute(types.AttributeKeyVersion, fmt.Sprintf("%d", capsule.Version)),
+			sdk.NewAttribute(types.AttributeKeyDataHash, capsule.DataHash),
+		),
+	)
+
+	k.logger.Info("Time capsule amended",
+		"capsule_id", capsuleID,
+		"version", capsule.Version,
+		"data_size", len(data),
+	)
+
+	return capsule, nil
+}
+
+// openPreviousVersions decrypts the retained superseded versions of a capsule, newest
+// first, each with its own sealed timelock key or key shares
+func (k Keeper) openPreviousVersions(ctx context.Context, capsule *types.TimeCapsule) ([]types.VersionContent, error) {
+	versions, err := k.GetCapsuleVersions(ctx, capsule.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var contents []types.VersionContent
+	for i := len(versions) - 1; i >= 0; i-- {
+		version := versions[i]
+		if !version.IsRetained() {
+			break // Versions superseded under the "latest" policy are not recoverable
+		}
+
+		var key []byte
+		switch {
+		case len(version.TimelockKey) > 0:
+			key, err = k.openSealedKey(ctx, version.TimelockEpoch, version.TimelockKey)
+		case len(version.KeyShares) > 0:
+			key, err = k.combineVersionShares(version, capsule.Threshold)
+		default:
+			err = fmt.Errorf("no key material")
+		}
+		if err != nil {
+			return nil, types.ErrInvalidEncryption.Wrapf("failed to recover key of version %d: %s", version.Version, err)
+		}
+
+		content := &crypto.EncryptedData{
+			Data:        version.EncryptedData,
+			Nonce:       version.DataNonce,
+			Algorithm:   version.EncryptionAlgo,
+			Compression: version.Compression,
+		}
+		data, err := k.decryptContent(ctx, capsule.ID, content, version.IPFSHash, version.DataHash, key)
+		crypto.WipeKey(key)
+		if err != nil {
+			return nil, types.ErrInvalidEncryption.Wrapf("failed to decrypt version %d: %s", version.Version, err)
+		}
+
+		contents = append(contents, types.VersionContent{Version: version.Version, Data: data})
+	}
+
+	return contents, nil
+}
+
+// combineVersionShares reconstructs the data key of a superseded version from the key
+// shares kept with it, skipping shares that do not match its commitments
+func (k Keeper) combineVersionShares(version types.CapsuleVersion, threshold uint32) ([]byte, error) {
+	shares := make([]*crypto.Share, 0, len(version.KeyShares))
+	for _, keyShare := range version.KeyShares {
+		share, err := crypto.BytesToShare(keyShare.EncryptedShare)
+		if err != nil {
+			continue
+		}
+		if len(version.ShareCommitments) > 0 && crypto.VerifyShare(version.ShareCommitments, share) != nil {
+			continue
+		}
+		shares = append(shares, share)
+	}
+
+	if len(shares) < int(threshold) {
+		return nil, fmt.Errorf("need %d valid key shares, have %d", threshold, len(shares))
+	}
+
+	return k.shamirSecretSharing.CombineShares(shares[:threshold])
+}
+
+// decryptContent loads a version's ciphertext from the chain or IPFS, decrypts and
+// decompresses it and verifies it against the committed data hash
+func (k Keeper) decryptContent(ctx context.Context, capsuleID uint64, content *crypto.EncryptedData, ipfsHash, dataHash string, key []byte) ([]byte, error) {
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if ipfsHash != "" {
+		retrieved, err := k.ipfsManager.RetrieveCapsuleData(ctx, &ipfs.IPFSMetadata{Hash: ipfsHash, CapsuleID: capsuleID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve data from IPFS: %w", err)
+		}
+		content.Data = retrieved
+	}
+
+	plaintext, err := k.encryptionManager.DecryptAndDecompress(content, key, params.MaxDataSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if !crypto.VerifyDataIntegrity(plaintext, dataHash) {
+		return nil, fmt.Errorf("data integrity check failed")
+	}
+
+	return plaintext, nil
+}
+
+// removeKeyShares deletes the key shares of a capsule
+func (k Keeper) removeKeyShares(ctx context.Context, capsuleID uint64) error {
+	rng := collections.NewPrefixedPairRange[uint64, uint32](capsuleID)
+	iter, err := k.keyShares.Iterate(ctx, rng)
+	if err != nil {
+		return err
+	}
+	entries, err := iter.KeyValues()
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := k.keyShares.Remove(ctx, entry.Key); err != nil {
+			return fmt.Errorf("failed to remove key share %d: %w", entry.Key.K2(), err)
+		}
+		if err := k.custodianShares.Remove(ctx, collections.Join3(entry.Value.NodeID, capsuleID, entry.Key.K2())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
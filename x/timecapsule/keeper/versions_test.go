@@ -0,0 +1,58 @@
+package keeper_test
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// TestAmendKeepsVersionSharesUntilOpen checks that a capsule delivering all versions is
+// amended without any key shares, and that every version opens with the capsule
+func (s *KeeperTestSuite) TestAmendKeepsVersionSharesUntilOpen() {
+	owner := sdk.AccAddress("owner_______________").String()
+
+	// Capsule IDs start at one
+	s.Require().NoError(s.keeper.SetCapsuleCounter(s.ctx, 1))
+
+	capsule, err := s.keeper.CreateCapsule(s.ctx, owner, "", []byte("first will"), "",
+		types.CapsuleType_SAFE, 2, 3, nil, 0, nil, "", 0, 0, "", nil, nil, nil, nil)
+	s.Require().NoError(err)
+	s.Require().NoError(s.keeper.SetVersionPolicy(s.ctx, capsule, types.VersionPolicyAll))
+
+	firstShares, err := s.keeper.GetAllKeyShares(s.ctx)
+	s.Require().NoError(err)
+
+	for _, data := range []string{"second will", "third will"} {
+		_, err = s.keeper.AmendCapsule(s.ctx, owner, capsule.ID, []byte(data), "", "", "", nil, nil)
+		s.Require().NoError(err)
+	}
+
+	// The superseded versions keep the shares they were distributed with
+	versions, err := s.keeper.GetCapsuleVersions(s.ctx, capsule.ID)
+	s.Require().NoError(err)
+	s.Require().Len(versions, 2)
+	s.Require().Len(versions[0].KeyShares, 3)
+	s.Require().NotEmpty(versions[0].ShareCommitments)
+	for i, keyShare := range versions[0].KeyShares {
+		s.Require().Equal(firstShares[i].EncryptedShare, keyShare.EncryptedShare)
+	}
+
+	keyShares, err := s.keeper.GetAllKeyShares(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(keyShares, 3)
+	var shares []*crypto.Share
+	for _, keyShare := range keyShares[:2] {
+		share, err := crypto.BytesToShare(keyShare.EncryptedShare)
+		s.Require().NoError(err)
+		shares = append(shares, share)
+	}
+
+	data, previous, err := s.keeper.OpenCapsule(s.ctx, capsule.ID, owner, shares, nil)
+	s.Require().NoError(err)
+	s.Require().Equal([]byte("third will"), data)
+	s.Require().Equal([]types.VersionContent{
+		{Version: 2, Data: []byte("second will")},
+		{Version: 1, Data: []byte("first will")},
+	}, previous)
+}
@@ -0,0 +1,374 @@
+package keeper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cosmossdk.io/collections"
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// TimelockHandler runs timelock key generation and release through vote extensions.
+// Validators deal epoch key shares and publish their secret shares once an epoch is
+// due; the proposer injects the signed extensions into the block so that every node
+// applies them in PreBlocker.
+type TimelockHandler struct {
+	keeper   Keeper
+	valStore baseapp.ValidatorStore
+	proposal *baseapp.DefaultProposalHandler
+
+	// Local timelock key, nil when the node does not take part in key generation
+	privKey []byte
+	pubKey  []byte
+}
+
+// NewTimelockHandler creates a new timelock vote extension handler
+func NewTimelockHandler(
+	keeper Keeper,
+	valStore baseapp.ValidatorStore,
+	proposal *baseapp.DefaultProposalHandler,
+	privKey []byte,
+) *TimelockHandler {
+	h := &TimelockHandler{
+		keeper:   keeper,
+		valStore: valStore,
+		proposal: proposal,
+	}
+
+	if privKey != nil {
+		pubKey, err := crypto.TimelockPublicKey(privKey)
+		if err != nil {
+			keeper.logger.Error("invalid timelock key, not taking part in key generation", "error", err)
+		} else {
+			h.privKey, h.pubKey = privKey, pubKey
+		}
+	}
+
+	return h
+}
+
+// SetHandlers registers the vote extension and proposal handlers on the app
+func (h *TimelockHandler) SetHandlers(bApp *baseapp.BaseApp) {
+	bApp.SetExtendVoteHandler(h.ExtendVote())
+	bApp.SetVerifyVoteExtensionHandler(h.VerifyVoteExtension())
+	bApp.SetPrepareProposal(h.PrepareProposal())
+	bApp.SetProcessProposal(h.ProcessProposal())
+}
+
+// ExtendVote attaches the local validator's dealings, complaints and secret shares
+func (h *TimelockHandler) ExtendVote() sdk.ExtendVoteHandler {
+	return func(ctx sdk.Context, req *abci.RequestExtendVote) (*abci.ResponseExtendVote, error) {
+		ve := types.TimelockVoteExtension{Height: req.Height}
+
+		if h.privKey != nil {
+			built, err := h.keeper.BuildTimelockVoteExtension(ctx, h.privKey, h.pubKey, req.Height, req.Time)
+			if err != nil {
+				// Failing to extend must never keep the validator from voting
+				h.keeper.logger.Error("failed to build timelock vote extension", "height", req.Height, "error", err)
+			} else {
+				ve = built
+			}
+		}
+
+		bz, err := json.Marshal(ve)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode timelock vote extension: %w", err)
+		}
+
+		return &abci.ResponseExtendVote{VoteExtension: bz}, nil
+	}
+}
+
+// VerifyVoteExtension checks dealings and shares against the epoch commitments
+func (h *TimelockHandler) VerifyVoteExtension() sdk.VerifyVoteExtensionHandler {
+	return func(ctx sdk.Context, req *abci.RequestVerifyVoteExtension) (*abci.ResponseVerifyVoteExtension, error) {
+		var ve types.TimelockVoteExtension
+
+		if err := json.Unmarshal(req.VoteExtension, &ve); err != nil {
+			return &abci.ResponseVerifyVoteExtension{Status: abci.ResponseVerifyVoteExtension_REJECT}, nil
+		}
+
+		if ve.Height != req.Height {
+			return &abci.ResponseVerifyVoteExtension{Status: abci.ResponseVerifyVoteExtension_REJECT}, nil
+		}
+
+		consAddr := sdk.ConsAddress(req.ValidatorAddress).String()
+		if err := h.keeper.VerifyTimelockVoteExtension(ctx, consAddr, ve); err != nil {
+			h.keeper.logger.Debug("rejecting timelock vote extension", "validator", consAddr, "error", err)
+			return &abci.ResponseVerifyVoteExtension{Status: abci.ResponseVerifyVoteExtension_REJECT}, nil
+		}
+
+		return &abci.ResponseVerifyVoteExtension{Status: abci.ResponseVerifyVoteExtension_ACCEPT}, nil
+	}
+}
+
+// PrepareProposal injects the previous block's vote extensions as the first tx
+func (h *TimelockHandler) PrepareProposal() sdk.PrepareProposalHandler {
+	prepare := h.proposal.PrepareProposalHandler()
+
+	return func(ctx sdk.Context, req *abci.RequestPrepareProposal) (*abci.ResponsePrepareProposal, error) {
+		var injection []byte
+		if voteExtensionsEnabled(ctx, req.Height) && hasTimelockExtensions(req.LocalLastCommit) {
+			bz, err := types.EncodeTimelockInjection(req.LocalLastCommit)
+			if err != nil {
+				h.keeper.logger.Error("failed to encode timelock injection", "height", req.Height, "error", err)
+			} else {
+				injection = bz
+				req.MaxTxBytes -= int64(len(injection))
+			}
+		}
+
+		resp, err := prepare(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		if injection != nil {
+			resp.Txs = append([][]byte{injection}, resp.Txs...)
+		}
+
+		return resp, nil
+	}
+}
+
+// ProcessProposal verifies the signatures of injected vote extensions before handing the
+// remaining txs to the default handler
+func (h *TimelockHandler) ProcessProposal() sdk.ProcessProposalHandler {
+	process := h.proposal.ProcessProposalHandler()
+
+	return func(ctx sdk.Context, req *abci.RequestProcessProposal) (*abci.ResponseProcessProposal, error) {
+		if len(req.Txs) == 0 || !types.IsTimelockInjection(req.Txs[0]) {
+			return process(ctx, req)
+		}
+
+		commit, err := types.DecodeTimelockInjection(req.Txs[0])
+		if err != nil {
+			return &abci.ResponseProcessProposal{Status: abci.ResponseProcessProposal_REJECT}, nil
+		}
+
+		if err := baseapp.ValidateVoteExtensions(ctx, h.valStore, req.Height, ctx.ChainID(), *commit); err != nil {
+			h.keeper.logger.Debug("rejecting proposal with invalid timelock injection", "height", req.Height, "error", err)
+			return &abci.ResponseProcessProposal{Status: abci.ResponseProcessProposal_REJECT}, nil
+		}
+
+		// Leave the original request untouched, it is reused for optimistic execution
+		stripped := *req
+		stripped.Txs = req.Txs[1:]
+
+		return process(ctx, &stripped)
+	}
+}
+
+// ApplyTimelockInjection applies the vote extensions injected by the proposer. It runs in
+// the app's PreBlocker so that released epochs are visible to the block's transactions.
+func (k Keeper) ApplyTimelockInjection(ctx sdk.Context, req *abci.RequestFinalizeBlock) error {
+	if len(req.Txs) == 0 || !types.IsTimelockInjection(req.Txs[0]) {
+		return nil
+	}
+
+	commit, err := types.DecodeTimelockInjection(req.Txs[0])
+	if err != nil {
+		return err
+	}
+
+	var shares []types.TimelockShare
+	for _, vote := range commit.Votes {
+		if vote.BlockIdFlag != cmtproto.BlockIDFlagCommit || len(vote.VoteExtension) == 0 {
+			continue
+		}
+
+		var ve types.TimelockVoteExtension
+		if err := json.Unmarshal(vote.VoteExtension, &ve); err != nil {
+			continue
+		}
+
+		consAddr := sdk.ConsAddress(vote.Validator.Address).String()
+		validShares, err := k.ApplyTimelockVoteExtension(ctx, consAddr, ve)
+		if err != nil {
+			return err
+		}
+		shares = append(shares, validShares...)
+	}
+
+	return k.releaseTimelockEpochs(ctx, shares)
+}
+
+// BuildTimelockVoteExtension assembles the vote extension of the validator owning the
+// given timelock key. At most one dealing is produced per block to bound its size.
+func (k Keeper) BuildTimelockVoteExtension(ctx context.Context, privKey, pubKey []byte, height int64, blockTime time.Time) (types.TimelockVoteExtension, error) {
+	ve := types.TimelockVoteExtension{Height: height}
+
+	params, err := k.GetParams(ctx)
+	if err != nil {
+		return ve, err
+	}
+
+	start := types.TimelockEpochFor(blockTime, params.TimelockEpochDuration)
+	if start > 0 {
+		start--
+	}
+
+	dealt := false
+	rng := new(collections.Range[uint64]).StartInclusive(start)
+	err = k.timelockEpochs.Walk(ctx, rng, func(_ uint64, epoch types.TimelockEpoch) (bool, error) {
+		participant, found := epoch.ParticipantByKey(pubKey)
+		if !found {
+			return false, nil
+		}
+
+		switch epoch.Status {
+		case types.TimelockEpochStatus_DEALING:
+			if !dealt {
+				has, err := k.timelockDealings.Has(ctx, collections.Join(epoch.Epoch, participant.Index))
+				if err != nil {
+					return true, err
+				}
+				if !has {
+					dealing, err := dealTimelockEpoch(epoch, participant)
+					if err != nil {
+						return true, err
+					}
+					ve.Dealings = append(ve.Dealings, dealing)
+					dealt = true
+				}
+			}
+
+			complaints, err := k.timelockComplaints(ctx, epoch, participant, privKey, height)
+			if err != nil {
+				return true, err
+			}
+			ve.Complaints = append(ve.Complaints, complaints...)
+
+		case types.TimelockEpochStatus_KEYED:
+			if epoch.UnlockTime.After(blockTime) {
+				return false, nil
+			}
+
+			share, err := k.timelockSecretShare(ctx, epoch, participant, privKey)
+			if err != nil {
+				k.logger.Error("failed to derive timelock secret share", "epoch", epoch.Epoch, "error", err)
+				return false, nil
+			}
+			ve.Shares = append(ve.Shares, share)
+		}
+
+		return false, nil
+	})
+
+	return ve, err
+}
+
+// dealTimelockEpoch creates the local validator's dealing for an epoch
+func dealTimelockEpoch(epoch types.TimelockEpoch, dealer types.TimelockParticipant) (types.TimelockDealing, error) {
+	recipients := make([][]byte, len(epoch.Participants))
+	for i, p := range epoch.Participants {
+		recipients[i] = p.PublicKey
+	}
+
+	commitments, sealedShares, err := crypto.DealTimelockShares(int(epoch.Threshold), recipients)
+	if err != nil {
+		return types.TimelockDealing{}, fmt.Errorf("failed to deal epoch %d: %w", epoch.Epoch, err)
+	}
+
+	return types.TimelockDealing{
+		Epoch:        epoch.Epoch,
+		Dealer:       dealer.Index,
+		Commitments:  commitments,
+		SealedShares: sealedShares,
+	}, nil
+}
+
+// timelockComplaints checks the shares the local validator received in recent dealings
+// and produces a complaint for every one that does not match its dealer's commitments
+func (k Keeper) timelockComplaints(ctx context.Context, epoch types.TimelockEpoch, participant types.TimelockParticipant, privKey []byte, height int64) ([]types.TimelockComplaint, error) {
+	var complaints []types.TimelockComplaint
+
+	rng := collections.NewPrefixedPairRange[uint64, uint32](epoch.Epoch)
+	err := k.timelockDealings.Walk(ctx, rng, func(_ collections.Pair[uint64, uint32], dealing types.TimelockDealing) (bool, error) {
+		if dealing.Disqualified || dealing.Dealer == participant.Index || dealing.Height < height-types.TimelockDealingWindow {
+			return false, nil
+		}
+
+		sealed := dealing.SealedShares[participant.Index-1]
+		share, err := crypto.OpenTimelockShare(privKey, sealed)
+		if err == nil && crypto.VerifyTimelockShare(dealing.Commitments, participant.Index, share) == nil {
+			return false, nil
+		}
+
+		sharedKey, proof, err := crypto.ProveTimelockSharedKey(privKey, sealed)
+		if err != nil {
+			return true, err
+		}
+
+		complaints = append(complaints, types.TimelockComplaint{
+			Epoch:      epoch.Epoch,
+			Dealer:     dealing.Dealer,
+			Complainer: participant.Index,
+			SharedKey:  sharedKey,
+			Proof:      proof,
+		})
+		return false, nil
+	})
+
+	return complaints, err
+}
+
+// timelockSecretShare sums the shares the local validator received from the qualified
+// dealers of an epoch into its share of the epoch secret
+func (k Keeper) timelockSecretShare(ctx context.Context, epoch types.TimelockEpoch, participant types.TimelockParticipant, privKey []byte) (types.TimelockShare, error) {
+	received := make([][]byte, 0, len(epoch.Dealers))
+
+	for _, dealer := range epoch.Dealers {
+		dealing, err := k.timelockDealings.Get(ctx, collections.Join(epoch.Epoch, dealer))
+		if err != nil {
+			return types.TimelockShare{}, fmt.Errorf("missing dealing %d: %w", dealer, err)
+		}
+
+		share, err := crypto.OpenTimelockShare(privKey, dealing.SealedShares[participant.Index-1])
+		if err != nil {
+			return types.TimelockShare{}, fmt.Errorf("failed to open share from dealer %d: %w", dealer, err)
+		}
+		received = append(received, share)
+	}
+
+	secretShare, err := crypto.AddTimelockShares(received)
+	if err != nil {
+		return types.TimelockShare{}, err
+	}
+
+	return types.TimelockShare{
+		Epoch: epoch.Epoch,
+		Index: participant.Index,
+		Share: secretShare,
+	}, nil
+}
+
+// voteExtensionsEnabled checks whether the previous block carried vote extensions
+func voteExtensionsEnabled(ctx sdk.Context, height int64) bool {
+	cp := ctx.ConsensusParams()
+	return cp.Abci != nil && cp.Abci.VoteExtensionsEnableHeight != 0 && height > cp.Abci.VoteExtensionsEnableHeight
+}
+
+// hasTimelockExtensions checks whether any committed vote carries timelock content
+func hasTimelockExtensions(commit abci.ExtendedCommitInfo) bool {
+	for _, vote := range commit.Votes {
+		if vote.BlockIdFlag != cmtproto.BlockIDFlagCommit || len(vote.VoteExtension) == 0 {
+			continue
+		}
+
+		var ve types.TimelockVoteExtension
+		if err := json.Unmarshal(vote.VoteExtension, &ve); err == nil && !ve.IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
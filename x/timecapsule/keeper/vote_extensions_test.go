@@ -0,0 +1,197 @@
+package keeper_test
+
+import (
+	"encoding/json"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"cosmossdk.io/core/header"
+	"cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/keeper"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// timelockValidator is a bonded validator running a timelock vote extension handler
+type timelockValidator struct {
+	consAddr sdk.ConsAddress
+	handler  *keeper.TimelockHandler
+}
+
+// setupTimelockValidators bonds n validators of equal stake and registers their timelock keys
+func (s *KeeperTestSuite) setupTimelockValidators(n int) []timelockValidator {
+	validators := make([]timelockValidator, n)
+	for i := range validators {
+		consKey := ed25519.GenPrivKey().PubKey()
+		operator := sdk.ValAddress(consKey.Address()).String()
+
+		val, err := stakingtypes.NewValidator(operator, consKey, stakingtypes.Description{})
+		s.Require().NoError(err)
+		val.Status = stakingtypes.Bonded
+		val.Tokens = math.NewInt(100)
+		s.stakingKeeper.validators[operator] = val
+		s.stakingKeeper.bonded = append(s.stakingKeeper.bonded, val)
+
+		privKey, pubKey, err := crypto.GenerateTimelockKey()
+		s.Require().NoError(err)
+		s.Require().NoError(s.keeper.RegisterTimelockKey(s.ctx, operator, pubKey))
+
+		validators[i] = timelockValidator{
+			consAddr: sdk.ConsAddress(consKey.Address()),
+			handler:  keeper.NewTimelockHandler(s.keeper, nil, nil, privKey),
+		}
+	}
+	return validators
+}
+
+// finalizeTimelockBlock runs a block the way the app does: the vote extensions of the
+// previous block are applied in PreBlocker, the epochs advance in EndBlocker, and every
+// validator then extends its vote, which every validator verifies. It returns the
+// injection the next proposer builds from those votes.
+func (s *KeeperTestSuite) finalizeTimelockBlock(validators []timelockValidator, height int64, blockTime time.Time, injection []byte) []byte {
+	ctx := s.ctx.
+		WithBlockHeader(cmtproto.Header{Height: height, Time: blockTime}).
+		WithHeaderInfo(header.Info{Height: height, Time: blockTime})
+
+	req := &abci.RequestFinalizeBlock{Height: height, Time: blockTime}
+	if injection != nil {
+		req.Txs = [][]byte{injection}
+	}
+	s.Require().NoError(s.keeper.ApplyTimelockInjection(ctx, req))
+	s.Require().NoError(s.keeper.EndBlocker(ctx))
+
+	var commit abci.ExtendedCommitInfo
+	for _, val := range validators {
+		res, err := val.handler.ExtendVote()(ctx, &abci.RequestExtendVote{Height: height, Time: blockTime})
+		s.Require().NoError(err)
+
+		for _, verifier := range validators {
+			verified, err := verifier.handler.VerifyVoteExtension()(ctx, &abci.RequestVerifyVoteExtension{
+				Height:           height,
+				ValidatorAddress: val.consAddr,
+				VoteExtension:    res.VoteExtension,
+			})
+			s.Require().NoError(err)
+			s.Require().Equal(abci.ResponseVerifyVoteExtension_ACCEPT, verified.Status)
+		}
+
+		commit.Votes = append(commit.Votes, abci.ExtendedVoteInfo{
+			Validator:     abci.Validator{Address: val.consAddr, Power: 100},
+			VoteExtension: res.VoteExtension,
+			BlockIdFlag:   cmtproto.BlockIDFlagCommit,
+		})
+	}
+
+	bz, err := types.EncodeTimelockInjection(commit)
+	s.Require().NoError(err)
+	return bz
+}
+
+// TestTimelockVoteExtensions keys an epoch through the validators' dealings and releases
+// its secret through their shares once the epoch unlock time is reached
+func (s *KeeperTestSuite) TestTimelockVoteExtensions() {
+	validators := s.setupTimelockValidators(4)
+
+	params, err := s.keeper.GetParams(s.ctx)
+	s.Require().NoError(err)
+	epochNum := types.TimelockEpochFor(s.ctx.BlockTime(), params.TimelockEpochDuration) + 1
+
+	// The validators deal the next epoch, which is keyed once the complaint window closed
+	var injection []byte
+	height, blockTime := s.ctx.BlockHeight(), s.ctx.BlockTime()
+	for ; height < 2*types.TimelockDealingWindow; height++ {
+		injection = s.finalizeTimelockBlock(validators, height, blockTime, injection)
+		blockTime = blockTime.Add(5 * time.Second)
+
+		epoch, err := s.keeper.GetTimelockEpoch(s.ctx, epochNum)
+		if err == nil && epoch.Status == types.TimelockEpochStatus_KEYED {
+			break
+		}
+	}
+
+	epoch, err := s.keeper.GetTimelockEpoch(s.ctx, epochNum)
+	s.Require().NoError(err)
+	s.Require().Equal(types.TimelockEpochStatus_KEYED, epoch.Status)
+	s.Require().Len(epoch.Dealers, len(validators))
+	s.Require().Empty(epoch.SecretKey)
+
+	dataKey, err := crypto.SecureRandom(32)
+	s.Require().NoError(err)
+	sealed, err := crypto.SealToTimelock(epoch.PublicKey, epochNum, dataKey)
+	s.Require().NoError(err)
+
+	// No share is published before the unlock time
+	height++
+	injection = s.finalizeTimelockBlock(validators, height, blockTime, injection)
+	var ve types.TimelockVoteExtension
+	commit, err := types.DecodeTimelockInjection(injection)
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(commit.Votes[0].VoteExtension, &ve))
+	s.Require().Empty(ve.Shares)
+
+	// At the unlock time the validators publish their shares, applied in the next block
+	height++
+	injection = s.finalizeTimelockBlock(validators, height, epoch.UnlockTime, injection)
+
+	commit, err = types.DecodeTimelockInjection(injection)
+	s.Require().NoError(err)
+	s.Require().NoError(json.Unmarshal(commit.Votes[0].VoteExtension, &ve))
+	s.Require().Len(ve.Shares, 1)
+
+	// A share claimed by another validator is rejected
+	forged := ve
+	forged.Shares = []types.TimelockShare{ve.Shares[0]}
+	forged.Shares[0].Index++
+	bz, err := json.Marshal(forged)
+	s.Require().NoError(err)
+	res, err := validators[1].handler.VerifyVoteExtension()(s.ctx, &abci.RequestVerifyVoteExtension{
+		Height:           height,
+		ValidatorAddress: validators[0].consAddr,
+		VoteExtension:    bz,
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(abci.ResponseVerifyVoteExtension_REJECT, res.Status)
+
+	height++
+	s.finalizeTimelockBlock(validators, height, epoch.UnlockTime.Add(5*time.Second), injection)
+
+	released, err := s.keeper.GetTimelockEpoch(s.ctx, epochNum)
+	s.Require().NoError(err)
+	s.Require().Equal(types.TimelockEpochStatus_RELEASED, released.Status)
+	s.Require().Equal(height, released.ReleasedHeight)
+
+	pubKey, err := crypto.TimelockPublicKey(released.SecretKey)
+	s.Require().NoError(err)
+	s.Require().Equal(epoch.PublicKey, pubKey)
+
+	opened, err := crypto.OpenTimelock(released.SecretKey, epochNum, sealed)
+	s.Require().NoError(err)
+	s.Require().Equal(dataKey, opened)
+}
+
+// TestVerifyVoteExtensionRejectsMalformed checks that extensions which do not decode or
+// were built for another height are rejected
+func (s *KeeperTestSuite) TestVerifyVoteExtensionRejectsMalformed() {
+	validators := s.setupTimelockValidators(1)
+	verify := validators[0].handler.VerifyVoteExtension()
+
+	otherHeight, err := json.Marshal(types.TimelockVoteExtension{Height: 2})
+	s.Require().NoError(err)
+
+	for _, ext := range [][]byte{[]byte("not json"), otherHeight} {
+		res, err := verify(s.ctx, &abci.RequestVerifyVoteExtension{
+			Height:           1,
+			ValidatorAddress: validators[0].consAddr,
+			VoteExtension:    ext,
+		})
+		s.Require().NoError(err)
+		s.Require().Equal(abci.ResponseVerifyVoteExtension_REJECT, res.Status)
+	}
+}
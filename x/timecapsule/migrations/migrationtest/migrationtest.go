@@ -0,0 +1,40 @@
+// Package migrationtest holds the store fixtures shared by the tests of the time
+// capsule store migrations.
+package migrationtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/collections/colltest"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// Capsules returns the capsules map as laid out by the keeper
+func Capsules(sb *collections.SchemaBuilder) collections.Map[uint64, types.TimeCapsule] {
+	return collections.NewMap(sb, types.CapsuleKeyPrefix, "capsules", collections.Uint64Key, colltest.MockValueCodec[types.TimeCapsule]())
+}
+
+// SetCapsules stores capsules by ID
+func SetCapsules(t *testing.T, ctx context.Context, capsules collections.Map[uint64, types.TimeCapsule], stored ...types.TimeCapsule) {
+	t.Helper()
+
+	for _, capsule := range stored {
+		require.NoError(t, capsules.Set(ctx, capsule.ID, capsule))
+	}
+}
+
+// Keys returns the keys of a key set in order
+func Keys[K any](t *testing.T, ctx context.Context, keySet collections.KeySet[K]) []K {
+	t.Helper()
+
+	iter, err := keySet.Iterate(ctx, nil)
+	require.NoError(t, err)
+	keys, err := iter.Keys()
+	require.NoError(t, err)
+	return keys
+}
@@ -0,0 +1,31 @@
+package v2
+
+import (
+	"context"
+
+	"cosmossdk.io/collections"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// MigrateStore performs in-place store migrations from v1 to v2. The v2 store indexes
+// key shares by custodian and emergency actions by capsule; both indexes are built from
+// the stored key shares and emergency actions.
+func MigrateStore(
+	ctx context.Context,
+	keyShares collections.Map[collections.Pair[uint64, uint32], types.KeyShare],
+	custodianShares collections.KeySet[collections.Triple[string, uint64, uint32]],
+	emergencyActions collections.Map[string, types.EmergencyAction],
+	capsuleEmergencyActions collections.KeySet[collections.Pair[uint64, string]],
+) error {
+	err := keyShares.Walk(ctx, nil, func(_ collections.Pair[uint64, uint32], keyShare types.KeyShare) (bool, error) {
+		return false, custodianShares.Set(ctx, collections.Join3(keyShare.NodeID, keyShare.CapsuleID, keyShare.ShareIndex))
+	})
+	if err != nil {
+		return err
+	}
+
+	return emergencyActions.Walk(ctx, nil, func(_ string, action types.EmergencyAction) (bool, error) {
+		return false, capsuleEmergencyActions.Set(ctx, collections.Join(action.CapsuleID, action.ID))
+	})
+}
@@ -0,0 +1,107 @@
+package v2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/collections/colltest"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/migrations/migrationtest"
+	v2 "github.com/cosmos/cosmos-sdk/x/timecapsule/migrations/v2"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+func TestMigrateStore(t *testing.T) {
+	kv, ctx := colltest.MockStore()
+	sb := collections.NewSchemaBuilder(kv)
+
+	// v1 collections, as laid out by the keeper
+	keyShares := collections.NewMap(sb, types.KeySharesKeyPrefix, "key_shares", collections.PairKeyCodec(collections.Uint64Key, collections.Uint32Key), colltest.MockValueCodec[types.KeyShare]())
+	emergencyActions := collections.NewMap(sb, types.EmergencyActionsKeyPrefix, "emergency_actions", collections.StringKey, colltest.MockValueCodec[types.EmergencyAction]())
+
+	// v2 indexes
+	custodianShares := collections.NewKeySet(sb, types.CustodianSharesKeyPrefix, "custodian_shares", collections.TripleKeyCodec(collections.StringKey, collections.Uint64Key, collections.Uint32Key))
+	capsuleEmergencyActions := collections.NewKeySet(sb, types.CapsuleEmergencyActionsKeyPrefix, "capsule_emergency_actions", collections.PairKeyCodec(collections.Uint64Key, collections.StringKey))
+
+	_, err := sb.Build()
+	require.NoError(t, err)
+
+	shares := []types.KeyShare{
+		{CapsuleID: 1, ShareIndex: 0, NodeID: "node-a"},
+		{CapsuleID: 1, ShareIndex: 1, NodeID: "node-b"},
+		{CapsuleID: 1, ShareIndex: 2, NodeID: "node-c"},
+		{CapsuleID: 2, ShareIndex: 0, NodeID: "node-a"},
+		{CapsuleID: 2, ShareIndex: 1, NodeID: "node-c"},
+	}
+	for _, share := range shares {
+		require.NoError(t, keyShares.Set(ctx, collections.Join(share.CapsuleID, share.ShareIndex), share))
+	}
+
+	actions := []types.EmergencyAction{
+		{ID: "1-1", CapsuleID: 1, ActionType: "freeze"},
+		{ID: "2-1", CapsuleID: 2, ActionType: "force_unlock"},
+		{ID: "2-2", CapsuleID: 2, ActionType: "rotate_recipient"},
+	}
+	for _, action := range actions {
+		require.NoError(t, emergencyActions.Set(ctx, action.ID, action))
+	}
+
+	require.NoError(t, v2.MigrateStore(ctx, keyShares, custodianShares, emergencyActions, capsuleEmergencyActions))
+
+	// every key share is indexed by its custodian, and every index entry has a key share
+	for _, share := range shares {
+		has, err := custodianShares.Has(ctx, collections.Join3(share.NodeID, share.CapsuleID, share.ShareIndex))
+		require.NoError(t, err)
+		require.True(t, has)
+	}
+	custodianKeys := migrationtest.Keys(t, ctx, custodianShares)
+	require.Len(t, custodianKeys, len(shares))
+	for _, key := range custodianKeys {
+		share, err := keyShares.Get(ctx, collections.Join(key.K2(), key.K3()))
+		require.NoError(t, err)
+		require.Equal(t, key.K1(), share.NodeID)
+	}
+
+	// the shares of a custodian are found by prefix
+	iter, err := custodianShares.Iterate(ctx, collections.NewPrefixedTripleRange[string, uint64, uint32]("node-a"))
+	require.NoError(t, err)
+	nodeA, err := iter.Keys()
+	require.NoError(t, err)
+	require.Equal(t, []collections.Triple[string, uint64, uint32]{
+		collections.Join3("node-a", uint64(1), uint32(0)),
+		collections.Join3("node-a", uint64(2), uint32(0)),
+	}, nodeA)
+
+	// every emergency action is indexed by its capsule
+	actionKeys := migrationtest.Keys(t, ctx, capsuleEmergencyActions)
+	require.Len(t, actionKeys, len(actions))
+	for _, key := range actionKeys {
+		action, err := emergencyActions.Get(ctx, key.K2())
+		require.NoError(t, err)
+		require.Equal(t, key.K1(), action.CapsuleID)
+	}
+
+	// migrating an already migrated store changes nothing
+	require.NoError(t, v2.MigrateStore(ctx, keyShares, custodianShares, emergencyActions, capsuleEmergencyActions))
+	require.Equal(t, custodianKeys, migrationtest.Keys(t, ctx, custodianShares))
+	require.Equal(t, actionKeys, migrationtest.Keys(t, ctx, capsuleEmergencyActions))
+}
+
+func TestMigrateStoreEmpty(t *testing.T) {
+	kv, ctx := colltest.MockStore()
+	sb := collections.NewSchemaBuilder(kv)
+
+	keyShares := collections.NewMap(sb, types.KeySharesKeyPrefix, "key_shares", collections.PairKeyCodec(collections.Uint64Key, collections.Uint32Key), colltest.MockValueCodec[types.KeyShare]())
+	emergencyActions := collections.NewMap(sb, types.EmergencyActionsKeyPrefix, "emergency_actions", collections.StringKey, colltest.MockValueCodec[types.EmergencyAction]())
+	custodianShares := collections.NewKeySet(sb, types.CustodianSharesKeyPrefix, "custodian_shares", collections.TripleKeyCodec(collections.StringKey, collections.Uint64Key, collections.Uint32Key))
+	capsuleEmergencyActions := collections.NewKeySet(sb, types.CapsuleEmergencyActionsKeyPrefix, "capsule_emergency_actions", collections.PairKeyCodec(collections.Uint64Key, collections.StringKey))
+
+	_, err := sb.Build()
+	require.NoError(t, err)
+
+	require.NoError(t, v2.MigrateStore(ctx, keyShares, custodianShares, emergencyActions, capsuleEmergencyActions))
+	require.Empty(t, migrationtest.Keys(t, ctx, custodianShares))
+	require.Empty(t, migrationtest.Keys(t, ctx, capsuleEmergencyActions))
+}
@@ -0,0 +1,29 @@
+package v3
+
+import (
+	"context"
+
+	"cosmossdk.io/collections"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// MigrateStore performs in-place store migrations from v2 to v3. The v3 store holds
+// module-wide capsule statistics, which are derived from the stored capsules. Capsules
+// opened before v3 have no opening time and are left out of the unlock latency histogram.
+func MigrateStore(
+	ctx context.Context,
+	capsules collections.Map[uint64, types.TimeCapsule],
+	capsuleStats collections.Item[types.CapsuleAggregates],
+) error {
+	stats := types.NewCapsuleAggregates()
+	err := capsules.Walk(ctx, nil, func(_ uint64, capsule types.TimeCapsule) (bool, error) {
+		stats.Add(capsule)
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return capsuleStats.Set(ctx, stats)
+}
@@ -0,0 +1,85 @@
+package v3_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/collections/colltest"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/migrations/migrationtest"
+	v3 "github.com/cosmos/cosmos-sdk/x/timecapsule/migrations/v3"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+func TestMigrateStore(t *testing.T) {
+	kv, ctx := colltest.MockStore()
+	sb := collections.NewSchemaBuilder(kv)
+
+	// v2 capsules, as laid out by the keeper
+	capsules := migrationtest.Capsules(sb)
+
+	// v3 statistics
+	capsuleStats := collections.NewItem(sb, types.CapsuleStatsKey, "capsule_stats", colltest.MockValueCodec[types.CapsuleAggregates]())
+
+	_, err := sb.Build()
+	require.NoError(t, err)
+
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	opened := created.Add(2 * time.Hour)
+	stored := []types.TimeCapsule{
+		{ID: 1, CapsuleType: types.CapsuleType_SAFE, Status: types.CapsuleStatus_ACTIVE, StorageType: "blockchain", DataSize: 100, CreatedAt: created},
+		{ID: 2, CapsuleType: types.CapsuleType_TIME_LOCK, Status: types.CapsuleStatus_UNLOCKED, StorageType: "blockchain", DataSize: 200, CreatedAt: created, OpenedAt: &opened},
+		{ID: 3, CapsuleType: types.CapsuleType_TIME_LOCK, Status: types.CapsuleStatus_UNLOCKED, StorageType: "ipfs", DataSize: 300, CreatedAt: created},
+		{ID: 4, CapsuleType: types.CapsuleType_SAFE, Status: types.CapsuleStatus_CANCELLED, StorageType: "blockchain", DataSize: 400, CreatedAt: created},
+	}
+	migrationtest.SetCapsules(t, ctx, capsules, stored...)
+
+	require.NoError(t, v3.MigrateStore(ctx, capsules, capsuleStats))
+
+	stats, err := capsuleStats.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), stats.Total)
+	require.Equal(t, map[string]uint64{"SAFE": 2, "TIME_LOCK": 2}, stats.ByType)
+	require.Equal(t, map[string]uint64{"ACTIVE": 1, "UNLOCKED": 2, "CANCELLED": 1}, stats.ByStatus)
+	require.Equal(t, map[string]uint64{"blockchain": 3, "ipfs": 1}, stats.ByStorage)
+	require.Equal(t, uint64(1000), stats.TotalBytes)
+
+	// only the capsule with an opening time is in the histogram, in the one day bucket
+	require.Equal(t, uint64(1), stats.UnlockLatency.Count)
+	require.Equal(t, int64(7200), stats.UnlockLatency.Sum)
+	require.Equal(t, []uint64{0, 1, 0, 0, 0, 0}, stats.UnlockLatency.Buckets)
+
+	// the migrated statistics are those maintained by incremental updates
+	incremental := types.NewCapsuleAggregates()
+	for _, capsule := range stored {
+		incremental.Add(capsule)
+	}
+	require.Empty(t, stats.Diff(incremental))
+
+	// migrating an already migrated store changes nothing
+	require.NoError(t, v3.MigrateStore(ctx, capsules, capsuleStats))
+	again, err := capsuleStats.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, stats, again)
+}
+
+func TestMigrateStoreEmpty(t *testing.T) {
+	kv, ctx := colltest.MockStore()
+	sb := collections.NewSchemaBuilder(kv)
+
+	capsules := migrationtest.Capsules(sb)
+	capsuleStats := collections.NewItem(sb, types.CapsuleStatsKey, "capsule_stats", colltest.MockValueCodec[types.CapsuleAggregates]())
+
+	_, err := sb.Build()
+	require.NoError(t, err)
+
+	require.NoError(t, v3.MigrateStore(ctx, capsules, capsuleStats))
+
+	stats, err := capsuleStats.Get(ctx)
+	require.NoError(t, err)
+	require.Empty(t, stats.Diff(types.NewCapsuleAggregates()))
+	require.Zero(t, stats.Total)
+}
@@ -0,0 +1,37 @@
+package v4
+
+import (
+	"context"
+	"time"
+
+	"cosmossdk.io/collections"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// MigrateStore performs in-place store migrations from v3 to v4. The v4 store queues
+// active capsules for their expiry and finished capsules for the purge of their data.
+// Capsules unlocked before v4 are purged once the retention period after their opening
+// ends, and cancelled or expired capsules are purged in the first block after the upgrade.
+func MigrateStore(
+	ctx context.Context,
+	capsules collections.Map[uint64, types.TimeCapsule],
+	expiryQueue collections.KeySet[collections.Pair[time.Time, uint64]],
+	purgeQueue collections.KeySet[collections.Pair[time.Time, uint64]],
+	retention time.Duration,
+) error {
+	return capsules.Walk(ctx, nil, func(id uint64, capsule types.TimeCapsule) (bool, error) {
+		if capsule.Status == types.CapsuleStatus_ACTIVE {
+			if capsule.ExpiresAt == nil {
+				return false, nil
+			}
+			return false, expiryQueue.Set(ctx, collections.Join(*capsule.ExpiresAt, id))
+		}
+
+		purgeTime, ok := capsule.PurgeTime(retention)
+		if !ok {
+			return false, nil
+		}
+		return false, purgeQueue.Set(ctx, collections.Join(purgeTime, id))
+	})
+}
@@ -0,0 +1,65 @@
+package v4_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/collections/colltest"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/migrations/migrationtest"
+	v4 "github.com/cosmos/cosmos-sdk/x/timecapsule/migrations/v4"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+func TestMigrateStore(t *testing.T) {
+	kv, ctx := colltest.MockStore()
+	sb := collections.NewSchemaBuilder(kv)
+
+	// v3 capsules, as laid out by the keeper
+	capsules := migrationtest.Capsules(sb)
+
+	// v4 expiry and purge queues
+	expiryQueue := collections.NewKeySet(sb, types.ExpiryQueueKeyPrefix, "expiry_queue", collections.PairKeyCodec(sdk.TimeKey, collections.Uint64Key))
+	purgeQueue := collections.NewKeySet(sb, types.PurgeQueueKeyPrefix, "purge_queue", collections.PairKeyCodec(sdk.TimeKey, collections.Uint64Key))
+
+	_, err := sb.Build()
+	require.NoError(t, err)
+
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	opened := created.Add(2 * time.Hour)
+	updated := created.Add(3 * time.Hour)
+	expires := created.Add(48 * time.Hour)
+	retention := 24 * time.Hour
+	stored := []types.TimeCapsule{
+		{ID: 1, CapsuleType: types.CapsuleType_SAFE, Status: types.CapsuleStatus_ACTIVE, CreatedAt: created, UpdatedAt: created},
+		{ID: 2, CapsuleType: types.CapsuleType_SAFE, Status: types.CapsuleStatus_ACTIVE, CreatedAt: created, UpdatedAt: created, ExpiresAt: &expires},
+		{ID: 3, CapsuleType: types.CapsuleType_TIME_LOCK, Status: types.CapsuleStatus_UNLOCKED, CreatedAt: created, UpdatedAt: opened, OpenedAt: &opened},
+		{ID: 4, CapsuleType: types.CapsuleType_SAFE, Status: types.CapsuleStatus_CANCELLED, CreatedAt: created, UpdatedAt: updated},
+		{ID: 5, CapsuleType: types.CapsuleType_PUBLIC_REVEAL, Status: types.CapsuleStatus_UNLOCKED, CreatedAt: created, UpdatedAt: opened, OpenedAt: &opened},
+		{ID: 6, CapsuleType: types.CapsuleType_SAFE, Status: types.CapsuleStatus_CANCELLED, CreatedAt: created, UpdatedAt: updated, Tombstone: &types.CapsuleTombstone{Reason: types.PurgeReasonCancelled}},
+	}
+	migrationtest.SetCapsules(t, ctx, capsules, stored...)
+
+	require.NoError(t, v4.MigrateStore(ctx, capsules, expiryQueue, purgeQueue, retention))
+
+	// only the active capsule with an expiry is queued to expire
+	expiries := migrationtest.Keys(t, ctx, expiryQueue)
+	require.Equal(t, []collections.Pair[time.Time, uint64]{collections.Join(expires, uint64(2))}, expiries)
+
+	// unlocked capsules are purged after the retention, cancelled ones at once;
+	// public reveal and purged capsules are not queued
+	purges := migrationtest.Keys(t, ctx, purgeQueue)
+	require.Equal(t, []collections.Pair[time.Time, uint64]{
+		collections.Join(updated, uint64(4)),
+		collections.Join(opened.Add(retention), uint64(3)),
+	}, purges)
+
+	// migrating an already migrated store changes nothing
+	require.NoError(t, v4.MigrateStore(ctx, capsules, expiryQueue, purgeQueue, retention))
+	require.Equal(t, expiries, migrationtest.Keys(t, ctx, expiryQueue))
+	require.Equal(t, purges, migrationtest.Keys(t, ctx, purgeQueue))
+}
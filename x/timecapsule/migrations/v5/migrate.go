@@ -0,0 +1,28 @@
+package v5
+
+import (
+	"context"
+	"time"
+
+	"cosmossdk.io/collections"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// MigrateStore performs in-place store migrations from v4 to v5. The v5 store queues
+// active time-locked capsules unlocking on a block time, and active dead man's switches,
+// so that the keeper announces when they become unlockable. Capsules whose unlock time
+// already passed are announced in the first block after the upgrade.
+func MigrateStore(
+	ctx context.Context,
+	capsules collections.Map[uint64, types.TimeCapsule],
+	unlockTimeQueue collections.KeySet[collections.Pair[time.Time, uint64]],
+) error {
+	return capsules.Walk(ctx, nil, func(id uint64, capsule types.TimeCapsule) (bool, error) {
+		unlockTime, ok := capsule.UnlockTimeTrigger()
+		if !ok {
+			return false, nil
+		}
+		return false, unlockTimeQueue.Set(ctx, collections.Join(unlockTime, id))
+	})
+}
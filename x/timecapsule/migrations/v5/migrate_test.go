@@ -0,0 +1,57 @@
+package v5_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/collections/colltest"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/migrations/migrationtest"
+	v5 "github.com/cosmos/cosmos-sdk/x/timecapsule/migrations/v5"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+func TestMigrateStore(t *testing.T) {
+	kv, ctx := colltest.MockStore()
+	sb := collections.NewSchemaBuilder(kv)
+
+	// v4 capsules, as laid out by the keeper
+	capsules := migrationtest.Capsules(sb)
+
+	// v5 unlock time queue
+	unlockTimeQueue := collections.NewKeySet(sb, types.UnlockTimeQueueKeyPrefix, "unlock_time_queue", collections.PairKeyCodec(sdk.TimeKey, collections.Uint64Key))
+
+	_, err := sb.Build()
+	require.NoError(t, err)
+
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	unlocks := created.Add(48 * time.Hour)
+	lastActivity := created.Add(time.Hour)
+	stored := []types.TimeCapsule{
+		{ID: 1, CapsuleType: types.CapsuleType_SAFE, Status: types.CapsuleStatus_ACTIVE, CreatedAt: created},
+		{ID: 2, CapsuleType: types.CapsuleType_TIME_LOCK, Status: types.CapsuleStatus_ACTIVE, CreatedAt: created, UnlockTime: &unlocks},
+		{ID: 3, CapsuleType: types.CapsuleType_TIME_LOCK, Status: types.CapsuleStatus_ACTIVE, CreatedAt: created, UnlockHeight: 100},
+		{ID: 4, CapsuleType: types.CapsuleType_DEAD_MANS_SWITCH, Status: types.CapsuleStatus_ACTIVE, CreatedAt: created, LastActivity: &lastActivity, InactivityPeriod: 3600},
+		{ID: 5, CapsuleType: types.CapsuleType_TIME_LOCK, Status: types.CapsuleStatus_UNLOCKED, CreatedAt: created, UnlockTime: &unlocks},
+		{ID: 6, CapsuleType: types.CapsuleType_PUBLIC_REVEAL, Status: types.CapsuleStatus_ACTIVE, CreatedAt: created, UnlockTime: &unlocks},
+	}
+	migrationtest.SetCapsules(t, ctx, capsules, stored...)
+
+	require.NoError(t, v5.MigrateStore(ctx, capsules, unlockTimeQueue))
+
+	// only active capsules unlocking on a block time are queued, dead man's switches
+	// at the end of their inactivity period
+	queued := migrationtest.Keys(t, ctx, unlockTimeQueue)
+	require.Equal(t, []collections.Pair[time.Time, uint64]{
+		collections.Join(lastActivity.Add(time.Hour), uint64(4)),
+		collections.Join(unlocks, uint64(2)),
+	}, queued)
+
+	// migrating an already migrated store changes nothing
+	require.NoError(t, v5.MigrateStore(ctx, capsules, unlockTimeQueue))
+	require.Equal(t, queued, migrationtest.Keys(t, ctx, unlockTimeQueue))
+}
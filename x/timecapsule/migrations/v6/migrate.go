@@ -0,0 +1,34 @@
+package v6
+
+import (
+	"context"
+
+	"cosmossdk.io/collections"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// MigrateStore performs in-place store migrations from v5 to v6. The v6 store indexes
+// capsules by owner and tag, and by owner and the entries of the indexed metadata keys,
+// so that an owner's capsules can be looked up without walking them all.
+func MigrateStore(
+	ctx context.Context,
+	capsules collections.Map[uint64, types.TimeCapsule],
+	capsuleTags collections.KeySet[collections.Triple[string, string, uint64]],
+	capsuleMetadata collections.KeySet[collections.Triple[string, string, uint64]],
+	indexedMetadataKeys []string,
+) error {
+	return capsules.Walk(ctx, nil, func(id uint64, capsule types.TimeCapsule) (bool, error) {
+		for _, tag := range capsule.Tags {
+			if err := capsuleTags.Set(ctx, collections.Join3(capsule.Owner, tag, id)); err != nil {
+				return false, err
+			}
+		}
+		for _, term := range capsule.IndexedMetadata(indexedMetadataKeys) {
+			if err := capsuleMetadata.Set(ctx, collections.Join3(capsule.Owner, term, id)); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	})
+}
@@ -0,0 +1,58 @@
+package v6_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/collections/colltest"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/migrations/migrationtest"
+	v6 "github.com/cosmos/cosmos-sdk/x/timecapsule/migrations/v6"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+func TestMigrateStore(t *testing.T) {
+	kv, ctx := colltest.MockStore()
+	sb := collections.NewSchemaBuilder(kv)
+
+	// v5 capsules, as laid out by the keeper
+	capsules := migrationtest.Capsules(sb)
+
+	// v6 tag and metadata indexes
+	tripleCodec := collections.TripleKeyCodec(collections.StringKey, collections.StringKey, collections.Uint64Key)
+	capsuleTags := collections.NewKeySet(sb, types.CapsuleTagsKeyPrefix, "capsule_tags", tripleCodec)
+	capsuleMetadata := collections.NewKeySet(sb, types.CapsuleMetadataKeyPrefix, "capsule_metadata", tripleCodec)
+
+	_, err := sb.Build()
+	require.NoError(t, err)
+
+	stored := []types.TimeCapsule{
+		{ID: 1, Owner: "alice", Status: types.CapsuleStatus_ACTIVE},
+		{ID: 2, Owner: "alice", Status: types.CapsuleStatus_ACTIVE, Tags: []string{"family", "letters"}, Metadata: map[string]string{"category": "will", "title": "To my kids"}},
+		{ID: 3, Owner: "bob", Status: types.CapsuleStatus_UNLOCKED, Tags: []string{"family"}, Metadata: map[string]string{"title": "Photos"}},
+	}
+	migrationtest.SetCapsules(t, ctx, capsules, stored...)
+
+	require.NoError(t, v6.MigrateStore(ctx, capsules, capsuleTags, capsuleMetadata, []string{"category"}))
+
+	// every tag is indexed under the capsule's owner
+	tags := migrationtest.Keys(t, ctx, capsuleTags)
+	require.Equal(t, []collections.Triple[string, string, uint64]{
+		collections.Join3("alice", "family", uint64(2)),
+		collections.Join3("alice", "letters", uint64(2)),
+		collections.Join3("bob", "family", uint64(3)),
+	}, tags)
+
+	// only the entries of indexed metadata keys are indexed
+	metadata := migrationtest.Keys(t, ctx, capsuleMetadata)
+	require.Equal(t, []collections.Triple[string, string, uint64]{
+		collections.Join3("alice", types.MetadataIndexTerm("category", "will"), uint64(2)),
+	}, metadata)
+
+	// migrating an already migrated store changes nothing
+	require.NoError(t, v6.MigrateStore(ctx, capsules, capsuleTags, capsuleMetadata, []string{"category"}))
+	require.Equal(t, tags, migrationtest.Keys(t, ctx, capsuleTags))
+	require.Equal(t, metadata, migrationtest.Keys(t, ctx, capsuleMetadata))
+}
@@ -19,16 +19,19 @@ import (
 	cdctypes "github.com/cosmos/cosmos-sdk/codec/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/module"
+	simtypes "github.com/cosmos/cosmos-sdk/types/simulation"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 
 	"github.com/cosmos/cosmos-sdk/x/timecapsule/client/cli"
 	"github.com/cosmos/cosmos-sdk/x/timecapsule/keeper"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/simulation"
 	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
 )
 
 const (
-	ConsensusVersion = 1
+	ConsensusVersion = 6
 )
 
 var (
@@ -36,6 +39,7 @@ var (
 	_ module.HasGenesis         = AppModule{}
 	_ module.HasInvariants      = AppModule{}
 	_ module.HasConsensusVersion = AppModule{}
+	_ module.AppModuleSimulation = AppModule{}
 
 	_ appmodule.AppModule   = AppModule{}
 	_ appmodule.HasBeginBlocker = AppModule{}
@@ -110,6 +114,7 @@ type AppModule struct {
 	keeper        keeper.Keeper
 	accountKeeper types.AccountKeeper
 	bankKeeper    types.BankKeeper
+	stakingKeeper types.ValidatorSetKeeper
 }
 
 // NewAppModule creates a new AppModule object
@@ -118,6 +123,7 @@ func NewAppModule(
 	keeper keeper.Keeper,
 	accountKeeper types.AccountKeeper,
 	bankKeeper types.BankKeeper,
+	stakingKeeper types.ValidatorSetKeeper,
 	ac address.Codec,
 ) AppModule {
 	return AppModule{
@@ -125,6 +131,7 @@ func NewAppModule(
 		keeper:         keeper,
 		accountKeeper:  accountKeeper,
 		bankKeeper:     bankKeeper,
+		stakingKeeper:  stakingKeeper,
 	}
 }
 
@@ -147,6 +154,23 @@ func (am AppModule) RegisterServices(cfg module.Configurator) {
 	types.RegisterMsgServer(cfg.MsgServer(), keeper.NewMsgServerImpl(am.keeper))
 	types.RegisterQueryServer(cfg.QueryServer(), keeper.NewQueryServerImpl(am.keeper))
 
+	m := keeper.NewMigrator(am.keeper)
+	if err := cfg.RegisterMigration(types.ModuleName, 1, m.Migrate1to2); err != nil {
+		panic(fmt.Sprintf("failed to migrate x/%s from version 1 to 2: %v", types.ModuleName, err))
+	}
+	if err := cfg.RegisterMigration(types.ModuleName, 2, m.Migrate2to3); err != nil {
+		panic(fmt.Sprintf("failed to migrate x/%s from version 2 to 3: %v", types.ModuleName, err))
+	}
+	if err := cfg.RegisterMigration(types.ModuleName, 3, m.Migrate3to4); err != nil {
+		panic(fmt.Sprintf("failed to migrate x/%s from version 3 to 4: %v", types.ModuleName, err))
+	}
+	if err := cfg.RegisterMigration(types.ModuleName, 4, m.Migrate4to5); err != nil {
+		panic(fmt.Sprintf("failed to migrate x/%s from version 4 to 5: %v", types.ModuleName, err))
+	}
+	if err := cfg.RegisterMigration(types.ModuleName, 5, m.Migrate5to6); err != nil {
+		panic(fmt.Sprintf("failed to migrate x/%s from version 5 to 6: %v", types.ModuleName, err))
+	}
+
 	// Register legacy querier if needed
 	// cfg.RegisterQueryHandler(types.ModuleName, am.keeper.LegacyQuerierHandler(cfg.LegacyQueryHandler()))
 }
@@ -184,6 +208,33 @@ func (am AppModule) EndBlock(ctx context.Context) error {
 	return am.keeper.EndBlocker(ctx)
 }
 
+// AppModuleSimulation functions
+
+// GenerateGenesisState creates a randomized GenState of the time capsule module.
+func (AppModule) GenerateGenesisState(simState *module.SimulationState) {
+	capsules, keyShares := simulation.RandomizedGenCapsules(simState)
+
+	genState := DefaultGenesis()
+	genState.Capsules = capsules
+	genState.KeyShares = keyShares
+	genState.CapsuleCounter = uint64(len(capsules)) + 1
+
+	simState.GenState[types.ModuleName] = simState.Cdc.MustMarshalJSON(genState)
+}
+
+// RegisterStoreDecoder registers a decoder for time capsule module's types
+func (am AppModule) RegisterStoreDecoder(sdr simtypes.StoreDecoderRegistry) {
+	sdr[types.StoreKey] = simtypes.NewStoreDecoderFuncFromCollectionsSchema(am.keeper.Schema())
+}
+
+// WeightedOperations returns the all the time capsule module operations with their respective weights.
+func (am AppModule) WeightedOperations(simState module.SimulationState) []simtypes.WeightedOperation {
+	return simulation.WeightedOperations(
+		simState.AppParams, simState.Cdc, simState.TxConfig,
+		am.accountKeeper, am.bankKeeper, am.keeper, am.stakingKeeper,
+	)
+}
+
 //
 // App Wiring Setup
 //
@@ -205,6 +256,10 @@ type ModuleInputs struct {
 
 	AccountKeeper types.AccountKeeper
 	BankKeeper    types.BankKeeper
+	StakingKeeper types.ValidatorSetKeeper
+	UpgradeKeeper types.UpgradeKeeper `optional:"true"`
+	OracleKeeper  types.OracleKeeper  `optional:"true"`
+	GroupKeeper   types.GroupKeeper   `optional:"true"`
 }
 
 type ModuleOutputs struct {
@@ -212,6 +267,7 @@ type ModuleOutputs struct {
 
 	TimeCapsuleKeeper keeper.Keeper
 	Module            appmodule.AppModule
+	Hooks             stakingtypes.StakingHooksWrapper
 }
 
 func ProvideModule(in ModuleInputs) ModuleOutputs {
@@ -228,14 +284,33 @@ func ProvideModule(in ModuleInputs) ModuleOutputs {
 		log.NewNopLogger(),
 		in.BankKeeper,
 		in.AccountKeeper,
+		in.StakingKeeper,
+		in.GroupKeeper,
+		authority.String(),
 	)
+
+	// Let capsules unlock a number of epochs after an upgrade plan is applied
+	if in.UpgradeKeeper != nil {
+		k.SetEpochSource(types.EpochSourceUpgrade, keeper.NewUpgradeEpochSource(in.UpgradeKeeper, types.DefaultUpgradeEpochBlocks))
+	}
+
+	// Evaluate oracle conditions against the on-chain oracle
+	if in.OracleKeeper != nil {
+		k.SetOracleKeeper(in.OracleKeeper)
+	}
+
 	m := NewAppModule(
 		in.Cdc,
 		k,
 		in.AccountKeeper,
 		in.BankKeeper,
+		in.StakingKeeper,
 		in.AddressCodec,
 	)
 
-	return ModuleOutputs{TimeCapsuleKeeper: k, Module: m}
+	return ModuleOutputs{
+		TimeCapsuleKeeper: k,
+		Module:            m,
+		Hooks:             stakingtypes.StakingHooksWrapper{StakingHooks: k.Hooks()},
+	}
 }
\ No newline at end of file
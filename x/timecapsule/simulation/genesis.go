@@ -0,0 +1,116 @@
+package simulation
+
+import (
+	"fmt"
+	"math/rand"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	simtypes "github.com/cosmos/cosmos-sdk/types/simulation"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// Simulation parameter constants
+const (
+	NumGenesisCapsules = "num_genesis_capsules"
+)
+
+// GenNumGenesisCapsules randomized number of genesis capsules
+func GenNumGenesisCapsules(r *rand.Rand) int {
+	return r.Intn(21)
+}
+
+// RandomizedGenCapsules generates random safe capsules of the simulation accounts, with
+// IDs from 1, and the key shares of their data keys held by the initially bonded
+// validators. The data keys are drawn from the simulation's randomness; nonces and share
+// polynomials come from the module's crypto primitives.
+func RandomizedGenCapsules(simState *module.SimulationState) ([]types.TimeCapsule, []types.KeyShare) {
+	var numCapsules int
+	simState.AppParams.GetOrGenerate(
+		NumGenesisCapsules, &numCapsules, simState.Rand,
+		func(r *rand.Rand) { numCapsules = GenNumGenesisCapsules(r) },
+	)
+
+	r := simState.Rand
+	accs := simState.Accounts
+	genTime := simState.GenTimestamp
+
+	params := types.DefaultParams()
+	em := crypto.NewEncryptionManager()
+	sss := crypto.NewShamirSecretSharing()
+
+	capsules := make([]types.TimeCapsule, 0, numCapsules)
+	var keyShares []types.KeyShare
+	for i := 0; i < numCapsules; i++ {
+		id := uint64(i + 1)
+		owner, _ := simtypes.RandomAcc(r, accs)
+
+		data := []byte(simtypes.RandStringOfLength(r, simtypes.RandIntBetween(r, 16, 512)))
+		key := make([]byte, 32)
+		r.Read(key)
+
+		encrypted, err := em.Encrypt(data, key)
+		if err != nil {
+			panic(err)
+		}
+
+		totalShares := uint32(simtypes.RandIntBetween(r, int(params.MinThreshold), int(params.MaxShares)+1))
+		threshold := uint32(simtypes.RandIntBetween(r, int(params.MinThreshold), int(totalShares)+1))
+		shares, commitments, err := sss.SplitSecretVerifiable(key, int(threshold), int(totalShares))
+		if err != nil {
+			panic(err)
+		}
+
+		capsule := types.TimeCapsule{
+			ID:               id,
+			Owner:            owner.Address.String(),
+			CapsuleType:      types.CapsuleType_SAFE,
+			Status:           types.CapsuleStatus_ACTIVE,
+			Version:          1,
+			VersionPolicy:    types.VersionPolicyLatest,
+			EncryptedData:    encrypted.Data,
+			DataHash:         crypto.HashData(data),
+			EncryptionAlgo:   encrypted.Algorithm,
+			DataNonce:        encrypted.Nonce,
+			DataSize:         int64(len(encrypted.Data)),
+			StorageType:      "blockchain",
+			Threshold:        threshold,
+			TotalShares:      totalShares,
+			ShareHolders:     make([]string, totalShares),
+			ShareCommitments: commitments,
+			CreatedAt:        genTime,
+			UpdatedAt:        genTime,
+			Title:            simtypes.RandStringOfLength(r, 10),
+		}
+
+		// Safe capsules may name a recipient besides the owner
+		if recipient, _ := simtypes.RandomAcc(r, accs); r.Intn(2) == 0 && !recipient.Address.Equals(owner.Address) {
+			capsule.Recipient = recipient.Address.String()
+		}
+
+		capsules = append(capsules, capsule)
+
+		for j, share := range shares {
+			keyShares = append(keyShares, types.KeyShare{
+				CapsuleID:      id,
+				ShareIndex:     uint32(j),
+				NodeID:         genesisCustodian(accs, simState.NumBonded, j),
+				EncryptedShare: crypto.ShareToBytes(share),
+				CreatedAt:      genTime,
+			})
+		}
+	}
+
+	return capsules, keyShares
+}
+
+// genesisCustodian returns the custodian of the i-th key share of a genesis capsule,
+// cycling through the initially bonded validators like the keeper does
+func genesisCustodian(accs []simtypes.Account, numBonded int64, i int) string {
+	if numBonded <= 0 {
+		return fmt.Sprintf("masternode-%d", i)
+	}
+	return sdk.ValAddress(accs[int64(i)%numBonded].Address).String()
+}
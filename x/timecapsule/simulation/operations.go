@@ -0,0 +1,1451 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	simtypes "github.com/cosmos/cosmos-sdk/types/simulation"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/keeper"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// Simulation operation weights constants
+const (
+	OpWeightMsgCreateCapsule          = "op_weight_msg_create_capsule"
+	OpWeightMsgOpenCapsule            = "op_weight_msg_open_capsule"
+	OpWeightMsgUpdateActivity         = "op_weight_msg_update_activity"
+	OpWeightMsgCancelCapsule          = "op_weight_msg_cancel_capsule"
+	OpWeightMsgTransferCapsule        = "op_weight_msg_transfer_capsule"
+	OpWeightMsgBatchTransferCapsules  = "op_weight_msg_batch_transfer_capsules"
+	OpWeightMsgApproveTransfer        = "op_weight_msg_approve_transfer"
+	OpWeightMsgRegisterTimelockKey    = "op_weight_msg_register_timelock_key"
+	OpWeightMsgProposeEmergencyAction = "op_weight_msg_propose_emergency_action"
+	OpWeightMsgApproveEmergencyAction = "op_weight_msg_approve_emergency_action"
+	OpWeightMsgVetoEmergencyAction    = "op_weight_msg_veto_emergency_action"
+	OpWeightMsgExecuteEmergencyAction = "op_weight_msg_execute_emergency_action"
+	OpWeightMsgReverseEmergencyAction = "op_weight_msg_reverse_emergency_action"
+	OpWeightMsgAmendCapsule           = "op_weight_msg_amend_capsule"
+	OpWeightMsgRotateRecipient        = "op_weight_msg_rotate_recipient"
+	OpWeightMsgGrantCapsuleAllowance  = "op_weight_msg_grant_capsule_allowance"
+	OpWeightMsgRevokeCapsuleAllowance = "op_weight_msg_revoke_capsule_allowance"
+	OpWeightMsgRegisterAttestor       = "op_weight_msg_register_attestor"
+	OpWeightMsgSubmitAttestation      = "op_weight_msg_submit_attestation"
+	OpWeightMsgRevokeCapsuleAttestor  = "op_weight_msg_revoke_capsule_attestor"
+	OpWeightMsgEraseCapsule           = "op_weight_msg_erase_capsule"
+	DefaultWeightMsgCreateCapsule     = 100
+	DefaultWeightMsgOpenCapsule       = 60
+	DefaultWeightMsgUpdateActivity    = 20
+	DefaultWeightMsgCancelCapsule     = 10
+	DefaultWeightMsgTransferCapsule   = 20
+	DefaultWeightMsgBatchTransfer     = 10
+	DefaultWeightMsgApproveTransfer   = 5
+	DefaultWeightMsgRegisterTimelock  = 5
+	DefaultWeightMsgProposeEmergency  = 20
+	DefaultWeightMsgApproveEmergency  = 30
+	DefaultWeightMsgVetoEmergency     = 5
+	DefaultWeightMsgExecuteEmergency  = 20
+	DefaultWeightMsgReverseEmergency  = 5
+	DefaultWeightMsgAmendCapsule      = 20
+	DefaultWeightMsgRotateRecipient   = 10
+	DefaultWeightMsgGrantAllowance    = 10
+	DefaultWeightMsgRevokeAllowance   = 5
+	DefaultWeightMsgRegisterAttestor  = 10
+	DefaultWeightMsgSubmitAttestation = 30
+	DefaultWeightMsgRevokeAttestor    = 5
+	DefaultWeightMsgEraseCapsule      = 5
+)
+
+// capsuleTypes are the capsule types created by the simulation, weighted by repetition
+var capsuleTypes = []types.CapsuleType{
+	types.CapsuleType_SAFE,
+	types.CapsuleType_SAFE,
+	types.CapsuleType_TIME_LOCK,
+	types.CapsuleType_TIME_LOCK,
+	types.CapsuleType_DEAD_MANS_SWITCH,
+	types.CapsuleType_CONDITIONAL,
+	types.CapsuleType_MULTI_SIG,
+	types.CapsuleType_PUBLIC_REVEAL,
+}
+
+// compressions are the compression algorithms capsule data is sealed with
+var compressions = []string{"", "gzip", "zstd"}
+
+// WeightedOperations returns all the operations from the module with their respective weights
+func WeightedOperations(
+	appParams simtypes.AppParams,
+	_ codec.JSONCodec,
+	txGen client.TxConfig,
+	ak types.AccountKeeper,
+	bk types.BankKeeper,
+	k keeper.Keeper,
+	sk types.ValidatorSetKeeper,
+) simulation.WeightedOperations {
+	weight := func(key string, defaultWeight int) int {
+		var w int
+		appParams.GetOrGenerate(key, &w, nil, func(_ *rand.Rand) { w = defaultWeight })
+		return w
+	}
+
+	return simulation.WeightedOperations{
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgCreateCapsule, DefaultWeightMsgCreateCapsule),
+			SimulateMsgCreateCapsule(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgOpenCapsule, DefaultWeightMsgOpenCapsule),
+			SimulateMsgOpenCapsule(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgUpdateActivity, DefaultWeightMsgUpdateActivity),
+			SimulateMsgUpdateActivity(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgCancelCapsule, DefaultWeightMsgCancelCapsule),
+			SimulateMsgCancelCapsule(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgTransferCapsule, DefaultWeightMsgTransferCapsule),
+			SimulateMsgTransferCapsule(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgBatchTransferCapsules, DefaultWeightMsgBatchTransfer),
+			SimulateMsgBatchTransferCapsules(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgApproveTransfer, DefaultWeightMsgApproveTransfer),
+			SimulateMsgApproveTransfer(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgRegisterTimelockKey, DefaultWeightMsgRegisterTimelock),
+			SimulateMsgRegisterTimelockKey(txGen, ak, bk, sk),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgProposeEmergencyAction, DefaultWeightMsgProposeEmergency),
+			SimulateMsgProposeEmergencyAction(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgApproveEmergencyAction, DefaultWeightMsgApproveEmergency),
+			SimulateMsgApproveEmergencyAction(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgVetoEmergencyAction, DefaultWeightMsgVetoEmergency),
+			SimulateMsgVetoEmergencyAction(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgExecuteEmergencyAction, DefaultWeightMsgExecuteEmergency),
+			SimulateMsgExecuteEmergencyAction(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgReverseEmergencyAction, DefaultWeightMsgReverseEmergency),
+			SimulateMsgReverseEmergencyAction(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgAmendCapsule, DefaultWeightMsgAmendCapsule),
+			SimulateMsgAmendCapsule(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgRotateRecipient, DefaultWeightMsgRotateRecipient),
+			SimulateMsgRotateRecipient(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgGrantCapsuleAllowance, DefaultWeightMsgGrantAllowance),
+			SimulateMsgGrantCapsuleAllowance(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgRevokeCapsuleAllowance, DefaultWeightMsgRevokeAllowance),
+			SimulateMsgRevokeCapsuleAllowance(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgRegisterAttestor, DefaultWeightMsgRegisterAttestor),
+			SimulateMsgRegisterAttestor(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgSubmitAttestation, DefaultWeightMsgSubmitAttestation),
+			SimulateMsgSubmitAttestation(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgRevokeCapsuleAttestor, DefaultWeightMsgRevokeAttestor),
+			SimulateMsgRevokeCapsuleAttestor(txGen, ak, bk, k),
+		),
+		simulation.NewWeightedOperation(
+			weight(OpWeightMsgEraseCapsule, DefaultWeightMsgEraseCapsule),
+			SimulateMsgEraseCapsule(txGen, ak, bk, k),
+		),
+	}
+}
+
+// SimulateMsgCreateCapsule generates a MsgCreateCapsule of a random capsule type and
+// schedules the opening or reveal of the capsule once it is due
+func SimulateMsgCreateCapsule(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		creator, _ := simtypes.RandomAcc(r, accs)
+		capsuleType := capsuleTypes[r.Intn(len(capsuleTypes))]
+
+		params, err := k.GetParams(ctx)
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgCreateCapsule, "unable to get params"), nil, err
+		}
+
+		quota, err := k.GetCreationQuota(ctx, creator.Address.String())
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgCreateCapsule, "unable to get creation quota"), nil, err
+		}
+		if _, err := quota.Consume(ctx.BlockHeight(), 1, params); err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgCreateCapsule, "creation quota exceeded"), nil, nil
+		}
+
+		capsuleID, err := k.GetCapsuleCounter(ctx)
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgCreateCapsule, "unable to get capsule counter"), nil, err
+		}
+		if capsuleID == 0 {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgCreateCapsule, "capsule counter not initialized"), nil, nil
+		}
+
+		msg := &types.MsgCreateCapsule{
+			Creator:     creator.Address.String(),
+			CapsuleType: capsuleType,
+			Title:       simtypes.RandStringOfLength(r, 10),
+		}
+
+		// Dead man's switches must name a recipient, other capsules may
+		if recipient, _ := simtypes.RandomAcc(r, accs); !recipient.Address.Equals(creator.Address) &&
+			(capsuleType == types.CapsuleType_DEAD_MANS_SWITCH || r.Intn(2) == 0) {
+			msg.Recipient = recipient.Address.String()
+		}
+		if capsuleType == types.CapsuleType_DEAD_MANS_SWITCH && msg.Recipient == "" {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgCreateCapsule, "no recipient for dead man's switch"), nil, nil
+		}
+
+		msg.Guardians, msg.GuardianThreshold = randomGuardians(r, accs, creator)
+
+		var data, salt []byte
+		if capsuleType == types.CapsuleType_PUBLIC_REVEAL {
+			data = []byte(simtypes.RandStringOfLength(r, simtypes.RandIntBetween(r, 16, 1024)))
+			salt = []byte(simtypes.RandStringOfLength(r, 16))
+			msg.Commitment = crypto.CommitContent(data, salt)
+			msg.Recipient = ""
+		} else {
+			msg.Data = []byte(simtypes.RandStringOfLength(r, simtypes.RandIntBetween(r, 16, 1024)))
+			msg.Compression = compressions[r.Intn(len(compressions))]
+			msg.TotalShares = uint32(simtypes.RandIntBetween(r, int(params.MinThreshold), int(params.MaxShares)+1))
+			msg.Threshold = uint32(simtypes.RandIntBetween(r, int(params.MinThreshold), int(msg.TotalShares)+1))
+
+			if executor, _ := simtypes.RandomAcc(r, accs); r.Intn(5) == 0 && !executor.Address.Equals(creator.Address) {
+				msg.Executor = executor.Address.String()
+			}
+		}
+
+		blockTime := ctx.BlockTime()
+		switch capsuleType {
+		case types.CapsuleType_TIME_LOCK:
+			if r.Intn(2) == 0 {
+				unlockTime := blockTime.Add(randomDuration(r, time.Hour, 48*time.Hour))
+				msg.UnlockTime = &unlockTime
+			} else {
+				msg.UnlockHeight = ctx.BlockHeight() + int64(simtypes.RandIntBetween(r, 1, 21))
+			}
+
+		case types.CapsuleType_CONDITIONAL:
+			msg.Attestation, err = randomAttestationCondition(r, ctx, k, accs)
+			if err != nil {
+				return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgCreateCapsule, "unable to get attestors"), nil, err
+			}
+			if msg.Attestation == nil {
+				contract, _ := simtypes.RandomAcc(r, accs)
+				msg.ConditionContract = contract.Address.String()
+			}
+
+		case types.CapsuleType_MULTI_SIG:
+			msg.RequiredSigs = uint32(simtypes.RandIntBetween(r, 1, 4))
+
+		case types.CapsuleType_DEAD_MANS_SWITCH:
+			msg.InactivityPeriod = uint64(simtypes.RandIntBetween(r, 3600, 86401))
+
+		case types.CapsuleType_PUBLIC_REVEAL:
+			unlockTime := blockTime.Add(randomDuration(r, time.Hour, 48*time.Hour))
+			msg.UnlockTime = &unlockTime
+		}
+
+		// Some capsules expire unopened, after they unlock
+		if capsuleType != types.CapsuleType_PUBLIC_REVEAL && r.Intn(5) == 0 {
+			expiresAt := blockTime.Add(randomDuration(r, 49*time.Hour, 96*time.Hour))
+			msg.ExpiresAt = &expiresAt
+		}
+
+		// The payer, the creator or its sponsor, pays the creation fee and the storage fee,
+		// compression and encryption overhead included. The creator pays the escrow.
+		fee, err := k.CreationFee(ctx)
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgCreateCapsule, "unable to get creation fee"), nil, err
+		}
+		sealedSize := int64(0)
+		if len(msg.Data) > 0 {
+			sealedSize = int64(len(msg.Data) + 64)
+			fee = fee.Add(params.StorageFee(sealedSize)...)
+		}
+
+		sponsor, err := randomSponsor(r, ctx, k, creator, sealedSize, fee)
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgCreateCapsule, "unable to get capsule allowances"), nil, err
+		}
+
+		spent := fee
+		if sponsor != "" {
+			msg.Sponsor = sponsor
+			if _, hasNeg := bk.SpendableCoins(ctx, sdk.MustAccAddressFromBech32(sponsor)).SafeSub(fee...); hasNeg {
+				return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgCreateCapsule, "insufficient sponsor funds"), nil, nil
+			}
+			spent = sdk.NewCoins()
+		}
+
+		spendable := bk.SpendableCoins(ctx, creator.Address)
+		available, hasNeg := spendable.SafeSub(spent...)
+		if hasNeg {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgCreateCapsule, "insufficient funds"), nil, nil
+		}
+
+		// Escrow is paid out to the recipient on open
+		if msg.Recipient != "" && r.Intn(3) == 0 {
+			msg.Escrow = simtypes.RandSubsetCoins(r, available)
+		}
+
+		opMsg, _, err := deliver(r, app, ctx, txGen, ak, bk, creator, msg, spent.Add(msg.Escrow...))
+		if err != nil || !opMsg.OK {
+			return opMsg, nil, err
+		}
+
+		var futureOps []simtypes.FutureOperation
+		switch {
+		case capsuleType == types.CapsuleType_PUBLIC_REVEAL:
+			futureOps = append(futureOps, simtypes.FutureOperation{
+				BlockTime: msg.UnlockTime.Add(time.Second),
+				Op:        simulateRevealCapsule(txGen, ak, bk, k, capsuleID, data, salt),
+			})
+
+		case capsuleType == types.CapsuleType_TIME_LOCK && msg.UnlockTime != nil:
+			futureOps = append(futureOps, simtypes.FutureOperation{
+				BlockTime: msg.UnlockTime.Add(time.Second),
+				Op:        simulateOpenDueCapsule(txGen, ak, bk, k, capsuleID),
+			})
+
+		case capsuleType == types.CapsuleType_TIME_LOCK:
+			futureOps = append(futureOps, simtypes.FutureOperation{
+				BlockHeight: int(msg.UnlockHeight),
+				Op:          simulateOpenDueCapsule(txGen, ak, bk, k, capsuleID),
+			})
+
+		case capsuleType == types.CapsuleType_DEAD_MANS_SWITCH:
+			futureOps = append(futureOps, simtypes.FutureOperation{
+				BlockTime: blockTime.Add(time.Duration(msg.InactivityPeriod+1) * time.Second),
+				Op:        simulateOpenDueCapsule(txGen, ak, bk, k, capsuleID),
+			})
+		}
+
+		return opMsg, futureOps, nil
+	}
+}
+
+// SimulateMsgOpenCapsule generates a MsgOpenCapsule for a random unlockable capsule
+func SimulateMsgOpenCapsule(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		capsule, err := randomCapsule(r, ctx, k, func(capsule *types.TimeCapsule) bool {
+			_, ok := capsuleAccessor(accs, capsule)
+			return ok && !capsule.IsPublicReveal() && capsule.IsUnlockable(ctx)
+		})
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgOpenCapsule, "unable to get capsules"), nil, err
+		}
+		if capsule == nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgOpenCapsule, "no unlockable capsule"), nil, nil
+		}
+
+		return openCapsule(r, app, ctx, accs, txGen, ak, bk, k, capsule)
+	}
+}
+
+// simulateOpenDueCapsule opens a capsule once its unlock conditions are met. Dead man's
+// switches whose owner was active since are tried again when they fall due.
+func simulateOpenDueCapsule(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper, capsuleID uint64) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		capsule, err := k.GetCapsule(ctx, capsuleID)
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgOpenCapsule, "capsule not found"), nil, nil
+		}
+		if capsule.Status != types.CapsuleStatus_ACTIVE || capsule.Frozen {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgOpenCapsule, "capsule cannot be opened"), nil, nil
+		}
+
+		if !capsule.IsUnlockable(ctx) {
+			if capsule.CapsuleType == types.CapsuleType_DEAD_MANS_SWITCH && capsule.LastActivity != nil {
+				due := capsule.LastActivity.Add(time.Duration(capsule.InactivityPeriod+1) * time.Second)
+				return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgOpenCapsule, "owner was active"),
+					[]simtypes.FutureOperation{{BlockTime: due, Op: simulateOpenDueCapsule(txGen, ak, bk, k, capsuleID)}}, nil
+			}
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgOpenCapsule, "capsule not unlockable"), nil, nil
+		}
+
+		return openCapsule(r, app, ctx, accs, txGen, ak, bk, k, capsule)
+	}
+}
+
+// openCapsule delivers a MsgOpenCapsule of an unlockable capsule, with the key shares
+// held by its custodians unless the key is timelocked or delivered to the recipient
+func openCapsule(
+	r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account,
+	txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper, capsule *types.TimeCapsule,
+) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+	accessor, ok := capsuleAccessor(accs, capsule)
+	if !ok {
+		return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgOpenCapsule, "no simulation account can open the capsule"), nil, nil
+	}
+
+	msg := types.NewMsgOpenCapsule(accessor.Address.String(), capsule.ID)
+
+	switch {
+	case capsule.IsTimelocked():
+		epoch, err := k.GetTimelockEpoch(ctx, capsule.TimelockEpoch)
+		if err != nil || epoch.Status != types.TimelockEpochStatus_RELEASED {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgOpenCapsule, "timelock epoch not released"), nil, nil
+		}
+
+	case len(capsule.RecipientKey) > 0:
+		// Delivered to the recipient key without the key shares
+
+	default:
+		res, err := keeper.NewQueryServerImpl(k).KeyShares(ctx, &types.QueryKeySharesRequest{CapsuleId: capsule.ID})
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgOpenCapsule, "unable to get key shares"), nil, err
+		}
+		if len(res.KeyShares) < int(capsule.Threshold) {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgOpenCapsule, "insufficient key shares"), nil, nil
+		}
+
+		for _, keyShare := range res.KeyShares {
+			share, err := crypto.BytesToShare(keyShare.EncryptedShare)
+			if err != nil {
+				return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgOpenCapsule, "invalid key share"), nil, err
+			}
+			bz, err := json.Marshal(share)
+			if err != nil {
+				return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgOpenCapsule, "unable to encode key share"), nil, err
+			}
+			msg.KeyShares = append(msg.KeyShares, string(bz))
+		}
+	}
+
+	return deliver(r, app, ctx, txGen, ak, bk, accessor, msg, nil)
+}
+
+// simulateRevealCapsule reveals the content of a public reveal capsule once it is due
+func simulateRevealCapsule(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper, capsuleID uint64, data, salt []byte) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		capsule, err := k.GetCapsule(ctx, capsuleID)
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRevealCapsule, "capsule not found"), nil, nil
+		}
+		if !capsule.IsPublicReveal() || !capsule.IsUnlockable(ctx) {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRevealCapsule, "capsule cannot be revealed"), nil, nil
+		}
+
+		// Anyone knowing the content can reveal it
+		revealer, _ := simtypes.RandomAcc(r, accs)
+		msg := types.NewMsgRevealCapsule(revealer.Address.String(), capsuleID, data, salt)
+
+		return deliver(r, app, ctx, txGen, ak, bk, revealer, msg, nil)
+	}
+}
+
+// SimulateMsgUpdateActivity generates a MsgUpdateActivity for a random dead man's switch
+func SimulateMsgUpdateActivity(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		capsule, err := randomCapsule(r, ctx, k, func(capsule *types.TimeCapsule) bool {
+			_, ok := findAccount(accs, capsule.Owner)
+			return ok && capsule.CapsuleType == types.CapsuleType_DEAD_MANS_SWITCH && capsule.Status == types.CapsuleStatus_ACTIVE
+		})
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgUpdateActivity, "unable to get capsules"), nil, err
+		}
+		if capsule == nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgUpdateActivity, "no active dead man's switch"), nil, nil
+		}
+
+		owner, _ := findAccount(accs, capsule.Owner)
+		msg := types.NewMsgUpdateActivity(capsule.Owner, capsule.ID)
+
+		return deliver(r, app, ctx, txGen, ak, bk, owner, msg, nil)
+	}
+}
+
+// SimulateMsgCancelCapsule generates a MsgCancelCapsule for a random capsule
+func SimulateMsgCancelCapsule(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		capsule, err := randomCapsule(r, ctx, k, func(capsule *types.TimeCapsule) bool {
+			if _, ok := findAccount(accs, capsule.Owner); !ok {
+				return false
+			}
+			if capsule.Status != types.CapsuleStatus_ACTIVE || capsule.Frozen || capsule.IsPublicReveal() {
+				return false
+			}
+			// Escrow of due time-locked capsules and dead man's switches is not refunded
+			switch capsule.CapsuleType {
+			case types.CapsuleType_TIME_LOCK, types.CapsuleType_DEAD_MANS_SWITCH:
+				return !capsule.HasEscrow() || !capsule.IsUnlockable(ctx)
+			}
+			return true
+		})
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgCancelCapsule, "unable to get capsules"), nil, err
+		}
+		if capsule == nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgCancelCapsule, "no cancellable capsule"), nil, nil
+		}
+
+		owner, _ := findAccount(accs, capsule.Owner)
+		msg := types.NewMsgCancelCapsule(capsule.Owner, capsule.ID, simtypes.RandStringOfLength(r, 20))
+
+		return deliver(r, app, ctx, txGen, ak, bk, owner, msg, nil)
+	}
+}
+
+// SimulateMsgTransferCapsule generates a MsgTransferCapsule for a random capsule
+func SimulateMsgTransferCapsule(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		capsule, err := randomCapsule(r, ctx, k, func(capsule *types.TimeCapsule) bool {
+			_, ok := findAccount(accs, capsule.Owner)
+			return ok && transferable(capsule)
+		})
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgTransferCapsule, "unable to get capsules"), nil, err
+		}
+		if capsule == nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgTransferCapsule, "no transferable capsule"), nil, nil
+		}
+
+		newOwner, _ := simtypes.RandomAcc(r, accs)
+		if newOwner.Address.String() == capsule.Owner || capsule.IsGuardian(newOwner.Address.String()) {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgTransferCapsule, "new owner is the owner or a guardian"), nil, nil
+		}
+
+		owner, _ := findAccount(accs, capsule.Owner)
+		msg := types.NewMsgTransferCapsule(capsule.Owner, newOwner.Address.String(), capsule.ID)
+
+		return deliver(r, app, ctx, txGen, ak, bk, owner, msg, nil)
+	}
+}
+
+// SimulateMsgBatchTransferCapsules generates a MsgBatchTransferCapsules for a few capsules
+// of a random owner
+func SimulateMsgBatchTransferCapsules(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		owner, _ := simtypes.RandomAcc(r, accs)
+
+		capsules, err := k.GetAllCapsules(ctx)
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgBatchTransferCapsules, "unable to get capsules"), nil, err
+		}
+
+		var transfers []types.CapsuleTransfer
+		for _, i := range r.Perm(len(capsules)) {
+			capsule := capsules[i]
+			if capsule.Owner != owner.Address.String() || !transferable(&capsule) {
+				continue
+			}
+
+			newOwner, _ := simtypes.RandomAcc(r, accs)
+			if newOwner.Address.Equals(owner.Address) || capsule.IsGuardian(newOwner.Address.String()) {
+				continue
+			}
+
+			transfers = append(transfers, types.CapsuleTransfer{
+				CapsuleID: capsule.ID,
+				NewOwner:  newOwner.Address.String(),
+				Message:   simtypes.RandStringOfLength(r, 10),
+			})
+			if len(transfers) == 3 {
+				break
+			}
+		}
+		if len(transfers) == 0 {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgBatchTransferCapsules, "no transferable capsules"), nil, nil
+		}
+
+		msg := types.NewMsgBatchTransferCapsules(owner.Address.String(), transfers)
+
+		return deliver(r, app, ctx, txGen, ak, bk, owner, msg, nil)
+	}
+}
+
+// SimulateMsgApproveTransfer generates a MsgApproveTransfer approving or rejecting a
+// random pending transfer
+func SimulateMsgApproveTransfer(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		transfers, err := k.GetAllPendingTransfers(ctx)
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgApproveTransfer, "unable to get pending transfers"), nil, err
+		}
+
+		var candidates []types.PendingTransfer
+		for _, transfer := range transfers {
+			if _, ok := findAccount(accs, transfer.ToOwner); ok && transfer.Status == "pending" && !ctx.BlockTime().After(transfer.ExpiryTime) {
+				candidates = append(candidates, transfer)
+			}
+		}
+		if len(candidates) == 0 {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgApproveTransfer, "no pending transfer"), nil, nil
+		}
+
+		transfer := candidates[r.Intn(len(candidates))]
+		approver, _ := findAccount(accs, transfer.ToOwner)
+
+		// Only approve transfers the capsule still allows
+		approved := false
+		if capsule, err := k.GetCapsule(ctx, transfer.CapsuleID); err == nil && r.Intn(2) == 0 {
+			approved = capsule.Owner == transfer.FromOwner && transferable(capsule) && !capsule.IsGuardian(transfer.ToOwner)
+		}
+
+		msg := types.NewMsgApproveTransfer(transfer.ToOwner, transfer.TransferID, transfer.CapsuleID, approved)
+
+		return deliver(r, app, ctx, txGen, ak, bk, approver, msg, nil)
+	}
+}
+
+// SimulateMsgRegisterTimelockKey generates a MsgRegisterTimelockKey of a random bonded
+// validator, registering the public key of its operator account
+func SimulateMsgRegisterTimelockKey(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, sk types.ValidatorSetKeeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		validators, err := sk.GetBondedValidatorsByPower(ctx)
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRegisterTimelockKey, "unable to get validators"), nil, err
+		}
+		if len(validators) == 0 {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRegisterTimelockKey, "no bonded validators"), nil, nil
+		}
+
+		validator := validators[r.Intn(len(validators))]
+		valAddr, err := sdk.ValAddressFromBech32(validator.GetOperator())
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRegisterTimelockKey, "invalid validator address"), nil, err
+		}
+
+		operator, ok := simtypes.FindAccount(accs, sdk.AccAddress(valAddr))
+		if !ok {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRegisterTimelockKey, "validator operator not found"), nil, nil
+		}
+
+		msg := types.NewMsgRegisterTimelockKey(validator.GetOperator(), operator.PubKey.Bytes())
+
+		return deliver(r, app, ctx, txGen, ak, bk, operator, msg, nil)
+	}
+}
+
+// SimulateMsgProposeEmergencyAction generates a MsgProposeEmergencyAction of an action
+// that applies to a random capsule with guardians
+func SimulateMsgProposeEmergencyAction(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		capsule, err := randomCapsule(r, ctx, k, func(capsule *types.TimeCapsule) bool {
+			return capsule.GuardianThreshold > 0 && capsule.Status == types.CapsuleStatus_ACTIVE && len(accountsOf(accs, capsule.Guardians)) > 0
+		})
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgProposeEmergencyAction, "unable to get capsules"), nil, err
+		}
+		if capsule == nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgProposeEmergencyAction, "no capsule with guardians"), nil, nil
+		}
+
+		existing, err := k.GetCapsuleEmergencyActions(ctx, capsule.ID)
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgProposeEmergencyAction, "unable to get emergency actions"), nil, err
+		}
+		pending := make(map[string]bool)
+		for _, action := range existing {
+			if action.Status == types.EmergencyStatusPending {
+				pending[action.ActionType] = true
+			}
+		}
+
+		newRecipient, _ := simtypes.RandomAcc(r, accs)
+		var candidates []types.EmergencyAction
+		for _, actionType := range []string{
+			types.EmergencyActionContractDeletion,
+			types.EmergencyActionForceUnlock,
+			types.EmergencyActionFreeze,
+			types.EmergencyActionRotateRecipient,
+		} {
+			action := types.EmergencyAction{ActionType: actionType}
+			if actionType == types.EmergencyActionRotateRecipient {
+				action.NewRecipient = newRecipient.Address.String()
+			}
+			if !pending[actionType] && emergencyActionApplies(capsule, &action) {
+				candidates = append(candidates, action)
+			}
+		}
+		if len(candidates) == 0 {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgProposeEmergencyAction, "no applicable emergency action"), nil, nil
+		}
+
+		action := candidates[r.Intn(len(candidates))]
+		guardians := accountsOf(accs, capsule.Guardians)
+		guardian := guardians[r.Intn(len(guardians))]
+
+		msg := types.NewMsgProposeEmergencyAction(
+			guardian.Address.String(), capsule.ID, action.ActionType, simtypes.RandStringOfLength(r, 20), action.NewRecipient,
+		)
+
+		opMsg, _, err := deliver(r, app, ctx, txGen, ak, bk, guardian, msg, nil)
+		if err != nil || !opMsg.OK {
+			return opMsg, nil, err
+		}
+
+		// The proposal counts as the proposer's approval
+		var futureOps []simtypes.FutureOperation
+		if capsule.GuardianThreshold <= 1 {
+			actionID := fmt.Sprintf("emergency_%d_%d", capsule.ID, len(existing)+1)
+			futureOps = append(futureOps, executeEmergencyActionFuture(ctx, txGen, ak, bk, k, actionID))
+		}
+
+		return opMsg, futureOps, nil
+	}
+}
+
+// SimulateMsgApproveEmergencyAction generates a MsgApproveEmergencyAction for a random
+// pending emergency action, and schedules its execution once it is approved
+func SimulateMsgApproveEmergencyAction(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		action, capsule, signers, err := randomEmergencyAction(r, ctx, k, func(action *types.EmergencyAction, capsule *types.TimeCapsule) []simtypes.Account {
+			if action.Status != types.EmergencyStatusPending {
+				return nil
+			}
+			var approvers []simtypes.Account
+			for _, guardian := range accountsOf(accs, capsule.Guardians) {
+				if !action.HasApproved(guardian.Address.String()) {
+					approvers = append(approvers, guardian)
+				}
+			}
+			return approvers
+		})
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgApproveEmergencyAction, "unable to get emergency actions"), nil, err
+		}
+		if action == nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgApproveEmergencyAction, "no emergency action to approve"), nil, nil
+		}
+
+		guardian := signers[r.Intn(len(signers))]
+		msg := &types.MsgApproveEmergencyAction{
+			Guardian: guardian.Address.String(),
+			ActionID: action.ID,
+		}
+
+		opMsg, _, err := deliver(r, app, ctx, txGen, ak, bk, guardian, msg, nil)
+		if err != nil || !opMsg.OK {
+			return opMsg, nil, err
+		}
+
+		var futureOps []simtypes.FutureOperation
+		if action.ExecuteAfter == nil && uint32(len(action.Approvals)+1) >= capsule.GuardianThreshold {
+			futureOps = append(futureOps, executeEmergencyActionFuture(ctx, txGen, ak, bk, k, action.ID))
+		}
+
+		return opMsg, futureOps, nil
+	}
+}
+
+// SimulateMsgVetoEmergencyAction generates a MsgVetoEmergencyAction for a random pending
+// emergency action
+func SimulateMsgVetoEmergencyAction(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		action, _, signers, err := randomEmergencyAction(r, ctx, k, func(action *types.EmergencyAction, capsule *types.TimeCapsule) []simtypes.Account {
+			if action.Status != types.EmergencyStatusPending {
+				return nil
+			}
+			return challengers(accs, capsule, action)
+		})
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgVetoEmergencyAction, "unable to get emergency actions"), nil, err
+		}
+		if action == nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgVetoEmergencyAction, "no emergency action to veto"), nil, nil
+		}
+
+		sender := signers[r.Intn(len(signers))]
+		msg := &types.MsgVetoEmergencyAction{
+			Sender:   sender.Address.String(),
+			ActionID: action.ID,
+			Reason:   simtypes.RandStringOfLength(r, 20),
+		}
+
+		return deliver(r, app, ctx, txGen, ak, bk, sender, msg, nil)
+	}
+}
+
+// SimulateMsgExecuteEmergencyAction generates a MsgExecuteEmergencyAction for a random
+// emergency action whose delay elapsed
+func SimulateMsgExecuteEmergencyAction(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		action, _, _, err := randomEmergencyAction(r, ctx, k, func(action *types.EmergencyAction, capsule *types.TimeCapsule) []simtypes.Account {
+			if !executable(ctx, capsule, action) {
+				return nil
+			}
+			return accountsOf(accs, capsule.Guardians)
+		})
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgExecuteEmergencyAction, "unable to get emergency actions"), nil, err
+		}
+		if action == nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgExecuteEmergencyAction, "no executable emergency action"), nil, nil
+		}
+
+		return executeEmergencyAction(r, app, ctx, accs, txGen, ak, bk, k, action.ID)
+	}
+}
+
+// executeEmergencyActionFuture schedules the execution of an emergency action after the
+// emergency delay that starts with its last required approval
+func executeEmergencyActionFuture(ctx sdk.Context, txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper, actionID string) simtypes.FutureOperation {
+	delay := types.DefaultEmergencyActionDelay
+	if params, err := k.GetParams(ctx); err == nil {
+		delay = params.EmergencyActionDelay
+	}
+
+	return simtypes.FutureOperation{
+		BlockTime: ctx.BlockTime().Add(delay + time.Second),
+		Op: func(
+			r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+		) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+			return executeEmergencyAction(r, app, ctx, accs, txGen, ak, bk, k, actionID)
+		},
+	}
+}
+
+// executeEmergencyAction delivers a MsgExecuteEmergencyAction of an executable action. The
+// capsule is opened in the next block once the action waived its unlock conditions.
+func executeEmergencyAction(
+	r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account,
+	txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper, actionID string,
+) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+	action, err := k.GetEmergencyAction(ctx, actionID)
+	if err != nil {
+		return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgExecuteEmergencyAction, "emergency action not found"), nil, nil
+	}
+	capsule, err := k.GetCapsule(ctx, action.CapsuleID)
+	if err != nil {
+		return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgExecuteEmergencyAction, "capsule not found"), nil, nil
+	}
+	if !executable(ctx, capsule, action) {
+		return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgExecuteEmergencyAction, "emergency action not executable"), nil, nil
+	}
+
+	guardians := accountsOf(accs, capsule.Guardians)
+	if len(guardians) == 0 {
+		return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgExecuteEmergencyAction, "no guardian account"), nil, nil
+	}
+	guardian := guardians[r.Intn(len(guardians))]
+
+	msg := &types.MsgExecuteEmergencyAction{
+		Guardian: guardian.Address.String(),
+		ActionID: actionID,
+	}
+
+	opMsg, _, err := deliver(r, app, ctx, txGen, ak, bk, guardian, msg, nil)
+	if err != nil || !opMsg.OK {
+		return opMsg, nil, err
+	}
+
+	var futureOps []simtypes.FutureOperation
+	switch action.ActionType {
+	case types.EmergencyActionForceUnlock, types.EmergencyActionContractDeletion:
+		futureOps = append(futureOps, simtypes.FutureOperation{
+			BlockHeight: int(ctx.BlockHeight()) + 1,
+			Op:          simulateOpenDueCapsule(txGen, ak, bk, k, capsule.ID),
+		})
+	}
+
+	return opMsg, futureOps, nil
+}
+
+// SimulateMsgReverseEmergencyAction generates a MsgReverseEmergencyAction for a random
+// executed emergency action within its reversal window
+func SimulateMsgReverseEmergencyAction(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		action, _, signers, err := randomEmergencyAction(r, ctx, k, func(action *types.EmergencyAction, capsule *types.TimeCapsule) []simtypes.Account {
+			if action.Status != types.EmergencyStatusExecuted || !action.IsReversible || capsule.Status != types.CapsuleStatus_ACTIVE {
+				return nil
+			}
+			if action.ReversibleUntil != nil && ctx.BlockTime().After(*action.ReversibleUntil) {
+				return nil
+			}
+			if action.ActionType == types.EmergencyActionRotateRecipient && capsule.Recipient != action.NewRecipient {
+				return nil
+			}
+			return challengers(accs, capsule, action)
+		})
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgReverseEmergencyAction, "unable to get emergency actions"), nil, err
+		}
+		if action == nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgReverseEmergencyAction, "no reversible emergency action"), nil, nil
+		}
+
+		sender := signers[r.Intn(len(signers))]
+		msg := &types.MsgReverseEmergencyAction{
+			Sender:   sender.Address.String(),
+			ActionID: action.ID,
+			Reason:   simtypes.RandStringOfLength(r, 20),
+		}
+
+		return deliver(r, app, ctx, txGen, ak, bk, sender, msg, nil)
+	}
+}
+
+// SimulateMsgAmendCapsule generates a MsgAmendCapsule with new random content for a
+// random capsule
+func SimulateMsgAmendCapsule(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		capsule, err := randomCapsule(r, ctx, k, func(capsule *types.TimeCapsule) bool {
+			_, ok := findAccount(accs, capsule.Owner)
+			return ok && capsule.Status == types.CapsuleStatus_ACTIVE && !capsule.Frozen && !capsule.IsPublicReveal() &&
+				capsule.CurrentVersion() < types.MaxCapsuleVersions
+		})
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgAmendCapsule, "unable to get capsules"), nil, err
+		}
+		if capsule == nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgAmendCapsule, "no amendable capsule"), nil, nil
+		}
+
+		params, err := k.GetParams(ctx)
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgAmendCapsule, "unable to get params"), nil, err
+		}
+
+		owner, _ := findAccount(accs, capsule.Owner)
+		msg := &types.MsgAmendCapsule{
+			Owner:       capsule.Owner,
+			CapsuleID:   capsule.ID,
+			Data:        []byte(simtypes.RandStringOfLength(r, simtypes.RandIntBetween(r, 16, 1024))),
+			Title:       simtypes.RandStringOfLength(r, 10),
+			Compression: compressions[r.Intn(len(compressions))],
+		}
+
+		fee := params.StorageFee(int64(len(msg.Data) + 64))
+		if !bk.SpendableCoins(ctx, owner.Address).IsAllGTE(fee) {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgAmendCapsule, "insufficient funds"), nil, nil
+		}
+
+		return deliver(r, app, ctx, txGen, ak, bk, owner, msg, fee)
+	}
+}
+
+// SimulateMsgRotateRecipient generates a MsgRotateRecipient of a random capsule by its
+// owner or executor
+func SimulateMsgRotateRecipient(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		capsule, err := randomCapsule(r, ctx, k, func(capsule *types.TimeCapsule) bool {
+			return capsule.Status == types.CapsuleStatus_ACTIVE && !capsule.Frozen && !capsule.IsPublicReveal() &&
+				len(accountsOf(accs, []string{capsule.Owner, capsule.Executor})) > 0
+		})
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRotateRecipient, "unable to get capsules"), nil, err
+		}
+		if capsule == nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRotateRecipient, "no capsule to rotate"), nil, nil
+		}
+
+		newRecipient, _ := simtypes.RandomAcc(r, accs)
+		if newRecipient.Address.String() == capsule.Recipient && len(capsule.RecipientKey) == 0 {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRotateRecipient, "new recipient is the recipient"), nil, nil
+		}
+
+		senders := accountsOf(accs, []string{capsule.Owner, capsule.Executor})
+		sender := senders[r.Intn(len(senders))]
+		msg := types.NewMsgRotateRecipient(sender.Address.String(), capsule.ID, newRecipient.Address.String(), nil)
+
+		return deliver(r, app, ctx, txGen, ak, bk, sender, msg, nil)
+	}
+}
+
+// SimulateMsgGrantCapsuleAllowance generates a MsgGrantCapsuleAllowance from a random
+// sponsor to another random account
+func SimulateMsgGrantCapsuleAllowance(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		sponsor, _ := simtypes.RandomAcc(r, accs)
+		grantee, _ := simtypes.RandomAcc(r, accs)
+		if sponsor.Address.Equals(grantee.Address) {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgGrantCapsuleAllowance, "sponsor is the grantee"), nil, nil
+		}
+
+		var maxCapsules uint32
+		var maxBytes uint64
+		if r.Intn(2) == 0 {
+			maxCapsules = uint32(simtypes.RandIntBetween(r, 1, 11))
+		}
+		if r.Intn(2) == 0 {
+			maxBytes = uint64(simtypes.RandIntBetween(r, 1024, 16*1024))
+		}
+
+		// The spend limit covers the fees of a few capsules
+		var spendLimit sdk.Coins
+		if r.Intn(2) == 0 {
+			fee, err := k.CreationFee(ctx)
+			if err != nil {
+				return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgGrantCapsuleAllowance, "unable to get creation fee"), nil, err
+			}
+			spendLimit = fee.MulInt(math.NewInt(int64(simtypes.RandIntBetween(r, 1, 11))))
+		}
+
+		var expiration *time.Time
+		if r.Intn(2) == 0 {
+			expiresAt := ctx.BlockTime().Add(randomDuration(r, time.Hour, 7*24*time.Hour))
+			expiration = &expiresAt
+		}
+
+		msg := types.NewMsgGrantCapsuleAllowance(sponsor.Address.String(), grantee.Address.String(), maxCapsules, maxBytes, spendLimit, expiration)
+
+		return deliver(r, app, ctx, txGen, ak, bk, sponsor, msg, nil)
+	}
+}
+
+// SimulateMsgRevokeCapsuleAllowance generates a MsgRevokeCapsuleAllowance for a random
+// allowance granted by a simulation account
+func SimulateMsgRevokeCapsuleAllowance(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		allowances, err := k.GetAllCapsuleAllowances(ctx)
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRevokeCapsuleAllowance, "unable to get capsule allowances"), nil, err
+		}
+		if len(allowances) == 0 {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRevokeCapsuleAllowance, "no capsule allowance to revoke"), nil, nil
+		}
+
+		allowance := allowances[r.Intn(len(allowances))]
+		sponsor, found := findAccount(accs, allowance.Sponsor)
+		if !found {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRevokeCapsuleAllowance, "sponsor is not a simulation account"), nil, nil
+		}
+
+		msg := types.NewMsgRevokeCapsuleAllowance(allowance.Sponsor, allowance.Grantee)
+
+		return deliver(r, app, ctx, txGen, ak, bk, sponsor, msg, nil)
+	}
+}
+
+// SimulateMsgRegisterAttestor generates a MsgRegisterAttestor of a random account, which
+// signs its attestations with its account key
+func SimulateMsgRegisterAttestor(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		attestor, _ := simtypes.RandomAcc(r, accs)
+
+		msg := types.NewMsgRegisterAttestor(
+			attestor.Address.String(),
+			attestor.PubKey.Type(),
+			attestor.PubKey.Bytes(),
+			simtypes.RandStringOfLength(r, simtypes.RandIntBetween(r, 8, 64)),
+		)
+
+		return deliver(r, app, ctx, txGen, ak, bk, attestor, msg, nil)
+	}
+}
+
+// SimulateMsgSubmitAttestation generates a MsgSubmitAttestation of a listed attestor of a
+// random capsule, relayed by a random account
+func SimulateMsgSubmitAttestation(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		capsule, err := randomCapsule(r, ctx, k, func(capsule *types.TimeCapsule) bool {
+			return capsule.Status == types.CapsuleStatus_ACTIVE && capsule.Attestation != nil && !capsule.Attestation.IsMet()
+		})
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgSubmitAttestation, "unable to get capsules"), nil, err
+		}
+		if capsule == nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgSubmitAttestation, "no capsule awaiting attestations"), nil, nil
+		}
+
+		attestations, err := k.GetCapsuleAttestations(ctx, capsule.ID)
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgSubmitAttestation, "unable to get attestations"), nil, err
+		}
+		attested := make(map[string]bool)
+		for _, attestation := range attestations {
+			attested[attestation.Attestor] = true
+		}
+
+		var candidates []simtypes.Account
+		for _, acc := range accountsOf(accs, capsule.Attestation.Attestors) {
+			attestor, err := k.GetAttestor(ctx, acc.Address.String())
+			if err != nil || attested[attestor.Address] || !acc.PubKey.Equals(mustAttestorKey(attestor)) {
+				continue
+			}
+			if capsule.Attestation.CuratedOnly && !attestor.Curated {
+				continue
+			}
+			candidates = append(candidates, acc)
+		}
+		if len(candidates) == 0 {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgSubmitAttestation, "no attestor can attest"), nil, nil
+		}
+		attestor := candidates[r.Intn(len(candidates))]
+
+		signBytes := types.AttestationSignBytes(ctx.ChainID(), capsule.ID, capsule.Attestation.Claim)
+		signature, err := attestor.PrivKey.Sign(signBytes)
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgSubmitAttestation, "unable to sign attestation"), nil, err
+		}
+
+		submitter, _ := simtypes.RandomAcc(r, accs)
+		msg := types.NewMsgSubmitAttestation(submitter.Address.String(), capsule.ID, attestor.Address.String(), capsule.Attestation.Claim, signature)
+
+		return deliver(r, app, ctx, txGen, ak, bk, submitter, msg, nil)
+	}
+}
+
+// SimulateMsgRevokeCapsuleAttestor generates a MsgRevokeCapsuleAttestor removing a random
+// attestor from a capsule that lists more attestors than its threshold
+func SimulateMsgRevokeCapsuleAttestor(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		capsule, err := randomCapsule(r, ctx, k, func(capsule *types.TimeCapsule) bool {
+			return capsule.Status == types.CapsuleStatus_ACTIVE && capsule.Attestation != nil &&
+				len(capsule.Attestation.Attestors) > int(capsule.Attestation.Threshold)
+		})
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRevokeCapsuleAttestor, "unable to get capsules"), nil, err
+		}
+		if capsule == nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRevokeCapsuleAttestor, "no capsule with a spare attestor"), nil, nil
+		}
+
+		owner, found := findAccount(accs, capsule.Owner)
+		if !found {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgRevokeCapsuleAttestor, "owner is not a simulation account"), nil, nil
+		}
+
+		attestor := capsule.Attestation.Attestors[r.Intn(len(capsule.Attestation.Attestors))]
+		msg := types.NewMsgRevokeCapsuleAttestor(capsule.Owner, capsule.ID, attestor)
+
+		return deliver(r, app, ctx, txGen, ak, bk, owner, msg, nil)
+	}
+}
+
+// SimulateMsgEraseCapsule generates a MsgEraseCapsule for a random finished capsule
+func SimulateMsgEraseCapsule(txGen client.TxConfig, ak types.AccountKeeper, bk types.BankKeeper, k keeper.Keeper) simtypes.Operation {
+	return func(
+		r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simtypes.Account, chainID string,
+	) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+		capsule, err := randomCapsule(r, ctx, k, func(capsule *types.TimeCapsule) bool {
+			if _, ok := findAccount(accs, capsule.Owner); !ok {
+				return false
+			}
+			return capsule.Status != types.CapsuleStatus_ACTIVE && !capsule.IsPurged() &&
+				!capsule.IsPublicReveal() && !capsule.Frozen
+		})
+		if err != nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgEraseCapsule, "unable to get capsules"), nil, err
+		}
+		if capsule == nil {
+			return simtypes.NoOpMsg(types.ModuleName, types.TypeMsgEraseCapsule, "no erasable capsule"), nil, nil
+		}
+
+		owner, _ := findAccount(accs, capsule.Owner)
+		msg := types.NewMsgEraseCapsule(capsule.Owner, capsule.ID)
+
+		return deliver(r, app, ctx, txGen, ak, bk, owner, msg, nil)
+	}
+}
+
+// deliver signs msg with the simulation account and delivers it with random fees out of
+// the coins not spent by the message
+func deliver(
+	r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, txGen client.TxConfig,
+	ak types.AccountKeeper, bk types.BankKeeper, simAccount simtypes.Account, msg sdk.Msg, spent sdk.Coins,
+) (simtypes.OperationMsg, []simtypes.FutureOperation, error) {
+	txCtx := simulation.OperationInput{
+		R:               r,
+		App:             app,
+		TxGen:           txGen,
+		Cdc:             nil,
+		Msg:             msg,
+		CoinsSpentInMsg: spent,
+		Context:         ctx,
+		SimAccount:      simAccount,
+		AccountKeeper:   ak,
+		Bankkeeper:      bk,
+		ModuleName:      types.ModuleName,
+	}
+
+	return simulation.GenAndDeliverTxWithRandFees(txCtx)
+}
+
+// randomSponsor returns, half of the time, a random sponsor whose allowance for the
+// creator covers a capsule of dataSize bytes and its fees, or an empty string
+func randomSponsor(r *rand.Rand, ctx sdk.Context, k keeper.Keeper, creator simtypes.Account, dataSize int64, fees sdk.Coins) (string, error) {
+	if r.Intn(2) == 0 {
+		return "", nil
+	}
+
+	allowances, err := k.GetAllCapsuleAllowances(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var sponsors []string
+	for _, allowance := range allowances {
+		if allowance.Grantee != creator.Address.String() {
+			continue
+		}
+		if _, err := allowance.Use(ctx.BlockTime(), dataSize, fees); err == nil {
+			sponsors = append(sponsors, allowance.Sponsor)
+		}
+	}
+	if len(sponsors) == 0 {
+		return "", nil
+	}
+	return sponsors[r.Intn(len(sponsors))], nil
+}
+
+// randomAttestationCondition returns, half of the time, an attestation condition listing
+// random registered attestors, or nil
+func randomAttestationCondition(r *rand.Rand, ctx sdk.Context, k keeper.Keeper, accs []simtypes.Account) (*types.AttestationCondition, error) {
+	if r.Intn(2) == 0 {
+		return nil, nil
+	}
+
+	attestors, err := k.GetAllAttestors(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(attestors) == 0 {
+		return nil, nil
+	}
+
+	r.Shuffle(len(attestors), func(i, j int) { attestors[i], attestors[j] = attestors[j], attestors[i] })
+	count := simtypes.RandIntBetween(r, 1, min(len(attestors), 4)+1)
+
+	condition := &types.AttestationCondition{
+		Threshold: uint32(simtypes.RandIntBetween(r, 1, count+1)),
+		Claim:     simtypes.RandStringOfLength(r, simtypes.RandIntBetween(r, 8, 64)),
+	}
+	for _, attestor := range attestors[:count] {
+		condition.Attestors = append(condition.Attestors, attestor.Address)
+	}
+
+	return condition, nil
+}
+
+// mustAttestorKey returns the key of a stored, and thus valid, attestor
+func mustAttestorKey(attestor *types.Attestor) cryptotypes.PubKey {
+	pubKey, err := attestor.PublicKey()
+	if err != nil {
+		panic(err)
+	}
+	return pubKey
+}
+
+// randomCapsule returns a random capsule matching the filter, or nil if there is none
+func randomCapsule(r *rand.Rand, ctx sdk.Context, k keeper.Keeper, filter func(*types.TimeCapsule) bool) (*types.TimeCapsule, error) {
+	capsules, err := k.GetAllCapsules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*types.TimeCapsule
+	for i := range capsules {
+		if filter(&capsules[i]) {
+			candidates = append(candidates, &capsules[i])
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	return candidates[r.Intn(len(candidates))], nil
+}
+
+// randomEmergencyAction returns a random emergency action, with its capsule, for which
+// signers returns simulation accounts that can sign the simulated message
+func randomEmergencyAction(
+	r *rand.Rand, ctx sdk.Context, k keeper.Keeper,
+	signers func(*types.EmergencyAction, *types.TimeCapsule) []simtypes.Account,
+) (*types.EmergencyAction, *types.TimeCapsule, []simtypes.Account, error) {
+	actions, err := k.GetAllEmergencyActions(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	for _, i := range r.Perm(len(actions)) {
+		action := &actions[i]
+		capsule, err := k.GetCapsule(ctx, action.CapsuleID)
+		if err != nil {
+			continue
+		}
+		if accs := signers(action, capsule); len(accs) > 0 {
+			return action, capsule, accs, nil
+		}
+	}
+
+	return nil, nil, nil, nil
+}
+
+// emergencyActionApplies mirrors the keeper's checks of an emergency action against the
+// capsule it applies to
+func emergencyActionApplies(capsule *types.TimeCapsule, action *types.EmergencyAction) bool {
+	if capsule.Status != types.CapsuleStatus_ACTIVE {
+		return false
+	}
+	if capsule.Frozen {
+		return false
+	}
+
+	canForceUnlock := !capsule.ForceUnlocked && capsule.Recipient != "" && !capsule.IsTimelocked()
+	switch action.ActionType {
+	case types.EmergencyActionContractDeletion:
+		return capsule.CapsuleType == types.CapsuleType_CONDITIONAL && capsule.ConditionContract != "" && canForceUnlock
+	case types.EmergencyActionForceUnlock:
+		return capsule.CapsuleType != types.CapsuleType_SAFE && canForceUnlock
+	case types.EmergencyActionFreeze:
+		return true
+	case types.EmergencyActionRotateRecipient:
+		return action.NewRecipient != capsule.Recipient || len(capsule.RecipientKey) > 0
+	}
+	return false
+}
+
+// executable checks whether a pending emergency action was approved, waited out its
+// delay and still applies to the capsule
+func executable(ctx sdk.Context, capsule *types.TimeCapsule, action *types.EmergencyAction) bool {
+	return action.Status == types.EmergencyStatusPending && action.ExecuteAfter != nil &&
+		!ctx.BlockTime().Before(*action.ExecuteAfter) && emergencyActionApplies(capsule, action)
+}
+
+// transferable checks whether a capsule can change hands
+func transferable(capsule *types.TimeCapsule) bool {
+	return capsule.Status == types.CapsuleStatus_ACTIVE && !capsule.Frozen
+}
+
+// capsuleAccessor returns a simulation account that can open the capsule: its recipient,
+// or the owner of a safe
+func capsuleAccessor(accs []simtypes.Account, capsule *types.TimeCapsule) (simtypes.Account, bool) {
+	if acc, ok := findAccount(accs, capsule.Recipient); ok {
+		return acc, true
+	}
+	if capsule.CapsuleType == types.CapsuleType_SAFE {
+		return findAccount(accs, capsule.Owner)
+	}
+	return simtypes.Account{}, false
+}
+
+// challengers returns the simulation accounts that can veto or reverse an emergency action
+func challengers(accs []simtypes.Account, capsule *types.TimeCapsule, action *types.EmergencyAction) []simtypes.Account {
+	addresses := append([]string{capsule.Recipient}, capsule.Guardians...)
+	if action.ActionType == types.EmergencyActionRotateRecipient {
+		addresses = append(addresses, action.PreviousRecipient)
+	}
+	return accountsOf(accs, addresses)
+}
+
+// randomGuardians nominates up to three guardians besides the creator for some capsules
+func randomGuardians(r *rand.Rand, accs []simtypes.Account, creator simtypes.Account) ([]string, uint32) {
+	if r.Intn(3) != 0 {
+		return nil, 0
+	}
+
+	var guardians []string
+	seen := map[string]bool{creator.Address.String(): true}
+	for i := simtypes.RandIntBetween(r, 1, 4); i > 0; i-- {
+		guardian, _ := simtypes.RandomAcc(r, accs)
+		if address := guardian.Address.String(); !seen[address] {
+			seen[address] = true
+			guardians = append(guardians, address)
+		}
+	}
+	if len(guardians) == 0 {
+		return nil, 0
+	}
+
+	return guardians, uint32(simtypes.RandIntBetween(r, 1, len(guardians)+1))
+}
+
+// randomDuration returns a random duration in [min, max), in whole seconds
+func randomDuration(r *rand.Rand, min, max time.Duration) time.Duration {
+	return time.Duration(simtypes.RandIntBetween(r, int(min.Seconds()), int(max.Seconds()))) * time.Second
+}
+
+// accountsOf returns the distinct simulation accounts of the given addresses
+func accountsOf(accs []simtypes.Account, addresses []string) []simtypes.Account {
+	var found []simtypes.Account
+	seen := make(map[string]bool)
+	for _, address := range addresses {
+		if address == "" || seen[address] {
+			continue
+		}
+		seen[address] = true
+		if acc, ok := findAccount(accs, address); ok {
+			found = append(found, acc)
+		}
+	}
+	return found
+}
+
+// findAccount returns the simulation account of a bech32 address
+func findAccount(accs []simtypes.Account, address string) (simtypes.Account, bool) {
+	addr, err := sdk.AccAddressFromBech32(address)
+	if err != nil {
+		return simtypes.Account{}, false
+	}
+	return simtypes.FindAccount(accs, addr)
+}
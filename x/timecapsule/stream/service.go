@@ -0,0 +1,112 @@
+package stream
+
+import (
+	"context"
+
+	gogogrpc "github.com/cosmos/gogoproto/grpc"
+	"google.golang.org/grpc"
+)
+
+// WatchServiceName is the full name of the watch service
+const WatchServiceName = "cosmos.timecapsule.v1.Watch"
+
+// WatchCapsulesMethod is the full method name of the WatchCapsules stream
+const WatchCapsulesMethod = "/" + WatchServiceName + "/WatchCapsules"
+
+// WatchServer is the server API for the Watch service
+type WatchServer interface {
+	// WatchCapsules streams the events of the capsules matching a filter, replaying
+	// the retained blocks from a cursor or height first
+	WatchCapsules(*WatchCapsulesRequest, Watch_WatchCapsulesServer) error
+}
+
+// Watch_WatchCapsulesServer is the server side of the WatchCapsules stream
+type Watch_WatchCapsulesServer interface {
+	Send(*CapsuleEvent) error
+	grpc.ServerStream
+}
+
+type watchWatchCapsulesServer struct {
+	grpc.ServerStream
+}
+
+func (x *watchWatchCapsulesServer) Send(m *CapsuleEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Watch_WatchCapsules_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCapsulesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WatchServer).WatchCapsules(m, &watchWatchCapsulesServer{stream})
+}
+
+// Watch_serviceDesc describes the Watch service of watch.proto
+var Watch_serviceDesc = grpc.ServiceDesc{
+	ServiceName: WatchServiceName,
+	HandlerType: (*WatchServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchCapsules",
+			Handler:       _Watch_WatchCapsules_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "cosmos/timecapsule/v1/watch.proto",
+}
+
+// RegisterWatchServer registers the Watch service with a gRPC server. The server must
+// serve streams, which the BaseApp query router does not.
+func RegisterWatchServer(s gogogrpc.Server, srv WatchServer) {
+	s.RegisterService(&Watch_serviceDesc, srv)
+}
+
+// WatchClient is the client API for the Watch service
+type WatchClient interface {
+	// WatchCapsules streams the events of the capsules matching a filter
+	WatchCapsules(ctx context.Context, in *WatchCapsulesRequest, opts ...grpc.CallOption) (Watch_WatchCapsulesClient, error)
+}
+
+// Watch_WatchCapsulesClient is the client side of the WatchCapsules stream
+type Watch_WatchCapsulesClient interface {
+	Recv() (*CapsuleEvent, error)
+	grpc.ClientStream
+}
+
+type watchClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewWatchClient creates a client of the Watch service
+func NewWatchClient(cc grpc.ClientConnInterface) WatchClient {
+	return &watchClient{cc}
+}
+
+func (c *watchClient) WatchCapsules(ctx context.Context, in *WatchCapsulesRequest, opts ...grpc.CallOption) (Watch_WatchCapsulesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Watch_serviceDesc.Streams[0], WatchCapsulesMethod, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &watchWatchCapsulesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type watchWatchCapsulesClient struct {
+	grpc.ClientStream
+}
+
+func (x *watchWatchCapsulesClient) Recv() (*CapsuleEvent, error) {
+	m := new(CapsuleEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,195 @@
+package stream
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmos/gogoproto/proto"
+)
+
+// MaxWatchFilters bounds the capsule ids, owners and recipients a watch request can select
+const MaxWatchFilters = 100
+
+// CapsuleEventType is the lifecycle event of a capsule
+type CapsuleEventType int32
+
+const (
+	CapsuleEventType_UNSPECIFIED CapsuleEventType = 0
+	CapsuleEventType_CREATED     CapsuleEventType = 1 // Capsule created
+	CapsuleEventType_HEARTBEAT   CapsuleEventType = 2 // Dead man's switch owner reported activity
+	CapsuleEventType_UNLOCKABLE  CapsuleEventType = 3 // Capsule reached its unlock trigger
+	CapsuleEventType_OPENED      CapsuleEventType = 4 // Capsule opened or revealed
+	CapsuleEventType_TRANSFERRED CapsuleEventType = 5 // Capsule changed owner
+	CapsuleEventType_CANCELLED   CapsuleEventType = 6 // Capsule cancelled
+)
+
+var CapsuleEventType_name = map[int32]string{
+	0: "CAPSULE_EVENT_TYPE_UNSPECIFIED",
+	1: "CAPSULE_EVENT_TYPE_CREATED",
+	2: "CAPSULE_EVENT_TYPE_HEARTBEAT",
+	3: "CAPSULE_EVENT_TYPE_UNLOCKABLE",
+	4: "CAPSULE_EVENT_TYPE_OPENED",
+	5: "CAPSULE_EVENT_TYPE_TRANSFERRED",
+	6: "CAPSULE_EVENT_TYPE_CANCELLED",
+}
+
+var CapsuleEventType_value = map[string]int32{
+	"CAPSULE_EVENT_TYPE_UNSPECIFIED": 0,
+	"CAPSULE_EVENT_TYPE_CREATED":     1,
+	"CAPSULE_EVENT_TYPE_HEARTBEAT":   2,
+	"CAPSULE_EVENT_TYPE_UNLOCKABLE":  3,
+	"CAPSULE_EVENT_TYPE_OPENED":      4,
+	"CAPSULE_EVENT_TYPE_TRANSFERRED": 5,
+	"CAPSULE_EVENT_TYPE_CANCELLED":   6,
+}
+
+func init() {
+	proto.RegisterEnum("cosmos.timecapsule.v1.CapsuleEventType", CapsuleEventType_name, CapsuleEventType_value)
+}
+
+// String returns the proto name of the event type
+func (t CapsuleEventType) String() string {
+	if name, ok := CapsuleEventType_name[int32(t)]; ok {
+		return name
+	}
+	return strconv.Itoa(int(t))
+}
+
+// ParseCapsuleEventType parses an event type from its proto name or its short name,
+// e.g. CAPSULE_EVENT_TYPE_UNLOCKABLE or unlockable
+func ParseCapsuleEventType(s string) (CapsuleEventType, error) {
+	name := strings.ToUpper(strings.TrimSpace(s))
+	if !strings.HasPrefix(name, "CAPSULE_EVENT_TYPE_") {
+		name = "CAPSULE_EVENT_TYPE_" + name
+	}
+	value, ok := CapsuleEventType_value[name]
+	if !ok || value == int32(CapsuleEventType_UNSPECIFIED) {
+		return CapsuleEventType_UNSPECIFIED, fmt.Errorf("unknown capsule event type %s", s)
+	}
+	return CapsuleEventType(value), nil
+}
+
+// WatchCapsulesRequest selects the capsules to watch. An event matches when it is about
+// one of the capsule ids, owners or recipients, or any capsule if none is set.
+type WatchCapsulesRequest struct {
+	CapsuleIds []uint64 `protobuf:"varint,1,rep,packed,name=capsule_ids,json=capsuleIds,proto3" json:"capsule_ids,omitempty"`
+	// Owners also match the previous owner of a transferred capsule
+	Owners     []string `protobuf:"bytes,2,rep,name=owners,proto3" json:"owners,omitempty"`
+	Recipients []string `protobuf:"bytes,3,rep,name=recipients,proto3" json:"recipients,omitempty"`
+	// EventTypes restricts the streamed events, all are streamed if empty
+	EventTypes []CapsuleEventType `protobuf:"varint,4,rep,packed,name=event_types,json=eventTypes,proto3,enum=cosmos.timecapsule.v1.CapsuleEventType" json:"event_types,omitempty"`
+	// Cursor resumes the stream after the event the cursor was received with
+	Cursor string `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// StartHeight replays the events from a height, if no cursor is given
+	StartHeight int64 `protobuf:"varint,6,opt,name=start_height,json=startHeight,proto3" json:"start_height,omitempty"`
+}
+
+func (m *WatchCapsulesRequest) Reset()         { *m = WatchCapsulesRequest{} }
+func (m *WatchCapsulesRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchCapsulesRequest) ProtoMessage()    {}
+
+// Validate validates a watch request
+func (m *WatchCapsulesRequest) Validate() error {
+	if len(m.CapsuleIds) > MaxWatchFilters || len(m.Owners) > MaxWatchFilters || len(m.Recipients) > MaxWatchFilters {
+		return fmt.Errorf("cannot watch more than %d capsule ids, owners or recipients", MaxWatchFilters)
+	}
+	for _, eventType := range m.EventTypes {
+		if _, ok := CapsuleEventType_name[int32(eventType)]; !ok || eventType == CapsuleEventType_UNSPECIFIED {
+			return fmt.Errorf("invalid event type %s", eventType)
+		}
+	}
+	if m.StartHeight < 0 {
+		return fmt.Errorf("start height cannot be negative")
+	}
+	if m.Cursor != "" {
+		if m.StartHeight != 0 {
+			return fmt.Errorf("cannot resume from both a cursor and a start height")
+		}
+		if _, err := ParseCursor(m.Cursor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Matches checks whether an event is selected by the request
+func (m *WatchCapsulesRequest) Matches(event *CapsuleEvent) bool {
+	if len(m.EventTypes) > 0 && !contains(m.EventTypes, event.Type) {
+		return false
+	}
+
+	if len(m.CapsuleIds) == 0 && len(m.Owners) == 0 && len(m.Recipients) == 0 {
+		return true
+	}
+
+	return contains(m.CapsuleIds, event.CapsuleId) ||
+		(event.Owner != "" && contains(m.Owners, event.Owner)) ||
+		(event.Type == CapsuleEventType_TRANSFERRED && contains(m.Owners, event.Attributes[attributeKeyFrom])) ||
+		(event.Recipient != "" && contains(m.Recipients, event.Recipient))
+}
+
+func contains[T comparable](list []T, value T) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// CapsuleEvent is a lifecycle event of a capsule
+type CapsuleEvent struct {
+	// Cursor identifies the event, to resume the stream after it
+	Cursor    string    `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Height    int64     `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	BlockTime time.Time `protobuf:"bytes,3,opt,name=block_time,json=blockTime,proto3,stdtime" json:"block_time"`
+	// TxHash is empty for the events of the block, e.g. unlockable capsules
+	TxHash    string           `protobuf:"bytes,4,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	Type      CapsuleEventType `protobuf:"varint,5,opt,name=type,proto3,enum=cosmos.timecapsule.v1.CapsuleEventType" json:"type,omitempty"`
+	CapsuleId uint64           `protobuf:"varint,6,opt,name=capsule_id,json=capsuleId,proto3" json:"capsule_id,omitempty"`
+	Owner     string           `protobuf:"bytes,7,opt,name=owner,proto3" json:"owner,omitempty"`
+	Recipient string           `protobuf:"bytes,8,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	// AbciEvent is the module event the event was derived from
+	AbciEvent  string            `protobuf:"bytes,9,opt,name=abci_event,json=abciEvent,proto3" json:"abci_event,omitempty"`
+	Attributes map[string]string `protobuf:"bytes,10,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *CapsuleEvent) Reset()         { *m = CapsuleEvent{} }
+func (m *CapsuleEvent) String() string { return proto.CompactTextString(m) }
+func (*CapsuleEvent) ProtoMessage()    {}
+
+// Cursor is the position of an event in the stream: the index of the event among the
+// capsule events of its block
+type Cursor struct {
+	Height int64
+	Index  int
+}
+
+// String encodes the cursor as height/index
+func (c Cursor) String() string {
+	return fmt.Sprintf("%d/%d", c.Height, c.Index)
+}
+
+// After checks whether a position comes after the cursor
+func (c Cursor) After(height int64, index int) bool {
+	return height > c.Height || (height == c.Height && index > c.Index)
+}
+
+// ParseCursor decodes a height/index cursor
+func ParseCursor(s string) (Cursor, error) {
+	heightStr, indexStr, ok := strings.Cut(s, "/")
+	if !ok {
+		return Cursor{}, fmt.Errorf("invalid cursor %q, expected height/index", s)
+	}
+	height, err := strconv.ParseInt(heightStr, 10, 64)
+	if err != nil || height <= 0 {
+		return Cursor{}, fmt.Errorf("invalid cursor height %q", heightStr)
+	}
+	index, err := strconv.Atoi(indexStr)
+	if err != nil || index < 0 {
+		return Cursor{}, fmt.Errorf("invalid cursor index %q", indexStr)
+	}
+	return Cursor{Height: height, Index: index}, nil
+}
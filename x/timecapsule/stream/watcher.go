@@ -0,0 +1,360 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"cosmossdk.io/log"
+	storetypes "cosmossdk.io/store/types"
+)
+
+const (
+	// FlagWatchHistoryBlocks is the app option setting the number of committed blocks
+	// the watcher keeps to replay
+	FlagWatchHistoryBlocks = "timecapsule.watch-history-blocks"
+
+	// DefaultHistoryBlocks is the number of committed blocks a watcher keeps to replay
+	DefaultHistoryBlocks = 1000
+
+	// DefaultSubscriberBuffer is the number of events a subscriber can fall behind
+	// before it is dropped
+	DefaultSubscriberBuffer = 1024
+)
+
+// The module events and attributes the watcher follows. They are those of
+// x/timecapsule/types, which the watcher reads from the ABCI responses like any other
+// consumer, so that it does not depend on the module's generated types.
+const (
+	moduleName = "timecapsule"
+
+	eventTypeCapsuleCreated     = "capsule_created"
+	eventTypeCapsuleHeartbeat   = "capsule_heartbeat"
+	eventTypeCapsuleUnlockable  = "capsule_unlockable"
+	eventTypeCapsuleAttested    = "capsule_attested"
+	eventTypeCapsuleOpened      = "capsule_opened"
+	eventTypeCapsuleRevealed    = "capsule_revealed"
+	eventTypeCapsuleTransferred = "capsule_transferred"
+	eventTypeCapsuleCancelled   = "capsule_cancelled"
+
+	attributeKeyCapsuleID = "capsule_id"
+	attributeKeyOwner     = "owner"
+	attributeKeyRecipient = "recipient"
+	attributeKeyFrom      = "from"
+	attributeKeyTo        = "to"
+	attributeKeyMode      = "mode"
+)
+
+// eventTypes maps the module events to the streamed lifecycle events
+var eventTypes = map[string]CapsuleEventType{
+	eventTypeCapsuleCreated:     CapsuleEventType_CREATED,
+	eventTypeCapsuleHeartbeat:   CapsuleEventType_HEARTBEAT,
+	eventTypeCapsuleUnlockable:  CapsuleEventType_UNLOCKABLE,
+	eventTypeCapsuleAttested:    CapsuleEventType_UNLOCKABLE,
+	eventTypeCapsuleOpened:      CapsuleEventType_OPENED,
+	eventTypeCapsuleRevealed:    CapsuleEventType_OPENED,
+	eventTypeCapsuleTransferred: CapsuleEventType_TRANSFERRED,
+	eventTypeCapsuleCancelled:   CapsuleEventType_CANCELLED,
+}
+
+// block holds the capsule events of a block
+type block struct {
+	height int64
+	events []*CapsuleEvent
+}
+
+// Watcher streams capsule lifecycle events to gRPC subscribers. It is an ABCIListener:
+// the events of a block are collected from its FinalizeBlock response and published
+// once the block commits. The last committed blocks are kept in memory, so that
+// subscribers can resume from a cursor after a disconnect. Nothing is persisted, a
+// restarted node only replays the blocks it committed since.
+type Watcher struct {
+	logger           log.Logger
+	historyBlocks    int
+	subscriberBuffer int
+
+	mu          sync.Mutex
+	pending     *block
+	history     []block // committed blocks, oldest first
+	subscribers map[*subscriber]struct{}
+}
+
+var (
+	_ storetypes.ABCIListener = (*Watcher)(nil)
+	_ WatchServer             = (*Watcher)(nil)
+)
+
+// NewWatcher creates a watcher keeping the events of the given number of committed blocks
+func NewWatcher(logger log.Logger, historyBlocks int) *Watcher {
+	if historyBlocks <= 0 {
+		historyBlocks = DefaultHistoryBlocks
+	}
+	return &Watcher{
+		logger:           logger.With("module", "x/"+moduleName, "service", "watch"),
+		historyBlocks:    historyBlocks,
+		subscriberBuffer: DefaultSubscriberBuffer,
+		subscribers:      make(map[*subscriber]struct{}),
+	}
+}
+
+// ListenFinalizeBlock implements storetypes.ABCIListener. It collects the capsule events
+// of the block: the begin block events, then the events of the successful transactions,
+// then the end block events.
+func (w *Watcher) ListenFinalizeBlock(_ context.Context, req abci.RequestFinalizeBlock, res abci.ResponseFinalizeBlock) error {
+	b := &block{height: req.Height}
+	add := func(event abci.Event, txHash string) {
+		capsuleEvent, ok := toCapsuleEvent(event)
+		if !ok {
+			return
+		}
+		capsuleEvent.Cursor = Cursor{Height: req.Height, Index: len(b.events)}.String()
+		capsuleEvent.Height = req.Height
+		capsuleEvent.BlockTime = req.Time
+		capsuleEvent.TxHash = txHash
+		b.events = append(b.events, capsuleEvent)
+	}
+
+	for _, event := range res.Events {
+		if eventMode(event) != "EndBlock" {
+			add(event, "")
+		}
+	}
+	for i, result := range res.TxResults {
+		if result == nil || result.Code != abci.CodeTypeOK || i >= len(req.Txs) {
+			continue
+		}
+		txHash := fmt.Sprintf("%X", cmttypes.Tx(req.Txs[i]).Hash())
+		for _, event := range result.Events {
+			add(event, txHash)
+		}
+	}
+	for _, event := range res.Events {
+		if eventMode(event) == "EndBlock" {
+			add(event, "")
+		}
+	}
+
+	w.mu.Lock()
+	w.pending = b
+	w.mu.Unlock()
+
+	return nil
+}
+
+// ListenCommit implements storetypes.ABCIListener. It publishes the events of the
+// committed block to the history and the subscribers. It never fails, so that a
+// watcher cannot stop the node.
+func (w *Watcher) ListenCommit(_ context.Context, _ abci.ResponseCommit, _ []*storetypes.StoreKVPair) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	b := w.pending
+	w.pending = nil
+	if b == nil {
+		return nil
+	}
+
+	w.history = append(w.history, *b)
+	if len(w.history) > w.historyBlocks {
+		w.history = w.history[len(w.history)-w.historyBlocks:]
+	}
+
+	for sub := range w.subscribers {
+		sub.publish(w, b)
+	}
+
+	return nil
+}
+
+// WatchCapsules implements WatchServer
+func (w *Watcher) WatchCapsules(req *WatchCapsulesRequest, srv Watch_WatchCapsulesServer) error {
+	if err := req.Validate(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sub, replay, err := w.subscribe(req)
+	if err != nil {
+		return err
+	}
+	defer w.unsubscribe(sub)
+
+	for _, event := range replay {
+		if err := srv.Send(event); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-srv.Context().Done():
+			return srv.Context().Err()
+		case <-sub.done:
+			return sub.err
+		case event := <-sub.events:
+			if err := srv.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// subscribe registers a subscriber and returns the retained events it must be sent
+// first. Replaying and registering under the lock guarantees that the subscriber
+// misses no block between the replay and the live events.
+func (w *Watcher) subscribe(req *WatchCapsulesRequest) (*subscriber, []*CapsuleEvent, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	sub := &subscriber{
+		filter: req,
+		events: make(chan *CapsuleEvent, w.subscriberBuffer),
+		done:   make(chan struct{}),
+	}
+
+	// The position the stream resumes after, if any
+	var from *Cursor
+	switch {
+	case req.Cursor != "":
+		cursor, _ := ParseCursor(req.Cursor)
+		from = &cursor
+	case req.StartHeight > 0:
+		from = &Cursor{Height: req.StartHeight, Index: -1}
+	}
+
+	var replay []*CapsuleEvent
+	if from != nil {
+		// The block of the cursor or start height must still be retained
+		if len(w.history) > 0 && from.Height < w.history[0].height {
+			return nil, nil, status.Errorf(codes.OutOfRange, "height %d is no longer retained, the oldest retained height is %d", from.Height, w.history[0].height)
+		}
+
+		for _, b := range w.history {
+			for i, event := range b.events {
+				if from.After(b.height, i) && req.Matches(event) {
+					replay = append(replay, event)
+				}
+			}
+		}
+
+		// Blocks are published in order, the subscriber expects the block after the
+		// cursor or the last committed one
+		sub.nextHeight = from.Height
+		if from.Index >= 0 {
+			sub.nextHeight = from.Height + 1
+		}
+		if len(w.history) > 0 && w.history[len(w.history)-1].height >= sub.nextHeight {
+			sub.nextHeight = w.history[len(w.history)-1].height + 1
+		}
+	}
+
+	w.subscribers[sub] = struct{}{}
+	return sub, replay, nil
+}
+
+func (w *Watcher) unsubscribe(sub *subscriber) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.subscribers, sub)
+}
+
+// subscriber is a WatchCapsules stream. Its events are buffered, a subscriber that
+// falls behind the buffer is dropped.
+type subscriber struct {
+	filter *WatchCapsulesRequest
+	events chan *CapsuleEvent
+	done   chan struct{}
+	err    error
+
+	// nextHeight is the height of the next block the subscriber must receive, zero for
+	// subscribers that only receive the blocks committed after they subscribed
+	nextHeight int64
+}
+
+// publish sends the matching events of a committed block to the subscriber. It must be
+// called with the watcher lock held.
+func (s *subscriber) publish(w *Watcher, b *block) {
+	if s.nextHeight > 0 {
+		switch {
+		case b.height < s.nextHeight:
+			return
+		case b.height > s.nextHeight:
+			// The node restarted and lost the blocks the subscriber resumes from
+			s.drop(w, status.Errorf(codes.OutOfRange, "height %d is no longer retained, the oldest retained height is %d", s.nextHeight, b.height))
+			return
+		}
+		s.nextHeight = b.height + 1
+	}
+
+	for _, event := range b.events {
+		if !s.filter.Matches(event) {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			s.drop(w, status.Errorf(codes.ResourceExhausted, "subscriber fell behind by more than %d events, resume from the last received cursor", cap(s.events)))
+			return
+		}
+	}
+}
+
+// drop ends the stream of the subscriber with an error
+func (s *subscriber) drop(w *Watcher, err error) {
+	delete(w.subscribers, s)
+	s.err = err
+	close(s.done)
+	w.logger.Debug("dropped capsule watch subscriber", "error", err)
+}
+
+// toCapsuleEvent converts a module event to a lifecycle event
+func toCapsuleEvent(event abci.Event) (*CapsuleEvent, bool) {
+	eventType, ok := eventTypes[event.Type]
+	if !ok {
+		return nil, false
+	}
+
+	capsuleEvent := &CapsuleEvent{
+		Type:       eventType,
+		AbciEvent:  event.Type,
+		Attributes: make(map[string]string, len(event.Attributes)),
+	}
+	for _, attr := range event.Attributes {
+		switch attr.Key {
+		case attributeKeyCapsuleID:
+			id, err := strconv.ParseUint(attr.Value, 10, 64)
+			if err != nil {
+				return nil, false
+			}
+			capsuleEvent.CapsuleId = id
+		case attributeKeyOwner:
+			capsuleEvent.Owner = attr.Value
+		case attributeKeyRecipient:
+			capsuleEvent.Recipient = attr.Value
+		case attributeKeyMode:
+		default:
+			capsuleEvent.Attributes[attr.Key] = attr.Value
+		}
+	}
+
+	// The owner of a transferred capsule is the new owner
+	if eventType == CapsuleEventType_TRANSFERRED {
+		capsuleEvent.Owner = capsuleEvent.Attributes[attributeKeyTo]
+	}
+
+	return capsuleEvent, true
+}
+
+func eventMode(event abci.Event) string {
+	for _, attr := range event.Attributes {
+		if attr.Key == attributeKeyMode {
+			return attr.Value
+		}
+	}
+	return ""
+}
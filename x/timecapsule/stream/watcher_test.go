@@ -0,0 +1,260 @@
+package stream_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"cosmossdk.io/log"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/stream"
+)
+
+const (
+	alice = "cosmos1alice"
+	bob   = "cosmos1bob"
+	carol = "cosmos1carol"
+)
+
+var genesisTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// node feeds blocks to a watcher the way BaseApp calls its ABCI listeners, and serves
+// the watcher over gRPC with the codec of the node's gRPC server
+type node struct {
+	watcher *stream.Watcher
+	client  stream.WatchClient
+	height  int64
+}
+
+func newNode(t *testing.T, historyBlocks int) *node {
+	t.Helper()
+
+	watcher := stream.NewWatcher(log.NewNopLogger(), historyBlocks)
+	grpcCodec := codec.NewProtoCodec(codectypes.NewInterfaceRegistry()).GRPCCodec()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.ForceServerCodec(grpcCodec))
+	stream.RegisterWatchServer(server, watcher)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(grpcCodec)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return &node{watcher: watcher, client: stream.NewWatchClient(conn)}
+}
+
+// commit finalizes and commits the next block, with the given begin block events and
+// one transaction per list of transaction events
+func (n *node) commit(t *testing.T, blockEvents []abci.Event, txEvents ...[]abci.Event) {
+	t.Helper()
+
+	n.height++
+	req := abci.RequestFinalizeBlock{Height: n.height, Time: genesisTime.Add(time.Duration(n.height) * time.Second)}
+	res := abci.ResponseFinalizeBlock{}
+	for _, event := range blockEvents {
+		event.Attributes = append(event.Attributes, abci.EventAttribute{Key: "mode", Value: "BeginBlock"})
+		res.Events = append(res.Events, event)
+	}
+	for i, events := range txEvents {
+		req.Txs = append(req.Txs, []byte(fmt.Sprintf("tx-%d-%d", n.height, i)))
+		res.TxResults = append(res.TxResults, &abci.ExecTxResult{Code: abci.CodeTypeOK, Events: events})
+	}
+
+	require.NoError(t, n.watcher.ListenFinalizeBlock(context.Background(), req, res))
+	require.NoError(t, n.watcher.ListenCommit(context.Background(), abci.ResponseCommit{}, nil))
+}
+
+func (n *node) watch(t *testing.T, req *stream.WatchCapsulesRequest) stream.Watch_WatchCapsulesClient {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	sub, err := n.client.WatchCapsules(ctx, req)
+	require.NoError(t, err)
+	return sub
+}
+
+func recv(t *testing.T, sub stream.Watch_WatchCapsulesClient, count int) []*stream.CapsuleEvent {
+	t.Helper()
+
+	events := make([]*stream.CapsuleEvent, 0, count)
+	for len(events) < count {
+		event, err := sub.Recv()
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+	return events
+}
+
+func event(eventType string, attrs ...string) abci.Event {
+	e := abci.Event{Type: eventType}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		e.Attributes = append(e.Attributes, abci.EventAttribute{Key: attrs[i], Value: attrs[i+1]})
+	}
+	return e
+}
+
+func created(id uint64, owner, recipient string) abci.Event {
+	return event("capsule_created",
+		"capsule_id", fmt.Sprint(id), "owner", owner, "recipient", recipient)
+}
+
+func unlockable(id uint64, owner, recipient string) abci.Event {
+	return event("capsule_unlockable",
+		"capsule_id", fmt.Sprint(id), "owner", owner, "recipient", recipient)
+}
+
+func transferred(id uint64, from, to, recipient string) abci.Event {
+	return event("capsule_transferred",
+		"capsule_id", fmt.Sprint(id), "from", from, "to", to, "recipient", recipient)
+}
+
+func TestWatchCapsules(t *testing.T) {
+	n := newNode(t, 10)
+
+	// Subscribing from the next height cannot miss a block committed while the
+	// stream is being set up
+	sub := n.watch(t, &stream.WatchCapsulesRequest{Owners: []string{alice}, StartHeight: 1})
+
+	n.commit(t, nil,
+		[]abci.Event{created(1, alice, bob), event("transfer", "amount", "10stake")},
+		[]abci.Event{created(2, carol, bob)},
+	)
+	n.commit(t, []abci.Event{unlockable(1, alice, bob)},
+		[]abci.Event{transferred(1, alice, carol, bob)},
+	)
+
+	events := recv(t, sub, 3)
+
+	require.Equal(t, stream.CapsuleEventType_CREATED, events[0].Type)
+	require.Equal(t, uint64(1), events[0].CapsuleId)
+	require.Equal(t, alice, events[0].Owner)
+	require.Equal(t, bob, events[0].Recipient)
+	require.Equal(t, "1/0", events[0].Cursor)
+	require.Equal(t, int64(1), events[0].Height)
+	require.True(t, genesisTime.Add(time.Second).Equal(events[0].BlockTime))
+	require.Equal(t, fmt.Sprintf("%X", cmttypes.Tx("tx-1-0").Hash()), events[0].TxHash)
+
+	// block events come first and have no transaction
+	require.Equal(t, stream.CapsuleEventType_UNLOCKABLE, events[1].Type)
+	require.Equal(t, "2/0", events[1].Cursor)
+	require.Empty(t, events[1].TxHash)
+	require.NotContains(t, events[1].Attributes, "mode")
+
+	// a transfer is streamed to the previous owner, and names the new one
+	require.Equal(t, stream.CapsuleEventType_TRANSFERRED, events[2].Type)
+	require.Equal(t, carol, events[2].Owner)
+	require.Equal(t, alice, events[2].Attributes["from"])
+	require.Equal(t, "capsule_transferred", events[2].AbciEvent)
+}
+
+func TestWatchCapsulesFilters(t *testing.T) {
+	n := newNode(t, 10)
+
+	n.commit(t, []abci.Event{unlockable(3, carol, alice)},
+		[]abci.Event{created(1, alice, bob)},
+		[]abci.Event{created(2, carol, bob)},
+	)
+
+	testCases := []struct {
+		name string
+		req  *stream.WatchCapsulesRequest
+		ids  []uint64
+	}{
+		{"all capsules", &stream.WatchCapsulesRequest{}, []uint64{3, 1, 2}},
+		{"capsule id", &stream.WatchCapsulesRequest{CapsuleIds: []uint64{2}}, []uint64{2}},
+		{"owner or recipient", &stream.WatchCapsulesRequest{Owners: []string{alice}, Recipients: []string{alice}}, []uint64{3, 1}},
+		{"recipient", &stream.WatchCapsulesRequest{Recipients: []string{bob}}, []uint64{1, 2}},
+		{"event type", &stream.WatchCapsulesRequest{Recipients: []string{bob, alice}, EventTypes: []stream.CapsuleEventType{stream.CapsuleEventType_UNLOCKABLE}}, []uint64{3}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.req.StartHeight = 1
+			events := recv(t, n.watch(t, tc.req), len(tc.ids))
+
+			ids := make([]uint64, 0, len(events))
+			for _, event := range events {
+				ids = append(ids, event.CapsuleId)
+			}
+			require.Equal(t, tc.ids, ids)
+		})
+	}
+}
+
+func TestWatchCapsulesResume(t *testing.T) {
+	n := newNode(t, 3)
+
+	n.commit(t, nil, []abci.Event{created(1, alice, bob)}, []abci.Event{created(2, alice, bob)})
+	n.commit(t, nil, []abci.Event{created(3, alice, bob)})
+
+	// resuming after the first event of block 1 replays the rest of the retained
+	// blocks, then streams the committed ones
+	sub := n.watch(t, &stream.WatchCapsulesRequest{Cursor: "1/0"})
+	events := recv(t, sub, 2)
+	require.Equal(t, []string{"1/1", "2/0"}, []string{events[0].Cursor, events[1].Cursor})
+
+	n.commit(t, nil, []abci.Event{created(4, alice, bob)})
+	events = recv(t, sub, 1)
+	require.Equal(t, uint64(4), events[0].CapsuleId)
+
+	// block 1 is no longer retained once block 4 commits
+	n.commit(t, nil)
+	_, err := n.watch(t, &stream.WatchCapsulesRequest{Cursor: "1/1"}).Recv()
+	require.Equal(t, codes.OutOfRange, status.Code(err))
+
+	_, err = n.watch(t, &stream.WatchCapsulesRequest{StartHeight: 1}).Recv()
+	require.Equal(t, codes.OutOfRange, status.Code(err))
+
+	// the oldest retained block can still be resumed from
+	events = recv(t, n.watch(t, &stream.WatchCapsulesRequest{StartHeight: 2}), 2)
+	require.Equal(t, []uint64{3, 4}, []uint64{events[0].CapsuleId, events[1].CapsuleId})
+}
+
+func TestWatchCapsulesInvalidRequest(t *testing.T) {
+	n := newNode(t, 10)
+
+	for _, req := range []*stream.WatchCapsulesRequest{
+		{Cursor: "1"},
+		{Cursor: "1/0", StartHeight: 1},
+		{StartHeight: -1},
+		{EventTypes: []stream.CapsuleEventType{stream.CapsuleEventType_UNSPECIFIED}},
+		{CapsuleIds: make([]uint64, stream.MaxWatchFilters+1)},
+	} {
+		_, err := n.watch(t, req).Recv()
+		require.Equal(t, codes.InvalidArgument, status.Code(err), "%v", req)
+	}
+}
+
+func TestParseCapsuleEventType(t *testing.T) {
+	eventType, err := stream.ParseCapsuleEventType("unlockable")
+	require.NoError(t, err)
+	require.Equal(t, stream.CapsuleEventType_UNLOCKABLE, eventType)
+
+	eventType, err = stream.ParseCapsuleEventType("CAPSULE_EVENT_TYPE_OPENED")
+	require.NoError(t, err)
+	require.Equal(t, stream.CapsuleEventType_OPENED, eventType)
+
+	_, err = stream.ParseCapsuleEventType("unspecified")
+	require.Error(t, err)
+}
@@ -0,0 +1,266 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+)
+
+const (
+	// ArchiveFormat identifies a capsule archive
+	ArchiveFormat = "timecapsule-archive"
+
+	// ArchiveVersion is the version of the archive format written by this module
+	ArchiveVersion = uint32(1)
+)
+
+// CapsuleArchive is a portable, self-describing artifact that opens a capsule offline.
+// It holds the capsule record with its inclusion proof and the signed header the proof
+// is made against, the ciphertext, and the key shares gathered by the holder, sealed
+// to the holder's key. Only the current version of the capsule is archived.
+type CapsuleArchive struct {
+	Format           string          `json:"format"`
+	Version          uint32          `json:"version"`
+	ChainID          string          `json:"chain_id"`
+	CapsuleID        uint64          `json:"capsule_id"`
+	ExportedAt       time.Time       `json:"exported_at"`
+	Capsule          *TimeCapsule    `json:"capsule"`                     // Readable copy of the proven record
+	Proof            CapsuleProof    `json:"proof"`                       // Inclusion proof, its value is the capsule record
+	Header           json.RawMessage `json:"header"`                      // Signed header of block Proof.Height+1
+	Ciphertext       []byte          `json:"ciphertext"`                  // Encrypted data, from the chain or the storage backend
+	ShareCommitments [][][]byte      `json:"share_commitments,omitempty"` // Commitments the shares are verified against
+	Shares           *SealedShares   `json:"shares,omitempty"`            // Unset for capsules delivered to a recipient key
+}
+
+// SealedShares are key shares encrypted to the holder of an archive. The shares are
+// encrypted under a secret that is itself encrypted to the holder's secp256k1 key, like
+// a recipient delivery.
+type SealedShares struct {
+	HolderKey  []byte `json:"holder_key"`
+	Ciphertext []byte `json:"ciphertext"` // Secret encrypted to the holder key
+	Data       []byte `json:"data"`       // Shares encrypted under the secret
+	Nonce      []byte `json:"nonce"`
+	Algorithm  string `json:"algorithm"`
+}
+
+// SealShares encrypts key shares to a holder key
+func SealShares(holderKey []byte, shares []*crypto.Share) (*SealedShares, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("no key shares to seal")
+	}
+	if err := crypto.ValidateRecipientKey(holderKey); err != nil {
+		return nil, fmt.Errorf("invalid holder key: %w", err)
+	}
+
+	bz, err := json.Marshal(shares)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.WipeKey(bz)
+
+	secret, ciphertext, err := crypto.NewDeliverySecret(holderKey)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.WipeKey(secret)
+
+	encData, err := crypto.NewEncryptionManager().Encrypt(bz, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SealedShares{
+		HolderKey:  holderKey,
+		Ciphertext: ciphertext,
+		Data:       encData.Data,
+		Nonce:      encData.Nonce,
+		Algorithm:  encData.Algorithm,
+	}, nil
+}
+
+// Open decrypts the sealed shares with the holder's private key
+func (s SealedShares) Open(holderPriv []byte) ([]*crypto.Share, error) {
+	secret, err := crypto.OpenDelivery(holderPriv, s.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sealed shares: %w", err)
+	}
+	defer crypto.WipeKey(secret)
+
+	bz, err := crypto.NewEncryptionManager().Decrypt(&crypto.EncryptedData{
+		Data:      s.Data,
+		Nonce:     s.Nonce,
+		Algorithm: s.Algorithm,
+	}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("shares are not sealed to this key: %w", err)
+	}
+	defer crypto.WipeKey(bz)
+
+	var shares []*crypto.Share
+	if err := json.Unmarshal(bz, &shares); err != nil {
+		return nil, fmt.Errorf("invalid sealed shares: %w", err)
+	}
+	return shares, nil
+}
+
+// Validate performs stateless checks of the archive's structure
+func (a CapsuleArchive) Validate() error {
+	if a.Format != ArchiveFormat {
+		return fmt.Errorf("not a capsule archive: format %q", a.Format)
+	}
+	if a.Version == 0 || a.Version > ArchiveVersion {
+		return fmt.Errorf("unsupported archive version %d, this client reads up to version %d", a.Version, ArchiveVersion)
+	}
+	if a.Proof.CapsuleID != a.CapsuleID {
+		return fmt.Errorf("archive of capsule %d holds the proof of capsule %d", a.CapsuleID, a.Proof.CapsuleID)
+	}
+	if !a.Proof.Exists() {
+		return fmt.Errorf("archive of capsule %d holds no capsule record", a.CapsuleID)
+	}
+	if len(a.Header) == 0 {
+		return fmt.Errorf("archive of capsule %d has no header", a.CapsuleID)
+	}
+	if len(a.Ciphertext) == 0 {
+		return fmt.Errorf("archive of capsule %d has no ciphertext", a.CapsuleID)
+	}
+	if a.Shares == nil && a.Capsule != nil && a.Capsule.Delivery == nil {
+		return fmt.Errorf("archive of capsule %d has neither key shares nor a recipient delivery", a.CapsuleID)
+	}
+	return nil
+}
+
+// Verify checks the archive against a trusted app hash of block Proof.Height+1 and
+// returns the proven capsule record. The readable copy, the commitments and, for
+// capsules stored on chain, the ciphertext must match the record.
+func (a CapsuleArchive) Verify(cdc codec.BinaryCodec, appHash []byte) (*TimeCapsule, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	if err := a.Proof.Verify(appHash); err != nil {
+		return nil, err
+	}
+
+	var capsule TimeCapsule
+	if err := cdc.Unmarshal(a.Proof.Value, &capsule); err != nil {
+		return nil, fmt.Errorf("proven value is not a capsule: %w", err)
+	}
+	if capsule.ID != a.CapsuleID {
+		return nil, fmt.Errorf("proven value is capsule %d, not %d", capsule.ID, a.CapsuleID)
+	}
+
+	if a.Capsule != nil {
+		proven, err := json.Marshal(&capsule)
+		if err != nil {
+			return nil, err
+		}
+		copied, err := json.Marshal(a.Capsule)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(proven, copied) {
+			return nil, fmt.Errorf("capsule record of the archive does not match the proven record")
+		}
+	}
+
+	if !equalCommitments(capsule.ShareCommitments, a.ShareCommitments) {
+		return nil, fmt.Errorf("share commitments of the archive do not match the proven record")
+	}
+	if len(capsule.EncryptedData) > 0 && !bytes.Equal(capsule.EncryptedData, a.Ciphertext) {
+		return nil, fmt.Errorf("ciphertext of the archive does not match the proven record")
+	}
+	if capsule.IsPurged() {
+		return nil, fmt.Errorf("capsule %d data was purged at height %d (%s)", capsule.ID, capsule.Tombstone.PurgedHeight, capsule.Tombstone.Reason)
+	}
+
+	return &capsule, nil
+}
+
+// Decrypt recovers the data of a verified capsule with the holder's private key. The
+// data key is reconstructed from the sealed shares that match the commitments, or
+// taken from the capsule's delivery to the holder's recipient key, which also needs
+// the passphrase of capsules protected by a passphrase factor. The decompressed data is
+// bounded by maxDataSize, which the reader chooses: an archive cannot be trusted to
+// bound its own data.
+func (a CapsuleArchive) Decrypt(capsule *TimeCapsule, holderPriv []byte, passphrase string, maxDataSize uint64) ([]byte, error) {
+	em := crypto.NewEncryptionManager()
+
+	var encryptionKey []byte
+	switch {
+	case a.Shares != nil:
+		shares, err := a.Shares.Open(holderPriv)
+		if err != nil {
+			return nil, err
+		}
+
+		valid := make([]*crypto.Share, 0, len(shares))
+		seen := make(map[string]bool, len(shares))
+		for i, share := range shares {
+			if share == nil || share.X == nil || seen[share.X.String()] {
+				continue
+			}
+			if len(capsule.ShareCommitments) > 0 {
+				if err := crypto.VerifyShare(capsule.ShareCommitments, share); err != nil {
+					return nil, fmt.Errorf("sealed share %d does not match the commitments: %w", i, err)
+				}
+			}
+			seen[share.X.String()] = true
+			valid = append(valid, share)
+		}
+		if len(valid) < int(capsule.Threshold) {
+			return nil, ErrInsufficientShares.Wrapf("need %d shares, archive holds %d", capsule.Threshold, len(valid))
+		}
+
+		encryptionKey, err = crypto.NewShamirSecretSharing().CombineShares(valid[:capsule.Threshold])
+		if err != nil {
+			return nil, ErrInvalidKeyShare.Wrapf("failed to reconstruct key: %s", err)
+		}
+
+	case capsule.Delivery != nil:
+		var err error
+		encryptionKey, err = capsule.OpenDeliveredKey(holderPriv, passphrase)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("archive of capsule %d has neither key shares nor a recipient delivery", capsule.ID)
+	}
+	defer crypto.WipeKey(encryptionKey)
+
+	data, err := em.DecryptAndDecompress(&crypto.EncryptedData{
+		Data:        a.Ciphertext,
+		Nonce:       capsule.DataNonce,
+		Algorithm:   capsule.EncryptionAlgo,
+		Compression: capsule.Compression,
+	}, encryptionKey, maxDataSize)
+	if err != nil {
+		return nil, ErrInvalidEncryption.Wrapf("failed to decrypt data: %s", err)
+	}
+
+	if !crypto.VerifyDataIntegrity(data, capsule.DataHash) {
+		return nil, ErrInvalidEncryption.Wrap("data integrity check failed")
+	}
+
+	return data, nil
+}
+
+func equalCommitments(a, b [][][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if !bytes.Equal(a[i][j], b[i][j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
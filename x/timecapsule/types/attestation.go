@@ -0,0 +1,192 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Key types attestors sign their attestations with
+const (
+	AttestorKeyTypeSecp256k1 = "secp256k1"
+	AttestorKeyTypeEd25519   = "ed25519"
+)
+
+const (
+	// MaxAttestorDescriptionLength is the maximum length of an attestor description
+	MaxAttestorDescriptionLength = 512
+
+	// MaxAttestationClaimLength is the maximum length of the claim attestors sign
+	MaxAttestationClaimLength = 256
+
+	// MaxCapsuleAttestors is the maximum number of attestors listed on a capsule
+	MaxCapsuleAttestors = 16
+)
+
+// Attestor is an organization, e.g. a notary or a registry of deaths, that attests
+// claims about capsules. It signs its attestations with a key it registers on chain,
+// which need not be the key of its account.
+type Attestor struct {
+	Address      string    `json:"address"`     // Account that registered the attestor
+	KeyType      string    `json:"key_type"`    // "secp256k1" or "ed25519"
+	PubKey       []byte    `json:"pub_key"`     // Key the attestations are signed with
+	Description  string    `json:"description"` // Who the attestor is
+	Curated      bool      `json:"curated"`     // Approved by governance
+	RegisteredAt time.Time `json:"registered_at"`
+}
+
+// PublicKey returns the key the attestor signs its attestations with
+func (a Attestor) PublicKey() (cryptotypes.PubKey, error) {
+	switch a.KeyType {
+	case AttestorKeyTypeSecp256k1:
+		if len(a.PubKey) != secp256k1.PubKeySize || (a.PubKey[0] != 0x02 && a.PubKey[0] != 0x03) {
+			return nil, fmt.Errorf("secp256k1 public key must be %d compressed bytes", secp256k1.PubKeySize)
+		}
+		return &secp256k1.PubKey{Key: a.PubKey}, nil
+	case AttestorKeyTypeEd25519:
+		if len(a.PubKey) != ed25519.PubKeySize {
+			return nil, fmt.Errorf("ed25519 public key must be %d bytes", ed25519.PubKeySize)
+		}
+		return &ed25519.PubKey{Key: a.PubKey}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q, expected %s or %s", a.KeyType, AttestorKeyTypeSecp256k1, AttestorKeyTypeEd25519)
+	}
+}
+
+// VerifyAttestation checks the attestor's signature of a claim about a capsule
+func (a Attestor) VerifyAttestation(chainID string, capsuleID uint64, claim string, signature []byte) error {
+	pubKey, err := a.PublicKey()
+	if err != nil {
+		return err
+	}
+	if !pubKey.VerifySignature(AttestationSignBytes(chainID, capsuleID, claim), signature) {
+		return fmt.Errorf("invalid %s signature of attestor %s", a.KeyType, a.Address)
+	}
+	return nil
+}
+
+// Validate performs basic validation of an attestor
+func (a Attestor) Validate() error {
+	if _, err := sdk.AccAddressFromBech32(a.Address); err != nil {
+		return fmt.Errorf("invalid attestor address: %w", err)
+	}
+	if _, err := a.PublicKey(); err != nil {
+		return err
+	}
+	if a.Description == "" {
+		return fmt.Errorf("attestor description cannot be empty")
+	}
+	if len(a.Description) > MaxAttestorDescriptionLength {
+		return fmt.Errorf("attestor description exceeds %d characters", MaxAttestorDescriptionLength)
+	}
+	return nil
+}
+
+// AttestationSignBytes returns the bytes an attestor signs to attest a claim about a
+// capsule. The chain ID keeps attestations from being replayed on another chain.
+func AttestationSignBytes(chainID string, capsuleID uint64, claim string) []byte {
+	bz, err := json.Marshal(struct {
+		Type      string `json:"type"`
+		ChainID   string `json:"chain_id"`
+		CapsuleID string `json:"capsule_id"`
+		Claim     string `json:"claim"`
+	}{
+		Type:      "timecapsule/Attestation",
+		ChainID:   chainID,
+		CapsuleID: strconv.FormatUint(capsuleID, 10),
+		Claim:     claim,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(bz)
+}
+
+// AttestationCondition unlocks a conditional capsule once Threshold of its attestors
+// signed its claim, e.g. "death certificate of the owner issued".
+type AttestationCondition struct {
+	Attestors   []string   `json:"attestors"`              // Registered attestors that may attest
+	Threshold   uint32     `json:"threshold"`              // Attestations required to unlock
+	Claim       string     `json:"claim"`                  // What the attestors attest
+	CuratedOnly bool       `json:"curated_only,omitempty"` // Only governance curated attestors may attest
+	AttestedAt  *time.Time `json:"attested_at,omitempty"`  // Set once enough attestations are submitted
+}
+
+// HasAttestor checks whether an attestor is listed on the condition
+func (c AttestationCondition) HasAttestor(attestor string) bool {
+	for _, listed := range c.Attestors {
+		if listed == attestor {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMet checks whether enough attestations were submitted
+func (c AttestationCondition) IsMet() bool {
+	return c.AttestedAt != nil
+}
+
+// Validate performs basic validation of an attestation condition
+func (c AttestationCondition) Validate() error {
+	if len(c.Attestors) == 0 {
+		return fmt.Errorf("attestation condition must list attestors")
+	}
+	if len(c.Attestors) > MaxCapsuleAttestors {
+		return fmt.Errorf("at most %d attestors can be listed, got %d", MaxCapsuleAttestors, len(c.Attestors))
+	}
+
+	seen := make(map[string]bool)
+	for i, attestor := range c.Attestors {
+		if _, err := sdk.AccAddressFromBech32(attestor); err != nil {
+			return fmt.Errorf("invalid attestor address at index %d: %w", i, err)
+		}
+		if seen[attestor] {
+			return fmt.Errorf("duplicate attestor %s", attestor)
+		}
+		seen[attestor] = true
+	}
+
+	if c.Threshold == 0 || int(c.Threshold) > len(c.Attestors) {
+		return fmt.Errorf("attestation threshold must be between 1 and %d, got %d", len(c.Attestors), c.Threshold)
+	}
+	if c.Claim == "" {
+		return fmt.Errorf("attestation claim cannot be empty")
+	}
+	if len(c.Claim) > MaxAttestationClaimLength {
+		return fmt.Errorf("attestation claim exceeds %d characters", MaxAttestationClaimLength)
+	}
+	return nil
+}
+
+// Attestation is an attestor's signed claim about a capsule
+type Attestation struct {
+	CapsuleID   uint64    `json:"capsule_id"`
+	Attestor    string    `json:"attestor"`
+	Signature   []byte    `json:"signature"`
+	Submitter   string    `json:"submitter"` // Account that relayed the attestation
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// Validate performs basic validation of an attestation
+func (a Attestation) Validate() error {
+	if a.CapsuleID == 0 {
+		return fmt.Errorf("capsule ID cannot be zero")
+	}
+	if _, err := sdk.AccAddressFromBech32(a.Attestor); err != nil {
+		return fmt.Errorf("invalid attestor address: %w", err)
+	}
+	if _, err := sdk.AccAddressFromBech32(a.Submitter); err != nil {
+		return fmt.Errorf("invalid submitter address: %w", err)
+	}
+	if len(a.Signature) == 0 {
+		return fmt.Errorf("attestation signature cannot be empty")
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 	
 	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
 )
 
 // CapsuleType defines the type of time capsule
@@ -17,6 +19,7 @@ const (
 	CapsuleType_CONDITIONAL    CapsuleType = 3 // Condition-based capsule
 	CapsuleType_MULTI_SIG      CapsuleType = 4 // Multi-signature capsule
 	CapsuleType_DEAD_MANS_SWITCH CapsuleType = 5 // Dead man's switch capsule
+	CapsuleType_PUBLIC_REVEAL  CapsuleType = 6 // Commit-reveal capsule, revealed publicly
 )
 
 // String returns the string representation of CapsuleType
@@ -34,6 +37,8 @@ func (t CapsuleType) String() string {
 		return "MULTI_SIG"
 	case CapsuleType_DEAD_MANS_SWITCH:
 		return "DEAD_MANS_SWITCH"
+	case CapsuleType_PUBLIC_REVEAL:
+		return "PUBLIC_REVEAL"
 	default:
 		return "UNKNOWN"
 	}
@@ -80,31 +85,78 @@ type TimeCapsule struct {
 	EncryptedData   []byte `json:"encrypted_data,omitempty"`  // For small data < 1MB
 	DataHash        string `json:"data_hash"`                 // SHA-256 hash of original data
 	EncryptionAlgo  string `json:"encryption_algo"`           // e.g., "AES-256-GCM"
+	DataNonce       []byte `json:"data_nonce,omitempty"`      // AES-GCM nonce of the encrypted data
+	Compression     string `json:"compression,omitempty"`     // "gzip" or "zstd" when the data was compressed before encryption
+	
+	// Timelock encryption (time-locked capsules sealed to a validator epoch key)
+	TimelockEpoch   uint64 `json:"timelock_epoch,omitempty"`  // Epoch whose key seals the data key
+	TimelockKey     []byte `json:"timelock_key,omitempty"`    // Data key sealed to the epoch public key
 	
 	// IPFS Storage (for large data)
 	IPFSHash        string `json:"ipfs_hash,omitempty"`       // IPFS hash for large data
-	DataSize        int64  `json:"data_size"`                 // Original data size in bytes
+	DataSize        int64  `json:"data_size"`                 // Stored data size in bytes, after compression
 	StorageType     string `json:"storage_type"`              // "blockchain" or "ipfs"
 	
 	// Access conditions
 	UnlockTime      *time.Time `json:"unlock_time,omitempty"`      // For time-locked capsules
+	UnlockHeight    int64         `json:"unlock_height,omitempty"` // Block height time-locked capsules unlock at instead
+	UnlockEpoch     *EpochTrigger `json:"unlock_epoch,omitempty"`  // Epoch trigger, sets the unlock height once resolved
 	ConditionContract string   `json:"condition_contract,omitempty"` // Smart contract address
 	RequiredSigs    uint32     `json:"required_sigs,omitempty"`    // For multi-sig capsules
+	GroupPolicy     string     `json:"group_policy,omitempty"`     // x/group policy owning a multi-sig capsule instead
+	Attestation     *AttestationCondition `json:"attestation,omitempty"` // Attestors unlocking a conditional capsule instead of a contract
 	
 	// Key management (Shamir's Secret Sharing)
 	Threshold       uint32   `json:"threshold"`         // Minimum shares needed
 	TotalShares     uint32   `json:"total_shares"`      // Total shares created
 	ShareHolders    []string `json:"share_holders"`     // Addresses holding shares
+	ShareCommitments [][][]byte `json:"share_commitments,omitempty"` // Pedersen commitments per key chunk, set in VSS mode
+	
+	// Recipient delivery: the data key is wrapped under a secret the custodians
+	// re-encrypt to the recipient key on open, without learning it
+	RecipientKey       []byte             `json:"recipient_key,omitempty"`        // Recipient's secp256k1 public key
+	Executor           string             `json:"executor,omitempty"`             // May rotate the recipient besides the owner
+	DeliveryKey        []byte             `json:"delivery_key,omitempty"`         // Public key whose secret the custodians share
+	DeliveryCiphertext []byte             `json:"delivery_ciphertext,omitempty"`  // Delivery secret encrypted to the delivery key
+	DeliveryWrappedKey []byte             `json:"delivery_wrapped_key,omitempty"` // Data key encrypted under the delivery secret
+	DeliveryKeyNonce   []byte             `json:"delivery_key_nonce,omitempty"`   // AES-GCM nonce of the wrapped data key
+	Delivery           *RecipientDelivery `json:"delivery,omitempty"`             // Set once the capsule is delivered
+	PassphraseFactor   *PassphraseFactor  `json:"passphrase_factor,omitempty"`    // Passphrase the delivered data key is also wrapped under
+	
+	// Public reveal: only a commitment to the content is stored until anyone reveals
+	// the content after the unlock time
+	CommitHeight    int64         `json:"commit_height,omitempty"`     // Block the commitment was included in
+	CommitBlockHash []byte        `json:"commit_block_hash,omitempty"` // Header hash of that block
+	Reveal          *PublicReveal `json:"reveal,omitempty"`            // Set once the content is revealed
 	
 	// Metadata
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
-	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	OpenedAt        *time.Time `json:"opened_at,omitempty"` // Set when the capsule is unlocked
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"` // Set by the owner, an unopened capsule expires then
+	Tombstone       *CapsuleTombstone `json:"tombstone,omitempty"` // Set once the capsule's data is purged
 	
 	// Dead man's switch specific
 	LastActivity    *time.Time `json:"last_activity,omitempty"`
 	InactivityPeriod uint64    `json:"inactivity_period,omitempty"` // In seconds
 	
+	// Token escrow held in the module account until the capsule is settled
+	Escrow          sdk.Coins     `json:"escrow,omitempty"`
+	Beneficiaries   []Beneficiary `json:"beneficiaries,omitempty"` // Escrow split, in basis points
+	
+	// Sponsor that paid the creation and storage fees under its capsule allowance
+	Sponsor         string        `json:"sponsor,omitempty"`
+	
+	// Guardians approve emergency actions on the capsule
+	Guardians         []string `json:"guardians,omitempty"`
+	GuardianThreshold uint32   `json:"guardian_threshold,omitempty"` // Approvals required for an emergency action
+	Frozen            bool     `json:"frozen,omitempty"`             // Frozen by the guardians
+	ForceUnlocked     bool     `json:"force_unlocked,omitempty"`     // Unlock conditions waived by the guardians
+	
+	// Content versioning
+	Version         uint32 `json:"version"`                  // Current content version, starting at 1
+	VersionPolicy   string `json:"version_policy,omitempty"` // "latest" or "all"
+	
 	// Additional metadata
 	Title           string            `json:"title,omitempty"`
 	Description     string            `json:"description,omitempty"`
@@ -112,12 +164,56 @@ type TimeCapsule struct {
 	Metadata        map[string]string `json:"metadata,omitempty"`
 }
 
+// IsTimelocked checks whether the data key is sealed to a timelock epoch
+// rather than split into Shamir shares
+func (tc *TimeCapsule) IsTimelocked() bool {
+	return tc.TimelockEpoch != 0 && len(tc.TimelockKey) > 0
+}
+
+// HasDelivery checks whether the capsule's data key can be re-encrypted to a recipient key
+func (tc *TimeCapsule) HasDelivery() bool {
+	return len(tc.DeliveryKey) > 0 && len(tc.DeliveryCiphertext) > 0
+}
+
+// CurrentVersion returns the version of the capsule's current content
+func (tc *TimeCapsule) CurrentVersion() uint32 {
+	if tc.Version == 0 {
+		return 1 // Capsules created before versioning
+	}
+	return tc.Version
+}
+
+// DeliversAllVersions checks whether the recipient receives superseded versions on open
+func (tc *TimeCapsule) DeliversAllVersions() bool {
+	return tc.VersionPolicy == VersionPolicyAll
+}
+
+// IsGuardian checks whether an address is one of the capsule's guardians
+func (tc *TimeCapsule) IsGuardian(address string) bool {
+	for _, guardian := range tc.Guardians {
+		if guardian == address {
+			return true
+		}
+	}
+	return false
+}
+
+// BasisPoints is the total weight of a capsule's beneficiary split (100%)
+const BasisPoints = uint32(10000)
+
+// Beneficiary represents a weighted payee of a capsule's escrow
+type Beneficiary struct {
+	Address string `json:"address"`
+	Weight  uint32 `json:"weight"` // Share of the escrow in basis points
+}
+
 // KeyShare represents a Shamir secret share
 type KeyShare struct {
 	CapsuleID   uint64 `json:"capsule_id"`
 	ShareIndex  uint32 `json:"share_index"`
 	NodeID      string `json:"node_id"`      // Masternode holding the share
 	EncryptedShare []byte `json:"encrypted_share"` // The actual encrypted share
+	DeliveryShare  []byte `json:"delivery_share,omitempty"` // Share of the capsule's delivery key
 	CreatedAt   time.Time `json:"created_at"`
 }
 
@@ -135,6 +231,8 @@ type CapsuleAccess struct {
 	CapsuleID   uint64    `json:"capsule_id"`
 	Accessor    string    `json:"accessor"`
 	AccessTime  time.Time `json:"access_time"`
+	BlockHeight int64     `json:"block_height"`
+	Sequence    uint64    `json:"sequence"` // Position in the module-wide access log
 	Success     bool      `json:"success"`
 	Reason      string    `json:"reason,omitempty"`
 }
@@ -189,6 +287,16 @@ func (tc *TimeCapsule) Validate() error {
 		return fmt.Errorf("invalid owner address: %w", err)
 	}
 	
+	// Public reveal capsules hold a commitment rather than encrypted data
+	if tc.IsPublicReveal() {
+		return tc.validatePublicReveal()
+	}
+	
+	// Purged capsules only keep a tombstone of their data
+	if tc.IsPurged() {
+		return tc.validateTombstone()
+	}
+	
 	if tc.Recipient != "" {
 		if _, err := sdk.AccAddressFromBech32(tc.Recipient); err != nil {
 			return fmt.Errorf("invalid recipient address: %w", err)
@@ -211,22 +319,93 @@ func (tc *TimeCapsule) Validate() error {
 		return fmt.Errorf("total shares must be greater than zero")
 	}
 	
+	if err := ValidateEscrow(tc.Escrow, tc.Beneficiaries); err != nil {
+		return err
+	}
+	
+	if err := ValidateGuardians(tc.Owner, tc.Guardians, tc.GuardianThreshold); err != nil {
+		return err
+	}
+	
+	if err := ValidateVersionPolicy(tc.VersionPolicy); err != nil {
+		return err
+	}
+	
+	if err := ValidateExpiry(tc.ExpiresAt, tc.UnlockTime); err != nil {
+		return err
+	}
+	
+	if len(tc.ShareCommitments) > 0 {
+		if tc.IsTimelocked() {
+			return fmt.Errorf("timelocked capsule cannot have share commitments")
+		}
+		if err := crypto.ValidateShareCommitments(tc.ShareCommitments, int(tc.Threshold)); err != nil {
+			return fmt.Errorf("invalid share commitments: %w", err)
+		}
+	}
+	
+	if err := ValidateRecipientKey(tc.RecipientKey); err != nil {
+		return err
+	}
+	
+	if err := ValidateExecutor(tc.Owner, tc.Executor); err != nil {
+		return err
+	}
+	
+	if tc.HasDelivery() && tc.IsTimelocked() {
+		return fmt.Errorf("timelocked capsule cannot have a delivery key")
+	}
+	
+	if tc.PassphraseFactor != nil {
+		if err := tc.PassphraseFactor.Validate(); err != nil {
+			return fmt.Errorf("invalid passphrase factor: %w", err)
+		}
+		if len(tc.RecipientKey) == 0 {
+			return fmt.Errorf("passphrase factor protects the delivery to a recipient key, capsule has none")
+		}
+	}
+	
+	if tc.CapsuleType != CapsuleType_TIME_LOCK && (tc.UnlockHeight != 0 || tc.UnlockEpoch != nil) {
+		return fmt.Errorf("only time-locked capsules can unlock on a height or epoch")
+	}
+	
+	if tc.GroupPolicy != "" && (tc.CapsuleType != CapsuleType_MULTI_SIG || tc.Owner != tc.GroupPolicy) {
+		return fmt.Errorf("group policy must own its multi-sig capsule")
+	}
+	
+	if tc.Attestation != nil && tc.CapsuleType != CapsuleType_CONDITIONAL {
+		return fmt.Errorf("only conditional capsules can have an attestation condition")
+	}
+	
 	// Validate capsule type specific requirements
 	switch tc.CapsuleType {
 	case CapsuleType_TIME_LOCK:
-		if tc.UnlockTime == nil {
-			return fmt.Errorf("time-locked capsule must have unlock time")
+		// An epoch trigger records its resolved height as the unlock height
+		unlockHeight := tc.UnlockHeight
+		if tc.UnlockEpoch != nil {
+			unlockHeight = 0
+		}
+		if err := ValidateUnlockTrigger(tc.UnlockTime, unlockHeight, tc.UnlockEpoch); err != nil {
+			return err
 		}
-		if tc.UnlockTime.Before(time.Now()) {
+		if tc.UnlockTime != nil && tc.UnlockTime.Before(time.Now()) {
 			return fmt.Errorf("unlock time must be in the future")
 		}
 	case CapsuleType_CONDITIONAL:
-		if tc.ConditionContract == "" {
-			return fmt.Errorf("conditional capsule must have condition contract")
+		if tc.ConditionContract == "" && tc.Attestation == nil {
+			return fmt.Errorf("conditional capsule must have condition contract or attestation condition")
+		}
+		if tc.ConditionContract != "" && tc.Attestation != nil {
+			return fmt.Errorf("conditional capsule cannot have both condition contract and attestation condition")
+		}
+		if tc.Attestation != nil {
+			if err := tc.Attestation.Validate(); err != nil {
+				return err
+			}
 		}
 	case CapsuleType_MULTI_SIG:
-		if tc.RequiredSigs == 0 {
-			return fmt.Errorf("multi-sig capsule must have required signatures")
+		if tc.RequiredSigs == 0 && tc.GroupPolicy == "" {
+			return fmt.Errorf("multi-sig capsule must have required signatures or a group policy")
 		}
 	case CapsuleType_DEAD_MANS_SWITCH:
 		if tc.InactivityPeriod == 0 {
@@ -237,21 +416,69 @@ func (tc *TimeCapsule) Validate() error {
 	return nil
 }
 
+// ValidateEscrow validates an escrow deposit and its beneficiary split
+func ValidateEscrow(escrow sdk.Coins, beneficiaries []Beneficiary) error {
+	if !escrow.IsValid() {
+		return fmt.Errorf("invalid escrow: %s", escrow)
+	}
+	
+	if len(beneficiaries) == 0 {
+		return nil
+	}
+	
+	if escrow.Empty() {
+		return fmt.Errorf("beneficiaries cannot be set without an escrow")
+	}
+	
+	seen := make(map[string]bool)
+	totalWeight := uint64(0)
+	for i, beneficiary := range beneficiaries {
+		if _, err := sdk.AccAddressFromBech32(beneficiary.Address); err != nil {
+			return fmt.Errorf("invalid beneficiary address at index %d: %w", i, err)
+		}
+		if seen[beneficiary.Address] {
+			return fmt.Errorf("duplicate beneficiary %s", beneficiary.Address)
+		}
+		seen[beneficiary.Address] = true
+		
+		if beneficiary.Weight == 0 {
+			return fmt.Errorf("beneficiary %s must have a positive weight", beneficiary.Address)
+		}
+		if beneficiary.Weight > BasisPoints {
+			return fmt.Errorf("beneficiary %s weight %d exceeds %d", beneficiary.Address, beneficiary.Weight, BasisPoints)
+		}
+		totalWeight += uint64(beneficiary.Weight)
+	}
+	
+	if totalWeight != uint64(BasisPoints) {
+		return fmt.Errorf("beneficiary weights must sum to %d, got %d", BasisPoints, totalWeight)
+	}
+	
+	return nil
+}
+
+// HasEscrow returns true if the capsule carries an escrow deposit
+func (tc *TimeCapsule) HasEscrow() bool {
+	return !tc.Escrow.IsZero()
+}
+
 // IsUnlockable checks if the capsule can be unlocked based on current conditions
 func (tc *TimeCapsule) IsUnlockable(ctx sdk.Context) bool {
-	if tc.Status != CapsuleStatus_ACTIVE {
+	if tc.Status != CapsuleStatus_ACTIVE || tc.Frozen {
 		return false
 	}
 	
+	// Guardians waived the unlock conditions
+	if tc.ForceUnlocked {
+		return true
+	}
+	
 	switch tc.CapsuleType {
 	case CapsuleType_SAFE:
 		return true // Always unlockable by owner
 		
 	case CapsuleType_TIME_LOCK:
-		if tc.UnlockTime == nil {
-			return false
-		}
-		return ctx.BlockTime().After(*tc.UnlockTime)
+		return tc.UnlockTriggerReached(ctx)
 		
 	case CapsuleType_DEAD_MANS_SWITCH:
 		if tc.LastActivity == nil || tc.InactivityPeriod == 0 {
@@ -260,15 +487,25 @@ func (tc *TimeCapsule) IsUnlockable(ctx sdk.Context) bool {
 		inactivityDuration := time.Duration(tc.InactivityPeriod) * time.Second
 		return ctx.BlockTime().After(tc.LastActivity.Add(inactivityDuration))
 		
+	case CapsuleType_PUBLIC_REVEAL:
+		if tc.UnlockTime == nil {
+			return false
+		}
+		return ctx.BlockTime().After(*tc.UnlockTime)
+		
 	case CapsuleType_CONDITIONAL:
+		// The keeper records when the attestors reached their threshold
+		if tc.Attestation != nil {
+			return tc.Attestation.IsMet()
+		}
 		// This would require checking the smart contract condition
 		// Implementation depends on the specific condition logic
 		return false // Placeholder
 		
 	case CapsuleType_MULTI_SIG:
-		// This would require checking if enough signatures have been provided
-		// Implementation depends on signature collection mechanism
-		return false // Placeholder
+		// Group policy capsules are opened by a passed group proposal, which the
+		// keeper checks on the accessor
+		return tc.GroupPolicy != ""
 	}
 	
 	return false
@@ -330,9 +567,9 @@ type CapsuleMetrics struct {
 	CreationRate       float64 `json:"creation_rate"`       // Capsules per hour
 	OpeningRate        float64 `json:"opening_rate"`        // Openings per hour
 	AverageDataSize    float64 `json:"average_data_size"`   // In bytes
-	StorageEfficiency  float64 `json:"storage_efficiency"`  // Percentage
-	SecurityScore      float64 `json:"security_score"`      // 0-100
-	UptimePercentage   float64 `json:"uptime_percentage"`   // 0-100
+	StorageEfficiency  float64 `json:"storage_efficiency"`  // Deprecated: no longer estimated, always zero
+	SecurityScore      float64 `json:"security_score"`      // Deprecated: no longer estimated, always zero
+	UptimePercentage   float64 `json:"uptime_percentage"`   // Deprecated: tracked outside the chain, always zero
 }
 
 // SmartOpenCondition represents intelligent opening conditions
@@ -355,13 +592,16 @@ type OptimizedCapsuleView struct {
 	DataSize        int64     `json:"data_size"`
 	StorageType     string    `json:"storage_type"`
 	UnlockTime      *time.Time `json:"unlock_time,omitempty"`
+	UnlockHeight    int64         `json:"unlock_height,omitempty"`
+	UnlockEpoch     *EpochTrigger `json:"unlock_epoch,omitempty"`
 	CreatedAt       time.Time `json:"created_at"`
 	IsUnlockable    bool      `json:"is_unlockable"`
 	TimeRemaining   *int64    `json:"time_remaining,omitempty"` // Seconds until unlock
 }
 
-// GetOptimizedView returns a lightweight view of the capsule
-func (tc *TimeCapsule) GetOptimizedView(currentTime time.Time) *OptimizedCapsuleView {
+// GetOptimizedView returns a lightweight view of the capsule. The time remaining until a
+// height unlock is estimated from the expected block time.
+func (tc *TimeCapsule) GetOptimizedView(currentTime time.Time, currentHeight int64, expectedBlockTime time.Duration) *OptimizedCapsuleView {
 	view := &OptimizedCapsuleView{
 		ID:           tc.ID,
 		Title:        tc.Title,
@@ -372,6 +612,8 @@ func (tc *TimeCapsule) GetOptimizedView(currentTime time.Time) *OptimizedCapsule
 		DataSize:     tc.DataSize,
 		StorageType:  tc.StorageType,
 		UnlockTime:   tc.UnlockTime,
+		UnlockHeight: tc.UnlockHeight,
+		UnlockEpoch:  tc.UnlockEpoch,
 		CreatedAt:    tc.CreatedAt,
 		IsUnlockable: false,
 	}
@@ -380,14 +622,12 @@ func (tc *TimeCapsule) GetOptimizedView(currentTime time.Time) *OptimizedCapsule
 	switch tc.CapsuleType {
 	case CapsuleType_SAFE:
 		view.IsUnlockable = (tc.Status == CapsuleStatus_ACTIVE)
-	case CapsuleType_TIME_LOCK:
-		if tc.UnlockTime != nil {
-			if currentTime.After(*tc.UnlockTime) {
-				view.IsUnlockable = (tc.Status == CapsuleStatus_ACTIVE)
-			} else {
-				remaining := int64(tc.UnlockTime.Sub(currentTime).Seconds())
-				view.TimeRemaining = &remaining
-			}
+	case CapsuleType_TIME_LOCK, CapsuleType_PUBLIC_REVEAL:
+		if tc.unlockReached(currentTime, currentHeight) {
+			view.IsUnlockable = (tc.Status == CapsuleStatus_ACTIVE)
+		} else if timeLeft, ok := tc.timeUntilUnlock(currentTime, currentHeight, expectedBlockTime); ok {
+			remaining := int64(timeLeft.Seconds())
+			view.TimeRemaining = &remaining
 		}
 	case CapsuleType_DEAD_MANS_SWITCH:
 		if tc.LastActivity != nil && tc.InactivityPeriod > 0 {
@@ -414,7 +654,7 @@ func (tc *TimeCapsule) IsExpiringSoon(hours int) bool {
 	threshold := time.Now().Add(time.Duration(hours) * time.Hour)
 	
 	switch tc.CapsuleType {
-	case CapsuleType_TIME_LOCK:
+	case CapsuleType_TIME_LOCK, CapsuleType_PUBLIC_REVEAL:
 		return tc.UnlockTime != nil && tc.UnlockTime.Before(threshold)
 	case CapsuleType_DEAD_MANS_SWITCH:
 		if tc.LastActivity != nil && tc.InactivityPeriod > 0 {
@@ -426,18 +666,3 @@ func (tc *TimeCapsule) IsExpiringSoon(hours int) bool {
 	
 	return false
 }
-
-// EmergencyAction represents an emergency action taken on a capsule
-type EmergencyAction struct {
-	ID               string    `json:"id"`
-	CapsuleID        uint64    `json:"capsule_id"`
-	Creator          string    `json:"creator"`
-	ActionType       string    `json:"action_type"`       // "contract_deletion", "force_unlock", etc.
-	Reason           string    `json:"reason"`
-	ConfirmationCode string    `json:"confirmation_code"`
-	ActionTime       time.Time `json:"action_time"`
-	BlockHeight      int64     `json:"block_height"`
-	IsReversible     bool      `json:"is_reversible"`
-	ReversedAt       *time.Time `json:"reversed_at,omitempty"`
-	ReversedBy       string    `json:"reversed_by,omitempty"`
-}
\ No newline at end of file
@@ -0,0 +1,88 @@
+package types_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+func TestValidateEscrow(t *testing.T) {
+	alice := sdk.AccAddress("alice_______________").String()
+	bob := sdk.AccAddress("bob_________________").String()
+	escrow := sdk.NewCoins(sdk.NewInt64Coin("stake", 1000))
+
+	testCases := []struct {
+		name          string
+		escrow        sdk.Coins
+		beneficiaries []types.Beneficiary
+		expErr        string
+	}{
+		{
+			name:   "escrow without beneficiaries",
+			escrow: escrow,
+		},
+		{
+			name:   "split summing to basis points",
+			escrow: escrow,
+			beneficiaries: []types.Beneficiary{
+				{Address: alice, Weight: 4000},
+				{Address: bob, Weight: 6000},
+			},
+		},
+		{
+			name:          "beneficiaries without escrow",
+			beneficiaries: []types.Beneficiary{{Address: alice, Weight: types.BasisPoints}},
+			expErr:        "without an escrow",
+		},
+		{
+			name:   "split not summing to basis points",
+			escrow: escrow,
+			beneficiaries: []types.Beneficiary{
+				{Address: alice, Weight: 4000},
+				{Address: bob, Weight: 5000},
+			},
+			expErr: "must sum to",
+		},
+		{
+			name:   "duplicate beneficiary",
+			escrow: escrow,
+			beneficiaries: []types.Beneficiary{
+				{Address: alice, Weight: 5000},
+				{Address: alice, Weight: 5000},
+			},
+			expErr: "duplicate beneficiary",
+		},
+		{
+			name:          "zero weight",
+			escrow:        escrow,
+			beneficiaries: []types.Beneficiary{{Address: alice, Weight: 0}},
+			expErr:        "positive weight",
+		},
+		{
+			// 4294967295 + 10001 wraps around to 10000 in uint32 arithmetic
+			name:   "weights overflowing uint32",
+			escrow: escrow,
+			beneficiaries: []types.Beneficiary{
+				{Address: alice, Weight: math.MaxUint32},
+				{Address: bob, Weight: 10001},
+			},
+			expErr: "exceeds",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := types.ValidateEscrow(tc.escrow, tc.beneficiaries)
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -17,6 +17,22 @@ func RegisterCodec(cdc *codec.LegacyAmino) {
 	cdc.RegisterConcrete(&MsgUpdateActivity{}, "timecapsule/MsgUpdateActivity", nil)
 	cdc.RegisterConcrete(&MsgCancelCapsule{}, "timecapsule/MsgCancelCapsule", nil)
 	cdc.RegisterConcrete(&MsgTransferCapsule{}, "timecapsule/MsgTransferCapsule", nil)
+	cdc.RegisterConcrete(&MsgRegisterTimelockKey{}, "timecapsule/MsgRegisterTimelockKey", nil)
+	cdc.RegisterConcrete(&MsgProposeEmergencyAction{}, "timecapsule/MsgProposeEmergencyAction", nil)
+	cdc.RegisterConcrete(&MsgApproveEmergencyAction{}, "timecapsule/MsgApproveEmergencyAction", nil)
+	cdc.RegisterConcrete(&MsgVetoEmergencyAction{}, "timecapsule/MsgVetoEmergencyAction", nil)
+	cdc.RegisterConcrete(&MsgExecuteEmergencyAction{}, "timecapsule/MsgExecuteEmergencyAction", nil)
+	cdc.RegisterConcrete(&MsgReverseEmergencyAction{}, "timecapsule/MsgReverseEmergencyAction", nil)
+	cdc.RegisterConcrete(&MsgAmendCapsule{}, "timecapsule/MsgAmendCapsule", nil)
+	cdc.RegisterConcrete(&MsgRotateRecipient{}, "timecapsule/MsgRotateRecipient", nil)
+	cdc.RegisterConcrete(&MsgRevealCapsule{}, "timecapsule/MsgRevealCapsule", nil)
+	cdc.RegisterConcrete(&MsgGrantCapsuleAllowance{}, "timecapsule/MsgGrantCapsuleAllowance", nil)
+	cdc.RegisterConcrete(&MsgRevokeCapsuleAllowance{}, "timecapsule/MsgRevokeCapsuleAllowance", nil)
+	cdc.RegisterConcrete(&MsgRegisterAttestor{}, "timecapsule/MsgRegisterAttestor", nil)
+	cdc.RegisterConcrete(&MsgCurateAttestor{}, "timecapsule/MsgCurateAttestor", nil)
+	cdc.RegisterConcrete(&MsgSubmitAttestation{}, "timecapsule/MsgSubmitAttestation", nil)
+	cdc.RegisterConcrete(&MsgRevokeCapsuleAttestor{}, "timecapsule/MsgRevokeCapsuleAttestor", nil)
+	cdc.RegisterConcrete(&MsgEraseCapsule{}, "timecapsule/MsgEraseCapsule", nil)
 }
 
 // RegisterInterfaces registers the x/timecapsule interfaces types with the
@@ -28,6 +44,22 @@ func RegisterInterfaces(registry cdctypes.InterfaceRegistry) {
 		&MsgUpdateActivity{},
 		&MsgCancelCapsule{},
 		&MsgTransferCapsule{},
+		&MsgRegisterTimelockKey{},
+		&MsgProposeEmergencyAction{},
+		&MsgApproveEmergencyAction{},
+		&MsgVetoEmergencyAction{},
+		&MsgExecuteEmergencyAction{},
+		&MsgReverseEmergencyAction{},
+		&MsgAmendCapsule{},
+		&MsgRotateRecipient{},
+		&MsgRevealCapsule{},
+		&MsgGrantCapsuleAllowance{},
+		&MsgRevokeCapsuleAllowance{},
+		&MsgRegisterAttestor{},
+		&MsgCurateAttestor{},
+		&MsgSubmitAttestation{},
+		&MsgRevokeCapsuleAttestor{},
+		&MsgEraseCapsule{},
 	)
 
 	// msgservice.RegisterMsgServiceDesc(registry, &_Msg_serviceDesc) // TODO: implement when protobuf is generated
@@ -67,10 +99,23 @@ type QueryClient interface {
 	UserCapsules(ctx interface{}, req *QueryUserCapsulesRequest) (*QueryUserCapsulesResponse, error)
 	CapsulesByType(ctx interface{}, req *QueryCapsulesByTypeRequest) (*QueryCapsulesByTypeResponse, error)
 	CapsulesByStatus(ctx interface{}, req *QueryCapsulesByStatusRequest) (*QueryCapsulesByStatusResponse, error)
+	CapsulesByTag(ctx interface{}, req *QueryCapsulesByTagRequest) (*QueryCapsulesByTagResponse, error)
 	Stats(ctx interface{}, req *QueryStatsRequest) (*QueryStatsResponse, error)
 	KeyShares(ctx interface{}, req *QueryKeySharesRequest) (*QueryKeySharesResponse, error)
 	ConditionContract(ctx interface{}, req *QueryConditionContractRequest) (*QueryConditionContractResponse, error)
 	ConditionContracts(ctx interface{}, req *QueryConditionContractsRequest) (*QueryConditionContractsResponse, error)
+	TimelockEpoch(ctx interface{}, req *QueryTimelockEpochRequest) (*QueryTimelockEpochResponse, error)
+	EmergencyAction(ctx interface{}, req *QueryEmergencyActionRequest) (*QueryEmergencyActionResponse, error)
+	CapsuleEmergencyActions(ctx interface{}, req *QueryCapsuleEmergencyActionsRequest) (*QueryCapsuleEmergencyActionsResponse, error)
+	AccessLog(ctx interface{}, req *QueryAccessLogRequest) (*QueryAccessLogResponse, error)
+	CapsuleVersions(ctx interface{}, req *QueryCapsuleVersionsRequest) (*QueryCapsuleVersionsResponse, error)
+	ShareHealth(ctx interface{}, req *QueryShareHealthRequest) (*QueryShareHealthResponse, error)
+	Reveal(ctx interface{}, req *QueryRevealRequest) (*QueryRevealResponse, error)
+	CapsuleAllowance(ctx interface{}, req *QueryCapsuleAllowanceRequest) (*QueryCapsuleAllowanceResponse, error)
+	CapsuleAllowances(ctx interface{}, req *QueryCapsuleAllowancesRequest) (*QueryCapsuleAllowancesResponse, error)
+	Attestor(ctx interface{}, req *QueryAttestorRequest) (*QueryAttestorResponse, error)
+	Attestors(ctx interface{}, req *QueryAttestorsRequest) (*QueryAttestorsResponse, error)
+	CapsuleAttestations(ctx interface{}, req *QueryCapsuleAttestationsRequest) (*QueryCapsuleAttestationsResponse, error)
 }
 
 // queryClient stub implementation
@@ -100,6 +145,10 @@ func (q *queryClient) CapsulesByStatus(ctx interface{}, req *QueryCapsulesByStat
 	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
 }
 
+func (q *queryClient) CapsulesByTag(ctx interface{}, req *QueryCapsulesByTagRequest) (*QueryCapsulesByTagResponse, error) {
+	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
+}
+
 func (q *queryClient) Stats(ctx interface{}, req *QueryStatsRequest) (*QueryStatsResponse, error) {
 	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
 }
@@ -114,4 +163,52 @@ func (q *queryClient) ConditionContract(ctx interface{}, req *QueryConditionCont
 
 func (q *queryClient) ConditionContracts(ctx interface{}, req *QueryConditionContractsRequest) (*QueryConditionContractsResponse, error) {
 	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
-}
\ No newline at end of file
+}
+
+func (q *queryClient) TimelockEpoch(ctx interface{}, req *QueryTimelockEpochRequest) (*QueryTimelockEpochResponse, error) {
+	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
+}
+
+func (q *queryClient) EmergencyAction(ctx interface{}, req *QueryEmergencyActionRequest) (*QueryEmergencyActionResponse, error) {
+	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
+}
+
+func (q *queryClient) CapsuleEmergencyActions(ctx interface{}, req *QueryCapsuleEmergencyActionsRequest) (*QueryCapsuleEmergencyActionsResponse, error) {
+	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
+}
+
+func (q *queryClient) AccessLog(ctx interface{}, req *QueryAccessLogRequest) (*QueryAccessLogResponse, error) {
+	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
+}
+
+func (q *queryClient) CapsuleVersions(ctx interface{}, req *QueryCapsuleVersionsRequest) (*QueryCapsuleVersionsResponse, error) {
+	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
+}
+
+func (q *queryClient) ShareHealth(ctx interface{}, req *QueryShareHealthRequest) (*QueryShareHealthResponse, error) {
+	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
+}
+
+func (q *queryClient) Reveal(ctx interface{}, req *QueryRevealRequest) (*QueryRevealResponse, error) {
+	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
+}
+
+func (q *queryClient) CapsuleAllowance(ctx interface{}, req *QueryCapsuleAllowanceRequest) (*QueryCapsuleAllowanceResponse, error) {
+	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
+}
+
+func (q *queryClient) CapsuleAllowances(ctx interface{}, req *QueryCapsuleAllowancesRequest) (*QueryCapsuleAllowancesResponse, error) {
+	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
+}
+
+func (q *queryClient) Attestor(ctx interface{}, req *QueryAttestorRequest) (*QueryAttestorResponse, error) {
+	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
+}
+
+func (q *queryClient) Attestors(ctx interface{}, req *QueryAttestorsRequest) (*QueryAttestorsResponse, error) {
+	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
+}
+
+func (q *queryClient) CapsuleAttestations(ctx interface{}, req *QueryCapsuleAttestationsRequest) (*QueryCapsuleAttestationsResponse, error) {
+	return nil, fmt.Errorf("query client not implemented - please use protobuf generation")
+}
@@ -1,10 +1,14 @@
 package types
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 	
+	"cosmossdk.io/math"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -13,6 +17,8 @@ type ConditionType string
 
 const (
 	ConditionType_TIME        ConditionType = "time"
+	ConditionType_HEIGHT      ConditionType = "height"
+	ConditionType_EPOCH       ConditionType = "epoch"
 	ConditionType_ORACLE      ConditionType = "oracle"
 	ConditionType_MULTISIG    ConditionType = "multisig"
 	ConditionType_COMPOSITE   ConditionType = "composite"
@@ -60,6 +66,76 @@ func (tc *TimeCondition) GetMetadata() map[string]interface{} {
 	}
 }
 
+// HeightCondition represents a block height based access condition
+type HeightCondition struct {
+	UnlockHeight int64 `json:"unlock_height"`
+}
+
+func (hc *HeightCondition) GetType() ConditionType {
+	return ConditionType_HEIGHT
+}
+
+func (hc *HeightCondition) Validate() error {
+	if hc.UnlockHeight <= 0 {
+		return fmt.Errorf("unlock height must be positive")
+	}
+	return nil
+}
+
+func (hc *HeightCondition) Evaluate(ctx sdk.Context, params map[string]interface{}) (bool, error) {
+	return ctx.BlockHeight() >= hc.UnlockHeight, nil
+}
+
+func (hc *HeightCondition) GetMetadata() map[string]interface{} {
+	return map[string]interface{}{
+		"unlock_height": hc.UnlockHeight,
+	}
+}
+
+// EpochCondition represents an access condition on an epoch of a pluggable epoch source.
+// It is met from the height the epoch starts at, which the keeper resolves from the
+// source and records in UnlockHeight or passes as the "unlock_height" parameter.
+type EpochCondition struct {
+	Trigger      EpochTrigger `json:"trigger"`
+	UnlockHeight int64        `json:"unlock_height,omitempty"`
+}
+
+func (ec *EpochCondition) GetType() ConditionType {
+	return ConditionType_EPOCH
+}
+
+func (ec *EpochCondition) Validate() error {
+	return ec.Trigger.Validate()
+}
+
+func (ec *EpochCondition) Evaluate(ctx sdk.Context, params map[string]interface{}) (bool, error) {
+	unlockHeight := ec.UnlockHeight
+	if height, ok := params["unlock_height"].(int64); ok && unlockHeight == 0 {
+		unlockHeight = height
+	}
+	
+	// The epoch has not started yet
+	if unlockHeight == 0 {
+		return false, nil
+	}
+	
+	return ctx.BlockHeight() >= unlockHeight, nil
+}
+
+func (ec *EpochCondition) GetMetadata() map[string]interface{} {
+	metadata := map[string]interface{}{
+		"source":     ec.Trigger.Source,
+		"identifier": ec.Trigger.Identifier,
+		"epochs":     ec.Trigger.Epochs,
+	}
+	
+	if ec.UnlockHeight > 0 {
+		metadata["unlock_height"] = ec.UnlockHeight
+	}
+	
+	return metadata
+}
+
 // MultiSigCondition represents a multi-signature access condition
 type MultiSigCondition struct {
 	RequiredSignatures uint32   `json:"required_signatures"`
@@ -125,13 +201,20 @@ func (msc *MultiSigCondition) GetMetadata() map[string]interface{} {
 	}
 }
 
-// OracleCondition represents an oracle-based access condition
+// OracleDataQueryPrefix prefixes oracle queries for a data key rather than a price symbol
+const OracleDataQueryPrefix = "data:"
+
+// OracleCondition represents an oracle-based access condition. The query is a price
+// symbol, e.g. "BTC", or a data key prefixed with "data:", e.g. "data:weather.berlin".
+// Values are read from the on-chain oracle, never from the caller.
 type OracleCondition struct {
-	OracleAddress string                 `json:"oracle_address"`
+	OracleAddress string                 `json:"oracle_address,omitempty"`
 	Query         string                 `json:"query"`
 	ExpectedValue interface{}            `json:"expected_value"`
 	Operator      string                 `json:"operator"` // "eq", "gt", "lt", "gte", "lte", "ne"
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+
+	oracle OracleKeeper
 }
 
 func (oc *OracleCondition) GetType() ConditionType {
@@ -139,10 +222,7 @@ func (oc *OracleCondition) GetType() ConditionType {
 }
 
 func (oc *OracleCondition) Validate() error {
-	if oc.OracleAddress == "" {
-		return fmt.Errorf("oracle address cannot be empty")
-	}
-	if oc.Query == "" {
+	if oc.Query == "" || oc.Query == OracleDataQueryPrefix {
 		return fmt.Errorf("oracle query cannot be empty")
 	}
 	if oc.ExpectedValue == nil {
@@ -157,27 +237,66 @@ func (oc *OracleCondition) Validate() error {
 		return fmt.Errorf("invalid operator: %s", oc.Operator)
 	}
 	
+	// Prices are always compared as decimals
+	if !strings.HasPrefix(oc.Query, OracleDataQueryPrefix) {
+		if _, err := toDec(oc.ExpectedValue); err != nil {
+			return fmt.Errorf("expected price must be a decimal: %w", err)
+		}
+	}
+	
 	return nil
 }
 
 func (oc *OracleCondition) Evaluate(ctx sdk.Context, params map[string]interface{}) (bool, error) {
-	// Placeholder for oracle query logic
-	// In practice, this would query the specified oracle
-	oracleValue, exists := params["oracle_value"]
-	if !exists {
-		return false, fmt.Errorf("oracle value not provided")
+	if oc.oracle == nil {
+		return false, fmt.Errorf("no oracle available to evaluate %s", oc.Query)
 	}
 	
-	return oc.compareValues(oracleValue, oc.ExpectedValue, oc.Operator)
+	if key, ok := strings.CutPrefix(oc.Query, OracleDataQueryPrefix); ok {
+		value, err := oc.oracle.GetData(ctx, key)
+		if err != nil {
+			return false, fmt.Errorf("failed to query oracle data %s: %w", key, err)
+		}
+		return oc.compareValues(string(value), oc.ExpectedValue, oc.Operator)
+	}
+	
+	price, err := oc.oracle.GetPrice(ctx, oc.Query)
+	if err != nil {
+		return false, fmt.Errorf("failed to query oracle price %s: %w", oc.Query, err)
+	}
+	return oc.compareValues(price, oc.ExpectedValue, oc.Operator)
 }
 
+// compareValues compares the oracle value to the expected value. Values that are both
+// decimals are compared numerically, other values can only be compared for equality.
 func (oc *OracleCondition) compareValues(actual, expected interface{}, operator string) (bool, error) {
+	actualDec, actualErr := toDec(actual)
+	expectedDec, expectedErr := toDec(expected)
+	
+	if actualErr != nil || expectedErr != nil {
+		switch operator {
+		case "eq":
+			return fmt.Sprint(actual) == fmt.Sprint(expected), nil
+		case "ne":
+			return fmt.Sprint(actual) != fmt.Sprint(expected), nil
+		default:
+			return false, fmt.Errorf("operator %s requires decimal values", operator)
+		}
+	}
+	
 	switch operator {
 	case "eq":
-		return actual == expected, nil
+		return actualDec.Equal(expectedDec), nil
 	case "ne":
-		return actual != expected, nil
-	// Add more comparison logic as needed
+		return !actualDec.Equal(expectedDec), nil
+	case "gt":
+		return actualDec.GT(expectedDec), nil
+	case "gte":
+		return actualDec.GTE(expectedDec), nil
+	case "lt":
+		return actualDec.LT(expectedDec), nil
+	case "lte":
+		return actualDec.LTE(expectedDec), nil
 	default:
 		return false, fmt.Errorf("unsupported operator: %s", operator)
 	}
@@ -198,6 +317,32 @@ func (oc *OracleCondition) GetMetadata() map[string]interface{} {
 	return metadata
 }
 
+// toDec converts a decimal oracle or condition value to a LegacyDec. JSON numbers are
+// formatted back to their shortest decimal form so no binary float rounding leaks in.
+func toDec(value interface{}) (math.LegacyDec, error) {
+	switch v := value.(type) {
+	case math.LegacyDec:
+		if v.IsNil() {
+			return math.LegacyDec{}, fmt.Errorf("nil decimal")
+		}
+		return v, nil
+	case string:
+		return math.LegacyNewDecFromStr(strings.TrimSpace(v))
+	case json.Number:
+		return math.LegacyNewDecFromStr(v.String())
+	case float64:
+		return math.LegacyNewDecFromStr(strconv.FormatFloat(v, 'f', -1, 64))
+	case int:
+		return math.LegacyNewDec(int64(v)), nil
+	case int64:
+		return math.LegacyNewDec(v), nil
+	case uint64:
+		return math.LegacyNewDecFromInt(math.NewIntFromUint64(v)), nil
+	default:
+		return math.LegacyDec{}, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
 // CompositeCondition represents a combination of multiple conditions
 type CompositeCondition struct {
 	Operator   string      `json:"operator"` // "AND", "OR", "NOT"
@@ -333,12 +478,19 @@ func (ic *InactivityCondition) GetMetadata() map[string]interface{} {
 }
 
 // ConditionFactory creates conditions from JSON data
-type ConditionFactory struct{}
+type ConditionFactory struct {
+	oracleKeeper OracleKeeper
+}
 
 func NewConditionFactory() *ConditionFactory {
 	return &ConditionFactory{}
 }
 
+// SetOracleKeeper sets the oracle that oracle conditions created by the factory query
+func (cf *ConditionFactory) SetOracleKeeper(oracleKeeper OracleKeeper) {
+	cf.oracleKeeper = oracleKeeper
+}
+
 func (cf *ConditionFactory) CreateCondition(conditionType ConditionType, data []byte) (Condition, error) {
 	switch conditionType {
 	case ConditionType_TIME:
@@ -348,6 +500,20 @@ func (cf *ConditionFactory) CreateCondition(conditionType ConditionType, data []
 		}
 		return &condition, nil
 		
+	case ConditionType_HEIGHT:
+		var condition HeightCondition
+		if err := json.Unmarshal(data, &condition); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal height condition: %w", err)
+		}
+		return &condition, nil
+		
+	case ConditionType_EPOCH:
+		var condition EpochCondition
+		if err := json.Unmarshal(data, &condition); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal epoch condition: %w", err)
+		}
+		return &condition, nil
+		
 	case ConditionType_MULTISIG:
 		var condition MultiSigCondition
 		if err := json.Unmarshal(data, &condition); err != nil {
@@ -356,10 +522,14 @@ func (cf *ConditionFactory) CreateCondition(conditionType ConditionType, data []
 		return &condition, nil
 		
 	case ConditionType_ORACLE:
+		// Decode numbers as json.Number so expected values keep their full precision
 		var condition OracleCondition
-		if err := json.Unmarshal(data, &condition); err != nil {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&condition); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal oracle condition: %w", err)
 		}
+		condition.oracle = cf.oracleKeeper
 		return &condition, nil
 		
 	case ConditionType_INACTIVITY:
@@ -0,0 +1,23 @@
+package types
+
+import "time"
+
+// ShareRefreshState tracks the refresh of capsule key shares. A refresh round, and the
+// re-distribution of the shares of a departed custodian, walk the capsules in batches
+// over several blocks.
+type ShareRefreshState struct {
+	LastRefreshAt          time.Time `json:"last_refresh_at"`                     // When the latest round started
+	NextCapsuleID          uint64    `json:"next_capsule_id,omitempty"`           // Next capsule of the running round, 0 when idle
+	DepartingCustodian     string    `json:"departing_custodian,omitempty"`       // Departed custodian being handled
+	NextDepartureCapsuleID uint64    `json:"next_departure_capsule_id,omitempty"` // Next capsule of the departing custodian
+}
+
+// CapsuleShareHealth reports how many key shares of a capsule are held by live custodians
+type CapsuleShareHealth struct {
+	CapsuleID      uint64   `json:"capsule_id"`
+	Threshold      uint32   `json:"threshold"`
+	TotalShares    uint32   `json:"total_shares"`
+	LiveShares     uint32   `json:"live_shares"`
+	Margin         int64    `json:"margin"`                    // Live shares above the threshold; negative when unopenable
+	LostCustodians []string `json:"lost_custodians,omitempty"` // Custodians that are no longer bonded
+}
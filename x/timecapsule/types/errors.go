@@ -36,4 +36,29 @@ var (
 	ErrInvalidAddress        = errors.Register(ModuleName, 27, "invalid address")
 	ErrInvalidRequest        = errors.Register(ModuleName, 28, "invalid request")
 	ErrInvalidCoins          = errors.Register(ModuleName, 29, "invalid coins")
+	ErrInvalidEscrow         = errors.Register(ModuleName, 30, "invalid escrow")
+	ErrInvalidTimelockKey    = errors.Register(ModuleName, 31, "invalid timelock key")
+	ErrTimelockEpochNotFound = errors.Register(ModuleName, 32, "timelock epoch not found")
+	ErrTimelockNotReleased   = errors.Register(ModuleName, 33, "timelock epoch key not released")
+	ErrInvalidGuardians      = errors.Register(ModuleName, 34, "invalid guardians")
+	ErrCapsuleFrozen         = errors.Register(ModuleName, 35, "capsule is frozen")
+	ErrEmergencyActionNotFound = errors.Register(ModuleName, 36, "emergency action not found")
+	ErrInvalidEmergencyAction  = errors.Register(ModuleName, 37, "invalid emergency action")
+	ErrEmergencyDelayActive    = errors.Register(ModuleName, 38, "emergency action delay has not elapsed")
+	ErrInvalidVersion          = errors.Register(ModuleName, 39, "invalid capsule version")
+	ErrInvalidReveal           = errors.Register(ModuleName, 40, "invalid capsule reveal")
+	ErrCreationQuotaExceeded   = errors.Register(ModuleName, 41, "capsule creation quota exceeded")
+	ErrTxPayloadTooLarge       = errors.Register(ModuleName, 42, "capsule payload of transaction too large")
+	ErrInsufficientCreationFee = errors.Register(ModuleName, 43, "insufficient funds for capsule creation fee")
+	ErrInvalidAllowance        = errors.Register(ModuleName, 44, "invalid capsule allowance")
+	ErrAllowanceNotFound       = errors.Register(ModuleName, 45, "capsule allowance not found")
+	ErrAllowanceExceeded       = errors.Register(ModuleName, 46, "capsule allowance exceeded")
+	ErrInvalidGroupPolicy      = errors.Register(ModuleName, 47, "invalid group policy")
+	ErrInvalidAttestor         = errors.Register(ModuleName, 48, "invalid attestor")
+	ErrAttestorNotFound        = errors.Register(ModuleName, 49, "attestor not found")
+	ErrInvalidAttestation      = errors.Register(ModuleName, 50, "invalid attestation")
+	ErrInvalidExpiry           = errors.Register(ModuleName, 51, "invalid capsule expiry")
+	ErrCapsulePurged           = errors.Register(ModuleName, 52, "capsule data has been purged")
+	ErrInvalidTags             = errors.Register(ModuleName, 53, "invalid capsule tags")
+	ErrInvalidPassphrase       = errors.Register(ModuleName, 54, "invalid passphrase factor")
 )
\ No newline at end of file
@@ -5,7 +5,8 @@ import (
 	"cosmossdk.io/math"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/group"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 )
 
 // AccountKeeper defines the expected account keeper used for simulations (noalias)
@@ -16,8 +17,8 @@ type AccountKeeper interface {
 	NewAccountWithAddress(ctx context.Context, addr sdk.AccAddress) sdk.AccountI
 	
 	GetModuleAddress(name string) sdk.AccAddress
-	GetModuleAccount(ctx context.Context, name string) types.ModuleAccountI
-	SetModuleAccount(ctx context.Context, macc types.ModuleAccountI)
+	GetModuleAccount(ctx context.Context, name string) sdk.ModuleAccountI
+	SetModuleAccount(ctx context.Context, macc sdk.ModuleAccountI)
 }
 
 // BankKeeper defines the expected interface needed to retrieve account balances.
@@ -43,6 +44,13 @@ type StakingKeeper interface {
 	GetBondedValidatorsByPower(ctx context.Context) []StakingValidator
 }
 
+// ValidatorSetKeeper defines the staking methods used by timelock key generation
+type ValidatorSetKeeper interface {
+	GetValidator(ctx context.Context, addr sdk.ValAddress) (stakingtypes.Validator, error)
+	GetValidatorByConsAddr(ctx context.Context, consAddr sdk.ConsAddress) (stakingtypes.Validator, error)
+	GetBondedValidatorsByPower(ctx context.Context) ([]stakingtypes.Validator, error)
+}
+
 // StakingValidator is a subset of the staking Validator type
 type StakingValidator interface {
 	GetOperator() sdk.ValAddress
@@ -107,6 +115,24 @@ type OracleKeeper interface {
 	IsDataAvailable(ctx context.Context, key string) bool
 }
 
+// EpochSource is a pluggable clock time-locked capsules can unlock on. A source keeps
+// epoch clocks by identifier, e.g. one clock per applied upgrade plan.
+type EpochSource interface {
+	// EpochStartHeight returns the height epoch starts at on the clock identifier, and
+	// false while that height cannot be known yet
+	EpochStartHeight(ctx context.Context, identifier string, epoch uint64) (int64, bool, error)
+}
+
+// UpgradeKeeper defines the upgrade keeper used by the upgrade epoch source
+type UpgradeKeeper interface {
+	GetDoneHeight(ctx context.Context, name string) (int64, error)
+}
+
+// GroupKeeper defines the x/group queries used to check group policy capsule owners
+type GroupKeeper interface {
+	GroupPolicyInfo(ctx context.Context, req *group.QueryGroupPolicyInfoRequest) (*group.QueryGroupPolicyInfoResponse, error)
+}
+
 // DistributionKeeper expected distribution keeper
 type DistributionKeeper interface {
 	FundCommunityPool(ctx context.Context, amount sdk.Coins, sender sdk.AccAddress) error
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// MaxGuardians is the maximum number of guardians a capsule can nominate
+const MaxGuardians = 10
+
+// Emergency action types
+const (
+	EmergencyActionContractDeletion = "contract_deletion" // Remove the condition contract and waive the unlock conditions
+	EmergencyActionForceUnlock      = "force_unlock"      // Waive the unlock conditions
+	EmergencyActionFreeze           = "freeze"            // Block opening, cancelling and transferring the capsule
+	EmergencyActionRotateRecipient  = "rotate_recipient"  // Replace the capsule recipient
+)
+
+// Emergency action statuses
+const (
+	EmergencyStatusPending  = "pending"  // Collecting approvals or waiting out the delay
+	EmergencyStatusExecuted = "executed" // Applied to the capsule
+	EmergencyStatusVetoed   = "vetoed"   // Rejected before execution
+	EmergencyStatusReversed = "reversed" // Undone after execution
+)
+
+// EmergencyAction represents a guardian-approved emergency action on a capsule
+type EmergencyAction struct {
+	ID              string     `json:"id"`
+	CapsuleID       uint64     `json:"capsule_id"`
+	Creator         string     `json:"creator"`     // Guardian that proposed the action
+	ActionType      string     `json:"action_type"` // "contract_deletion", "force_unlock", "freeze", "rotate_recipient"
+	Reason          string     `json:"reason"`
+	NewRecipient    string     `json:"new_recipient,omitempty"`     // For recipient rotation
+	NewRecipientKey []byte     `json:"new_recipient_key,omitempty"` // Delivery key of the new recipient
+	Approvals       []string   `json:"approvals"`                   // Approving guardians, including the proposer
+	Status          string     `json:"status"`
+	ActionTime      time.Time  `json:"action_time"` // When the action was proposed
+	BlockHeight     int64      `json:"block_height"`
+	ExecuteAfter    *time.Time `json:"execute_after,omitempty"` // End of the delay, set once approved
+	ExecutedAt      *time.Time `json:"executed_at,omitempty"`
+	IsReversible    bool       `json:"is_reversible"`
+	ReversibleUntil *time.Time `json:"reversible_until,omitempty"`
+	VetoedBy        string     `json:"vetoed_by,omitempty"`
+	ReversedAt      *time.Time `json:"reversed_at,omitempty"`
+	ReversedBy      string     `json:"reversed_by,omitempty"`
+
+	// Capsule state replaced by the action, restored on reversal
+	PreviousRecipient    string `json:"previous_recipient,omitempty"`
+	PreviousRecipientKey []byte `json:"previous_recipient_key,omitempty"`
+	PreviousContract     string `json:"previous_contract,omitempty"`
+}
+
+// HasApproved checks whether a guardian already approved the action
+func (a EmergencyAction) HasApproved(guardian string) bool {
+	for _, approval := range a.Approvals {
+		if approval == guardian {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateEmergencyActionType checks that an emergency action type is supported
+func ValidateEmergencyActionType(actionType string) error {
+	switch actionType {
+	case EmergencyActionContractDeletion, EmergencyActionForceUnlock,
+		EmergencyActionFreeze, EmergencyActionRotateRecipient:
+		return nil
+	default:
+		return fmt.Errorf("unknown emergency action type %q", actionType)
+	}
+}
+
+// ValidateGuardians validates a capsule's guardians and approval threshold
+func ValidateGuardians(owner string, guardians []string, threshold uint32) error {
+	if len(guardians) == 0 {
+		if threshold != 0 {
+			return fmt.Errorf("guardian threshold set without guardians")
+		}
+		return nil
+	}
+
+	if len(guardians) > MaxGuardians {
+		return fmt.Errorf("too many guardians: %d, maximum %d", len(guardians), MaxGuardians)
+	}
+
+	if threshold == 0 || int(threshold) > len(guardians) {
+		return fmt.Errorf("guardian threshold must be between 1 and %d, got %d", len(guardians), threshold)
+	}
+
+	seen := make(map[string]bool)
+	for i, guardian := range guardians {
+		if _, err := sdk.AccAddressFromBech32(guardian); err != nil {
+			return fmt.Errorf("invalid guardian address at index %d: %w", i, err)
+		}
+		if guardian == owner {
+			return fmt.Errorf("owner cannot be a guardian")
+		}
+		if seen[guardian] {
+			return fmt.Errorf("duplicate guardian %s", guardian)
+		}
+		seen[guardian] = true
+	}
+
+	return nil
+}
@@ -49,6 +49,75 @@ var (
 	
 	// EmergencyActionsKeyPrefix is the prefix for emergency actions storage
 	EmergencyActionsKeyPrefix = collections.NewPrefix(10)
+	
+	// TimelockKeysKeyPrefix is the prefix for validator timelock keys
+	TimelockKeysKeyPrefix = collections.NewPrefix(11)
+	
+	// TimelockEpochsKeyPrefix is the prefix for timelock epoch keys
+	TimelockEpochsKeyPrefix = collections.NewPrefix(12)
+	
+	// TimelockDealingsKeyPrefix is the prefix for timelock key generation dealings
+	TimelockDealingsKeyPrefix = collections.NewPrefix(13)
+	
+	// CapsuleEmergencyActionsKeyPrefix is the prefix for the capsule index of emergency actions
+	CapsuleEmergencyActionsKeyPrefix = collections.NewPrefix(14)
+	
+	// AccessLogKeyPrefix is the prefix for the capsule access log
+	AccessLogKeyPrefix = collections.NewPrefix(15)
+	
+	// AccessLogSequenceKey is the key for the access log sequence
+	AccessLogSequenceKey = collections.NewPrefix(16)
+	
+	// CapsuleVersionsKeyPrefix is the prefix for superseded capsule versions
+	CapsuleVersionsKeyPrefix = collections.NewPrefix(17)
+	
+	// CustodianSharesKeyPrefix is the prefix for the custodian index of key shares
+	CustodianSharesKeyPrefix = collections.NewPrefix(18)
+	
+	// CustodianDeparturesKeyPrefix is the prefix for custodians whose shares must be re-distributed
+	CustodianDeparturesKeyPrefix = collections.NewPrefix(19)
+	
+	// ShareRefreshStateKey is the key for the state of the periodic share refresh
+	ShareRefreshStateKey = collections.NewPrefix(20)
+	
+	// UnlockQueueKeyPrefix is the prefix for the queue of capsules unlocking at a height
+	UnlockQueueKeyPrefix = collections.NewPrefix(21)
+	
+	// EpochUnlocksKeyPrefix is the prefix for capsules waiting for their epoch trigger to resolve
+	EpochUnlocksKeyPrefix = collections.NewPrefix(22)
+	
+	// CreationQuotasKeyPrefix is the prefix for the per-account capsule creation quotas
+	CreationQuotasKeyPrefix = collections.NewPrefix(23)
+	
+	// CreationVolumeKey is the key for the module-wide creation volume driving the creation fee
+	CreationVolumeKey = collections.NewPrefix(24)
+	
+	// CapsuleStatsKey is the key for the module-wide capsule statistics
+	CapsuleStatsKey = collections.NewPrefix(25)
+	
+	// CapsuleAllowancesKeyPrefix is the prefix for the capsule fee allowances of sponsors
+	CapsuleAllowancesKeyPrefix = collections.NewPrefix(26)
+	
+	// AttestorsKeyPrefix is the prefix for the registered attestors
+	AttestorsKeyPrefix = collections.NewPrefix(27)
+	
+	// AttestationsKeyPrefix is the prefix for the attestations submitted for capsules
+	AttestationsKeyPrefix = collections.NewPrefix(28)
+	
+	// ExpiryQueueKeyPrefix is the prefix for the queue of capsules expiring at a time
+	ExpiryQueueKeyPrefix = collections.NewPrefix(29)
+	
+	// PurgeQueueKeyPrefix is the prefix for the queue of finished capsules whose data is purged at a time
+	PurgeQueueKeyPrefix = collections.NewPrefix(30)
+	
+	// UnlockTimeQueueKeyPrefix is the prefix for the queue of capsules unlocking after a block time
+	UnlockTimeQueueKeyPrefix = collections.NewPrefix(31)
+	
+	// CapsuleTagsKeyPrefix is the prefix for the index of capsules by owner and tag
+	CapsuleTagsKeyPrefix = collections.NewPrefix(32)
+	
+	// CapsuleMetadataKeyPrefix is the prefix for the index of capsules by owner and indexed metadata entry
+	CapsuleMetadataKeyPrefix = collections.NewPrefix(33)
 )
 
 // Event types
@@ -56,8 +125,43 @@ const (
 	EventTypeCapsuleCreated = "capsule_created"
 	EventTypeCapsuleOpened  = "capsule_opened"
 	EventTypeCapsuleUpdated = "capsule_updated"
+	EventTypeCapsuleAccessFailed = "capsule_access_failed"
 	EventTypeKeyShareDistributed = "key_share_distributed"
-	EventTypeEmergencyContractDeleted = "emergency_contract_deleted"
+	EventTypeKeyShareRejected    = "key_share_rejected"
+	EventTypeKeySharesRefreshed  = "key_shares_refreshed"
+	EventTypeKeyShareReassigned  = "key_share_reassigned"
+	EventTypeRecipientRotated    = "recipient_rotated"
+	EventTypeCapsuleDelivered    = "capsule_delivered"
+	EventTypeCapsuleRevealed     = "capsule_revealed"
+	EventTypeCapsuleUnlockable   = "capsule_unlockable"
+	EventTypeUnlockEpochResolved = "unlock_epoch_resolved"
+	EventTypeEmergencyActionProposed = "emergency_action_proposed"
+	EventTypeEmergencyActionApproved = "emergency_action_approved"
+	EventTypeEmergencyActionExecuted = "emergency_action_executed"
+	EventTypeEmergencyActionVetoed   = "emergency_action_vetoed"
+	EventTypeEmergencyActionReversed = "emergency_action_reversed"
+	EventTypeEscrowLocked   = "escrow_locked"
+	EventTypeEscrowReleased = "escrow_released"
+	EventTypeEscrowRefunded = "escrow_refunded"
+	EventTypeTimelockKeyRegistered = "timelock_key_registered"
+	EventTypeTimelockEpochScheduled = "timelock_epoch_scheduled"
+	EventTypeTimelockEpochKeyed = "timelock_epoch_keyed"
+	EventTypeTimelockEpochReleased = "timelock_epoch_released"
+	EventTypeTimelockEpochFailed = "timelock_epoch_failed"
+	EventTypeTimelockDealerDisqualified = "timelock_dealer_disqualified"
+	EventTypeCapsuleAllowanceGranted = "capsule_allowance_granted"
+	EventTypeCapsuleAllowanceRevoked = "capsule_allowance_revoked"
+	EventTypeCapsuleSponsored        = "capsule_sponsored"
+	EventTypeAttestorRegistered      = "attestor_registered"
+	EventTypeAttestorCurated         = "attestor_curated"
+	EventTypeAttestationSubmitted    = "attestation_submitted"
+	EventTypeCapsuleAttested         = "capsule_attested"
+	EventTypeCapsuleAttestorRevoked  = "capsule_attestor_revoked"
+	EventTypeCapsuleExpired          = "capsule_expired"
+	EventTypeCapsulePurged           = "capsule_purged"
+	EventTypeCapsuleHeartbeat        = "capsule_heartbeat"
+	EventTypeCapsuleTransferred      = "capsule_transferred"
+	EventTypeCapsuleCancelled        = "capsule_cancelled"
 )
 
 // Event attributes
@@ -68,9 +172,37 @@ const (
 	AttributeKeyCapsuleType  = "capsule_type"
 	AttributeKeyUnlockTime   = "unlock_time"
 	AttributeKeyDataHash     = "data_hash"
+	AttributeKeyIPFSHashes   = "ipfs_hashes"
 	AttributeKeyNodeID       = "node_id"
 	AttributeKeyShareIndex   = "share_index"
 	AttributeKeyEmergencyAction = "emergency_action"
-	AttributeKeyDeletionID   = "deletion_id"
+	AttributeKeyActionID     = "action_id"
+	AttributeKeyGuardian     = "guardian"
 	AttributeKeyEmergencyReason = "emergency_reason"
+	AttributeKeyAmount       = "amount"
+	AttributeKeyPayee        = "payee"
+	AttributeKeyValidator    = "validator"
+	AttributeKeyEpoch        = "epoch"
+	AttributeKeyDealer       = "dealer"
+	AttributeKeyVersion      = "version"
+	AttributeKeyPreviousNodeID = "previous_node_id"
+	AttributeKeyLiveShares   = "live_shares"
+	AttributeKeyPreviousRecipient = "previous_recipient"
+	AttributeKeyRecipientKey = "recipient_key"
+	AttributeKeySender       = "sender"
+	AttributeKeyRevealer     = "revealer"
+	AttributeKeyCommitHeight = "commit_height"
+	AttributeKeyUnlockHeight = "unlock_height"
+	AttributeKeyUnlockEpoch  = "unlock_epoch"
+	AttributeKeySponsor      = "sponsor"
+	AttributeKeyGrantee      = "grantee"
+	AttributeKeyAttestor     = "attestor"
+	AttributeKeyClaim        = "claim"
+	AttributeKeyCurated      = "curated"
+	AttributeKeyExpiresAt    = "expires_at"
+	AttributeKeyPurgeReason  = "purge_reason"
+	AttributeKeyLastActivity = "last_activity"
+	AttributeKeyFrom         = "from"
+	AttributeKeyTo           = "to"
+	AttributeKeyReason       = "reason"
 )
\ No newline at end of file
@@ -6,6 +6,7 @@ import (
 	
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"cosmossdk.io/errors"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
 )
 
 // Message types for time capsule operations
@@ -17,7 +18,22 @@ const (
 	TypeMsgTransferCapsule   = "transfer_capsule"
 	TypeMsgBatchTransferCapsules = "batch_transfer_capsules"
 	TypeMsgApproveTransfer   = "approve_transfer"
-	TypeMsgEmergencyDeleteContract = "emergency_delete_contract"
+	TypeMsgProposeEmergencyAction = "propose_emergency_action"
+	TypeMsgApproveEmergencyAction = "approve_emergency_action"
+	TypeMsgVetoEmergencyAction    = "veto_emergency_action"
+	TypeMsgExecuteEmergencyAction = "execute_emergency_action"
+	TypeMsgReverseEmergencyAction = "reverse_emergency_action"
+	TypeMsgRegisterTimelockKey = "register_timelock_key"
+	TypeMsgAmendCapsule        = "amend_capsule"
+	TypeMsgRotateRecipient     = "rotate_recipient"
+	TypeMsgRevealCapsule       = "reveal_capsule"
+	TypeMsgGrantCapsuleAllowance  = "grant_capsule_allowance"
+	TypeMsgRevokeCapsuleAllowance = "revoke_capsule_allowance"
+	TypeMsgRegisterAttestor       = "register_attestor"
+	TypeMsgCurateAttestor         = "curate_attestor"
+	TypeMsgSubmitAttestation      = "submit_attestation"
+	TypeMsgRevokeCapsuleAttestor  = "revoke_capsule_attestor"
+	TypeMsgEraseCapsule           = "erase_capsule"
 )
 
 // MsgCreateCapsule defines the message to create a new time capsule
@@ -35,6 +51,23 @@ type MsgCreateCapsule struct {
 	Title             string            `json:"title,omitempty"`
 	Description       string            `json:"description,omitempty"`
 	Metadata          map[string]string `json:"metadata,omitempty"`
+	Tags              []string          `json:"tags,omitempty"`               // Labels the owner can search capsules by
+	Escrow            sdk.Coins         `json:"escrow,omitempty"`        // Tokens locked with the capsule
+	Beneficiaries     []Beneficiary     `json:"beneficiaries,omitempty"` // Optional escrow split
+	Guardians         []string          `json:"guardians,omitempty"`          // Approvers of emergency actions
+	GuardianThreshold uint32            `json:"guardian_threshold,omitempty"` // Guardian approvals required
+	VersionPolicy     string            `json:"version_policy,omitempty"`     // "latest" (default) or "all"
+	RecipientKey      []byte            `json:"recipient_key,omitempty"`      // Recipient's secp256k1 public key for delivery
+	Executor          string            `json:"executor,omitempty"`           // May rotate the recipient besides the creator
+	Commitment        string            `json:"commitment,omitempty"`         // Content commitment of public reveal capsules, instead of data
+	UnlockHeight      int64             `json:"unlock_height,omitempty"`      // Block height time-locked capsules unlock at instead of a time
+	UnlockEpoch       *EpochTrigger     `json:"unlock_epoch,omitempty"`       // Epoch trigger time-locked capsules unlock at instead of a time
+	Compression       string            `json:"compression,omitempty"`        // "gzip" or "zstd" to compress the data before encryption
+	Sponsor           string            `json:"sponsor,omitempty"`            // Pays the creation and storage fees under its capsule allowance
+	GroupPolicy       string            `json:"group_policy,omitempty"`       // x/group policy owning a multi-sig capsule, acting through proposals
+	Attestation       *AttestationCondition `json:"attestation,omitempty"`    // Attestors unlocking a conditional capsule instead of a contract
+	ExpiresAt         *time.Time        `json:"expires_at,omitempty"`         // The capsule expires unopened then and its data is purged
+	PassphraseFactor  *PassphraseFactor `json:"passphrase_factor,omitempty"`  // Second factor, besides the recipient key, to open the delivery
 }
 
 // NewMsgCreateCapsule creates a new MsgCreateCapsule
@@ -97,6 +130,55 @@ func (msg *MsgCreateCapsule) ValidateBasic() error {
 		}
 	}
 
+	// Validate sponsor address if provided
+	if msg.Sponsor != "" {
+		if _, err := sdk.AccAddressFromBech32(msg.Sponsor); err != nil {
+			return errors.Wrapf(ErrInvalidAddress, "invalid sponsor address (%s)", err)
+		}
+		if msg.Sponsor == msg.Creator {
+			return errors.Wrap(ErrInvalidAllowance, "creator cannot sponsor its own capsule")
+		}
+	}
+
+	// Validate the group policy owning a multi-sig capsule
+	if msg.GroupPolicy != "" {
+		if msg.CapsuleType != CapsuleType_MULTI_SIG {
+			return errors.Wrap(ErrInvalidGroupPolicy, "only multi-sig capsules can be owned by a group policy")
+		}
+		if _, err := sdk.AccAddressFromBech32(msg.GroupPolicy); err != nil {
+			return errors.Wrapf(ErrInvalidAddress, "invalid group policy address (%s)", err)
+		}
+		if msg.RequiredSigs != 0 {
+			return errors.Wrap(ErrInvalidGroupPolicy, "group policy capsules take their threshold from the group decision policy, not required signatures")
+		}
+	}
+
+	// Validate the attestors unlocking a conditional capsule
+	if msg.Attestation != nil {
+		if msg.CapsuleType != CapsuleType_CONDITIONAL {
+			return errors.Wrap(ErrInvalidAttestation, "only conditional capsules can have an attestation condition")
+		}
+		if msg.Attestation.AttestedAt != nil {
+			return errors.Wrap(ErrInvalidAttestation, "attestation condition cannot be created attested")
+		}
+		if err := msg.Attestation.Validate(); err != nil {
+			return errors.Wrap(ErrInvalidAttestation, err.Error())
+		}
+	}
+
+	// Validate tags, bounded by the module parameters on creation
+	if err := ValidateTags(msg.Tags); err != nil {
+		return errors.Wrap(ErrInvalidTags, err.Error())
+	}
+
+	// Public reveal capsules commit to their content instead of encrypting it
+	if msg.CapsuleType == CapsuleType_PUBLIC_REVEAL {
+		return msg.validatePublicReveal()
+	}
+	if msg.Commitment != "" {
+		return errors.Wrap(ErrInvalidCapsule, "commitment is only used by public reveal capsules")
+	}
+
 	// Validate data
 	if len(msg.Data) == 0 {
 		return errors.Wrap(ErrInvalidCapsule, "data cannot be empty")
@@ -121,24 +203,75 @@ func (msg *MsgCreateCapsule) ValidateBasic() error {
 		return errors.Wrap(ErrInvalidThreshold, "threshold cannot exceed total shares")
 	}
 
+	// Validate escrow and beneficiary split
+	if err := ValidateEscrow(msg.Escrow, msg.Beneficiaries); err != nil {
+		return errors.Wrap(ErrInvalidEscrow, err.Error())
+	}
+
+	// Validate guardians
+	if err := ValidateGuardians(msg.Creator, msg.Guardians, msg.GuardianThreshold); err != nil {
+		return errors.Wrap(ErrInvalidGuardians, err.Error())
+	}
+
+	// Validate version policy
+	if err := ValidateVersionPolicy(msg.VersionPolicy); err != nil {
+		return errors.Wrap(ErrInvalidVersion, err.Error())
+	}
+
+	// Validate compression
+	if err := crypto.ValidateCompression(msg.Compression); err != nil {
+		return errors.Wrap(ErrInvalidEncryption, err.Error())
+	}
+
+	// Validate recipient delivery
+	if err := ValidateRecipientKey(msg.RecipientKey); err != nil {
+		return errors.Wrap(ErrInvalidRecipient, err.Error())
+	}
+	if err := ValidateExecutor(msg.Creator, msg.Executor); err != nil {
+		return errors.Wrap(ErrInvalidAddress, err.Error())
+	}
+	if msg.PassphraseFactor != nil {
+		if len(msg.RecipientKey) == 0 {
+			return errors.Wrap(ErrInvalidRecipient, "passphrase factor needs a recipient key to protect")
+		}
+		if len(msg.PassphraseFactor.Ciphertext) > 0 || len(msg.PassphraseFactor.Nonce) > 0 {
+			return errors.Wrap(ErrInvalidPassphrase, "passphrase factor cannot be created sealed")
+		}
+		if err := msg.PassphraseFactor.Validate(); err != nil {
+			return errors.Wrap(ErrInvalidPassphrase, err.Error())
+		}
+	}
+
+	if msg.CapsuleType != CapsuleType_TIME_LOCK && (msg.UnlockHeight != 0 || msg.UnlockEpoch != nil) {
+		return errors.Wrap(ErrInvalidTimelock, "only time-locked capsules can unlock on a height or epoch")
+	}
+
+	// Validate expiry
+	if err := ValidateExpiry(msg.ExpiresAt, msg.UnlockTime); err != nil {
+		return errors.Wrap(ErrInvalidExpiry, err.Error())
+	}
+
 	// Validate capsule type specific requirements
 	switch msg.CapsuleType {
 	case CapsuleType_TIME_LOCK:
-		if msg.UnlockTime == nil {
-			return errors.Wrap(ErrInvalidTimelock, "time-locked capsule must have unlock time")
+		if err := ValidateUnlockTrigger(msg.UnlockTime, msg.UnlockHeight, msg.UnlockEpoch); err != nil {
+			return errors.Wrap(ErrInvalidTimelock, err.Error())
 		}
-		if msg.UnlockTime.Before(time.Now()) {
+		if msg.UnlockTime != nil && msg.UnlockTime.Before(time.Now()) {
 			return errors.Wrap(ErrInvalidTimelock, "unlock time must be in the future")
 		}
 
 	case CapsuleType_CONDITIONAL:
-		if msg.ConditionContract == "" {
-			return errors.Wrap(ErrConditionNotMet, "conditional capsule must have condition contract")
+		if msg.ConditionContract == "" && msg.Attestation == nil {
+			return errors.Wrap(ErrConditionNotMet, "conditional capsule must have condition contract or attestation condition")
+		}
+		if msg.ConditionContract != "" && msg.Attestation != nil {
+			return errors.Wrap(ErrInvalidAttestation, "conditional capsule cannot have both condition contract and attestation condition")
 		}
 
 	case CapsuleType_MULTI_SIG:
-		if msg.RequiredSigs == 0 {
-			return errors.Wrap(ErrInvalidSignature, "multi-sig capsule must have required signatures")
+		if msg.RequiredSigs == 0 && msg.GroupPolicy == "" {
+			return errors.Wrap(ErrInvalidSignature, "multi-sig capsule must have required signatures or a group policy")
 		}
 
 	case CapsuleType_DEAD_MANS_SWITCH:
@@ -153,6 +286,50 @@ func (msg *MsgCreateCapsule) ValidateBasic() error {
 	return nil
 }
 
+// validatePublicReveal validates the creation of a public reveal capsule, whose data
+// stays off-chain until it is revealed
+func (msg *MsgCreateCapsule) validatePublicReveal() error {
+	if len(msg.Data) > 0 {
+		return errors.Wrap(ErrInvalidCapsule, "public reveal capsule takes a commitment, not data")
+	}
+
+	if err := crypto.ValidateCommitment(msg.Commitment); err != nil {
+		return errors.Wrap(ErrInvalidCapsule, err.Error())
+	}
+
+	if msg.UnlockTime == nil {
+		return errors.Wrap(ErrInvalidTimelock, "public reveal capsule must have unlock time")
+	}
+	if msg.UnlockTime.Before(time.Now()) {
+		return errors.Wrap(ErrInvalidTimelock, "unlock time must be in the future")
+	}
+	if msg.UnlockHeight != 0 || msg.UnlockEpoch != nil {
+		return errors.Wrap(ErrInvalidTimelock, "public reveal capsule can only unlock on its unlock time")
+	}
+
+	if !msg.Escrow.Empty() || len(msg.Beneficiaries) > 0 {
+		return errors.Wrap(ErrInvalidEscrow, "public reveal capsule cannot lock an escrow")
+	}
+
+	if len(msg.RecipientKey) > 0 || msg.PassphraseFactor != nil {
+		return errors.Wrap(ErrInvalidRecipient, "public reveal capsule is not delivered to a recipient key")
+	}
+
+	if msg.Compression != "" {
+		return errors.Wrap(ErrInvalidEncryption, "public reveal capsule content is not compressed")
+	}
+
+	if msg.ExpiresAt != nil {
+		return errors.Wrap(ErrInvalidExpiry, "public reveal capsule cannot expire")
+	}
+
+	if err := ValidateGuardians(msg.Creator, msg.Guardians, msg.GuardianThreshold); err != nil {
+		return errors.Wrap(ErrInvalidGuardians, err.Error())
+	}
+
+	return nil
+}
+
 // MsgOpenCapsule defines the message to open a time capsule
 type MsgOpenCapsule struct {
 	Accessor        string                 `json:"accessor"`
@@ -546,80 +723,963 @@ func (msg *MsgApproveTransfer) ValidateBasic() error {
 	return nil
 }
 
-// MsgEmergencyDeleteContract defines the message to emergency delete a capsule's smart contract
-type MsgEmergencyDeleteContract struct {
-	Creator        string `json:"creator"`
-	CapsuleID      uint64 `json:"capsule_id"`
-	EmergencyReason string `json:"emergency_reason"`
-	ConfirmationCode string `json:"confirmation_code"` // Additional security layer
+// MsgProposeEmergencyAction defines the message for a guardian to propose an emergency action
+type MsgProposeEmergencyAction struct {
+	Guardian        string `json:"guardian"`
+	CapsuleID       uint64 `json:"capsule_id"`
+	ActionType      string `json:"action_type"`
+	Reason          string `json:"reason"`
+	NewRecipient    string `json:"new_recipient,omitempty"`     // Required for recipient rotation
+	NewRecipientKey []byte `json:"new_recipient_key,omitempty"` // Optional delivery key of the new recipient
 }
 
-// NewMsgEmergencyDeleteContract creates a new MsgEmergencyDeleteContract
-func NewMsgEmergencyDeleteContract(creator string, capsuleID uint64, reason string, confirmationCode string) *MsgEmergencyDeleteContract {
-	return &MsgEmergencyDeleteContract{
-		Creator:          creator,
-		CapsuleID:        capsuleID,
-		EmergencyReason:  reason,
-		ConfirmationCode: confirmationCode,
+// NewMsgProposeEmergencyAction creates a new MsgProposeEmergencyAction
+func NewMsgProposeEmergencyAction(guardian string, capsuleID uint64, actionType, reason, newRecipient string) *MsgProposeEmergencyAction {
+	return &MsgProposeEmergencyAction{
+		Guardian:     guardian,
+		CapsuleID:    capsuleID,
+		ActionType:   actionType,
+		Reason:       reason,
+		NewRecipient: newRecipient,
 	}
 }
 
 // Route implements the sdk.Msg interface
-func (msg *MsgEmergencyDeleteContract) Route() string {
+func (msg *MsgProposeEmergencyAction) Route() string {
 	return RouterKey
 }
 
 // Type implements the sdk.Msg interface
-func (msg *MsgEmergencyDeleteContract) Type() string {
-	return TypeMsgEmergencyDeleteContract
+func (msg *MsgProposeEmergencyAction) Type() string {
+	return TypeMsgProposeEmergencyAction
 }
 
 // GetSigners implements the sdk.Msg interface
-func (msg *MsgEmergencyDeleteContract) GetSigners() []sdk.AccAddress {
-	creator, err := sdk.AccAddressFromBech32(msg.Creator)
+func (msg *MsgProposeEmergencyAction) GetSigners() []sdk.AccAddress {
+	guardian, err := sdk.AccAddressFromBech32(msg.Guardian)
 	if err != nil {
 		panic(err)
 	}
-	return []sdk.AccAddress{creator}
+	return []sdk.AccAddress{guardian}
 }
 
 // GetSignBytes implements the sdk.Msg interface
-func (msg *MsgEmergencyDeleteContract) GetSignBytes() []byte {
+func (msg *MsgProposeEmergencyAction) GetSignBytes() []byte {
 	bz := ModuleCdc.MustMarshalJSON(msg)
 	return sdk.MustSortJSON(bz)
 }
 
 // ValidateBasic implements the sdk.Msg interface
-func (msg *MsgEmergencyDeleteContract) ValidateBasic() error {
-	// Validate creator address
-	_, err := sdk.AccAddressFromBech32(msg.Creator)
+func (msg *MsgProposeEmergencyAction) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Guardian)
 	if err != nil {
-		return errors.Wrapf(ErrInvalidAddress, "invalid creator address (%s)", err)
+		return errors.Wrapf(ErrInvalidAddress, "invalid guardian address (%s)", err)
 	}
 
-	// Validate capsule ID
 	if msg.CapsuleID == 0 {
 		return errors.Wrap(ErrCapsuleNotFound, "capsule ID cannot be zero")
 	}
 
-	// Validate emergency reason
-	if msg.EmergencyReason == "" {
+	if err := ValidateEmergencyActionType(msg.ActionType); err != nil {
+		return errors.Wrap(ErrInvalidEmergencyAction, err.Error())
+	}
+
+	if msg.Reason == "" {
 		return errors.Wrap(ErrInvalidRequest, "emergency reason cannot be empty")
 	}
 
-	if len(msg.EmergencyReason) > 500 {
+	if len(msg.Reason) > 500 {
 		return errors.Wrap(ErrInvalidRequest, "emergency reason too long (max 500 characters)")
 	}
 
-	// Validate confirmation code
-	if msg.ConfirmationCode == "" {
-		return errors.Wrap(ErrInvalidRequest, "confirmation code cannot be empty")
+	if msg.ActionType == EmergencyActionRotateRecipient {
+		if _, err := sdk.AccAddressFromBech32(msg.NewRecipient); err != nil {
+			return errors.Wrapf(ErrInvalidRecipient, "invalid new recipient address (%s)", err)
+		}
+		if err := ValidateRecipientKey(msg.NewRecipientKey); err != nil {
+			return errors.Wrap(ErrInvalidRecipient, err.Error())
+		}
+	} else if msg.NewRecipient != "" || len(msg.NewRecipientKey) > 0 {
+		return errors.Wrap(ErrInvalidEmergencyAction, "new recipient is only used for recipient rotation")
+	}
+
+	return nil
+}
+
+// MsgApproveEmergencyAction defines the message for a guardian to approve a pending emergency action
+type MsgApproveEmergencyAction struct {
+	Guardian string `json:"guardian"`
+	ActionID string `json:"action_id"`
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgApproveEmergencyAction) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgApproveEmergencyAction) Type() string {
+	return TypeMsgApproveEmergencyAction
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgApproveEmergencyAction) GetSigners() []sdk.AccAddress {
+	guardian, err := sdk.AccAddressFromBech32(msg.Guardian)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{guardian}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgApproveEmergencyAction) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgApproveEmergencyAction) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Guardian)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid guardian address (%s)", err)
+	}
+
+	if msg.ActionID == "" {
+		return errors.Wrap(ErrEmergencyActionNotFound, "action ID cannot be empty")
+	}
+
+	return nil
+}
+
+// MsgVetoEmergencyAction defines the message for the recipient or a guardian to reject
+// an emergency action before it executes
+type MsgVetoEmergencyAction struct {
+	Sender   string `json:"sender"`
+	ActionID string `json:"action_id"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgVetoEmergencyAction) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgVetoEmergencyAction) Type() string {
+	return TypeMsgVetoEmergencyAction
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgVetoEmergencyAction) GetSigners() []sdk.AccAddress {
+	sender, err := sdk.AccAddressFromBech32(msg.Sender)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{sender}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgVetoEmergencyAction) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgVetoEmergencyAction) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Sender)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid sender address (%s)", err)
+	}
+
+	if msg.ActionID == "" {
+		return errors.Wrap(ErrEmergencyActionNotFound, "action ID cannot be empty")
+	}
+
+	if len(msg.Reason) > 500 {
+		return errors.Wrap(ErrInvalidRequest, "veto reason too long (max 500 characters)")
+	}
+
+	return nil
+}
+
+// MsgExecuteEmergencyAction defines the message for a guardian to execute an approved
+// emergency action once its delay has elapsed
+type MsgExecuteEmergencyAction struct {
+	Guardian string `json:"guardian"`
+	ActionID string `json:"action_id"`
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgExecuteEmergencyAction) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgExecuteEmergencyAction) Type() string {
+	return TypeMsgExecuteEmergencyAction
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgExecuteEmergencyAction) GetSigners() []sdk.AccAddress {
+	guardian, err := sdk.AccAddressFromBech32(msg.Guardian)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{guardian}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgExecuteEmergencyAction) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgExecuteEmergencyAction) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Guardian)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid guardian address (%s)", err)
+	}
+
+	if msg.ActionID == "" {
+		return errors.Wrap(ErrEmergencyActionNotFound, "action ID cannot be empty")
+	}
+
+	return nil
+}
+
+// MsgReverseEmergencyAction defines the message for the recipient or a guardian to undo
+// an executed emergency action within its reversal window
+type MsgReverseEmergencyAction struct {
+	Sender   string `json:"sender"`
+	ActionID string `json:"action_id"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgReverseEmergencyAction) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgReverseEmergencyAction) Type() string {
+	return TypeMsgReverseEmergencyAction
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgReverseEmergencyAction) GetSigners() []sdk.AccAddress {
+	sender, err := sdk.AccAddressFromBech32(msg.Sender)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{sender}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgReverseEmergencyAction) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgReverseEmergencyAction) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Sender)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid sender address (%s)", err)
+	}
+
+	if msg.ActionID == "" {
+		return errors.Wrap(ErrEmergencyActionNotFound, "action ID cannot be empty")
+	}
+
+	if len(msg.Reason) > 500 {
+		return errors.Wrap(ErrInvalidRequest, "reversal reason too long (max 500 characters)")
+	}
+
+	return nil
+}
+
+// MsgRegisterTimelockKey defines the message for a validator to register the key
+// it receives timelock key-generation shares with
+type MsgRegisterTimelockKey struct {
+	Validator string `json:"validator"`  // Validator operator address
+	PublicKey []byte `json:"public_key"` // Compressed secp256k1 public key
+}
+
+// NewMsgRegisterTimelockKey creates a new MsgRegisterTimelockKey
+func NewMsgRegisterTimelockKey(validator string, publicKey []byte) *MsgRegisterTimelockKey {
+	return &MsgRegisterTimelockKey{
+		Validator: validator,
+		PublicKey: publicKey,
+	}
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgRegisterTimelockKey) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgRegisterTimelockKey) Type() string {
+	return TypeMsgRegisterTimelockKey
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgRegisterTimelockKey) GetSigners() []sdk.AccAddress {
+	valAddr, err := sdk.ValAddressFromBech32(msg.Validator)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{sdk.AccAddress(valAddr)}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgRegisterTimelockKey) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgRegisterTimelockKey) ValidateBasic() error {
+	// Validate validator address
+	_, err := sdk.ValAddressFromBech32(msg.Validator)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid validator address (%s)", err)
 	}
 
-	// Simple validation for confirmation code format
-	if len(msg.ConfirmationCode) < 8 {
-		return errors.Wrap(ErrInvalidRequest, "confirmation code must be at least 8 characters")
+	// Validate public key
+	if err := crypto.ValidateTimelockPoint(msg.PublicKey); err != nil {
+		return errors.Wrapf(ErrInvalidTimelockKey, "invalid public key (%s)", err)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// MsgAmendCapsule defines the message for an owner to replace the content of an
+// active capsule with a new version
+type MsgAmendCapsule struct {
+	Owner       string            `json:"owner"`
+	CapsuleID   uint64            `json:"capsule_id"`
+	Data        []byte            `json:"data"`
+	Title       string            `json:"title,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Compression string            `json:"compression,omitempty"` // "gzip" or "zstd" to compress the data before encryption
+}
+
+// NewMsgAmendCapsule creates a new MsgAmendCapsule
+func NewMsgAmendCapsule(owner string, capsuleID uint64, data []byte) *MsgAmendCapsule {
+	return &MsgAmendCapsule{
+		Owner:     owner,
+		CapsuleID: capsuleID,
+		Data:      data,
+	}
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgAmendCapsule) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgAmendCapsule) Type() string {
+	return TypeMsgAmendCapsule
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgAmendCapsule) GetSigners() []sdk.AccAddress {
+	owner, err := sdk.AccAddressFromBech32(msg.Owner)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{owner}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgAmendCapsule) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgAmendCapsule) ValidateBasic() error {
+	// Validate owner address
+	_, err := sdk.AccAddressFromBech32(msg.Owner)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid owner address (%s)", err)
+	}
+
+	// Validate capsule ID
+	if msg.CapsuleID == 0 {
+		return errors.Wrap(ErrCapsuleNotFound, "capsule ID cannot be zero")
+	}
+
+	// Validate data
+	if len(msg.Data) == 0 {
+		return errors.Wrap(ErrInvalidCapsule, "data cannot be empty")
+	}
+
+	// Check data size limits (1MB max)
+	maxDataSize := 1024 * 1024
+	if len(msg.Data) > maxDataSize {
+		return errors.Wrapf(ErrDataTooLarge, "data size %d exceeds maximum %d", len(msg.Data), maxDataSize)
+	}
+
+	if err := crypto.ValidateCompression(msg.Compression); err != nil {
+		return errors.Wrap(ErrInvalidEncryption, err.Error())
+	}
+
+	if err := ValidateTags(msg.Tags); err != nil {
+		return errors.Wrap(ErrInvalidTags, err.Error())
+	}
+
+	return nil
+}
+
+// MsgRotateRecipient defines the message for the owner or executor of a capsule to
+// re-target it to a new recipient. The custodians re-encrypt the data key to the new
+// recipient key on delivery, so the plaintext is never needed.
+type MsgRotateRecipient struct {
+	Sender          string `json:"sender"`
+	CapsuleID       uint64 `json:"capsule_id"`
+	NewRecipient    string `json:"new_recipient"`
+	NewRecipientKey []byte `json:"new_recipient_key,omitempty"` // Clears the delivery key when empty
+}
+
+// NewMsgRotateRecipient creates a new MsgRotateRecipient
+func NewMsgRotateRecipient(sender string, capsuleID uint64, newRecipient string, newRecipientKey []byte) *MsgRotateRecipient {
+	return &MsgRotateRecipient{
+		Sender:          sender,
+		CapsuleID:       capsuleID,
+		NewRecipient:    newRecipient,
+		NewRecipientKey: newRecipientKey,
+	}
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgRotateRecipient) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgRotateRecipient) Type() string {
+	return TypeMsgRotateRecipient
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgRotateRecipient) GetSigners() []sdk.AccAddress {
+	sender, err := sdk.AccAddressFromBech32(msg.Sender)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{sender}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgRotateRecipient) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgRotateRecipient) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Sender)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid sender address (%s)", err)
+	}
+
+	if msg.CapsuleID == 0 {
+		return errors.Wrap(ErrCapsuleNotFound, "capsule ID cannot be zero")
+	}
+
+	if _, err := sdk.AccAddressFromBech32(msg.NewRecipient); err != nil {
+		return errors.Wrapf(ErrInvalidRecipient, "invalid new recipient address (%s)", err)
+	}
+
+	if err := ValidateRecipientKey(msg.NewRecipientKey); err != nil {
+		return errors.Wrap(ErrInvalidRecipient, err.Error())
+	}
+
+	return nil
+}
+
+// MsgRevealCapsule defines the message to publish the content of a public reveal
+// capsule after its unlock time. Anyone holding the content and salt may submit it.
+type MsgRevealCapsule struct {
+	Revealer  string `json:"revealer"`
+	CapsuleID uint64 `json:"capsule_id"`
+	Data      []byte `json:"data"`
+	Salt      []byte `json:"salt,omitempty"`
+}
+
+// NewMsgRevealCapsule creates a new MsgRevealCapsule
+func NewMsgRevealCapsule(revealer string, capsuleID uint64, data, salt []byte) *MsgRevealCapsule {
+	return &MsgRevealCapsule{
+		Revealer:  revealer,
+		CapsuleID: capsuleID,
+		Data:      data,
+		Salt:      salt,
+	}
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgRevealCapsule) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgRevealCapsule) Type() string {
+	return TypeMsgRevealCapsule
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgRevealCapsule) GetSigners() []sdk.AccAddress {
+	revealer, err := sdk.AccAddressFromBech32(msg.Revealer)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{revealer}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgRevealCapsule) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgRevealCapsule) ValidateBasic() error {
+	_, err := sdk.AccAddressFromBech32(msg.Revealer)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid revealer address (%s)", err)
+	}
+
+	if msg.CapsuleID == 0 {
+		return errors.Wrap(ErrCapsuleNotFound, "capsule ID cannot be zero")
+	}
+
+	// Check data size limits (1MB max)
+	maxDataSize := uint64(1024 * 1024)
+	if err := ValidateReveal(msg.Data, msg.Salt, maxDataSize); err != nil {
+		return errors.Wrap(ErrInvalidReveal, err.Error())
+	}
+
+	return nil
+}
+
+// MsgGrantCapsuleAllowance defines the message for a sponsor to pay the creation and
+// storage fees of the capsules a grantee creates. It replaces any allowance the sponsor
+// granted the grantee before.
+type MsgGrantCapsuleAllowance struct {
+	Sponsor     string     `json:"sponsor"`
+	Grantee     string     `json:"grantee"`
+	MaxCapsules uint32     `json:"max_capsules,omitempty"` // 0 for no limit
+	MaxBytes    uint64     `json:"max_bytes,omitempty"`    // 0 for no limit
+	SpendLimit  sdk.Coins  `json:"spend_limit,omitempty"`  // No limit when empty
+	Expiration  *time.Time `json:"expiration,omitempty"`   // No expiry when unset
+}
+
+// NewMsgGrantCapsuleAllowance creates a new MsgGrantCapsuleAllowance
+func NewMsgGrantCapsuleAllowance(sponsor, grantee string, maxCapsules uint32, maxBytes uint64, spendLimit sdk.Coins, expiration *time.Time) *MsgGrantCapsuleAllowance {
+	return &MsgGrantCapsuleAllowance{
+		Sponsor:     sponsor,
+		Grantee:     grantee,
+		MaxCapsules: maxCapsules,
+		MaxBytes:    maxBytes,
+		SpendLimit:  spendLimit,
+		Expiration:  expiration,
+	}
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgGrantCapsuleAllowance) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgGrantCapsuleAllowance) Type() string {
+	return TypeMsgGrantCapsuleAllowance
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgGrantCapsuleAllowance) GetSigners() []sdk.AccAddress {
+	sponsor, err := sdk.AccAddressFromBech32(msg.Sponsor)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{sponsor}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgGrantCapsuleAllowance) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgGrantCapsuleAllowance) ValidateBasic() error {
+	allowance := CapsuleAllowance{
+		Sponsor:     msg.Sponsor,
+		Grantee:     msg.Grantee,
+		MaxCapsules: msg.MaxCapsules,
+		MaxBytes:    msg.MaxBytes,
+		SpendLimit:  msg.SpendLimit,
+		Expiration:  msg.Expiration,
+	}
+	if err := allowance.Validate(); err != nil {
+		return errors.Wrap(ErrInvalidAllowance, err.Error())
+	}
+
+	return nil
+}
+
+// MsgRevokeCapsuleAllowance defines the message for a sponsor to revoke the allowance
+// of a grantee. Capsules already created under the allowance are not affected.
+type MsgRevokeCapsuleAllowance struct {
+	Sponsor string `json:"sponsor"`
+	Grantee string `json:"grantee"`
+}
+
+// NewMsgRevokeCapsuleAllowance creates a new MsgRevokeCapsuleAllowance
+func NewMsgRevokeCapsuleAllowance(sponsor, grantee string) *MsgRevokeCapsuleAllowance {
+	return &MsgRevokeCapsuleAllowance{
+		Sponsor: sponsor,
+		Grantee: grantee,
+	}
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgRevokeCapsuleAllowance) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgRevokeCapsuleAllowance) Type() string {
+	return TypeMsgRevokeCapsuleAllowance
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgRevokeCapsuleAllowance) GetSigners() []sdk.AccAddress {
+	sponsor, err := sdk.AccAddressFromBech32(msg.Sponsor)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{sponsor}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgRevokeCapsuleAllowance) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgRevokeCapsuleAllowance) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Sponsor); err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid sponsor address (%s)", err)
+	}
+
+	if _, err := sdk.AccAddressFromBech32(msg.Grantee); err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid grantee address (%s)", err)
+	}
+
+	return nil
+}
+
+// MsgRegisterAttestor defines the message for an organization to register as an
+// attestor, or to update the key and description it registered before
+type MsgRegisterAttestor struct {
+	Attestor    string `json:"attestor"`
+	KeyType     string `json:"key_type"`    // "secp256k1" or "ed25519"
+	PubKey      []byte `json:"pub_key"`     // Key the attestations are signed with
+	Description string `json:"description"` // Who the attestor is
+}
+
+// NewMsgRegisterAttestor creates a new MsgRegisterAttestor
+func NewMsgRegisterAttestor(attestor, keyType string, pubKey []byte, description string) *MsgRegisterAttestor {
+	return &MsgRegisterAttestor{
+		Attestor:    attestor,
+		KeyType:     keyType,
+		PubKey:      pubKey,
+		Description: description,
+	}
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgRegisterAttestor) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgRegisterAttestor) Type() string {
+	return TypeMsgRegisterAttestor
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgRegisterAttestor) GetSigners() []sdk.AccAddress {
+	attestor, err := sdk.AccAddressFromBech32(msg.Attestor)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{attestor}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgRegisterAttestor) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgRegisterAttestor) ValidateBasic() error {
+	attestor := Attestor{
+		Address:     msg.Attestor,
+		KeyType:     msg.KeyType,
+		PubKey:      msg.PubKey,
+		Description: msg.Description,
+	}
+	if err := attestor.Validate(); err != nil {
+		return errors.Wrap(ErrInvalidAttestor, err.Error())
+	}
+
+	return nil
+}
+
+// MsgCurateAttestor defines the governance message to curate an attestor. Capsules can
+// require their attestors to be curated.
+type MsgCurateAttestor struct {
+	Authority string `json:"authority"`
+	Attestor  string `json:"attestor"`
+	Curated   bool   `json:"curated"`
+}
+
+// NewMsgCurateAttestor creates a new MsgCurateAttestor
+func NewMsgCurateAttestor(authority, attestor string, curated bool) *MsgCurateAttestor {
+	return &MsgCurateAttestor{
+		Authority: authority,
+		Attestor:  attestor,
+		Curated:   curated,
+	}
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgCurateAttestor) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgCurateAttestor) Type() string {
+	return TypeMsgCurateAttestor
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgCurateAttestor) GetSigners() []sdk.AccAddress {
+	authority, err := sdk.AccAddressFromBech32(msg.Authority)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{authority}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgCurateAttestor) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgCurateAttestor) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Authority); err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid authority address (%s)", err)
+	}
+
+	if _, err := sdk.AccAddressFromBech32(msg.Attestor); err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid attestor address (%s)", err)
+	}
+
+	return nil
+}
+
+// MsgSubmitAttestation defines the message to submit an attestor's signed claim about
+// a capsule. Anyone can relay the attestation, the attestor's signature proves it.
+type MsgSubmitAttestation struct {
+	Submitter string `json:"submitter"`
+	CapsuleID uint64 `json:"capsule_id"`
+	Attestor  string `json:"attestor"`
+	Claim     string `json:"claim"`
+	Signature []byte `json:"signature"` // Attestor's signature of AttestationSignBytes
+}
+
+// NewMsgSubmitAttestation creates a new MsgSubmitAttestation
+func NewMsgSubmitAttestation(submitter string, capsuleID uint64, attestor, claim string, signature []byte) *MsgSubmitAttestation {
+	return &MsgSubmitAttestation{
+		Submitter: submitter,
+		CapsuleID: capsuleID,
+		Attestor:  attestor,
+		Claim:     claim,
+		Signature: signature,
+	}
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgSubmitAttestation) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgSubmitAttestation) Type() string {
+	return TypeMsgSubmitAttestation
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgSubmitAttestation) GetSigners() []sdk.AccAddress {
+	submitter, err := sdk.AccAddressFromBech32(msg.Submitter)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{submitter}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgSubmitAttestation) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgSubmitAttestation) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Submitter); err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid submitter address (%s)", err)
+	}
+
+	if _, err := sdk.AccAddressFromBech32(msg.Attestor); err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid attestor address (%s)", err)
+	}
+
+	if msg.CapsuleID == 0 {
+		return errors.Wrap(ErrInvalidCapsule, "capsule ID cannot be zero")
+	}
+
+	if msg.Claim == "" || len(msg.Claim) > MaxAttestationClaimLength {
+		return errors.Wrapf(ErrInvalidAttestation, "claim must have 1 to %d characters", MaxAttestationClaimLength)
+	}
+
+	if len(msg.Signature) == 0 {
+		return errors.Wrap(ErrInvalidAttestation, "signature cannot be empty")
+	}
+
+	return nil
+}
+
+// MsgRevokeCapsuleAttestor defines the message for a capsule owner to remove an
+// attestor from the attestation condition of a capsule, discarding its attestation
+type MsgRevokeCapsuleAttestor struct {
+	Owner     string `json:"owner"`
+	CapsuleID uint64 `json:"capsule_id"`
+	Attestor  string `json:"attestor"`
+}
+
+// NewMsgRevokeCapsuleAttestor creates a new MsgRevokeCapsuleAttestor
+func NewMsgRevokeCapsuleAttestor(owner string, capsuleID uint64, attestor string) *MsgRevokeCapsuleAttestor {
+	return &MsgRevokeCapsuleAttestor{
+		Owner:     owner,
+		CapsuleID: capsuleID,
+		Attestor:  attestor,
+	}
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgRevokeCapsuleAttestor) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgRevokeCapsuleAttestor) Type() string {
+	return TypeMsgRevokeCapsuleAttestor
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgRevokeCapsuleAttestor) GetSigners() []sdk.AccAddress {
+	owner, err := sdk.AccAddressFromBech32(msg.Owner)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{owner}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgRevokeCapsuleAttestor) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgRevokeCapsuleAttestor) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Owner); err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid owner address (%s)", err)
+	}
+
+	if _, err := sdk.AccAddressFromBech32(msg.Attestor); err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid attestor address (%s)", err)
+	}
+
+	if msg.CapsuleID == 0 {
+		return errors.Wrap(ErrInvalidCapsule, "capsule ID cannot be zero")
+	}
+
+	return nil
+}
+
+// MsgEraseCapsule defines the message for a capsule owner to purge the data of a
+// finished capsule before its retention period ends
+type MsgEraseCapsule struct {
+	Owner     string `json:"owner"`
+	CapsuleID uint64 `json:"capsule_id"`
+}
+
+// NewMsgEraseCapsule creates a new MsgEraseCapsule
+func NewMsgEraseCapsule(owner string, capsuleID uint64) *MsgEraseCapsule {
+	return &MsgEraseCapsule{
+		Owner:     owner,
+		CapsuleID: capsuleID,
+	}
+}
+
+// Route implements the sdk.Msg interface
+func (msg *MsgEraseCapsule) Route() string {
+	return RouterKey
+}
+
+// Type implements the sdk.Msg interface
+func (msg *MsgEraseCapsule) Type() string {
+	return TypeMsgEraseCapsule
+}
+
+// GetSigners implements the sdk.Msg interface
+func (msg *MsgEraseCapsule) GetSigners() []sdk.AccAddress {
+	owner, err := sdk.AccAddressFromBech32(msg.Owner)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{owner}
+}
+
+// GetSignBytes implements the sdk.Msg interface
+func (msg *MsgEraseCapsule) GetSignBytes() []byte {
+	bz := ModuleCdc.MustMarshalJSON(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// ValidateBasic implements the sdk.Msg interface
+func (msg *MsgEraseCapsule) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(msg.Owner); err != nil {
+		return errors.Wrapf(ErrInvalidAddress, "invalid owner address (%s)", err)
+	}
+
+	if msg.CapsuleID == 0 {
+		return errors.Wrap(ErrInvalidCapsule, "capsule ID cannot be zero")
+	}
+
+	return nil
+}
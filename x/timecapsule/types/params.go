@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"strings"
 	"time"
 	"cosmossdk.io/math"
 	
@@ -20,6 +21,22 @@ var (
 	KeyMaxInactivityPeriod  = []byte("MaxInactivityPeriod")
 	KeyAllowedCapsuleTypes  = []byte("AllowedCapsuleTypes")
 	KeyMasterNodeMinStake   = []byte("MasterNodeMinStake")
+	KeyTimelockEpochDuration = []byte("TimelockEpochDuration")
+	KeyEmergencyActionDelay = []byte("EmergencyActionDelay")
+	KeyAccessLogRetention   = []byte("AccessLogRetention")
+	KeyShareRefreshInterval = []byte("ShareRefreshInterval")
+	KeyExpectedBlockTime    = []byte("ExpectedBlockTime")
+	KeyStorageFeePerKB      = []byte("StorageFeePerKB")
+	KeyMaxCreationsPerWindow    = []byte("MaxCreationsPerWindow")
+	KeyCreationQuotaWindow      = []byte("CreationQuotaWindow")
+	KeyTargetCreationsPerBlock  = []byte("TargetCreationsPerBlock")
+	KeyCreationFeeAdjustment    = []byte("CreationFeeAdjustment")
+	KeyMaxCreationFeeMultiplier = []byte("MaxCreationFeeMultiplier")
+	KeyMaxTxPayloadBytes        = []byte("MaxTxPayloadBytes")
+	KeyUnlockedDataRetention    = []byte("UnlockedDataRetention")
+	KeyMaxTags                  = []byte("MaxTags")
+	KeyMaxTagLength             = []byte("MaxTagLength")
+	KeyIndexedMetadataKeys      = []byte("IndexedMetadataKeys")
 )
 
 // Default parameter values
@@ -31,12 +48,35 @@ const (
 	DefaultMinInactivityPeriod = uint64(30 * 24 * 60 * 60) // 30 days in seconds
 	DefaultMaxInactivityPeriod = uint64(365 * 24 * 60 * 60) // 365 days in seconds
 	DefaultMasterNodeMinStake  = "1000000" // 1 million base units
+	DefaultTimelockEpochDuration = 24 * time.Hour // Granularity of timelock unlock times
+	DefaultEmergencyActionDelay  = 72 * time.Hour // Veto period before an emergency action executes
+	DefaultAccessLogRetention    = uint32(100)    // Access attempts kept per capsule
+	DefaultShareRefreshInterval  = 7 * 24 * time.Hour // Period of proactive key share refreshes
+	DefaultExpectedBlockTime     = 6 * time.Second    // Used to estimate the time until height unlocks
+	DefaultMaxCreationsPerWindow = uint32(20)         // Capsules an account can create per quota window
+	DefaultCreationQuotaWindow   = uint64(14400)      // Blocks per quota window, about a day
+	DefaultTargetCreationsPerBlock = uint32(10)       // Module-wide creations per block the creation fee targets
+	DefaultMaxTxPayloadBytes     = uint64(2 * 1024 * 1024) // Capsule payload bytes per transaction
+	DefaultUnlockedDataRetention = 30 * 24 * time.Hour     // Data of unlocked capsules is purged after 30 days
+	DefaultMaxTags               = uint32(16)              // Tags per capsule
+	DefaultMaxTagLength          = uint32(32)              // Bytes per tag, and per value of an indexed metadata key
+)
+
+// DefaultIndexedMetadataKeys are the metadata keys capsules are indexed by. None by
+// default, the title and description are free text.
+var DefaultIndexedMetadataKeys = []string{}
+
+// Default adjustment of the creation fee multiplier
+var (
+	DefaultCreationFeeAdjustment    = math.LegacyNewDecWithPrec(125, 3) // At most 12.5% per block, as in EIP-1559
+	DefaultMaxCreationFeeMultiplier = math.LegacyNewDec(100)
 )
 
 // Default creation and maintenance fees
 var (
 	DefaultCreationFee    = sdk.NewCoins(sdk.NewCoin("stake", math.NewInt(100000)))   // 0.1 stake
 	DefaultMaintenanceFee = sdk.NewCoins(sdk.NewCoin("stake", math.NewInt(10000)))    // 0.01 stake per day
+	DefaultStorageFeePerKB = sdk.NewCoins(sdk.NewCoin("stake", math.NewInt(100)))     // 0.0001 stake per stored KB
 )
 
 // Default allowed capsule types
@@ -46,6 +86,7 @@ var DefaultAllowedCapsuleTypes = []CapsuleType{
 	CapsuleType_CONDITIONAL,
 	CapsuleType_MULTI_SIG,
 	CapsuleType_DEAD_MANS_SWITCH,
+	CapsuleType_PUBLIC_REVEAL,
 }
 
 // Params defines the parameters for the time capsule module
@@ -60,6 +101,28 @@ type Params struct {
 	MaxInactivityPeriod  uint64        `json:"max_inactivity_period"`
 	AllowedCapsuleTypes  []CapsuleType `json:"allowed_capsule_types"`
 	MasterNodeMinStake   math.Int      `json:"master_node_min_stake"`
+	TimelockEpochDuration time.Duration `json:"timelock_epoch_duration"`
+	EmergencyActionDelay time.Duration `json:"emergency_action_delay"`
+	AccessLogRetention   uint32        `json:"access_log_retention"`
+	ShareRefreshInterval time.Duration `json:"share_refresh_interval"`
+	ExpectedBlockTime    time.Duration `json:"expected_block_time"`
+	StorageFeePerKB      sdk.Coins     `json:"storage_fee_per_kb"` // Charged per KB of stored, compressed data
+
+	// Anti-spam limits enforced by the ante decorator
+	MaxCreationsPerWindow    uint32         `json:"max_creations_per_window"`    // Capsules an account can create per quota window
+	CreationQuotaWindow      uint64         `json:"creation_quota_window"`       // Blocks per quota window
+	TargetCreationsPerBlock  uint32         `json:"target_creations_per_block"`  // Module-wide creations per block before the creation fee rises
+	CreationFeeAdjustment    math.LegacyDec `json:"creation_fee_adjustment"`     // Maximum change of the creation fee multiplier per block
+	MaxCreationFeeMultiplier math.LegacyDec `json:"max_creation_fee_multiplier"` // Cap of the creation fee multiplier
+	MaxTxPayloadBytes        uint64         `json:"max_tx_payload_bytes"`        // Capsule payload bytes per transaction
+
+	// Retention of the data of finished capsules
+	UnlockedDataRetention time.Duration `json:"unlocked_data_retention"` // Time the data of an unlocked capsule is kept before it is purged
+
+	// Bounds of the tag and metadata index
+	MaxTags             uint32   `json:"max_tags"`              // Tags per capsule
+	MaxTagLength        uint32   `json:"max_tag_length"`        // Bytes per tag, and per value of an indexed metadata key
+	IndexedMetadataKeys []string `json:"indexed_metadata_keys"` // Metadata keys capsules are indexed by
 }
 
 // NewParams creates a new Params object
@@ -74,6 +137,22 @@ func NewParams(
 	maxInactivityPeriod uint64,
 	allowedCapsuleTypes []CapsuleType,
 	masterNodeMinStake math.Int,
+	timelockEpochDuration time.Duration,
+	emergencyActionDelay time.Duration,
+	accessLogRetention uint32,
+	shareRefreshInterval time.Duration,
+	expectedBlockTime time.Duration,
+	storageFeePerKB sdk.Coins,
+	maxCreationsPerWindow uint32,
+	creationQuotaWindow uint64,
+	targetCreationsPerBlock uint32,
+	creationFeeAdjustment math.LegacyDec,
+	maxCreationFeeMultiplier math.LegacyDec,
+	maxTxPayloadBytes uint64,
+	unlockedDataRetention time.Duration,
+	maxTags uint32,
+	maxTagLength uint32,
+	indexedMetadataKeys []string,
 ) Params {
 	return Params{
 		MaxDataSize:         maxDataSize,
@@ -86,6 +165,22 @@ func NewParams(
 		MaxInactivityPeriod: maxInactivityPeriod,
 		AllowedCapsuleTypes: allowedCapsuleTypes,
 		MasterNodeMinStake:  masterNodeMinStake,
+		TimelockEpochDuration: timelockEpochDuration,
+		EmergencyActionDelay: emergencyActionDelay,
+		AccessLogRetention:   accessLogRetention,
+		ShareRefreshInterval: shareRefreshInterval,
+		ExpectedBlockTime:    expectedBlockTime,
+		StorageFeePerKB:      storageFeePerKB,
+		MaxCreationsPerWindow:    maxCreationsPerWindow,
+		CreationQuotaWindow:      creationQuotaWindow,
+		TargetCreationsPerBlock:  targetCreationsPerBlock,
+		CreationFeeAdjustment:    creationFeeAdjustment,
+		MaxCreationFeeMultiplier: maxCreationFeeMultiplier,
+		MaxTxPayloadBytes:        maxTxPayloadBytes,
+		UnlockedDataRetention:    unlockedDataRetention,
+		MaxTags:                  maxTags,
+		MaxTagLength:             maxTagLength,
+		IndexedMetadataKeys:      indexedMetadataKeys,
 	}
 }
 
@@ -102,6 +197,22 @@ func DefaultParams() Params {
 		DefaultMaxInactivityPeriod,
 		DefaultAllowedCapsuleTypes,
 		math.MustNewIntFromString(DefaultMasterNodeMinStake),
+		DefaultTimelockEpochDuration,
+		DefaultEmergencyActionDelay,
+		DefaultAccessLogRetention,
+		DefaultShareRefreshInterval,
+		DefaultExpectedBlockTime,
+		DefaultStorageFeePerKB,
+		DefaultMaxCreationsPerWindow,
+		DefaultCreationQuotaWindow,
+		DefaultTargetCreationsPerBlock,
+		DefaultCreationFeeAdjustment,
+		DefaultMaxCreationFeeMultiplier,
+		DefaultMaxTxPayloadBytes,
+		DefaultUnlockedDataRetention,
+		DefaultMaxTags,
+		DefaultMaxTagLength,
+		DefaultIndexedMetadataKeys,
 	)
 }
 
@@ -138,6 +249,54 @@ func (p Params) Validate() error {
 	if err := validateMasterNodeMinStake(p.MasterNodeMinStake); err != nil {
 		return err
 	}
+	if err := validateTimelockEpochDuration(p.TimelockEpochDuration); err != nil {
+		return err
+	}
+	if err := validateEmergencyActionDelay(p.EmergencyActionDelay); err != nil {
+		return err
+	}
+	if err := validateAccessLogRetention(p.AccessLogRetention); err != nil {
+		return err
+	}
+	if err := validateShareRefreshInterval(p.ShareRefreshInterval); err != nil {
+		return err
+	}
+	if err := validateExpectedBlockTime(p.ExpectedBlockTime); err != nil {
+		return err
+	}
+	if err := validateStorageFeePerKB(p.StorageFeePerKB); err != nil {
+		return err
+	}
+	if err := validateMaxCreationsPerWindow(p.MaxCreationsPerWindow); err != nil {
+		return err
+	}
+	if err := validateCreationQuotaWindow(p.CreationQuotaWindow); err != nil {
+		return err
+	}
+	if err := validateTargetCreationsPerBlock(p.TargetCreationsPerBlock); err != nil {
+		return err
+	}
+	if err := validateCreationFeeAdjustment(p.CreationFeeAdjustment); err != nil {
+		return err
+	}
+	if err := validateMaxCreationFeeMultiplier(p.MaxCreationFeeMultiplier); err != nil {
+		return err
+	}
+	if err := validateMaxTxPayloadBytes(p.MaxTxPayloadBytes); err != nil {
+		return err
+	}
+	if err := validateUnlockedDataRetention(p.UnlockedDataRetention); err != nil {
+		return err
+	}
+	if err := validateMaxTags(p.MaxTags); err != nil {
+		return err
+	}
+	if err := validateMaxTagLength(p.MaxTagLength); err != nil {
+		return err
+	}
+	if err := validateIndexedMetadataKeys(p.IndexedMetadataKeys); err != nil {
+		return err
+	}
 	
 	// Cross-field validation
 	if p.MinThreshold > p.MaxShares {
@@ -149,6 +308,10 @@ func (p Params) Validate() error {
 			p.MinInactivityPeriod, p.MaxInactivityPeriod)
 	}
 	
+	if p.MaxTxPayloadBytes < p.MaxDataSize {
+		return fmt.Errorf("max tx payload bytes (%d) cannot be less than max data size (%d)", p.MaxTxPayloadBytes, p.MaxDataSize)
+	}
+	
 	return nil
 }
 
@@ -289,7 +452,7 @@ func validateAllowedCapsuleTypes(i interface{}) error {
 		// Validate each type
 		switch capsuleType {
 		case CapsuleType_SAFE, CapsuleType_TIME_LOCK, CapsuleType_CONDITIONAL, 
-		     CapsuleType_MULTI_SIG, CapsuleType_DEAD_MANS_SWITCH:
+		     CapsuleType_MULTI_SIG, CapsuleType_DEAD_MANS_SWITCH, CapsuleType_PUBLIC_REVEAL:
 			// Valid types
 		default:
 			return fmt.Errorf("invalid capsule type: %v", capsuleType)
@@ -310,4 +473,279 @@ func validateMasterNodeMinStake(i interface{}) error {
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
+
+func validateTimelockEpochDuration(i interface{}) error {
+	v, ok := i.(time.Duration)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	if v < time.Minute {
+		return fmt.Errorf("timelock epoch duration must be at least one minute")
+	}
+	
+	if v%time.Second != 0 {
+		return fmt.Errorf("timelock epoch duration must be a whole number of seconds")
+	}
+	
+	return nil
+}
+
+func validateEmergencyActionDelay(i interface{}) error {
+	v, ok := i.(time.Duration)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	// Guardians and recipients need time to react to an emergency action
+	if v < time.Hour {
+		return fmt.Errorf("emergency action delay cannot be less than 1 hour")
+	}
+	
+	if v > 30*24*time.Hour {
+		return fmt.Errorf("emergency action delay cannot exceed 30 days")
+	}
+	
+	return nil
+}
+
+func validateAccessLogRetention(i interface{}) error {
+	v, ok := i.(uint32)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	if v == 0 {
+		return fmt.Errorf("access log retention must be positive")
+	}
+	
+	// Every retained entry is kept in state
+	if v > 10000 {
+		return fmt.Errorf("access log retention cannot exceed 10000 entries")
+	}
+	
+	return nil
+}
+
+func validateShareRefreshInterval(i interface{}) error {
+	v, ok := i.(time.Duration)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	// Every refresh rewrites the shares of all active capsules
+	if v < time.Hour {
+		return fmt.Errorf("share refresh interval cannot be less than 1 hour")
+	}
+	
+	if v > 365*24*time.Hour {
+		return fmt.Errorf("share refresh interval cannot exceed 1 year")
+	}
+	
+	return nil
+}
+
+func validateExpectedBlockTime(i interface{}) error {
+	v, ok := i.(time.Duration)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	if v <= 0 {
+		return fmt.Errorf("expected block time must be positive")
+	}
+	
+	if v > time.Hour {
+		return fmt.Errorf("expected block time cannot exceed 1 hour")
+	}
+	
+	return nil
+}
+
+func validateStorageFeePerKB(i interface{}) error {
+	v, ok := i.(sdk.Coins)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	return v.Validate()
+}
+
+func validateMaxCreationsPerWindow(i interface{}) error {
+	v, ok := i.(uint32)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	if v == 0 {
+		return fmt.Errorf("max creations per window must be positive")
+	}
+	
+	return nil
+}
+
+func validateCreationQuotaWindow(i interface{}) error {
+	v, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	if v == 0 {
+		return fmt.Errorf("creation quota window must be positive")
+	}
+	
+	return nil
+}
+
+func validateTargetCreationsPerBlock(i interface{}) error {
+	v, ok := i.(uint32)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	if v == 0 {
+		return fmt.Errorf("target creations per block must be positive")
+	}
+	
+	return nil
+}
+
+func validateCreationFeeAdjustment(i interface{}) error {
+	v, ok := i.(math.LegacyDec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	if v.IsNil() || v.IsNegative() {
+		return fmt.Errorf("creation fee adjustment cannot be negative")
+	}
+	
+	// A multiplier that can drop to zero in one block could not recover
+	if v.GTE(math.LegacyOneDec()) {
+		return fmt.Errorf("creation fee adjustment must be less than 1")
+	}
+	
+	return nil
+}
+
+func validateMaxCreationFeeMultiplier(i interface{}) error {
+	v, ok := i.(math.LegacyDec)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	if v.IsNil() || v.LT(math.LegacyOneDec()) {
+		return fmt.Errorf("max creation fee multiplier cannot be less than 1")
+	}
+	
+	return nil
+}
+
+func validateMaxTxPayloadBytes(i interface{}) error {
+	v, ok := i.(uint64)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	if v == 0 {
+		return fmt.Errorf("max tx payload bytes must be positive")
+	}
+	
+	return nil
+}
+
+func validateUnlockedDataRetention(i interface{}) error {
+	v, ok := i.(time.Duration)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	// Recipients need time to fetch the data of an opened capsule
+	if v < time.Hour {
+		return fmt.Errorf("unlocked data retention cannot be less than 1 hour")
+	}
+	
+	if v > 10*365*24*time.Hour {
+		return fmt.Errorf("unlocked data retention cannot exceed 10 years")
+	}
+	
+	return nil
+}
+
+func validateMaxTags(i interface{}) error {
+	v, ok := i.(uint32)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	if v == 0 {
+		return fmt.Errorf("max tags must be positive")
+	}
+	
+	// Every tag is an index entry written with the capsule
+	if v > 64 {
+		return fmt.Errorf("max tags cannot exceed 64")
+	}
+	
+	return nil
+}
+
+func validateMaxTagLength(i interface{}) error {
+	v, ok := i.(uint32)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	if v == 0 {
+		return fmt.Errorf("max tag length must be positive")
+	}
+	
+	if v > 256 {
+		return fmt.Errorf("max tag length cannot exceed 256 bytes")
+	}
+	
+	return nil
+}
+
+func validateIndexedMetadataKeys(i interface{}) error {
+	v, ok := i.([]string)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+	
+	if len(v) > 16 {
+		return fmt.Errorf("indexed metadata keys cannot exceed 16")
+	}
+	
+	seen := make(map[string]bool, len(v))
+	for _, key := range v {
+		if err := ValidateTag(key); err != nil {
+			return fmt.Errorf("invalid indexed metadata key: %w", err)
+		}
+		if strings.Contains(key, MetadataIndexSeparator) {
+			return fmt.Errorf("indexed metadata key %q cannot contain %q", key, MetadataIndexSeparator)
+		}
+		if seen[key] {
+			return fmt.Errorf("duplicate indexed metadata key: %s", key)
+		}
+		seen[key] = true
+	}
+	
+	return nil
+}
+
+// StorageFee returns the storage fee of dataSize stored bytes, charged per started KB
+func (p Params) StorageFee(dataSize int64) sdk.Coins {
+	if dataSize <= 0 || p.StorageFeePerKB.IsZero() {
+		return sdk.NewCoins()
+	}
+	
+	kb := math.NewInt((dataSize + 1023) / 1024)
+	fee := sdk.NewCoins()
+	for _, coin := range p.StorageFeePerKB {
+		fee = fee.Add(sdk.NewCoin(coin.Denom, coin.Amount.Mul(kb)))
+	}
+	
+	return fee
+}
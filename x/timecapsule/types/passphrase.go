@@ -0,0 +1,140 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+)
+
+// PassphraseFactor protects a capsule delivered to a recipient key with a second
+// factor: a passphrase the owner shares out-of-band, e.g. sealed in a will. The
+// passphrase is stretched with the KDF into a key pair whose public key is stored, and
+// the wrapped data key is wrapped again under a secret encrypted to it. Opening the
+// delivery then needs both the recipient key and the passphrase.
+type PassphraseFactor struct {
+	KDF            string `json:"kdf"`                       // Key derivation function, "scrypt"
+	Salt           []byte `json:"salt"`                      // Salt the passphrase is stretched with
+	N              uint32 `json:"n"`                         // scrypt CPU and memory cost
+	R              uint32 `json:"r"`                         // scrypt block size
+	P              uint32 `json:"p"`                         // scrypt parallelization
+	PublicKey      []byte `json:"public_key"`                // Public key derived from the passphrase
+	Ciphertext     []byte `json:"ciphertext,omitempty"`      // Passphrase secret encrypted to the public key
+	Nonce          []byte `json:"nonce,omitempty"`           // AES-GCM nonce of the passphrase layer
+	EncryptionAlgo string `json:"encryption_algo,omitempty"` // Algorithm of the passphrase layer
+}
+
+// NewPassphraseFactor derives the passphrase factor of a passphrase with a fresh salt
+// and the default KDF parameters
+func NewPassphraseFactor(passphrase string) (*PassphraseFactor, error) {
+	salt, err := crypto.SecureRandom(crypto.PassphraseSaltSize)
+	if err != nil {
+		return nil, err
+	}
+
+	params := crypto.DefaultScryptParams
+	privKey, pubKey, err := crypto.DerivePassphraseKey(passphrase, salt, params)
+	if err != nil {
+		return nil, err
+	}
+	crypto.WipeKey(privKey)
+
+	return &PassphraseFactor{
+		KDF:       crypto.KDFScrypt,
+		Salt:      salt,
+		N:         params.N,
+		R:         params.R,
+		P:         params.P,
+		PublicKey: pubKey,
+	}, nil
+}
+
+// ScryptParams returns the KDF parameters of the factor
+func (pf *PassphraseFactor) ScryptParams() crypto.ScryptParams {
+	return crypto.ScryptParams{N: pf.N, R: pf.R, P: pf.P}
+}
+
+// Validate performs stateless checks of a passphrase factor
+func (pf *PassphraseFactor) Validate() error {
+	if pf.KDF != crypto.KDFScrypt {
+		return fmt.Errorf("unsupported passphrase KDF %q", pf.KDF)
+	}
+	if len(pf.Salt) != crypto.PassphraseSaltSize {
+		return fmt.Errorf("passphrase salt must be %d bytes, got %d", crypto.PassphraseSaltSize, len(pf.Salt))
+	}
+	if err := pf.ScryptParams().Validate(); err != nil {
+		return err
+	}
+	if err := crypto.ValidateRecipientKey(pf.PublicKey); err != nil {
+		return fmt.Errorf("invalid passphrase key: %w", err)
+	}
+	return nil
+}
+
+// DeriveKey derives the private key of the factor from the passphrase, and checks it
+// against the factor's public key
+func (pf *PassphraseFactor) DeriveKey(passphrase string) ([]byte, error) {
+	privKey, pubKey, err := crypto.DerivePassphraseKey(passphrase, pf.Salt, pf.ScryptParams())
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(pubKey, pf.PublicKey) {
+		crypto.WipeKey(privKey)
+		return nil, fmt.Errorf("wrong passphrase")
+	}
+	return privKey, nil
+}
+
+// OpenDeliveredKey recovers the data key of a capsule delivered to a recipient key.
+// Capsules protected by a passphrase factor also need the passphrase.
+func (tc *TimeCapsule) OpenDeliveredKey(recipientPriv []byte, passphrase string) ([]byte, error) {
+	if tc.Delivery == nil {
+		return nil, fmt.Errorf("capsule %d has not been delivered", tc.ID)
+	}
+
+	secret, err := crypto.OpenDelivery(recipientPriv, tc.Delivery.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open delivery: %w", err)
+	}
+	defer crypto.WipeKey(secret)
+
+	em := crypto.NewEncryptionManager()
+	wrappedKey := tc.Delivery.WrappedKey
+
+	if pf := tc.PassphraseFactor; pf != nil {
+		if passphrase == "" {
+			return nil, fmt.Errorf("capsule %d is protected by a passphrase", tc.ID)
+		}
+		passphraseKey, err := pf.DeriveKey(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		defer crypto.WipeKey(passphraseKey)
+
+		passphraseSecret, err := crypto.OpenDelivery(passphraseKey, pf.Ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open passphrase layer: %w", err)
+		}
+		defer crypto.WipeKey(passphraseSecret)
+
+		wrappedKey, err = em.Decrypt(&crypto.EncryptedData{
+			Data:      wrappedKey,
+			Nonce:     pf.Nonce,
+			Algorithm: pf.EncryptionAlgo,
+		}, passphraseSecret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open passphrase layer: %w", err)
+		}
+	}
+
+	encryptionKey, err := em.Decrypt(&crypto.EncryptedData{
+		Data:      wrappedKey,
+		Nonce:     tc.Delivery.WrappedKeyNonce,
+		Algorithm: tc.Delivery.EncryptionAlgo,
+	}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("delivery is not for this recipient key: %w", err)
+	}
+
+	return encryptionKey, nil
+}
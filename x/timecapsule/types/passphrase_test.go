@@ -0,0 +1,106 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+const testPassphrase = "correct horse battery staple"
+
+// deliveredCapsule returns a capsule whose data key is delivered to a recipient key and
+// wrapped again under a passphrase factor, the way the keeper seals it
+func deliveredCapsule(t *testing.T, dataKey []byte) (*types.TimeCapsule, []byte) {
+	t.Helper()
+
+	recipientPriv, recipientPub, err := crypto.GenerateTimelockKey()
+	require.NoError(t, err)
+
+	em := crypto.NewEncryptionManager()
+	secret, ciphertext, err := crypto.NewDeliverySecret(recipientPub)
+	require.NoError(t, err)
+	wrapped, err := em.Encrypt(dataKey, secret)
+	require.NoError(t, err)
+
+	factor, err := types.NewPassphraseFactor(testPassphrase)
+	require.NoError(t, err)
+	require.NoError(t, factor.Validate())
+
+	passphraseSecret, passphraseCiphertext, err := crypto.NewDeliverySecret(factor.PublicKey)
+	require.NoError(t, err)
+	rewrapped, err := em.Encrypt(wrapped.Data, passphraseSecret)
+	require.NoError(t, err)
+	factor.Ciphertext = passphraseCiphertext
+	factor.Nonce = rewrapped.Nonce
+	factor.EncryptionAlgo = rewrapped.Algorithm
+
+	capsule := &types.TimeCapsule{
+		ID:               1,
+		RecipientKey:     recipientPub,
+		PassphraseFactor: factor,
+		Delivery: &types.RecipientDelivery{
+			RecipientKey:    recipientPub,
+			Ciphertext:      ciphertext,
+			WrappedKey:      rewrapped.Data,
+			WrappedKeyNonce: wrapped.Nonce,
+			EncryptionAlgo:  wrapped.Algorithm,
+		},
+	}
+	return capsule, recipientPriv
+}
+
+func TestOpenDeliveredKeyWithPassphrase(t *testing.T) {
+	dataKey, err := crypto.SecureRandom(32)
+	require.NoError(t, err)
+	capsule, recipientPriv := deliveredCapsule(t, dataKey)
+
+	otherPriv, _, err := crypto.GenerateTimelockKey()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		priv       []byte
+		passphrase string
+		expErr     string
+	}{
+		{"recipient key and passphrase", recipientPriv, testPassphrase, ""},
+		{"wrong passphrase", recipientPriv, "correct horse battery stapler", "wrong passphrase"},
+		{"missing passphrase", recipientPriv, "", "protected by a passphrase"},
+		{"passphrase without the recipient key", otherPriv, testPassphrase, "not for this recipient key"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := capsule.OpenDeliveredKey(tc.priv, tc.passphrase)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+				require.Equal(t, dataKey, key)
+				return
+			}
+			require.ErrorContains(t, err, tc.expErr)
+			require.Nil(t, key)
+		})
+	}
+}
+
+func TestPurgeClearsPassphraseLayer(t *testing.T) {
+	dataKey, err := crypto.SecureRandom(32)
+	require.NoError(t, err)
+	capsule, recipientPriv := deliveredCapsule(t, dataKey)
+
+	capsule.Purge(types.PurgeReasonErasure, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 10)
+
+	require.True(t, capsule.IsPurged())
+	require.Nil(t, capsule.PassphraseFactor.Ciphertext)
+	require.Nil(t, capsule.PassphraseFactor.Nonce)
+	require.Nil(t, capsule.Delivery.Ciphertext)
+	require.Nil(t, capsule.Delivery.WrappedKey)
+
+	// Neither factor opens the purged delivery any more
+	_, err = capsule.OpenDeliveredKey(recipientPriv, testPassphrase)
+	require.Error(t, err)
+}
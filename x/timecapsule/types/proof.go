@@ -0,0 +1,74 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+
+	"cosmossdk.io/collections"
+	"cosmossdk.io/store/rootmulti"
+	"github.com/cometbft/cometbft/crypto/merkle"
+	cmtcrypto "github.com/cometbft/cometbft/proto/tendermint/crypto"
+)
+
+// CapsuleProofQueryPath is the ABCI query path that returns the stored value of a
+// module store key together with its Merkle proof
+const CapsuleProofQueryPath = "/store/" + StoreKey + "/key"
+
+// CapsuleProof is the Merkle proof of a capsule's stored value at a height. The proof
+// is made against the app hash of the state at Height, which is committed in the
+// header of block Height+1.
+type CapsuleProof struct {
+	CapsuleID uint64              `json:"capsule_id"`
+	Height    int64               `json:"height"`
+	Key       []byte              `json:"key"`
+	Value     []byte              `json:"value,omitempty"` // Empty when the proof shows the capsule did not exist
+	ProofOps  *cmtcrypto.ProofOps `json:"proof_ops"`
+}
+
+// CapsuleStoreKey returns the key a capsule is stored under in the module store
+func CapsuleStoreKey(capsuleID uint64) ([]byte, error) {
+	return collections.EncodeKeyWithPrefix(CapsuleKeyPrefix, collections.Uint64Key, capsuleID)
+}
+
+// Exists returns whether the proof shows the capsule existed, rather than its absence
+func (p CapsuleProof) Exists() bool {
+	return len(p.Value) > 0
+}
+
+// Verify checks the proof against a trusted app hash, taken from the header of block
+// Height+1. It proves the stored value of the capsule, or that it did not exist.
+func (p CapsuleProof) Verify(appHash []byte) error {
+	if len(appHash) == 0 {
+		return fmt.Errorf("trusted app hash cannot be empty")
+	}
+	if p.ProofOps == nil || len(p.ProofOps.Ops) == 0 {
+		return fmt.Errorf("capsule %d has no proof", p.CapsuleID)
+	}
+
+	key, err := CapsuleStoreKey(p.CapsuleID)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(key, p.Key) {
+		return fmt.Errorf("proof key %X is not the key of capsule %d", p.Key, p.CapsuleID)
+	}
+
+	keyPath := merkle.KeyPath{}.
+		AppendKey([]byte(StoreKey), merkle.KeyEncodingURL).
+		AppendKey(key, merkle.KeyEncodingURL).
+		String()
+
+	prt := rootmulti.DefaultProofRuntime()
+	if !p.Exists() {
+		if err := prt.VerifyAbsence(p.ProofOps, appHash, keyPath); err != nil {
+			return fmt.Errorf("invalid absence proof of capsule %d: %w", p.CapsuleID, err)
+		}
+		return nil
+	}
+
+	if err := prt.VerifyValue(p.ProofOps, appHash, keyPath, p.Value); err != nil {
+		return fmt.Errorf("invalid proof of capsule %d: %w", p.CapsuleID, err)
+	}
+
+	return nil
+}
@@ -1,6 +1,12 @@
 package types
 
-import "context"
+import (
+	"context"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/query"
+)
 
 // Query request and response types
 
@@ -67,6 +73,26 @@ type QueryCapsulesByStatusResponse struct {
 	Status   CapsuleStatus `json:"status"`
 }
 
+// QueryCapsulesByTagRequest is the request type for the Query/CapsulesByTag RPC method.
+// The owner's capsules are looked up by tag, or by an indexed metadata entry, and
+// filtered by status (UNKNOWN matches any) and an unlock time range [after, before).
+type QueryCapsulesByTagRequest struct {
+	Owner         string             `json:"owner"`
+	Tag           string             `json:"tag,omitempty"`
+	MetadataKey   string             `json:"metadata_key,omitempty"`
+	MetadataValue string             `json:"metadata_value,omitempty"`
+	Status        CapsuleStatus      `json:"status,omitempty"`
+	UnlockAfter   *time.Time         `json:"unlock_after,omitempty"`
+	UnlockBefore  *time.Time         `json:"unlock_before,omitempty"`
+	Pagination    *query.PageRequest `json:"pagination,omitempty"`
+}
+
+// QueryCapsulesByTagResponse is the response type for the Query/CapsulesByTag RPC method
+type QueryCapsulesByTagResponse struct {
+	Capsules   []TimeCapsule       `json:"capsules"`
+	Pagination *query.PageResponse `json:"pagination,omitempty"`
+}
+
 // QueryStatsRequest is the request type for the Query/Stats RPC method
 type QueryStatsRequest struct{}
 
@@ -115,6 +141,138 @@ type QueryConditionContractsResponse struct {
 	Contracts []ConditionContract `json:"contracts"`
 }
 
+// QueryTimelockEpochRequest is the request type for the Query/TimelockEpoch RPC method
+type QueryTimelockEpochRequest struct {
+	Epoch uint64 `json:"epoch"`
+}
+
+// QueryTimelockEpochResponse is the response type for the Query/TimelockEpoch RPC method
+type QueryTimelockEpochResponse struct {
+	Epoch *TimelockEpoch `json:"epoch"`
+}
+
+// QueryEmergencyActionRequest is the request type for the Query/EmergencyAction RPC method
+type QueryEmergencyActionRequest struct {
+	ActionId string `json:"action_id"`
+}
+
+// QueryEmergencyActionResponse is the response type for the Query/EmergencyAction RPC method
+type QueryEmergencyActionResponse struct {
+	Action *EmergencyAction `json:"action"`
+}
+
+// QueryCapsuleEmergencyActionsRequest is the request type for the Query/CapsuleEmergencyActions RPC method
+type QueryCapsuleEmergencyActionsRequest struct {
+	CapsuleId uint64 `json:"capsule_id"`
+}
+
+// QueryCapsuleEmergencyActionsResponse is the response type for the Query/CapsuleEmergencyActions RPC method
+type QueryCapsuleEmergencyActionsResponse struct {
+	Actions []EmergencyAction `json:"actions"`
+}
+
+// QueryAccessLogRequest is the request type for the Query/AccessLog RPC method
+type QueryAccessLogRequest struct {
+	CapsuleId uint64 `json:"capsule_id"`
+}
+
+// QueryAccessLogResponse is the response type for the Query/AccessLog RPC method
+type QueryAccessLogResponse struct {
+	Accesses []CapsuleAccess `json:"accesses"`
+}
+
+// QueryCapsuleVersionsRequest is the request type for the Query/CapsuleVersions RPC method
+type QueryCapsuleVersionsRequest struct {
+	CapsuleId uint64 `json:"capsule_id"`
+}
+
+// QueryCapsuleVersionsResponse is the response type for the Query/CapsuleVersions RPC method
+type QueryCapsuleVersionsResponse struct {
+	CurrentVersion uint32           `json:"current_version"`
+	DataHash       string           `json:"data_hash"` // Data hash of the current version
+	Versions       []CapsuleVersion `json:"versions"`  // Superseded versions, oldest first
+}
+
+// QueryShareHealthRequest is the request type for the Query/ShareHealth RPC method
+type QueryShareHealthRequest struct {
+	CapsuleId uint64 `json:"capsule_id,omitempty"` // All active capsules when zero
+}
+
+// QueryShareHealthResponse is the response type for the Query/ShareHealth RPC method
+type QueryShareHealthResponse struct {
+	Capsules []CapsuleShareHealth `json:"capsules"`
+}
+
+// QueryCapsuleAllowanceRequest is the request type for the Query/CapsuleAllowance RPC method
+type QueryCapsuleAllowanceRequest struct {
+	Sponsor string `json:"sponsor"`
+	Grantee string `json:"grantee"`
+}
+
+// QueryCapsuleAllowanceResponse is the response type for the Query/CapsuleAllowance RPC method
+type QueryCapsuleAllowanceResponse struct {
+	Allowance *CapsuleAllowance `json:"allowance"`
+}
+
+// QueryCapsuleAllowancesRequest is the request type for the Query/CapsuleAllowances RPC method
+type QueryCapsuleAllowancesRequest struct {
+	Sponsor string `json:"sponsor"`
+}
+
+// QueryCapsuleAllowancesResponse is the response type for the Query/CapsuleAllowances RPC method
+type QueryCapsuleAllowancesResponse struct {
+	Allowances []CapsuleAllowance `json:"allowances"`
+}
+
+// QueryAttestorRequest is the request type for the Query/Attestor RPC method
+type QueryAttestorRequest struct {
+	Address string `json:"address"`
+}
+
+// QueryAttestorResponse is the response type for the Query/Attestor RPC method
+type QueryAttestorResponse struct {
+	Attestor *Attestor `json:"attestor"`
+}
+
+// QueryAttestorsRequest is the request type for the Query/Attestors RPC method
+type QueryAttestorsRequest struct {
+	CuratedOnly bool `json:"curated_only,omitempty"`
+}
+
+// QueryAttestorsResponse is the response type for the Query/Attestors RPC method
+type QueryAttestorsResponse struct {
+	Attestors []Attestor `json:"attestors"`
+}
+
+// QueryCapsuleAttestationsRequest is the request type for the Query/CapsuleAttestations RPC method
+type QueryCapsuleAttestationsRequest struct {
+	CapsuleId uint64 `json:"capsule_id"`
+}
+
+// QueryCapsuleAttestationsResponse is the response type for the Query/CapsuleAttestations RPC method
+type QueryCapsuleAttestationsResponse struct {
+	Condition    *AttestationCondition `json:"condition"`
+	Attestations []Attestation         `json:"attestations"`
+}
+
+// QueryRevealRequest is the request type for the Query/Reveal RPC method
+type QueryRevealRequest struct {
+	CapsuleId uint64 `json:"capsule_id"`
+}
+
+// QueryRevealResponse is the response type for the Query/Reveal RPC method. The
+// commitment block proves the content was fixed before it was revealed.
+type QueryRevealResponse struct {
+	CapsuleId       uint64        `json:"capsule_id"`
+	Commitment      string        `json:"commitment"`
+	CommitHeight    int64         `json:"commit_height"`
+	CommitBlockHash []byte        `json:"commit_block_hash,omitempty"`
+	CommittedAt     time.Time     `json:"committed_at"`
+	UnlockTime      *time.Time    `json:"unlock_time,omitempty"`
+	Revealed        bool          `json:"revealed"`
+	Reveal          *PublicReveal `json:"reveal,omitempty"` // Revealed content, once revealed
+}
+
 // Message response types
 
 // MsgCreateCapsuleResponse is the response type for MsgCreateCapsule
@@ -124,14 +282,19 @@ type MsgCreateCapsuleResponse struct {
 
 // MsgOpenCapsuleResponse is the response type for MsgOpenCapsule  
 type MsgOpenCapsuleResponse struct {
-	Data []byte `json:"data"`
+	Data             []byte             `json:"data"`
+	ReleasedEscrow   sdk.Coins          `json:"released_escrow,omitempty"`
+	PreviousVersions []VersionContent   `json:"previous_versions,omitempty"` // Superseded versions, under the "all" version policy
+	Delivery         *RecipientDelivery `json:"delivery,omitempty"`          // Data key delivered to the recipient key, instead of data
 }
 
 // MsgUpdateActivityResponse is the response type for MsgUpdateActivity
 type MsgUpdateActivityResponse struct{}
 
 // MsgCancelCapsuleResponse is the response type for MsgCancelCapsule
-type MsgCancelCapsuleResponse struct{}
+type MsgCancelCapsuleResponse struct {
+	RefundedEscrow sdk.Coins `json:"refunded_escrow,omitempty"`
+}
 
 // MsgTransferCapsuleResponse is the response type for MsgTransferCapsule
 type MsgTransferCapsuleResponse struct{}
@@ -153,12 +316,68 @@ type MsgApproveTransferResponse struct {
 	Approved bool `json:"approved"`
 }
 
-// MsgEmergencyDeleteContractResponse is the response type for MsgEmergencyDeleteContract
-type MsgEmergencyDeleteContractResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+// MsgRegisterTimelockKeyResponse is the response type for MsgRegisterTimelockKey
+type MsgRegisterTimelockKeyResponse struct{}
+
+// MsgAmendCapsuleResponse is the response type for MsgAmendCapsule
+type MsgAmendCapsuleResponse struct {
+	Version uint32 `json:"version"`
+}
+
+// MsgRotateRecipientResponse is the response type for MsgRotateRecipient
+type MsgRotateRecipientResponse struct{}
+
+// MsgRevealCapsuleResponse is the response type for MsgRevealCapsule
+type MsgRevealCapsuleResponse struct {
+	CommitHeight int64 `json:"commit_height"`
 }
 
+// MsgGrantCapsuleAllowanceResponse is the response type for MsgGrantCapsuleAllowance
+type MsgGrantCapsuleAllowanceResponse struct{}
+
+// MsgRevokeCapsuleAllowanceResponse is the response type for MsgRevokeCapsuleAllowance
+type MsgRevokeCapsuleAllowanceResponse struct{}
+
+// MsgRegisterAttestorResponse is the response type for MsgRegisterAttestor
+type MsgRegisterAttestorResponse struct{}
+
+// MsgCurateAttestorResponse is the response type for MsgCurateAttestor
+type MsgCurateAttestorResponse struct{}
+
+// MsgSubmitAttestationResponse is the response type for MsgSubmitAttestation
+type MsgSubmitAttestationResponse struct {
+	Attestations uint32 `json:"attestations"` // Valid attestations of the capsule so far
+	Attested     bool   `json:"attested"`     // Whether the capsule's attestation threshold is reached
+}
+
+// MsgRevokeCapsuleAttestorResponse is the response type for MsgRevokeCapsuleAttestor
+type MsgRevokeCapsuleAttestorResponse struct{}
+
+// MsgEraseCapsuleResponse is the response type for MsgEraseCapsule
+type MsgEraseCapsuleResponse struct{}
+
+// MsgProposeEmergencyActionResponse is the response type for MsgProposeEmergencyAction
+type MsgProposeEmergencyActionResponse struct {
+	ActionId string `json:"action_id"`
+}
+
+// MsgApproveEmergencyActionResponse is the response type for MsgApproveEmergencyAction
+type MsgApproveEmergencyActionResponse struct {
+	Approvals    uint32     `json:"approvals"`
+	ExecuteAfter *time.Time `json:"execute_after,omitempty"` // Set once the guardian threshold is reached
+}
+
+// MsgVetoEmergencyActionResponse is the response type for MsgVetoEmergencyAction
+type MsgVetoEmergencyActionResponse struct{}
+
+// MsgExecuteEmergencyActionResponse is the response type for MsgExecuteEmergencyAction
+type MsgExecuteEmergencyActionResponse struct {
+	ReversibleUntil *time.Time `json:"reversible_until,omitempty"`
+}
+
+// MsgReverseEmergencyActionResponse is the response type for MsgReverseEmergencyAction
+type MsgReverseEmergencyActionResponse struct{}
+
 // Interface definitions for gRPC services
 
 // QueryServer defines the gRPC querier service
@@ -180,6 +399,9 @@ type QueryServer interface {
 	
 	// CapsulesByStatus returns capsules filtered by status
 	CapsulesByStatus(context.Context, *QueryCapsulesByStatusRequest) (*QueryCapsulesByStatusResponse, error)
+
+	// CapsulesByTag returns an owner's capsules by tag or indexed metadata entry
+	CapsulesByTag(context.Context, *QueryCapsulesByTagRequest) (*QueryCapsulesByTagResponse, error)
 	
 	// Stats returns module statistics
 	Stats(context.Context, *QueryStatsRequest) (*QueryStatsResponse, error)
@@ -192,6 +414,42 @@ type QueryServer interface {
 	
 	// ConditionContracts returns all condition contracts
 	ConditionContracts(context.Context, *QueryConditionContractsRequest) (*QueryConditionContractsResponse, error)
+	
+	// TimelockEpoch returns a timelock epoch key, including its secret once released
+	TimelockEpoch(context.Context, *QueryTimelockEpochRequest) (*QueryTimelockEpochResponse, error)
+	
+	// EmergencyAction returns an emergency action
+	EmergencyAction(context.Context, *QueryEmergencyActionRequest) (*QueryEmergencyActionResponse, error)
+	
+	// CapsuleEmergencyActions returns all emergency actions proposed for a capsule
+	CapsuleEmergencyActions(context.Context, *QueryCapsuleEmergencyActionsRequest) (*QueryCapsuleEmergencyActionsResponse, error)
+
+	// AccessLog returns the retained open attempts on a capsule
+	AccessLog(context.Context, *QueryAccessLogRequest) (*QueryAccessLogResponse, error)
+
+	// CapsuleVersions returns the version history of a capsule
+	CapsuleVersions(context.Context, *QueryCapsuleVersionsRequest) (*QueryCapsuleVersionsResponse, error)
+
+	// ShareHealth returns the live key share margin of capsules
+	ShareHealth(context.Context, *QueryShareHealthRequest) (*QueryShareHealthResponse, error)
+
+	// Reveal returns the revealed content of a public reveal capsule with its commitment block
+	Reveal(context.Context, *QueryRevealRequest) (*QueryRevealResponse, error)
+
+	// CapsuleAllowance returns the capsule allowance a sponsor granted a grantee
+	CapsuleAllowance(context.Context, *QueryCapsuleAllowanceRequest) (*QueryCapsuleAllowanceResponse, error)
+
+	// CapsuleAllowances returns all capsule allowances granted by a sponsor
+	CapsuleAllowances(context.Context, *QueryCapsuleAllowancesRequest) (*QueryCapsuleAllowancesResponse, error)
+
+	// Attestor returns a registered attestor
+	Attestor(context.Context, *QueryAttestorRequest) (*QueryAttestorResponse, error)
+
+	// Attestors returns the registered attestors, or only the curated ones
+	Attestors(context.Context, *QueryAttestorsRequest) (*QueryAttestorsResponse, error)
+
+	// CapsuleAttestations returns the attestation condition of a capsule with the attestations submitted for it
+	CapsuleAttestations(context.Context, *QueryCapsuleAttestationsRequest) (*QueryCapsuleAttestationsResponse, error)
 }
 
 // MsgServer defines the gRPC message service
@@ -210,4 +468,52 @@ type MsgServer interface {
 	
 	// TransferCapsule transfers capsule ownership
 	TransferCapsule(context.Context, *MsgTransferCapsule) (*MsgTransferCapsuleResponse, error)
+	
+	// RegisterTimelockKey registers a validator's timelock key-generation key
+	RegisterTimelockKey(context.Context, *MsgRegisterTimelockKey) (*MsgRegisterTimelockKeyResponse, error)
+	
+	// ProposeEmergencyAction proposes a guardian emergency action
+	ProposeEmergencyAction(context.Context, *MsgProposeEmergencyAction) (*MsgProposeEmergencyActionResponse, error)
+	
+	// ApproveEmergencyAction adds a guardian approval to an emergency action
+	ApproveEmergencyAction(context.Context, *MsgApproveEmergencyAction) (*MsgApproveEmergencyActionResponse, error)
+	
+	// VetoEmergencyAction rejects a pending emergency action
+	VetoEmergencyAction(context.Context, *MsgVetoEmergencyAction) (*MsgVetoEmergencyActionResponse, error)
+	
+	// ExecuteEmergencyAction executes an approved emergency action after its delay
+	ExecuteEmergencyAction(context.Context, *MsgExecuteEmergencyAction) (*MsgExecuteEmergencyActionResponse, error)
+	
+	// ReverseEmergencyAction undoes an executed emergency action
+	ReverseEmergencyAction(context.Context, *MsgReverseEmergencyAction) (*MsgReverseEmergencyActionResponse, error)
+	
+	// AmendCapsule replaces the content of a capsule with a new version
+	AmendCapsule(context.Context, *MsgAmendCapsule) (*MsgAmendCapsuleResponse, error)
+	
+	// RotateRecipient re-targets a capsule to a new recipient
+	RotateRecipient(context.Context, *MsgRotateRecipient) (*MsgRotateRecipientResponse, error)
+	
+	// RevealCapsule publishes the content of a public reveal capsule
+	RevealCapsule(context.Context, *MsgRevealCapsule) (*MsgRevealCapsuleResponse, error)
+	
+	// GrantCapsuleAllowance lets a sponsor pay the capsule fees of a grantee
+	GrantCapsuleAllowance(context.Context, *MsgGrantCapsuleAllowance) (*MsgGrantCapsuleAllowanceResponse, error)
+	
+	// RevokeCapsuleAllowance revokes the capsule allowance of a grantee
+	RevokeCapsuleAllowance(context.Context, *MsgRevokeCapsuleAllowance) (*MsgRevokeCapsuleAllowanceResponse, error)
+	
+	// RegisterAttestor registers an attestor or updates its key and description
+	RegisterAttestor(context.Context, *MsgRegisterAttestor) (*MsgRegisterAttestorResponse, error)
+	
+	// CurateAttestor sets whether governance curates an attestor
+	CurateAttestor(context.Context, *MsgCurateAttestor) (*MsgCurateAttestorResponse, error)
+	
+	// SubmitAttestation submits an attestor's signed claim about a capsule
+	SubmitAttestation(context.Context, *MsgSubmitAttestation) (*MsgSubmitAttestationResponse, error)
+	
+	// RevokeCapsuleAttestor removes an attestor from the attestation condition of a capsule
+	RevokeCapsuleAttestor(context.Context, *MsgRevokeCapsuleAttestor) (*MsgRevokeCapsuleAttestorResponse, error)
+	
+	// EraseCapsule purges the data of a finished capsule at its owner's request
+	EraseCapsule(context.Context, *MsgEraseCapsule) (*MsgEraseCapsuleResponse, error)
 }
\ No newline at end of file
@@ -0,0 +1,103 @@
+package types
+
+import (
+	"fmt"
+
+	"cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// CreationQuota counts the capsules an account created in its current quota window
+type CreationQuota struct {
+	WindowStart int64  `json:"window_start"` // Height the current window started at
+	Count       uint32 `json:"count"`        // Capsules created in the current window
+}
+
+// AccountCreationQuota is the creation quota of an account, as exported in genesis
+type AccountCreationQuota struct {
+	Creator string        `json:"creator"`
+	Quota   CreationQuota `json:"quota"`
+}
+
+// CreationVolume tracks module-wide capsule creations, which drive the creation fee
+type CreationVolume struct {
+	Multiplier     math.LegacyDec `json:"multiplier"`      // Applied to the base creation fee, at least 1
+	BlockCreations uint32         `json:"block_creations"` // Capsules created in the current block
+}
+
+// NewCreationVolume returns the creation volume of a chain without recent creations
+func NewCreationVolume() CreationVolume {
+	return CreationVolume{Multiplier: math.LegacyOneDec()}
+}
+
+// Validate performs basic validation of an account creation quota
+func (q AccountCreationQuota) Validate() error {
+	if _, err := sdk.AccAddressFromBech32(q.Creator); err != nil {
+		return fmt.Errorf("invalid creator address: %w", err)
+	}
+	if q.Quota.WindowStart < 0 {
+		return fmt.Errorf("window start %d cannot be negative", q.Quota.WindowStart)
+	}
+	return nil
+}
+
+// Validate performs basic validation of the creation volume
+func (v CreationVolume) Validate() error {
+	if v.Multiplier.IsNil() || v.Multiplier.LT(math.LegacyOneDec()) {
+		return fmt.Errorf("creation fee multiplier must be at least 1, got %s", v.Multiplier)
+	}
+	return nil
+}
+
+// Consume records count creations at height, starting a new window when the current one
+// ended. It fails when the account would exceed its quota.
+func (q CreationQuota) Consume(height int64, count uint32, params Params) (CreationQuota, error) {
+	if q.Count == 0 || uint64(height-q.WindowStart) >= params.CreationQuotaWindow {
+		q = CreationQuota{WindowStart: height}
+	}
+
+	if uint64(q.Count)+uint64(count) > uint64(params.MaxCreationsPerWindow) {
+		return q, fmt.Errorf("%d capsules created since height %d, quota is %d per %d blocks",
+			q.Count, q.WindowStart, params.MaxCreationsPerWindow, params.CreationQuotaWindow)
+	}
+
+	q.Count += count
+	return q, nil
+}
+
+// NextMultiplier returns the creation fee multiplier of the next block. Like the EIP-1559
+// base fee it rises when the block had more creations than the target and falls when it
+// had fewer, by at most CreationFeeAdjustment, within [1, MaxCreationFeeMultiplier].
+func (v CreationVolume) NextMultiplier(params Params) math.LegacyDec {
+	multiplier := v.Multiplier
+	if multiplier.IsNil() {
+		multiplier = math.LegacyOneDec()
+	}
+
+	target := math.LegacyNewDec(int64(params.TargetCreationsPerBlock))
+	delta := math.LegacyNewDec(int64(v.BlockCreations)).Sub(target).Quo(target)
+	if delta.GT(math.LegacyOneDec()) {
+		delta = math.LegacyOneDec()
+	}
+
+	next := multiplier.Add(multiplier.Mul(delta).Mul(params.CreationFeeAdjustment))
+	if next.LT(math.LegacyOneDec()) {
+		next = math.LegacyOneDec()
+	}
+	if next.GT(params.MaxCreationFeeMultiplier) {
+		next = params.MaxCreationFeeMultiplier
+	}
+
+	return next
+}
+
+// CreationFeeAt returns the creation fee under a fee multiplier
+func (p Params) CreationFeeAt(multiplier math.LegacyDec) sdk.Coins {
+	fee := sdk.NewCoins()
+	for _, coin := range p.CreationFee {
+		amount := multiplier.MulInt(coin.Amount).Ceil().TruncateInt()
+		fee = fee.Add(sdk.NewCoin(coin.Denom, amount))
+	}
+	return fee
+}
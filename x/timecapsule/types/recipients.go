@@ -0,0 +1,49 @@
+package types
+
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+)
+
+// DefaultRecipientKeyFile is the location of a recipient's delivery key relative to the client home
+const DefaultRecipientKeyFile = "config/recipient_key.json"
+
+// RecipientDelivery is a capsule's data key delivered to its recipient key. The
+// ciphertext only opens with the recipient's private key, which recovers the secret
+// the data key is wrapped under.
+type RecipientDelivery struct {
+	RecipientKey    []byte    `json:"recipient_key"`
+	Ciphertext      []byte    `json:"ciphertext"`        // Delivery secret re-encrypted to the recipient key
+	WrappedKey      []byte    `json:"wrapped_key"`       // Data key encrypted under the delivery secret
+	WrappedKeyNonce []byte    `json:"wrapped_key_nonce"` // AES-GCM nonce of the wrapped data key
+	EncryptionAlgo  string    `json:"encryption_algo"`
+	DeliveredAt     time.Time `json:"delivered_at"`
+}
+
+// ValidateRecipientKey checks that a recipient key, if set, is a compressed secp256k1 public key
+func ValidateRecipientKey(recipientKey []byte) error {
+	if len(recipientKey) == 0 {
+		return nil
+	}
+	if err := crypto.ValidateRecipientKey(recipientKey); err != nil {
+		return fmt.Errorf("invalid recipient key: %w", err)
+	}
+	return nil
+}
+
+// ValidateExecutor checks that a capsule executor, if set, is an address other than the owner
+func ValidateExecutor(owner, executor string) error {
+	if executor == "" {
+		return nil
+	}
+	if _, err := sdk.AccAddressFromBech32(executor); err != nil {
+		return fmt.Errorf("invalid executor address: %w", err)
+	}
+	if executor == owner {
+		return fmt.Errorf("owner cannot be the executor")
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// Purge reasons, recorded in the tombstone of a purged capsule
+const (
+	PurgeReasonRetention = "retention" // Retention period after unlock elapsed
+	PurgeReasonCancelled = "cancelled" // Capsule was cancelled
+	PurgeReasonExpired   = "expired"   // Capsule expired before it was opened
+	PurgeReasonErasure   = "erasure"   // Owner exercised the right to erasure
+)
+
+// CapsuleTombstone records the purge of a capsule's data. The capsule keeps its data
+// hash, metadata and history; its ciphertext, key shares and delivery keys are deleted
+// and its IPFS content is announced for unpinning.
+type CapsuleTombstone struct {
+	Reason       string    `json:"reason"`
+	DataSize     int64     `json:"data_size"`           // Size of the purged ciphertext
+	IPFSHash     string    `json:"ipfs_hash,omitempty"` // IPFS content to unpin
+	PurgedAt     time.Time `json:"purged_at"`
+	PurgedHeight int64     `json:"purged_height"`
+}
+
+// IsPurged checks whether the capsule's data has been purged
+func (tc *TimeCapsule) IsPurged() bool {
+	return tc.Tombstone != nil
+}
+
+// PurgeTime returns when the data of a finished capsule is purged, given how long data
+// is retained after unlock. Cancelled and expired capsules are purged at once. Active,
+// already purged and public reveal capsules, which hold no ciphertext, are not purged.
+func (tc *TimeCapsule) PurgeTime(retention time.Duration) (time.Time, bool) {
+	if tc.IsPurged() || tc.IsPublicReveal() {
+		return time.Time{}, false
+	}
+
+	switch tc.Status {
+	case CapsuleStatus_UNLOCKED:
+		openedAt := tc.UpdatedAt
+		if tc.OpenedAt != nil {
+			openedAt = *tc.OpenedAt
+		}
+		return openedAt.Add(retention), true
+	case CapsuleStatus_CANCELLED, CapsuleStatus_EXPIRED:
+		return tc.UpdatedAt, true
+	}
+
+	return time.Time{}, false
+}
+
+// PurgeReason returns the reason a finished capsule is purged for on schedule
+func (tc *TimeCapsule) PurgeReason() string {
+	switch tc.Status {
+	case CapsuleStatus_CANCELLED:
+		return PurgeReasonCancelled
+	case CapsuleStatus_EXPIRED:
+		return PurgeReasonExpired
+	default:
+		return PurgeReasonRetention
+	}
+}
+
+// Purge deletes the ciphertext and key material held by the capsule and leaves a
+// tombstone in their place. Key shares and superseded versions are purged by the keeper.
+func (tc *TimeCapsule) Purge(reason string, blockTime time.Time, height int64) {
+	tc.Tombstone = &CapsuleTombstone{
+		Reason:       reason,
+		DataSize:     tc.DataSize,
+		IPFSHash:     tc.IPFSHash,
+		PurgedAt:     blockTime,
+		PurgedHeight: height,
+	}
+
+	tc.EncryptedData = nil
+	tc.DataNonce = nil
+	tc.IPFSHash = ""
+	tc.DataSize = 0
+	tc.TimelockKey = nil
+	tc.ShareCommitments = nil
+	tc.DeliveryCiphertext = nil
+	tc.DeliveryWrappedKey = nil
+	tc.DeliveryKeyNonce = nil
+	if tc.Delivery != nil {
+		tc.Delivery.Ciphertext = nil
+		tc.Delivery.WrappedKey = nil
+		tc.Delivery.WrappedKeyNonce = nil
+	}
+	if tc.PassphraseFactor != nil {
+		tc.PassphraseFactor.Ciphertext = nil
+		tc.PassphraseFactor.Nonce = nil
+	}
+	tc.UpdatedAt = blockTime
+}
+
+// Purge deletes the ciphertext and wrapped key of a superseded version, keeping its
+// data hash in the history
+func (v *CapsuleVersion) Purge() {
+	v.EncryptedData = nil
+	v.IPFSHash = ""
+	v.DataNonce = nil
+	v.TimelockKey = nil
+	v.KeyShares = nil
+	v.ShareCommitments = nil
+}
+
+// validateTombstone validates a purged capsule, which keeps its data hash but no
+// ciphertext or key material
+func (tc *TimeCapsule) validateTombstone() error {
+	switch tc.Status {
+	case CapsuleStatus_UNLOCKED, CapsuleStatus_CANCELLED, CapsuleStatus_EXPIRED:
+	default:
+		return fmt.Errorf("capsule with status %s cannot be purged", tc.Status)
+	}
+
+	switch tc.Tombstone.Reason {
+	case PurgeReasonRetention, PurgeReasonCancelled, PurgeReasonExpired, PurgeReasonErasure:
+	default:
+		return fmt.Errorf("unknown purge reason %q", tc.Tombstone.Reason)
+	}
+
+	if tc.DataHash == "" {
+		return fmt.Errorf("data hash cannot be empty")
+	}
+
+	if len(tc.EncryptedData) > 0 || tc.IPFSHash != "" || len(tc.TimelockKey) > 0 || len(tc.ShareCommitments) > 0 || len(tc.DeliveryWrappedKey) > 0 {
+		return fmt.Errorf("purged capsule cannot hold encrypted data or keys")
+	}
+
+	if tc.HasEscrow() {
+		return fmt.Errorf("purged capsule cannot hold an escrow")
+	}
+
+	return nil
+}
+
+// ValidateExpiry checks that a capsule expires after it unlocks, when it unlocks on a time
+func ValidateExpiry(expiresAt, unlockTime *time.Time) error {
+	if expiresAt == nil {
+		return nil
+	}
+	if unlockTime != nil && !expiresAt.After(*unlockTime) {
+		return fmt.Errorf("expiry %s must be after the unlock time %s", expiresAt.Format(time.RFC3339), unlockTime.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+)
+
+// StorageTypePublic marks capsules whose content is published in state on reveal
+const StorageTypePublic = "public"
+
+// PublicReveal is the content of a public reveal capsule, published once its
+// commitment is opened
+type PublicReveal struct {
+	Data         []byte    `json:"data"`
+	Salt         []byte    `json:"salt,omitempty"`
+	RevealedBy   string    `json:"revealed_by"`
+	RevealHeight int64     `json:"reveal_height"`
+	RevealedAt   time.Time `json:"revealed_at"`
+}
+
+// IsPublicReveal checks whether the capsule commits to content that is revealed publicly
+func (tc *TimeCapsule) IsPublicReveal() bool {
+	return tc.CapsuleType == CapsuleType_PUBLIC_REVEAL
+}
+
+// validatePublicReveal validates a public reveal capsule, which holds a commitment
+// in DataHash instead of encrypted data and key material
+func (tc *TimeCapsule) validatePublicReveal() error {
+	if err := crypto.ValidateCommitment(tc.DataHash); err != nil {
+		return fmt.Errorf("invalid commitment: %w", err)
+	}
+
+	if tc.UnlockTime == nil {
+		return fmt.Errorf("public reveal capsule must have unlock time")
+	}
+	if tc.UnlockHeight != 0 || tc.UnlockEpoch != nil {
+		return fmt.Errorf("public reveal capsule can only unlock on its unlock time")
+	}
+
+	if len(tc.EncryptedData) > 0 || tc.IPFSHash != "" || tc.TotalShares != 0 || tc.IsTimelocked() || tc.HasDelivery() {
+		return fmt.Errorf("public reveal capsule cannot hold encrypted data or keys")
+	}
+
+	if tc.HasEscrow() || len(tc.RecipientKey) > 0 {
+		return fmt.Errorf("public reveal capsule cannot have an escrow or recipient key")
+	}
+
+	if tc.ExpiresAt != nil {
+		return fmt.Errorf("public reveal capsule cannot expire")
+	}
+
+	if err := ValidateGuardians(tc.Owner, tc.Guardians, tc.GuardianThreshold); err != nil {
+		return err
+	}
+
+	if tc.Reveal != nil {
+		if crypto.CommitContent(tc.Reveal.Data, tc.Reveal.Salt) != tc.DataHash {
+			return fmt.Errorf("revealed content does not match the commitment")
+		}
+		if tc.Status != CapsuleStatus_UNLOCKED {
+			return fmt.Errorf("revealed capsule must be unlocked")
+		}
+	}
+
+	return nil
+}
+
+// ValidateReveal checks revealed content against the size limits of a reveal
+func ValidateReveal(data, salt []byte, maxDataSize uint64) error {
+	if len(data) == 0 {
+		return fmt.Errorf("revealed data cannot be empty")
+	}
+	if uint64(len(data)) > maxDataSize {
+		return fmt.Errorf("revealed data size %d exceeds maximum %d", len(data), maxDataSize)
+	}
+	if len(salt) > crypto.MaxRevealSaltSize {
+		return fmt.Errorf("salt size %d exceeds maximum %d", len(salt), crypto.MaxRevealSaltSize)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"fmt"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// CapsuleAllowance lets a sponsor pay the creation and storage fees of the capsules a
+// grantee creates. Like an x/feegrant allowance it is bounded, here by the number of
+// capsules, their stored bytes, the fees spent and an expiry.
+type CapsuleAllowance struct {
+	Sponsor      string     `json:"sponsor"`
+	Grantee      string     `json:"grantee"`
+	MaxCapsules  uint32     `json:"max_capsules,omitempty"` // Capsules the sponsor pays for, 0 for no limit
+	MaxBytes     uint64     `json:"max_bytes,omitempty"`    // Stored bytes the sponsor pays for, 0 for no limit
+	SpendLimit   sdk.Coins  `json:"spend_limit,omitempty"`  // Fees the sponsor still pays, no limit when empty
+	Expiration   *time.Time `json:"expiration,omitempty"`   // The allowance cannot be used from then on
+	UsedCapsules uint32     `json:"used_capsules"`          // Capsules created under the allowance
+	UsedBytes    uint64     `json:"used_bytes"`             // Stored bytes of those capsules
+}
+
+// IsExpired checks whether the allowance can no longer be used at a block time
+func (a CapsuleAllowance) IsExpired(blockTime time.Time) bool {
+	return a.Expiration != nil && !blockTime.Before(*a.Expiration)
+}
+
+// Use charges a capsule of dataSize stored bytes and its fees to the allowance. It fails
+// when the allowance expired or the capsule would exceed one of its limits. Like an
+// x/feegrant basic allowance, it reports whether the fees spent the rest of the spend
+// limit, in which case the allowance is used up and should be removed.
+func (a *CapsuleAllowance) Use(blockTime time.Time, dataSize int64, fees sdk.Coins) (spent bool, err error) {
+	if a.IsExpired(blockTime) {
+		return false, fmt.Errorf("allowance expired at %s", a.Expiration.Format(time.RFC3339))
+	}
+	if a.MaxCapsules != 0 && a.UsedCapsules >= a.MaxCapsules {
+		return false, fmt.Errorf("all %d sponsored capsules are used", a.MaxCapsules)
+	}
+	if a.MaxBytes != 0 && a.UsedBytes+uint64(dataSize) > a.MaxBytes {
+		return false, fmt.Errorf("%d bytes used, %d more exceed the limit of %d", a.UsedBytes, dataSize, a.MaxBytes)
+	}
+	if !a.SpendLimit.Empty() {
+		left, invalid := a.SpendLimit.SafeSub(fees...)
+		if invalid {
+			return false, fmt.Errorf("fees %s exceed the remaining spend limit %s", fees, a.SpendLimit)
+		}
+		a.SpendLimit = left
+		spent = left.IsZero()
+	}
+
+	a.UsedCapsules++
+	a.UsedBytes += uint64(dataSize)
+	return spent, nil
+}
+
+// Validate performs basic validation of a capsule allowance
+func (a CapsuleAllowance) Validate() error {
+	if _, err := sdk.AccAddressFromBech32(a.Sponsor); err != nil {
+		return fmt.Errorf("invalid sponsor address: %w", err)
+	}
+	if _, err := sdk.AccAddressFromBech32(a.Grantee); err != nil {
+		return fmt.Errorf("invalid grantee address: %w", err)
+	}
+	if a.Sponsor == a.Grantee {
+		return fmt.Errorf("sponsor cannot grant an allowance to itself")
+	}
+	if a.MaxCapsules != 0 && a.UsedCapsules > a.MaxCapsules {
+		return fmt.Errorf("used capsules %d exceed the limit of %d", a.UsedCapsules, a.MaxCapsules)
+	}
+	if a.MaxBytes != 0 && a.UsedBytes > a.MaxBytes {
+		return fmt.Errorf("used bytes %d exceed the limit of %d", a.UsedBytes, a.MaxBytes)
+	}
+	if err := a.SpendLimit.Validate(); err != nil {
+		return fmt.Errorf("invalid spend limit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// UnlockLatencyBuckets are the upper bounds, in seconds, of the unlock latency histogram:
+// one hour, one day, one week, 30 days and one year. Latencies above the last bound are
+// counted in an extra overflow bucket.
+var UnlockLatencyBuckets = []int64{3600, 86400, 7 * 86400, 30 * 86400, 365 * 86400}
+
+// LatencyHistogram counts capsule unlock latencies, the time from creation to opening
+type LatencyHistogram struct {
+	Buckets []uint64 `json:"buckets"` // Per-bucket counts, one per UnlockLatencyBuckets bound plus the overflow
+	Count   uint64   `json:"count"`   // Latencies observed
+	Sum     int64    `json:"sum"`     // Sum of the latencies, in seconds
+}
+
+// CapsuleAggregates holds module-wide capsule statistics. They are updated with every
+// capsule write, so statistics queries and metrics do not walk the capsules.
+type CapsuleAggregates struct {
+	Total         uint64            `json:"total"`
+	ByType        map[string]uint64 `json:"by_type"`
+	ByStatus      map[string]uint64 `json:"by_status"`
+	ByStorage     map[string]uint64 `json:"by_storage"`
+	TotalBytes    uint64            `json:"total_bytes"`
+	UnlockLatency LatencyHistogram  `json:"unlock_latency"`
+}
+
+// NewCapsuleAggregates returns the statistics of a chain without capsules
+func NewCapsuleAggregates() CapsuleAggregates {
+	return CapsuleAggregates{
+		ByType:        make(map[string]uint64),
+		ByStatus:      make(map[string]uint64),
+		ByStorage:     make(map[string]uint64),
+		UnlockLatency: LatencyHistogram{Buckets: make([]uint64, len(UnlockLatencyBuckets)+1)},
+	}
+}
+
+// UnlockLatency returns the time from the capsule's creation to its opening in seconds,
+// and whether the capsule was opened
+func (tc *TimeCapsule) UnlockLatency() (int64, bool) {
+	if tc.Status != CapsuleStatus_UNLOCKED || tc.OpenedAt == nil {
+		return 0, false
+	}
+	latency := int64(tc.OpenedAt.Sub(tc.CreatedAt).Seconds())
+	if latency < 0 {
+		latency = 0
+	}
+	return latency, true
+}
+
+// latencyBucket returns the index of the histogram bucket a latency falls in
+func latencyBucket(latency int64) int {
+	for i, bound := range UnlockLatencyBuckets {
+		if latency <= bound {
+			return i
+		}
+	}
+	return len(UnlockLatencyBuckets)
+}
+
+// LatencyBucketLabel returns the Prometheus "le" label of a histogram bucket
+func LatencyBucketLabel(bucket int) string {
+	if bucket >= len(UnlockLatencyBuckets) {
+		return "+Inf"
+	}
+	return strconv.FormatInt(UnlockLatencyBuckets[bucket], 10)
+}
+
+// Add counts a capsule in the statistics
+func (a *CapsuleAggregates) Add(capsule TimeCapsule) {
+	a.init()
+	a.Total++
+	a.ByType[capsule.CapsuleType.String()]++
+	a.ByStatus[capsule.Status.String()]++
+	a.ByStorage[capsule.StorageType]++
+	a.TotalBytes += uint64(capsule.DataSize)
+
+	if latency, ok := capsule.UnlockLatency(); ok {
+		a.UnlockLatency.Buckets[latencyBucket(latency)]++
+		a.UnlockLatency.Count++
+		a.UnlockLatency.Sum += latency
+	}
+}
+
+// Remove takes a previously added capsule out of the statistics. Counts that drop to
+// zero are deleted, so that the statistics only depend on the stored capsules.
+func (a *CapsuleAggregates) Remove(capsule TimeCapsule) {
+	a.init()
+	a.Total--
+	decrement(a.ByType, capsule.CapsuleType.String())
+	decrement(a.ByStatus, capsule.Status.String())
+	decrement(a.ByStorage, capsule.StorageType)
+	a.TotalBytes -= uint64(capsule.DataSize)
+
+	if latency, ok := capsule.UnlockLatency(); ok {
+		a.UnlockLatency.Buckets[latencyBucket(latency)]--
+		a.UnlockLatency.Count--
+		a.UnlockLatency.Sum -= latency
+	}
+}
+
+// init allocates the maps and buckets of statistics decoded from an empty item
+func (a *CapsuleAggregates) init() {
+	if a.ByType == nil {
+		a.ByType = make(map[string]uint64)
+	}
+	if a.ByStatus == nil {
+		a.ByStatus = make(map[string]uint64)
+	}
+	if a.ByStorage == nil {
+		a.ByStorage = make(map[string]uint64)
+	}
+	if len(a.UnlockLatency.Buckets) != len(UnlockLatencyBuckets)+1 {
+		buckets := make([]uint64, len(UnlockLatencyBuckets)+1)
+		copy(buckets, a.UnlockLatency.Buckets)
+		a.UnlockLatency.Buckets = buckets
+	}
+}
+
+func decrement(counts map[string]uint64, key string) {
+	if counts[key] <= 1 {
+		delete(counts, key)
+		return
+	}
+	counts[key]--
+}
+
+// Diff describes how the statistics differ from the expected ones, empty when they match
+func (a CapsuleAggregates) Diff(expected CapsuleAggregates) string {
+	a.init()
+	expected.init()
+
+	var diff string
+	if a.Total != expected.Total {
+		diff += fmt.Sprintf("total: %d, expected %d\n", a.Total, expected.Total)
+	}
+	diff += diffCounts("type", a.ByType, expected.ByType)
+	diff += diffCounts("status", a.ByStatus, expected.ByStatus)
+	diff += diffCounts("storage type", a.ByStorage, expected.ByStorage)
+	if a.TotalBytes != expected.TotalBytes {
+		diff += fmt.Sprintf("total bytes: %d, expected %d\n", a.TotalBytes, expected.TotalBytes)
+	}
+	for i := range a.UnlockLatency.Buckets {
+		if a.UnlockLatency.Buckets[i] != expected.UnlockLatency.Buckets[i] {
+			diff += fmt.Sprintf("unlock latency bucket le=%s: %d, expected %d\n",
+				LatencyBucketLabel(i), a.UnlockLatency.Buckets[i], expected.UnlockLatency.Buckets[i])
+		}
+	}
+	if a.UnlockLatency.Count != expected.UnlockLatency.Count || a.UnlockLatency.Sum != expected.UnlockLatency.Sum {
+		diff += fmt.Sprintf("unlock latency: %d observations summing to %ds, expected %d summing to %ds\n",
+			a.UnlockLatency.Count, a.UnlockLatency.Sum, expected.UnlockLatency.Count, expected.UnlockLatency.Sum)
+	}
+	return diff
+}
+
+func diffCounts(name string, counts, expected map[string]uint64) string {
+	keys := make([]string, 0, len(counts)+len(expected))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	for key := range expected {
+		if _, ok := counts[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var diff string
+	for _, key := range keys {
+		if counts[key] != expected[key] {
+			diff += fmt.Sprintf("%s %q: %d, expected %d\n", name, key, counts[key], expected[key])
+		}
+	}
+	return diff
+}
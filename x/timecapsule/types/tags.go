@@ -0,0 +1,126 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MetadataIndexSeparator separates the key and the value of an indexed metadata entry
+const MetadataIndexSeparator = "="
+
+// ValidateTag checks that a tag, or an indexed metadata key, is printable text without
+// surrounding whitespace
+func ValidateTag(tag string) error {
+	if tag == "" {
+		return fmt.Errorf("tag cannot be empty")
+	}
+	if !utf8.ValidString(tag) {
+		return fmt.Errorf("tag %q is not valid UTF-8", tag)
+	}
+	if strings.TrimSpace(tag) != tag {
+		return fmt.Errorf("tag %q cannot start or end with whitespace", tag)
+	}
+	for _, r := range tag {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("tag %q cannot contain control characters", tag)
+		}
+	}
+	return nil
+}
+
+// ValidateTags performs stateless checks of capsule tags. Their count and length are
+// bounded by the module parameters.
+func ValidateTags(tags []string) error {
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if err := ValidateTag(tag); err != nil {
+			return err
+		}
+		if seen[tag] {
+			return fmt.Errorf("duplicate tag: %s", tag)
+		}
+		seen[tag] = true
+	}
+	return nil
+}
+
+// ValidateTagBounds checks the tags of a capsule, and the values of its indexed metadata
+// keys, against the bounds of the tag index
+func (p Params) ValidateTagBounds(tags []string, metadata map[string]string) error {
+	if err := ValidateTags(tags); err != nil {
+		return err
+	}
+	if uint32(len(tags)) > p.MaxTags {
+		return fmt.Errorf("capsule has %d tags, at most %d are allowed", len(tags), p.MaxTags)
+	}
+	for _, tag := range tags {
+		if uint32(len(tag)) > p.MaxTagLength {
+			return fmt.Errorf("tag %q exceeds %d bytes", tag, p.MaxTagLength)
+		}
+	}
+
+	for _, key := range p.IndexedMetadataKeys {
+		value, ok := metadata[key]
+		if !ok {
+			continue
+		}
+		if err := ValidateTag(value); err != nil {
+			return fmt.Errorf("metadata %s: %w", key, err)
+		}
+		if uint32(len(value)) > p.MaxTagLength {
+			return fmt.Errorf("metadata %s exceeds %d bytes", key, p.MaxTagLength)
+		}
+	}
+
+	return nil
+}
+
+// IsIndexedMetadataKey checks whether capsules are indexed by the values of a metadata key
+func (p Params) IsIndexedMetadataKey(key string) bool {
+	for _, indexed := range p.IndexedMetadataKeys {
+		if indexed == key {
+			return true
+		}
+	}
+	return false
+}
+
+// MetadataIndexTerm returns the index entry of a metadata key and value
+func MetadataIndexTerm(key, value string) string {
+	return key + MetadataIndexSeparator + value
+}
+
+// IndexedMetadata returns the index entries of the capsule's metadata under the indexed
+// keys, in key order
+func (tc *TimeCapsule) IndexedMetadata(indexedKeys []string) []string {
+	var terms []string
+	for _, key := range indexedKeys {
+		if value, ok := tc.Metadata[key]; ok && value != "" {
+			terms = append(terms, MetadataIndexTerm(key, value))
+		}
+	}
+	sort.Strings(terms)
+	return terms
+}
+
+// UnlockTimeWithin checks whether the capsule's unlock time lies in [after, before).
+// Capsules without an unlock time are only within an unbounded range.
+func (tc *TimeCapsule) UnlockTimeWithin(after, before *time.Time) bool {
+	if after == nil && before == nil {
+		return true
+	}
+	if tc.UnlockTime == nil {
+		return false
+	}
+	if after != nil && tc.UnlockTime.Before(*after) {
+		return false
+	}
+	if before != nil && !tc.UnlockTime.Before(*before) {
+		return false
+	}
+	return true
+}
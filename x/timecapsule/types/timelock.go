@@ -0,0 +1,218 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/crypto"
+)
+
+// TimelockEpochStatus defines the lifecycle of an epoch key
+type TimelockEpochStatus int32
+
+const (
+	TimelockEpochStatus_UNKNOWN  TimelockEpochStatus = 0
+	TimelockEpochStatus_DEALING  TimelockEpochStatus = 1 // Validators are dealing key shares
+	TimelockEpochStatus_KEYED    TimelockEpochStatus = 2 // Public key known, secret still locked
+	TimelockEpochStatus_RELEASED TimelockEpochStatus = 3 // Secret published at unlock time
+	TimelockEpochStatus_FAILED   TimelockEpochStatus = 4 // Key generation did not complete in time
+)
+
+// String returns the string representation of TimelockEpochStatus
+func (s TimelockEpochStatus) String() string {
+	switch s {
+	case TimelockEpochStatus_DEALING:
+		return "DEALING"
+	case TimelockEpochStatus_KEYED:
+		return "KEYED"
+	case TimelockEpochStatus_RELEASED:
+		return "RELEASED"
+	case TimelockEpochStatus_FAILED:
+		return "FAILED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// DefaultTimelockKeyFile is the location of a validator's timelock key relative to the node home
+const DefaultTimelockKeyFile = "config/timelock_key.json"
+
+// TimelockDealingWindow is the number of blocks complaints are accepted for once an
+// epoch has collected a threshold of dealings
+const TimelockDealingWindow = int64(10)
+
+// TimelockValidatorKey is the key a validator registered to receive key-generation shares
+type TimelockValidatorKey struct {
+	Validator    string    `json:"validator"`    // Operator address
+	ConsAddress  string    `json:"cons_address"` // Consensus address signing vote extensions
+	PublicKey    []byte    `json:"public_key"`   // Compressed secp256k1 key
+	RegisteredAt time.Time `json:"registered_at"`
+}
+
+// TimelockParticipant is a validator taking part in an epoch's key generation
+type TimelockParticipant struct {
+	ConsAddress string `json:"cons_address"`
+	Index       uint32 `json:"index"` // Share index, starting at 1
+	PublicKey   []byte `json:"public_key"`
+}
+
+// TimelockEpoch is a threshold key whose secret is released at UnlockTime
+type TimelockEpoch struct {
+	Epoch           uint64                `json:"epoch"`
+	UnlockTime      time.Time             `json:"unlock_time"`
+	Status          TimelockEpochStatus   `json:"status"`
+	Threshold       uint32                `json:"threshold"`
+	Participants    []TimelockParticipant `json:"participants"`
+	Dealers         []uint32              `json:"dealers,omitempty"`     // Qualified dealer indices
+	PublicKey       []byte                `json:"public_key,omitempty"`  // Set once keyed
+	Commitments     [][]byte              `json:"commitments,omitempty"` // Aggregated Feldman commitments
+	SecretKey       []byte                `json:"secret_key,omitempty"`  // Set once released
+	DealingDeadline int64                 `json:"dealing_deadline,omitempty"`
+	CreatedHeight   int64                 `json:"created_height"`
+	KeyedHeight     int64                 `json:"keyed_height,omitempty"`
+	ReleasedHeight  int64                 `json:"released_height,omitempty"`
+}
+
+// Participant returns the participant with the given consensus address
+func (e TimelockEpoch) Participant(consAddress string) (TimelockParticipant, bool) {
+	for _, p := range e.Participants {
+		if p.ConsAddress == consAddress {
+			return p, true
+		}
+	}
+	return TimelockParticipant{}, false
+}
+
+// ParticipantByKey returns the participant that registered the given public key
+func (e TimelockEpoch) ParticipantByKey(pubKey []byte) (TimelockParticipant, bool) {
+	for _, p := range e.Participants {
+		if bytes.Equal(p.PublicKey, pubKey) {
+			return p, true
+		}
+	}
+	return TimelockParticipant{}, false
+}
+
+// TimelockDealing is one validator's contribution to an epoch key
+type TimelockDealing struct {
+	Epoch        uint64   `json:"epoch"`
+	Dealer       uint32   `json:"dealer"`        // Participant index of the dealer
+	Commitments  [][]byte `json:"commitments"`   // Feldman commitments to the dealer's polynomial
+	SealedShares [][]byte `json:"sealed_shares"` // One share per participant, in index order
+	Disqualified bool     `json:"disqualified,omitempty"`
+	Height       int64    `json:"height,omitempty"`
+}
+
+// Validate checks the dealing is well formed for the epoch
+func (d TimelockDealing) Validate(epoch TimelockEpoch) error {
+	if d.Epoch != epoch.Epoch {
+		return fmt.Errorf("dealing for epoch %d, expected %d", d.Epoch, epoch.Epoch)
+	}
+	if d.Dealer == 0 || int(d.Dealer) > len(epoch.Participants) {
+		return fmt.Errorf("invalid dealer index %d", d.Dealer)
+	}
+	if len(d.Commitments) != int(epoch.Threshold) {
+		return fmt.Errorf("dealing has %d commitments, expected %d", len(d.Commitments), epoch.Threshold)
+	}
+	if len(d.SealedShares) != len(epoch.Participants) {
+		return fmt.Errorf("dealing has %d shares, expected %d", len(d.SealedShares), len(epoch.Participants))
+	}
+	for i, c := range d.Commitments {
+		if err := crypto.ValidateTimelockPoint(c); err != nil {
+			return fmt.Errorf("invalid commitment %d: %w", i, err)
+		}
+	}
+	for i, s := range d.SealedShares {
+		if len(s) <= crypto.TimelockPointSize {
+			return fmt.Errorf("invalid sealed share %d", i+1)
+		}
+	}
+	return nil
+}
+
+// TimelockComplaint proves that a dealer sent an invalid share to the complainer
+type TimelockComplaint struct {
+	Epoch      uint64 `json:"epoch"`
+	Dealer     uint32 `json:"dealer"`
+	Complainer uint32 `json:"complainer"`
+	SharedKey  []byte `json:"shared_key"` // ECDH key of the sealed share
+	Proof      []byte `json:"proof"`      // Proof that SharedKey matches the complainer's key
+}
+
+// TimelockShare is a validator's share of a released epoch secret
+type TimelockShare struct {
+	Epoch uint64 `json:"epoch"`
+	Index uint32 `json:"index"`
+	Share []byte `json:"share"`
+}
+
+// TimelockVoteExtension is the payload validators attach to their precommits
+type TimelockVoteExtension struct {
+	Height     int64               `json:"height"`
+	Dealings   []TimelockDealing   `json:"dealings,omitempty"`
+	Complaints []TimelockComplaint `json:"complaints,omitempty"`
+	Shares     []TimelockShare     `json:"shares,omitempty"`
+}
+
+// IsEmpty checks whether the extension carries anything
+func (ve TimelockVoteExtension) IsEmpty() bool {
+	return len(ve.Dealings) == 0 && len(ve.Complaints) == 0 && len(ve.Shares) == 0
+}
+
+// TimelockInjectionPrefix marks the vote extensions the proposer injects as the first block tx
+var TimelockInjectionPrefix = []byte("timecapsule/timelock:")
+
+// IsTimelockInjection checks whether a block tx carries injected vote extensions
+func IsTimelockInjection(tx []byte) bool {
+	return bytes.HasPrefix(tx, TimelockInjectionPrefix)
+}
+
+// EncodeTimelockInjection wraps the previous block's extended commit into a block tx
+func EncodeTimelockInjection(commit abci.ExtendedCommitInfo) ([]byte, error) {
+	bz, err := commit.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, TimelockInjectionPrefix...), bz...), nil
+}
+
+// DecodeTimelockInjection unwraps the extended commit injected by the proposer
+func DecodeTimelockInjection(tx []byte) (*abci.ExtendedCommitInfo, error) {
+	if !IsTimelockInjection(tx) {
+		return nil, fmt.Errorf("not a timelock injection")
+	}
+
+	var commit abci.ExtendedCommitInfo
+	if err := commit.Unmarshal(tx[len(TimelockInjectionPrefix):]); err != nil {
+		return nil, fmt.Errorf("failed to decode timelock injection: %w", err)
+	}
+	return &commit, nil
+}
+
+// TimelockEpochFor returns the first epoch whose unlock time is not before t
+func TimelockEpochFor(t time.Time, duration time.Duration) uint64 {
+	seconds := int64(duration / time.Second)
+	unix := t.Unix()
+	if seconds <= 0 || unix <= 0 {
+		return 0
+	}
+	epoch := unix / seconds
+	if unix%seconds != 0 {
+		epoch++
+	}
+	return uint64(epoch)
+}
+
+// TimelockEpochTime returns the unlock time of an epoch
+func TimelockEpochTime(epoch uint64, duration time.Duration) time.Time {
+	return time.Unix(int64(epoch)*int64(duration/time.Second), 0).UTC()
+}
+
+// TimelockThreshold returns the number of shares required to release an epoch key.
+// More than two thirds of the participants must cooperate, matching consensus safety.
+func TimelockThreshold(participants int) uint32 {
+	return uint32(participants*2/3 + 1)
+}
@@ -0,0 +1,138 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// EpochSourceUpgrade is the epoch source whose clocks start when an upgrade plan is applied
+const EpochSourceUpgrade = "upgrade"
+
+// DefaultUpgradeEpochBlocks is the length in blocks of an epoch of the upgrade epoch source
+const DefaultUpgradeEpochBlocks = int64(14400)
+
+// MaxUnlockEpochs bounds the number of epochs a capsule can wait for
+const MaxUnlockEpochs = uint64(1_000_000)
+
+// EpochTrigger unlocks a time-locked capsule a number of epochs after an epoch clock of
+// a registered epoch source starts, e.g. 10 epochs after the upgrade plan "v2" is applied.
+// Once the source reports the height the epoch starts at, it is recorded as the capsule's
+// unlock height.
+type EpochTrigger struct {
+	Source     string `json:"source"`     // Registered epoch source, e.g. "upgrade"
+	Identifier string `json:"identifier"` // Epoch clock of the source, e.g. an upgrade plan name
+	Epochs     uint64 `json:"epochs"`     // Epochs after the clock starts
+}
+
+// Validate validates an epoch trigger
+func (et *EpochTrigger) Validate() error {
+	if et.Source == "" {
+		return fmt.Errorf("epoch source cannot be empty")
+	}
+	if et.Identifier == "" {
+		return fmt.Errorf("epoch identifier cannot be empty")
+	}
+	if et.Epochs > MaxUnlockEpochs {
+		return fmt.Errorf("epochs cannot exceed %d", MaxUnlockEpochs)
+	}
+	return nil
+}
+
+// String returns the trigger as source:identifier:epochs
+func (et *EpochTrigger) String() string {
+	return fmt.Sprintf("%s:%s:%d", et.Source, et.Identifier, et.Epochs)
+}
+
+// ValidateUnlockTrigger checks that a time-locked capsule unlocks on exactly one of a
+// block time, a block height or an epoch trigger
+func ValidateUnlockTrigger(unlockTime *time.Time, unlockHeight int64, unlockEpoch *EpochTrigger) error {
+	triggers := 0
+	if unlockTime != nil {
+		triggers++
+	}
+	if unlockHeight != 0 {
+		if unlockHeight < 0 {
+			return fmt.Errorf("unlock height cannot be negative")
+		}
+		triggers++
+	}
+	if unlockEpoch != nil {
+		if err := unlockEpoch.Validate(); err != nil {
+			return fmt.Errorf("invalid unlock epoch: %w", err)
+		}
+		triggers++
+	}
+
+	switch triggers {
+	case 0:
+		return fmt.Errorf("time-locked capsule must have an unlock time, height or epoch")
+	case 1:
+		return nil
+	default:
+		return fmt.Errorf("time-locked capsule can only unlock on one of unlock time, height or epoch")
+	}
+}
+
+// HasUnlockTrigger returns true if the capsule unlocks on a block time, height or epoch
+func (tc *TimeCapsule) HasUnlockTrigger() bool {
+	return tc.UnlockTime != nil || tc.UnlockHeight > 0 || tc.UnlockEpoch != nil
+}
+
+// UnlockTriggerReached checks whether the unlock trigger of a time-locked capsule has been
+// reached. An epoch trigger is reached once it resolved to an unlock height at or below
+// the current height.
+func (tc *TimeCapsule) UnlockTriggerReached(ctx sdk.Context) bool {
+	return tc.unlockReached(ctx.BlockTime(), ctx.BlockHeight())
+}
+
+// UnlockTimeTrigger returns the block time an active capsule becomes unlockable after:
+// the unlock time of a time-locked capsule, or the end of the inactivity period of a dead
+// man's switch
+func (tc *TimeCapsule) UnlockTimeTrigger() (time.Time, bool) {
+	if tc.Status != CapsuleStatus_ACTIVE {
+		return time.Time{}, false
+	}
+
+	switch tc.CapsuleType {
+	case CapsuleType_TIME_LOCK:
+		if tc.UnlockTime != nil {
+			return *tc.UnlockTime, true
+		}
+	case CapsuleType_DEAD_MANS_SWITCH:
+		if tc.LastActivity != nil && tc.InactivityPeriod > 0 {
+			return tc.LastActivity.Add(time.Duration(tc.InactivityPeriod) * time.Second), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (tc *TimeCapsule) unlockReached(currentTime time.Time, currentHeight int64) bool {
+	if tc.UnlockTime != nil {
+		return currentTime.After(*tc.UnlockTime)
+	}
+	if tc.UnlockHeight > 0 {
+		return currentHeight >= tc.UnlockHeight
+	}
+	return false
+}
+
+// timeUntilUnlock estimates the time left until the unlock trigger of a time-locked
+// capsule is reached. Height triggers are estimated from the expected block time; epoch
+// triggers whose height is not known yet cannot be estimated.
+func (tc *TimeCapsule) timeUntilUnlock(currentTime time.Time, currentHeight int64, expectedBlockTime time.Duration) (time.Duration, bool) {
+	if tc.UnlockTime != nil {
+		return tc.UnlockTime.Sub(currentTime), true
+	}
+	if tc.UnlockHeight > 0 {
+		blocks := tc.UnlockHeight - currentHeight
+		if blocks > 0 && expectedBlockTime > 0 && blocks > int64(math.MaxInt64/expectedBlockTime) {
+			return time.Duration(math.MaxInt64), true
+		}
+		return time.Duration(blocks) * expectedBlockTime, true
+	}
+	return 0, false
+}
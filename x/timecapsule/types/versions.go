@@ -0,0 +1,66 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxCapsuleVersions is the maximum number of versions a capsule can accumulate
+const MaxCapsuleVersions = 100
+
+// Version policies, deciding which versions the recipient receives on open
+const (
+	VersionPolicyLatest = "latest" // Only the latest version; superseded ciphertexts are discarded
+	VersionPolicyAll    = "all"    // Every version; superseded versions keep their own key material
+)
+
+// CapsuleVersion is a superseded version of a capsule's content. Its data hash
+// stays committed for audit; the ciphertext is only kept under the "all" policy.
+type CapsuleVersion struct {
+	CapsuleID      uint64 `json:"capsule_id"`
+	Version        uint32 `json:"version"`
+	DataHash       string `json:"data_hash"` // SHA-256 hash of the version's original data
+	DataSize       int64  `json:"data_size"`
+	StorageType    string `json:"storage_type"`
+	EncryptedData  []byte `json:"encrypted_data,omitempty"`
+	IPFSHash       string `json:"ipfs_hash,omitempty"`
+	EncryptionAlgo string `json:"encryption_algo,omitempty"`
+	DataNonce      []byte `json:"data_nonce,omitempty"`
+	Compression    string `json:"compression,omitempty"`
+
+	// Data key of the version, either sealed to its timelock epoch or split into
+	// the key shares the version was distributed with
+	TimelockEpoch    uint64     `json:"timelock_epoch,omitempty"`
+	TimelockKey      []byte     `json:"timelock_key,omitempty"`
+	KeyShares        []KeyShare `json:"key_shares,omitempty"`
+	ShareCommitments [][][]byte `json:"share_commitments,omitempty"`
+
+	Title            string            `json:"title,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	Tags             []string          `json:"tags,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+	CreatedAt        time.Time         `json:"created_at"`    // When the version was written
+	SupersededAt     time.Time         `json:"superseded_at"` // When the next version replaced it
+	SupersededHeight int64             `json:"superseded_height"`
+}
+
+// IsRetained checks whether the version's content can still be decrypted
+func (v CapsuleVersion) IsRetained() bool {
+	return len(v.EncryptedData) > 0 || v.IPFSHash != ""
+}
+
+// VersionContent is the decrypted content of a capsule version
+type VersionContent struct {
+	Version uint32 `json:"version"`
+	Data    []byte `json:"data"`
+}
+
+// ValidateVersionPolicy checks that a version policy is supported
+func ValidateVersionPolicy(policy string) error {
+	switch policy {
+	case "", VersionPolicyLatest, VersionPolicyAll:
+		return nil
+	default:
+		return fmt.Errorf("unknown version policy %q", policy)
+	}
+}
@@ -0,0 +1,81 @@
+package timecapsule_test
+
+import (
+	"context"
+	"testing"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"cosmossdk.io/log"
+
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/stream"
+	"github.com/cosmos/cosmos-sdk/x/timecapsule/types"
+)
+
+// replayServer collects the events a watch replays
+type replayServer struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events []*stream.CapsuleEvent
+}
+
+func (s *replayServer) Context() context.Context { return s.ctx }
+
+func (s *replayServer) Send(event *stream.CapsuleEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+// TestWatchStreamFollowsModuleEvents checks that the watch stream, which does not import
+// the module types, follows the events the module emits
+func TestWatchStreamFollowsModuleEvents(t *testing.T) {
+	testCases := []struct {
+		eventType string
+		expected  stream.CapsuleEventType
+	}{
+		{types.EventTypeCapsuleCreated, stream.CapsuleEventType_CREATED},
+		{types.EventTypeCapsuleHeartbeat, stream.CapsuleEventType_HEARTBEAT},
+		{types.EventTypeCapsuleUnlockable, stream.CapsuleEventType_UNLOCKABLE},
+		{types.EventTypeCapsuleAttested, stream.CapsuleEventType_UNLOCKABLE},
+		{types.EventTypeCapsuleOpened, stream.CapsuleEventType_OPENED},
+		{types.EventTypeCapsuleRevealed, stream.CapsuleEventType_OPENED},
+		{types.EventTypeCapsuleTransferred, stream.CapsuleEventType_TRANSFERRED},
+		{types.EventTypeCapsuleCancelled, stream.CapsuleEventType_CANCELLED},
+	}
+
+	res := abci.ResponseFinalizeBlock{TxResults: []*abci.ExecTxResult{{Code: abci.CodeTypeOK}}}
+	for _, tc := range testCases {
+		res.TxResults[0].Events = append(res.TxResults[0].Events, abci.Event{
+			Type: tc.eventType,
+			Attributes: []abci.EventAttribute{
+				{Key: types.AttributeKeyCapsuleID, Value: "7"},
+				{Key: types.AttributeKeyOwner, Value: "owner"},
+				{Key: types.AttributeKeyRecipient, Value: "recipient"},
+				{Key: types.AttributeKeyFrom, Value: "owner"},
+				{Key: types.AttributeKeyTo, Value: "owner"},
+			},
+		})
+	}
+
+	watcher := stream.NewWatcher(log.NewNopLogger(), 0)
+	req := abci.RequestFinalizeBlock{Height: 1, Txs: [][]byte{[]byte("tx")}}
+	require.NoError(t, watcher.ListenFinalizeBlock(context.Background(), req, res))
+	require.NoError(t, watcher.ListenCommit(context.Background(), abci.ResponseCommit{}, nil))
+
+	// The watch returns once it replayed the block, its context being done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	srv := &replayServer{ctx: ctx}
+	require.ErrorIs(t, watcher.WatchCapsules(&stream.WatchCapsulesRequest{StartHeight: 1}, srv), context.Canceled)
+
+	require.Len(t, srv.events, len(testCases))
+	for i, tc := range testCases {
+		require.Equal(t, tc.eventType, srv.events[i].AbciEvent)
+		require.Equal(t, tc.expected, srv.events[i].Type, tc.eventType)
+		require.Equal(t, uint64(7), srv.events[i].CapsuleId, tc.eventType)
+		require.Equal(t, "owner", srv.events[i].Owner, tc.eventType)
+		require.Equal(t, "recipient", srv.events[i].Recipient, tc.eventType)
+	}
+}